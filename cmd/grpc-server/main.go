@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	grpcHandlers "github.com/brenocoelho/messaging-app-go/internal/grpc"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories"
 	"github.com/brenocoelho/messaging-app-go/internal/services"
 	"github.com/brenocoelho/messaging-app-go/pkg/config"
+	"github.com/brenocoelho/messaging-app-go/pkg/encryption"
 	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
 	"github.com/brenocoelho/messaging-app-go/pkg/pgconn"
+	"github.com/brenocoelho/messaging-app-go/pkg/ratelimit"
 	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
+	"github.com/brenocoelho/messaging-app-go/pkg/webhook"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 )
 
@@ -29,13 +43,100 @@ type Config struct {
 	PostgresWriteDBName   string `mapstructure:"POSTGRES_WRITE_DB_NAME"`
 
 	GRPCPort string `mapstructure:"GRPC_PORT"`
+	HTTPPort string `mapstructure:"HTTP_PORT"`
 
 	RedisHost      string `mapstructure:"REDIS_HOST"`
 	RedisPort      string `mapstructure:"REDIS_PORT"`
 	RedisPassword  string `mapstructure:"REDIS_PASSWORD"`
 	IdempotencyTTL int    `mapstructure:"IDEMPOTENCY_TTL_MINUTES"`
+
+	AccessTokenTTLMinutes int `mapstructure:"ACCESS_TOKEN_TTL_MINUTES"`
+	RefreshTokenTTLHours  int `mapstructure:"REFRESH_TOKEN_TTL_HOURS"`
+
+	JWTKeyDir                    string `mapstructure:"JWT_KEY_DIR"`
+	JWTSigningKeyID              string `mapstructure:"JWT_SIGNING_KEY_ID"`
+	JWTKeyRotationOverlapMinutes int    `mapstructure:"JWT_KEY_ROTATION_OVERLAP_MINUTES"`
+	JWTKeyReloadSeconds          int    `mapstructure:"JWT_KEY_RELOAD_SECONDS"`
+	JWTIssuer                    string `mapstructure:"JWT_ISSUER"`
+	JWTAudience                  string `mapstructure:"JWT_AUDIENCE"`
+	JWTLeewaySeconds             int    `mapstructure:"JWT_LEEWAY_SECONDS"`
+
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     string `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailDir      string `mapstructure:"MAIL_DIR"`
+
+	PasswordResetURL           string `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetTTLMinutes    int    `mapstructure:"PASSWORD_RESET_TTL_MINUTES"`
+	PasswordResetEmailLimit    int    `mapstructure:"PASSWORD_RESET_EMAIL_LIMIT"`
+	PasswordResetIPLimit       int    `mapstructure:"PASSWORD_RESET_IP_LIMIT"`
+	PasswordResetWindowMinutes int    `mapstructure:"PASSWORD_RESET_WINDOW_MINUTES"`
+
+	EmailVerificationURL            string `mapstructure:"EMAIL_VERIFICATION_URL"`
+	EmailVerificationTTLHours       int    `mapstructure:"EMAIL_VERIFICATION_TTL_HOURS"`
+	EmailVerificationRequiredFor    string `mapstructure:"EMAIL_VERIFICATION_REQUIRED_FOR"`
+	VerificationResendLimit         int    `mapstructure:"VERIFICATION_RESEND_LIMIT"`
+	VerificationResendWindowMinutes int    `mapstructure:"VERIFICATION_RESEND_WINDOW_MINUTES"`
+
+	TOTPEncryptionKey string `mapstructure:"TOTP_ENCRYPTION_KEY"`
+	TOTPIssuer        string `mapstructure:"TOTP_ISSUER"`
+
+	// Failed logins per email back off after LOGIN_BACKOFF_AFTER failures and
+	// lock out after LOGIN_LOCKOUT_AFTER; the IP_ variants apply per client
+	// IP. Failures count within LOGIN_FAILURE_WINDOW_MINUTES.
+	LoginFailureWindowMinutes int `mapstructure:"LOGIN_FAILURE_WINDOW_MINUTES"`
+	LoginBackoffAfter         int `mapstructure:"LOGIN_BACKOFF_AFTER"`
+	LoginIPBackoffAfter       int `mapstructure:"LOGIN_IP_BACKOFF_AFTER"`
+	LoginBackoffBaseSeconds   int `mapstructure:"LOGIN_BACKOFF_BASE_SECONDS"`
+	LoginBackoffMaxSeconds    int `mapstructure:"LOGIN_BACKOFF_MAX_SECONDS"`
+	LoginLockoutAfter         int `mapstructure:"LOGIN_LOCKOUT_AFTER"`
+	LoginIPLockoutAfter       int `mapstructure:"LOGIN_IP_LOCKOUT_AFTER"`
+	LoginLockoutMinutes       int `mapstructure:"LOGIN_LOCKOUT_MINUTES"`
+
+	OIDCIssuer       string `mapstructure:"OIDC_ISSUER"`
+	OIDCClientID     string `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string `mapstructure:"OIDC_REDIRECT_URL"`
+
+	// RATE_LIMIT_MODE is redis (shared by all instances), memory or off.
+	// RATE_LIMIT_METHODS overrides the default limit per method, as
+	// "/messaging.MessagesService/SendMessage=5:20,...".
+	RateLimitMode         string  `mapstructure:"RATE_LIMIT_MODE"`
+	RateLimitDefaultRPS   float64 `mapstructure:"RATE_LIMIT_DEFAULT_RPS"`
+	RateLimitDefaultBurst int     `mapstructure:"RATE_LIMIT_DEFAULT_BURST"`
+	RateLimitMethods      string  `mapstructure:"RATE_LIMIT_METHODS"`
+
+	// Webhook deliveries are tried WEBHOOK_MAX_ATTEMPTS times, waiting from
+	// WEBHOOK_BACKOFF_BASE_SECONDS up to WEBHOOK_BACKOFF_MAX_MINUTES between
+	// attempts. The queue is polled every WEBHOOK_POLL_INTERVAL_SECONDS.
+	WebhookMaxAttempts         int `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoffBaseSeconds  int `mapstructure:"WEBHOOK_BACKOFF_BASE_SECONDS"`
+	WebhookBackoffMaxMinutes   int `mapstructure:"WEBHOOK_BACKOFF_MAX_MINUTES"`
+	WebhookTimeoutSeconds      int `mapstructure:"WEBHOOK_TIMEOUT_SECONDS"`
+	WebhookPollIntervalSeconds int `mapstructure:"WEBHOOK_POLL_INTERVAL_SECONDS"`
+
+	// Bots have COMMAND_TIMEOUT_SECONDS to answer a slash command.
+	CommandTimeoutSeconds int `mapstructure:"COMMAND_TIMEOUT_SECONDS"`
+
+	// IncomingWebhookBaseURL is the public URL of the HTTP server, which
+	// incoming webhook URLs start with.
+	IncomingWebhookBaseURL string `mapstructure:"INCOMING_WEBHOOK_BASE_URL"`
+
+	// Scheduled messages are looked for every SCHEDULER_POLL_INTERVAL_SECONDS.
+	// Messages found more than SCHEDULE_CATCH_UP_WINDOW_MINUTES late, after
+	// the schedulers were down, are sent late or skipped as SCHEDULE_CATCH_UP
+	// ("send" or "skip") says.
+	SchedulerPollIntervalSeconds int    `mapstructure:"SCHEDULER_POLL_INTERVAL_SECONDS"`
+	ScheduleCatchUp              string `mapstructure:"SCHEDULE_CATCH_UP"`
+	ScheduleCatchUpWindowMinutes int    `mapstructure:"SCHEDULE_CATCH_UP_WINDOW_MINUTES"`
 }
 
+// shutdownTimeout is how long the servers wait for running requests when
+// shutting down.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	if err := run(); err != nil {
 		slog.Error("Error running the application", "error", err)
@@ -61,7 +162,7 @@ func run() error {
 		Name:     cfg.PostgresReadDBName,
 	})
 	if err != nil {
-		slog.Error("could not create the read db client")
+		slog.Error("could not create the read db client", "error", err)
 		return err
 	}
 
@@ -73,7 +174,7 @@ func run() error {
 		Name:     cfg.PostgresWriteDBName,
 	})
 	if err != nil {
-		slog.Error("could not create the write db client")
+		slog.Error("could not create the write db client", "error", err)
 		return err
 	}
 
@@ -83,7 +184,7 @@ func run() error {
 		Password: cfg.RedisPassword,
 	})
 	if err != nil {
-		slog.Error("could not create the redis client")
+		slog.Error("could not create the redis client", "error", err)
 		return err
 	}
 
@@ -92,11 +193,191 @@ func run() error {
 		port = "50051"
 	}
 
+	httpPort := cfg.HTTPPort
+	if httpPort == "" {
+		httpPort = "8080"
+	}
+
+	keyDir := cfg.JWTKeyDir
+	if keyDir == "" {
+		keyDir = "keys"
+	}
+
+	mailFrom := cfg.MailFrom
+	if mailFrom == "" {
+		mailFrom = "no-reply@localhost"
+	}
+
+	// Without an SMTP server, emails are written to MAIL_DIR so they can be
+	// read during development.
+	var mail mailer.Mailer
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     mailFrom,
+		})
+	} else {
+		mailDir := cfg.MailDir
+		if mailDir == "" {
+			mailDir = "mail"
+		}
+		slog.Warn("SMTP_HOST is not set, writing emails to files", "dir", mailDir)
+		mail, err = mailer.NewFileMailer(mailDir, mailFrom)
+		if err != nil {
+			slog.Error("could not create the mail directory", "dir", mailDir, "error", err)
+			return err
+		}
+	}
+
+	passwordResetURL := cfg.PasswordResetURL
+	if passwordResetURL == "" {
+		passwordResetURL = "http://localhost:3000/reset-password"
+	}
+
+	emailVerificationURL := cfg.EmailVerificationURL
+	if emailVerificationURL == "" {
+		emailVerificationURL = "http://localhost:3000/verify-email"
+	}
+
+	verificationPolicy, err := services.ParseEmailVerificationPolicy(cfg.EmailVerificationRequiredFor)
+	if err != nil {
+		slog.Error("invalid EMAIL_VERIFICATION_REQUIRED_FOR", "error", err)
+		return err
+	}
+
+	var totpKey []byte
+	if cfg.TOTPEncryptionKey != "" {
+		totpKey, err = encryption.ParseKey(cfg.TOTPEncryptionKey)
+		if err != nil {
+			slog.Error("invalid TOTP_ENCRYPTION_KEY", "error", err)
+			return err
+		}
+	} else {
+		slog.Warn("TOTP_ENCRYPTION_KEY is not set, two-factor authentication is unavailable")
+	}
+
+	if cfg.OIDCIssuer == "" {
+		slog.Info("OIDC_ISSUER is not set, external login is disabled")
+	}
+
+	loginEmailThrottle, loginIPThrottle := loginThrottlePolicies(cfg)
+
+	schedulerCfg, err := schedulerConfig(cfg)
+	if err != nil {
+		slog.Error("Invalid scheduler config", "error", err)
+		return err
+	}
+
 	repos := repositories.NewRepositories(readerPool, writerPool)
 
-	svcs := services.NewServices(repos, cacheClient, cfg.IdempotencyTTL)
+	svcs, err := services.NewServices(repos, cacheClient, cfg.IdempotencyTTL, services.AuthConfig{
+		AccessTokenTTL:     time.Duration(cfg.AccessTokenTTLMinutes) * time.Minute,
+		RefreshTokenTTL:    time.Duration(cfg.RefreshTokenTTLHours) * time.Hour,
+		KeyDir:             keyDir,
+		SigningKeyID:       cfg.JWTSigningKeyID,
+		KeyRotationOverlap: time.Duration(cfg.JWTKeyRotationOverlapMinutes) * time.Minute,
+		Issuer:             cfg.JWTIssuer,
+		Audience:           cfg.JWTAudience,
+		Leeway:             time.Duration(cfg.JWTLeewaySeconds) * time.Second,
+		PasswordResetTTL:   time.Duration(cfg.PasswordResetTTLMinutes) * time.Minute,
+		PasswordResetURL:   passwordResetURL,
+
+		PasswordResetEmailLimit: cfg.PasswordResetEmailLimit,
+		PasswordResetIPLimit:    cfg.PasswordResetIPLimit,
+		PasswordResetWindow:     time.Duration(cfg.PasswordResetWindowMinutes) * time.Minute,
+
+		EmailVerificationTTL:     time.Duration(cfg.EmailVerificationTTLHours) * time.Hour,
+		EmailVerificationURL:     emailVerificationURL,
+		VerificationResendLimit:  cfg.VerificationResendLimit,
+		VerificationResendWindow: time.Duration(cfg.VerificationResendWindowMinutes) * time.Minute,
+		EmailVerification:        verificationPolicy,
+
+		TOTPEncryptionKey: totpKey,
+		TOTPIssuer:        cfg.TOTPIssuer,
+
+		LoginEmailThrottle: loginEmailThrottle,
+		LoginIPThrottle:    loginIPThrottle,
+
+		OIDC: oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Leeway:       time.Duration(cfg.JWTLeewaySeconds) * time.Second,
+		},
+	}, webhookConfig(cfg, httpPort), schedulerCfg, mail)
+	if err != nil {
+		slog.Error("could not create the services", "keyDir", keyDir, "error", err)
+		return err
+	}
+
+	// Picks up keys added to or removed from the key directory, so keys can
+	// be rotated without a restart.
+	keyReloadInterval := time.Duration(cfg.JWTKeyReloadSeconds) * time.Second
+	if keyReloadInterval <= 0 {
+		keyReloadInterval = time.Minute
+	}
+	go func() {
+		for range time.Tick(keyReloadInterval) {
+			if err := svcs.JWT.ReloadKeys(); err != nil {
+				slog.Warn("Could not reload the JWT keys, keeping the current ones", "keyDir", keyDir, "error", err)
+			}
+		}
+	}()
+
+	webhookPollInterval := time.Duration(cfg.WebhookPollIntervalSeconds) * time.Second
+	if webhookPollInterval <= 0 {
+		webhookPollInterval = time.Second
+	}
+	go deliverWebhooks(svcs.Webhooks, webhookPollInterval)
+
+	schedulerPollInterval := time.Duration(cfg.SchedulerPollIntervalSeconds) * time.Second
+	if schedulerPollInterval <= 0 {
+		schedulerPollInterval = time.Second
+	}
+	go deliverScheduled(svcs.Scheduled, schedulerPollInterval)
+
+	mux := http.NewServeMux()
+	mux.Handle(jwt.JWKSPath, jwt.JWKSHandler(svcs.JWT))
+	mux.Handle(services.IncomingWebhookPath, services.IncomingWebhookHandler(svcs.Incoming))
+
+	httpLis, err := net.Listen("tcp", ":"+httpPort)
+	if err != nil {
+		return err
+	}
+
+	// Incoming webhook bodies are small, so slow clients are cut off early.
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			slog.Error("Error shutting down the HTTP server", "error", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("HTTP server listening", "port", httpPort)
+		if err := httpServer.Serve(httpLis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server stopped", "error", err)
+		}
+	}()
+
+	jwtInterceptor := jwt.NewInterceptor(svcs.JWT, svcs.Revocations, svcs.Bots, grpcHandlers.Policies)
 
-	jwtInterceptor := jwt.NewInterceptor(svcs.JWT)
+	rateLimiter, err := newRateLimiter(cfg, cacheClient)
+	if err != nil {
+		slog.Error("Invalid rate limit config", "error", err)
+		return err
+	}
 
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
@@ -104,18 +385,51 @@ func run() error {
 	}
 
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(jwtInterceptor.UnaryInterceptor),
-		grpc.StreamInterceptor(jwtInterceptor.StreamInterceptor),
+		grpc.ChainUnaryInterceptor(jwtInterceptor.UnaryInterceptor, rateLimiter.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(jwtInterceptor.StreamInterceptor, rateLimiter.StreamInterceptor),
 	)
 
 	grpcServer := grpcHandlers.NewGRPCServer(
 		svcs.Messages,
 		svcs.Chats,
 		svcs.Users,
+		svcs.Moderation,
 		svcs.Realtime,
+		svcs.Bots,
+		svcs.Webhooks,
+		svcs.Incoming,
+		svcs.Commands,
+		svcs.Scheduled,
 	)
 	grpcServer.RegisterServices(server)
 
+	if err := grpcHandlers.Policies.Validate(server.GetServiceInfo()); err != nil {
+		slog.Error("Invalid auth policies", "error", err)
+		return err
+	}
+
+	// On SIGINT or SIGTERM, running calls get shutdownTimeout to finish;
+	// streams that are still open then are cut. The HTTP server is shut down
+	// once gRPC has stopped.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutting down")
+
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(shutdownTimeout):
+			server.Stop()
+		}
+	}()
+
 	slog.Info("gRPC server listening", "port", port)
 
 	if err := server.Serve(lis); err != nil {
@@ -124,3 +438,148 @@ func run() error {
 
 	return nil
 }
+
+// loginThrottlePolicies overrides the default login throttling with the
+// settings that are set.
+func loginThrottlePolicies(cfg Config) (redisconn.ThrottlePolicy, redisconn.ThrottlePolicy) {
+	email := services.DefaultLoginEmailThrottle
+	ip := services.DefaultLoginIPThrottle
+
+	for _, policy := range []*redisconn.ThrottlePolicy{&email, &ip} {
+		if cfg.LoginFailureWindowMinutes > 0 {
+			policy.Window = time.Duration(cfg.LoginFailureWindowMinutes) * time.Minute
+		}
+		if cfg.LoginBackoffBaseSeconds > 0 {
+			policy.BackoffBase = time.Duration(cfg.LoginBackoffBaseSeconds) * time.Second
+		}
+		if cfg.LoginBackoffMaxSeconds > 0 {
+			policy.BackoffMax = time.Duration(cfg.LoginBackoffMaxSeconds) * time.Second
+		}
+		if cfg.LoginLockoutMinutes > 0 {
+			policy.LockoutDuration = time.Duration(cfg.LoginLockoutMinutes) * time.Minute
+		}
+	}
+
+	if cfg.LoginBackoffAfter > 0 {
+		email.BackoffAfter = cfg.LoginBackoffAfter
+	}
+	if cfg.LoginLockoutAfter > 0 {
+		email.LockoutAfter = cfg.LoginLockoutAfter
+	}
+	if cfg.LoginIPBackoffAfter > 0 {
+		ip.BackoffAfter = cfg.LoginIPBackoffAfter
+	}
+	if cfg.LoginIPLockoutAfter > 0 {
+		ip.LockoutAfter = cfg.LoginIPLockoutAfter
+	}
+
+	return email, ip
+}
+
+// webhookConfig overrides the default webhook delivery settings with the
+// settings that are set.
+func webhookConfig(cfg Config, httpPort string) services.WebhookConfig {
+	webhooks := services.WebhookConfig{
+		MaxAttempts:     cfg.WebhookMaxAttempts,
+		BackoffBase:     time.Duration(cfg.WebhookBackoffBaseSeconds) * time.Second,
+		BackoffMax:      time.Duration(cfg.WebhookBackoffMaxMinutes) * time.Minute,
+		IncomingBaseURL: cfg.IncomingWebhookBaseURL,
+		CommandTimeout:  time.Duration(cfg.CommandTimeoutSeconds) * time.Second,
+	}
+
+	if webhooks.IncomingBaseURL == "" {
+		webhooks.IncomingBaseURL = "http://localhost:" + httpPort
+	}
+
+	if cfg.WebhookTimeoutSeconds > 0 {
+		timeout := time.Duration(cfg.WebhookTimeoutSeconds) * time.Second
+		webhooks.Sender = webhook.NewSender(webhook.NewClient(timeout), nil)
+		// A claimed delivery must stay reserved while its request runs.
+		webhooks.Lease = max(services.DefaultWebhookLease, 2*timeout)
+	}
+
+	return webhooks
+}
+
+// deliverWebhooks sends due webhook deliveries every interval, one batch
+// after the other until the queue has nothing due.
+func deliverWebhooks(webhooks services.WebhooksService, interval time.Duration) {
+	for range time.Tick(interval) {
+		for {
+			n, err := webhooks.DeliverDue(context.Background())
+			if err != nil {
+				slog.Error("Error delivering webhooks", "error", err)
+				break
+			}
+			if n == 0 {
+				break
+			}
+		}
+	}
+}
+
+// schedulerConfig reads the catch-up policy for scheduled messages.
+func schedulerConfig(cfg Config) (services.SchedulerConfig, error) {
+	scheduler := services.SchedulerConfig{
+		CatchUp:       services.CatchUpPolicy(cfg.ScheduleCatchUp),
+		CatchUpWindow: time.Duration(cfg.ScheduleCatchUpWindowMinutes) * time.Minute,
+	}
+
+	switch scheduler.CatchUp {
+	case "", services.CatchUpSend, services.CatchUpSkip:
+		return scheduler, nil
+	default:
+		return services.SchedulerConfig{}, fmt.Errorf("unknown schedule catch-up policy %q", cfg.ScheduleCatchUp)
+	}
+}
+
+// deliverScheduled sends due scheduled messages every interval, one batch
+// after another until none are left. Replicas can all run it; each message
+// is claimed by one of them.
+func deliverScheduled(scheduled services.ScheduledMessagesService, interval time.Duration) {
+	for range time.Tick(interval) {
+		for {
+			n, err := scheduled.DeliverDue(context.Background())
+			if err != nil {
+				slog.Error("Error delivering scheduled messages", "error", err)
+				break
+			}
+			if n == 0 {
+				break
+			}
+		}
+	}
+}
+
+// newRateLimiter builds the rate limit interceptor from the config. Without
+// settings every method gets 20 calls per second with a burst of 40, stored
+// in Redis.
+func newRateLimiter(cfg Config, cacheClient *redis.Client) (*ratelimit.Interceptor, error) {
+	limits := ratelimit.Config{
+		Default: ratelimit.Limit{Rate: 20, Burst: 40},
+	}
+	if cfg.RateLimitDefaultRPS > 0 {
+		limits.Default.Rate = cfg.RateLimitDefaultRPS
+		limits.Default.Burst = int(math.Ceil(cfg.RateLimitDefaultRPS))
+	}
+	if cfg.RateLimitDefaultBurst > 0 {
+		limits.Default.Burst = cfg.RateLimitDefaultBurst
+	}
+
+	methods, err := ratelimit.ParseMethodLimits(cfg.RateLimitMethods)
+	if err != nil {
+		return nil, err
+	}
+	limits.Methods = methods
+
+	switch cfg.RateLimitMode {
+	case "", "redis":
+		return ratelimit.NewInterceptor(ratelimit.NewRedisStore(cacheClient), limits), nil
+	case "memory":
+		return ratelimit.NewInterceptor(ratelimit.NewMemoryStore(nil), limits), nil
+	case "off":
+		return ratelimit.NewInterceptor(ratelimit.NewMemoryStore(nil), ratelimit.Config{}), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit mode %q", cfg.RateLimitMode)
+	}
+}
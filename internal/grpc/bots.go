@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type BotsGRPCServer struct {
+	pb.UnimplementedBotsServiceServer
+	botsService services.BotsService
+}
+
+func NewBotsGRPCServer(botsService services.BotsService) *BotsGRPCServer {
+	return &BotsGRPCServer{
+		botsService: botsService,
+	}
+}
+
+func (s *BotsGRPCServer) CreateBot(ctx context.Context, req *pb.CreateBotRequest) (*pb.CreateBotResponse, error) {
+	if req.Username == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+
+	if utf8.RuneCountInString(req.Username) > models.MaxUsernameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "username must be at most %d characters", models.MaxUsernameLength)
+	}
+
+	if utf8.RuneCountInString(req.DisplayName) > models.MaxDisplayNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "display_name must be at most %d characters", models.MaxDisplayNameLength)
+	}
+
+	bot, err := s.botsService.CreateBot(ctx, models.CreateBotRequest{
+		OwnerID:     caller(ctx).UserID,
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUsernameTaken):
+			return nil, status.Error(codes.AlreadyExists, "username is already taken")
+		case errors.Is(err, services.ErrLimitReached):
+			return nil, status.Errorf(codes.FailedPrecondition, "at most %d bots per user", models.MaxBotsPerOwner)
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "bots cannot create bots")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create bot: %v", err)
+	}
+
+	return &pb.CreateBotResponse{
+		Bot: toPBUser(bot),
+	}, nil
+}
+
+func (s *BotsGRPCServer) ListBots(ctx context.Context, req *pb.ListBotsRequest) (*pb.ListBotsResponse, error) {
+	bots, err := s.botsService.ListBots(ctx, caller(ctx).UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list bots: %v", err)
+	}
+
+	pbBots := make([]*pb.User, len(bots))
+	for i, bot := range bots {
+		pbBots[i] = toPBUser(bot)
+	}
+
+	return &pb.ListBotsResponse{
+		Bots: pbBots,
+	}, nil
+}
+
+func (s *BotsGRPCServer) AddBotToChat(ctx context.Context, req *pb.AddBotToChatRequest) (*pb.AddBotToChatResponse, error) {
+	if req.BotId == "" || req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "bot_id and chat_id are required")
+	}
+
+	err := s.botsService.AddBotToChat(ctx, caller(ctx).UserID, req.BotId, req.ChatId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "bot or chat not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only channel admins can add bots")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to add bot to chat: %v", err)
+	}
+
+	return &pb.AddBotToChatResponse{
+		ChatId: req.ChatId,
+	}, nil
+}
+
+func (s *BotsGRPCServer) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	if req.BotId == "" || len(req.Scopes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "bot_id and scopes are required")
+	}
+
+	if utf8.RuneCountInString(req.Name) > models.MaxAPIKeyNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", models.MaxAPIKeyNameLength)
+	}
+
+	if len(req.ChatIds) > models.MaxAPIKeyChats {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d chat_ids", models.MaxAPIKeyChats)
+	}
+
+	resp, err := s.botsService.CreateAPIKey(ctx, models.CreateAPIKeyRequest{
+		OwnerID: caller(ctx).UserID,
+		BotID:   req.BotId,
+		Name:    req.Name,
+		Scopes:  req.Scopes,
+		ChatIDs: req.ChatIds,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "bot not found")
+		case errors.Is(err, services.ErrInvalidScope):
+			return nil, status.Errorf(codes.InvalidArgument, "scopes must be among %v", models.APIKeyScopes)
+		case errors.Is(err, services.ErrLimitReached):
+			return nil, status.Errorf(codes.FailedPrecondition, "at most %d API keys per bot", models.MaxAPIKeysPerBot)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create API key: %v", err)
+	}
+
+	return &pb.CreateAPIKeyResponse{
+		Key:    resp.Key,
+		ApiKey: toPBAPIKey(resp.APIKey),
+	}, nil
+}
+
+func (s *BotsGRPCServer) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	if req.BotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "bot_id is required")
+	}
+
+	keys, err := s.botsService.ListAPIKeys(ctx, caller(ctx).UserID, req.BotId)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "bot not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list API keys: %v", err)
+	}
+
+	pbKeys := make([]*pb.APIKey, len(keys))
+	for i, key := range keys {
+		pbKeys[i] = toPBAPIKey(key)
+	}
+
+	return &pb.ListAPIKeysResponse{
+		ApiKeys: pbKeys,
+	}, nil
+}
+
+func (s *BotsGRPCServer) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.RevokeAPIKeyResponse, error) {
+	if req.BotId == "" || req.ApiKeyId == "" {
+		return nil, status.Error(codes.InvalidArgument, "bot_id and api_key_id are required")
+	}
+
+	err := s.botsService.RevokeAPIKey(ctx, caller(ctx).UserID, req.BotId, req.ApiKeyId)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "API key not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to revoke API key: %v", err)
+	}
+
+	return &pb.RevokeAPIKeyResponse{
+		ApiKeyId: req.ApiKeyId,
+	}, nil
+}
+
+func toPBAPIKey(key models.APIKey) *pb.APIKey {
+	pbKey := &pb.APIKey{
+		Id:        key.ID,
+		BotId:     key.BotID,
+		Name:      key.Name,
+		KeyPrefix: key.KeyPrefix,
+		Scopes:    key.Scopes,
+		ChatIds:   key.ChatIDs,
+		CreatedAt: timestamppb.New(key.CreatedAt),
+	}
+	if key.LastUsedAt != nil {
+		pbKey.LastUsedAt = timestamppb.New(*key.LastUsedAt)
+	}
+	return pbKey
+}
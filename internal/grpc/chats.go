@@ -2,10 +2,11 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"unicode/utf8"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
 	"github.com/brenocoelho/messaging-app-go/internal/services"
-	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
 	pb "github.com/brenocoelho/messaging-app-go/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -28,17 +29,24 @@ func (s *ChatsGRPCServer) CreateChat(ctx context.Context, req *pb.CreateChatRequ
 		return nil, status.Error(codes.InvalidArgument, "name and user_ids are required")
 	}
 
-	userID, _, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
+	if utf8.RuneCountInString(req.Name) > models.MaxChatNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", models.MaxChatNameLength)
 	}
 
+	userID := caller(ctx).UserID
+
 	resp, err := s.chatsService.CreateChat(ctx, models.CreateChatRequest{
 		UserID: userID,
 		Name:   req.Name,
 		Email:  req.Email,
 	})
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBlocked):
+			return nil, status.Error(codes.PermissionDenied, "cannot create a chat with this user")
+		case errors.Is(err, services.ErrEmailNotVerified):
+			return nil, status.Error(codes.FailedPrecondition, "verify your email to create chats")
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create chat: %v", err)
 	}
 
@@ -52,11 +60,12 @@ func (s *ChatsGRPCServer) GetChat(ctx context.Context, req *pb.GetChatRequest) (
 		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
 	}
 
-	userID, _, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
+	if err := chatAllowed(ctx, req.ChatId); err != nil {
+		return nil, err
 	}
 
+	userID := caller(ctx).UserID
+
 	chat, err := s.chatsService.GetChat(ctx, models.GetChatRequest{
 		ID:     req.ChatId,
 		UserID: userID,
@@ -69,31 +78,28 @@ func (s *ChatsGRPCServer) GetChat(ctx context.Context, req *pb.GetChatRequest) (
 		return nil, status.Error(codes.NotFound, "chat not found")
 	}
 
-	pbChat := &pb.Chat{
-		Id:          chat.ID,
-		Name:        chat.Name,
-		CreatedAt:   timestamppb.New(chat.CreatedAt),
-		Members:     []*pb.User{},
-		LastMessage: nil,
-	}
-
 	return &pb.GetChatResponse{
-		Chat: pbChat,
+		Chat: toPBChat(chat),
 	}, nil
 }
 
 func (s *ChatsGRPCServer) ListChats(ctx context.Context, req *pb.ListChatsRequest) (*pb.ListChatsResponse, error) {
-	userID, _, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
+	if req.ArchivedOnly && req.ExcludeArchived {
+		return nil, status.Error(codes.InvalidArgument, "archived_only and exclude_archived are mutually exclusive")
 	}
 
+	// API keys restricted to chats only see those.
+	claims := caller(ctx)
+
 	resp, err := s.chatsService.ListChats(ctx, models.ListChatsRequest{
-		UserID: userID,
+		UserID:  claims.UserID,
+		ChatIDs: claims.Chats,
 		Pagination: models.Pagination{
 			Page:  req.Page,
 			Limit: req.Limit,
 		},
+		ArchivedOnly:    req.ArchivedOnly,
+		ExcludeArchived: req.ExcludeArchived,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list chats: %v", err)
@@ -101,25 +107,7 @@ func (s *ChatsGRPCServer) ListChats(ctx context.Context, req *pb.ListChatsReques
 
 	chats := make([]*pb.Chat, len(resp.Chats))
 	for i, chat := range resp.Chats {
-		var lastMessage *pb.Message
-		if chat.LastMessage != nil {
-			lastMessage = &pb.Message{
-				Id:      chat.LastMessage.ID,
-				ChatId:  chat.LastMessage.ChatID,
-				UserId:  chat.LastMessage.UserID,
-				Content: chat.LastMessage.Body,
-				SentAt:  timestamppb.New(chat.LastMessage.CreatedAt),
-				Status:  string(chat.LastMessage.Status),
-			}
-		}
-
-		chats[i] = &pb.Chat{
-			Id:          chat.ID,
-			Name:        chat.Name,
-			CreatedAt:   timestamppb.New(chat.CreatedAt),
-			Members:     []*pb.User{},
-			LastMessage: lastMessage,
-		}
+		chats[i] = toPBChat(chat)
 	}
 
 	return &pb.ListChatsResponse{
@@ -127,3 +115,311 @@ func (s *ChatsGRPCServer) ListChats(ctx context.Context, req *pb.ListChatsReques
 		Total: resp.Total,
 	}, nil
 }
+
+func (s *ChatsGRPCServer) UpdateChatSettings(ctx context.Context, req *pb.UpdateChatSettingsRequest) (*pb.UpdateChatSettingsResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	if req.Unmute && req.MutedUntil != nil {
+		return nil, status.Error(codes.InvalidArgument, "muted_until and unmute are mutually exclusive")
+	}
+
+	userID := caller(ctx).UserID
+
+	update := models.UpdateChatSettingsRequest{
+		UserID:   userID,
+		ChatID:   req.ChatId,
+		Unmute:   req.Unmute,
+		Archived: req.Archived,
+		Pinned:   req.Pinned,
+		PinOrder: req.PinOrder,
+	}
+
+	if req.MutedUntil != nil {
+		mutedUntil := req.MutedUntil.AsTime()
+		update.MutedUntil = &mutedUntil
+	}
+
+	if req.NotificationLevel != nil {
+		level := models.NotificationLevel(*req.NotificationLevel)
+		switch level {
+		case models.NotificationLevelAll, models.NotificationLevelMentions, models.NotificationLevelNone:
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid notification_level: %s", level)
+		}
+		update.NotificationLevel = &level
+	}
+
+	settings, err := s.chatsService.UpdateChatSettings(ctx, update)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update chat settings: %v", err)
+	}
+
+	if settings.ChatID == "" {
+		return nil, status.Error(codes.NotFound, "chat not found")
+	}
+
+	return &pb.UpdateChatSettingsResponse{
+		Settings: toPBChatSettings(settings),
+	}, nil
+}
+
+func (s *ChatsGRPCServer) UpdateChat(ctx context.Context, req *pb.UpdateChatRequest) (*pb.UpdateChatResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	if req.Name == nil && req.Description == nil && req.AvatarAttachmentId == nil {
+		return nil, status.Error(codes.InvalidArgument, "at least one of name, description or avatar_attachment_id is required")
+	}
+
+	if req.Name != nil && *req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name cannot be empty")
+	}
+
+	if req.Name != nil && utf8.RuneCountInString(*req.Name) > models.MaxChatNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", models.MaxChatNameLength)
+	}
+
+	if req.Description != nil && utf8.RuneCountInString(*req.Description) > models.MaxChatDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d characters", models.MaxChatDescriptionLength)
+	}
+
+	if req.AvatarAttachmentId != nil && utf8.RuneCountInString(*req.AvatarAttachmentId) > models.MaxAttachmentIDLength {
+		return nil, status.Errorf(codes.InvalidArgument, "avatar_attachment_id must be at most %d characters", models.MaxAttachmentIDLength)
+	}
+
+	claims := caller(ctx)
+	userID, username := claims.UserID, claims.Username
+
+	chat, err := s.chatsService.UpdateChat(ctx, models.UpdateChatRequest{
+		UserID:             userID,
+		Username:           username,
+		ChatID:             req.ChatId,
+		Name:               req.Name,
+		Description:        req.Description,
+		AvatarAttachmentID: req.AvatarAttachmentId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can update the chat")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update chat: %v", err)
+	}
+
+	return &pb.UpdateChatResponse{
+		Chat: toPBChat(chat),
+	}, nil
+}
+
+func (s *ChatsGRPCServer) CreateChannel(ctx context.Context, req *pb.CreateChannelRequest) (*pb.CreateChannelResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if utf8.RuneCountInString(req.Name) > models.MaxChatNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", models.MaxChatNameLength)
+	}
+
+	if utf8.RuneCountInString(req.Description) > models.MaxChatDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d characters", models.MaxChatDescriptionLength)
+	}
+
+	visibility := models.ChatVisibility(req.Visibility)
+	switch visibility {
+	case "":
+		visibility = models.ChatVisibilityPublic
+	case models.ChatVisibilityPublic, models.ChatVisibilityPrivate:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid visibility: %s", req.Visibility)
+	}
+
+	userID := caller(ctx).UserID
+
+	resp, err := s.chatsService.CreateChannel(ctx, models.CreateChannelRequest{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Visibility:  visibility,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			return nil, status.Error(codes.FailedPrecondition, "verify your email to create channels")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create channel: %v", err)
+	}
+
+	return &pb.CreateChannelResponse{
+		ChatId: resp.ChatId,
+	}, nil
+}
+
+func (s *ChatsGRPCServer) JoinChannel(ctx context.Context, req *pb.JoinChannelRequest) (*pb.JoinChannelResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	err := s.chatsService.JoinChannel(ctx, models.JoinChannelRequest{
+		UserID: userID,
+		ChatID: req.ChatId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "channel not found")
+		case errors.Is(err, services.ErrEmailNotVerified):
+			return nil, status.Error(codes.FailedPrecondition, "verify your email to join channels")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to join channel: %v", err)
+	}
+
+	return &pb.JoinChannelResponse{
+		ChatId: req.ChatId,
+	}, nil
+}
+
+func (s *ChatsGRPCServer) LeaveChannel(ctx context.Context, req *pb.LeaveChannelRequest) (*pb.LeaveChannelResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	err := s.chatsService.LeaveChannel(ctx, models.LeaveChannelRequest{
+		UserID: userID,
+		ChatID: req.ChatId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "channel not found")
+		case errors.Is(err, services.ErrLastAdmin):
+			return nil, status.Error(codes.FailedPrecondition, "the last admin cannot leave the channel")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to leave channel: %v", err)
+	}
+
+	return &pb.LeaveChannelResponse{
+		ChatId: req.ChatId,
+	}, nil
+}
+
+func (s *ChatsGRPCServer) AddChannelMember(ctx context.Context, req *pb.AddChannelMemberRequest) (*pb.AddChannelMemberResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	err := s.chatsService.AddChannelMember(ctx, models.AddChannelMemberRequest{
+		UserID:   userID,
+		ChatID:   req.ChatId,
+		MemberID: req.UserId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "channel or user not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only channel admins can add members")
+		case errors.Is(err, services.ErrBlocked):
+			return nil, status.Error(codes.PermissionDenied, "cannot add this user to the channel")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to add channel member: %v", err)
+	}
+
+	return &pb.AddChannelMemberResponse{
+		ChatId: req.ChatId,
+		UserId: req.UserId,
+	}, nil
+}
+
+func (s *ChatsGRPCServer) DiscoverChannels(ctx context.Context, req *pb.DiscoverChannelsRequest) (*pb.DiscoverChannelsResponse, error) {
+	userID := caller(ctx).UserID
+
+	resp, err := s.chatsService.DiscoverChannels(ctx, models.DiscoverChannelsRequest{
+		UserID: userID,
+		Query:  req.Query,
+		Pagination: models.Pagination{
+			Page:  req.Page,
+			Limit: req.Limit,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to discover channels: %v", err)
+	}
+
+	channels := make([]*pb.Chat, len(resp.Channels))
+	for i, channel := range resp.Channels {
+		channels[i] = toPBChat(channel)
+	}
+
+	return &pb.DiscoverChannelsResponse{
+		Channels: channels,
+		Total:    resp.Total,
+	}, nil
+}
+
+func toPBChat(chat models.ChatWithLastMessage) *pb.Chat {
+	members := make([]*pb.User, len(chat.Members))
+	for i, member := range chat.Members {
+		members[i] = toPBUser(member)
+	}
+
+	var lastMessage *pb.Message
+	if chat.LastMessage != nil {
+		lastMessage = &pb.Message{
+			Id:      chat.LastMessage.ID,
+			ChatId:  chat.LastMessage.ChatID,
+			UserId:  chat.LastMessage.UserID,
+			Content: chat.LastMessage.Body,
+			SentAt:  timestamppb.New(chat.LastMessage.CreatedAt),
+			Status:  string(chat.LastMessage.Status),
+			Kind:    string(chat.LastMessage.Kind),
+		}
+	}
+
+	return &pb.Chat{
+		Id:                 chat.ID,
+		Name:               chat.Name,
+		Kind:               string(chat.Kind),
+		Visibility:         string(chat.Visibility),
+		Description:        chat.Description,
+		AvatarAttachmentId: chat.AvatarAttachmentID,
+		CreatedAt:          timestamppb.New(chat.CreatedAt),
+		Members:            members,
+		LastMessage:        lastMessage,
+		Settings:           toPBChatSettings(chat.Settings),
+		LastActivityAt:     timestamppb.New(chat.LastActivityAt),
+		UnreadCount:        int32(chat.UnreadCount),
+		ParticipantCount:   int32(chat.ParticipantCount),
+	}
+}
+
+func toPBChatSettings(settings models.ChatSettings) *pb.ChatSettings {
+	pbSettings := &pb.ChatSettings{
+		Archived:          settings.Archived,
+		Pinned:            settings.PinOrder != nil,
+		NotificationLevel: string(settings.NotificationLevel),
+	}
+
+	if settings.MutedUntil != nil {
+		pbSettings.MutedUntil = timestamppb.New(*settings.MutedUntil)
+	}
+
+	if settings.PinOrder != nil {
+		pbSettings.PinOrder = *settings.PinOrder
+	}
+
+	return pbSettings
+}
@@ -0,0 +1,138 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type CommandsGRPCServer struct {
+	pb.UnimplementedCommandsServiceServer
+	commandsService services.CommandsService
+}
+
+func NewCommandsGRPCServer(commandsService services.CommandsService) *CommandsGRPCServer {
+	return &CommandsGRPCServer{
+		commandsService: commandsService,
+	}
+}
+
+func (s *CommandsGRPCServer) RegisterCommand(ctx context.Context, req *pb.RegisterCommandRequest) (*pb.RegisterCommandResponse, error) {
+	if req.BotId == "" || req.Name == "" || req.Url == "" {
+		return nil, status.Error(codes.InvalidArgument, "bot_id, name and url are required")
+	}
+
+	if utf8.RuneCountInString(req.Description) > models.MaxCommandDescriptionLength {
+		return nil, status.Errorf(codes.InvalidArgument, "description must be at most %d characters", models.MaxCommandDescriptionLength)
+	}
+
+	if utf8.RuneCountInString(req.Usage) > models.MaxCommandUsageLength {
+		return nil, status.Errorf(codes.InvalidArgument, "usage must be at most %d characters", models.MaxCommandUsageLength)
+	}
+
+	if len(req.Url) > models.MaxWebhookURLLength {
+		return nil, status.Errorf(codes.InvalidArgument, "url must be at most %d characters", models.MaxWebhookURLLength)
+	}
+
+	resp, err := s.commandsService.RegisterCommand(ctx, models.RegisterCommandRequest{
+		OwnerID:     caller(ctx).UserID,
+		BotID:       req.BotId,
+		Name:        req.Name,
+		Description: req.Description,
+		Usage:       req.Usage,
+		URL:         req.Url,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "bot not found")
+		case errors.Is(err, services.ErrInvalidCommand):
+			return nil, status.Errorf(codes.InvalidArgument, "name must match %s", models.CommandNamePattern)
+		case errors.Is(err, services.ErrInvalidWebhookURL):
+			return nil, status.Error(codes.InvalidArgument, "url must be an http or https URL")
+		case errors.Is(err, services.ErrCommandTaken):
+			return nil, status.Error(codes.AlreadyExists, "the command is already registered")
+		case errors.Is(err, services.ErrLimitReached):
+			return nil, status.Errorf(codes.FailedPrecondition, "at most %d commands per bot", models.MaxCommandsPerBot)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to register command: %v", err)
+	}
+
+	return &pb.RegisterCommandResponse{
+		Command: toPBCommand(resp.Command),
+		Secret:  resp.Secret,
+	}, nil
+}
+
+func (s *CommandsGRPCServer) ListCommands(ctx context.Context, req *pb.ListCommandsRequest) (*pb.ListCommandsResponse, error) {
+	if (req.ChatId == "") == (req.BotId == "") {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of chat_id and bot_id is required")
+	}
+
+	if req.ChatId != "" {
+		if err := chatAllowed(ctx, req.ChatId); err != nil {
+			return nil, err
+		}
+	}
+
+	commands, err := s.commandsService.ListCommands(ctx, models.ListCommandsRequest{
+		UserID: caller(ctx).UserID,
+		ChatID: req.ChatId,
+		BotID:  req.BotId,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "chat or bot not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list commands: %v", err)
+	}
+
+	pbCommands := make([]*pb.Command, len(commands))
+	for i, command := range commands {
+		pbCommands[i] = toPBCommand(command)
+	}
+
+	return &pb.ListCommandsResponse{
+		Commands: pbCommands,
+	}, nil
+}
+
+func (s *CommandsGRPCServer) DeleteCommand(ctx context.Context, req *pb.DeleteCommandRequest) (*pb.DeleteCommandResponse, error) {
+	if req.BotId == "" || req.CommandId == "" {
+		return nil, status.Error(codes.InvalidArgument, "bot_id and command_id are required")
+	}
+
+	err := s.commandsService.DeleteCommand(ctx, caller(ctx).UserID, req.BotId, req.CommandId)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "command not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete command: %v", err)
+	}
+
+	return &pb.DeleteCommandResponse{
+		CommandId: req.CommandId,
+	}, nil
+}
+
+func toPBCommand(command models.Command) *pb.Command {
+	pbCommand := &pb.Command{
+		Id:          command.ID,
+		BotId:       command.BotID,
+		Name:        command.Name,
+		Description: command.Description,
+		Usage:       command.Usage,
+		Url:         command.URL,
+	}
+	if !command.CreatedAt.IsZero() {
+		pbCommand.CreatedAt = timestamppb.New(command.CreatedAt)
+	}
+	return pbCommand
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Upper bounds for what clients pass through from the identity provider.
+const (
+	maxIDTokenLength  = 16 << 10
+	maxOIDCCodeLength = 2048
+)
+
+func (s *UsersGRPCServer) LoginWithIDToken(ctx context.Context, req *pb.LoginWithIDTokenRequest) (*pb.LoginResponse, error) {
+	if req.IdToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "id_token is required")
+	}
+
+	if len(req.IdToken) > maxIDTokenLength {
+		return nil, status.Error(codes.InvalidArgument, "id_token is too long")
+	}
+
+	if utf8.RuneCountInString(req.DeviceName) > models.MaxDeviceNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "device_name must be at most %d characters", models.MaxDeviceNameLength)
+	}
+
+	loginResp, err := s.usersService.LoginWithIDToken(ctx, models.LoginWithIDTokenRequest{
+		IDToken: req.IdToken,
+		Nonce:   req.Nonce,
+		Device:  deviceFromContext(ctx, req.DeviceName),
+	})
+	if err != nil {
+		return nil, externalLoginError(err)
+	}
+
+	return s.toPBLoginResponse(ctx, loginResp)
+}
+
+func (s *UsersGRPCServer) ExchangeOIDCCode(ctx context.Context, req *pb.ExchangeOIDCCodeRequest) (*pb.LoginResponse, error) {
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if len(req.Code) > maxOIDCCodeLength || len(req.CodeVerifier) > maxOIDCCodeLength || len(req.RedirectUri) > maxOIDCCodeLength {
+		return nil, status.Error(codes.InvalidArgument, "code, code_verifier or redirect_uri is too long")
+	}
+
+	if utf8.RuneCountInString(req.DeviceName) > models.MaxDeviceNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "device_name must be at most %d characters", models.MaxDeviceNameLength)
+	}
+
+	loginResp, err := s.usersService.ExchangeOIDCCode(ctx, models.ExchangeOIDCCodeRequest{
+		Code:         req.Code,
+		CodeVerifier: req.CodeVerifier,
+		RedirectURI:  req.RedirectUri,
+		Device:       deviceFromContext(ctx, req.DeviceName),
+	})
+	if err != nil {
+		return nil, externalLoginError(err)
+	}
+
+	return s.toPBLoginResponse(ctx, loginResp)
+}
+
+func externalLoginError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrExternalLoginUnavailable):
+		return status.Error(codes.Unavailable, "external login is not available")
+	case errors.Is(err, services.ErrInvalidIDToken):
+		return status.Error(codes.Unauthenticated, "invalid ID token")
+	case errors.Is(err, services.ErrExchangeFailed):
+		return status.Error(codes.Unauthenticated, "authorization code was rejected")
+	case errors.Is(err, services.ErrExternalEmailNotVerified):
+		return status.Error(codes.PermissionDenied, "the identity provider has not verified your email")
+	case errors.Is(err, services.ErrLocalEmailNotVerified):
+		return status.Error(codes.FailedPrecondition, "an account with this email exists; verify its email or log in with its password first")
+	case errors.Is(err, services.ErrIdentityTaken):
+		return status.Error(codes.AlreadyExists, "this account is linked to another user")
+	case errors.Is(err, services.ErrEmailTaken):
+		return status.Error(codes.AlreadyExists, "email is already registered")
+	}
+	return status.Errorf(codes.Internal, "failed to log in: %v", err)
+}
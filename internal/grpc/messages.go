@@ -2,12 +2,12 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
 	"github.com/brenocoelho/messaging-app-go/internal/services"
-	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
 	pb "github.com/brenocoelho/messaging-app-go/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -16,14 +16,16 @@ import (
 
 type MessagesGRPCServer struct {
 	pb.UnimplementedMessagesServiceServer
-	messagesService services.MessagesService
-	realtimeService services.RealtimeService
+	messagesService  services.MessagesService
+	realtimeService  services.RealtimeService
+	scheduledService services.ScheduledMessagesService
 }
 
-func NewMessagesGRPCServer(messagesService services.MessagesService, realtimeService services.RealtimeService) *MessagesGRPCServer {
+func NewMessagesGRPCServer(messagesService services.MessagesService, realtimeService services.RealtimeService, scheduledService services.ScheduledMessagesService) *MessagesGRPCServer {
 	return &MessagesGRPCServer{
-		messagesService: messagesService,
-		realtimeService: realtimeService,
+		messagesService:  messagesService,
+		realtimeService:  realtimeService,
+		scheduledService: scheduledService,
 	}
 }
 
@@ -32,11 +34,12 @@ func (s *MessagesGRPCServer) SendMessage(ctx context.Context, req *pb.SendMessag
 		return nil, status.Error(codes.InvalidArgument, "chat_id, content, and idempotency_key are required")
 	}
 
-	userID, _, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
+	if err := chatAllowed(ctx, req.ChatId); err != nil {
+		return nil, err
 	}
 
+	userID := caller(ctx).UserID
+
 	resp, err := s.messagesService.SendMessage(ctx, models.SendMessageRequest{
 		UserID:         userID,
 		ChatID:         req.ChatId,
@@ -44,9 +47,23 @@ func (s *MessagesGRPCServer) SendMessage(ctx context.Context, req *pb.SendMessag
 		IdempotencyKey: req.IdempotencyKey,
 	})
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only channel admins can post")
+		case errors.Is(err, services.ErrBlocked):
+			return nil, status.Error(codes.PermissionDenied, "cannot send messages to this chat")
+		case errors.Is(err, services.ErrDuplicateMessage):
+			return nil, status.Error(codes.AlreadyExists, "message with idempotency key already exists")
+		}
 		return nil, status.Errorf(codes.Internal, "failed to send message: %v", err)
 	}
 
+	if resp.Reply != nil {
+		return toPBCommandResponse(req.ChatId, resp), nil
+	}
+
 	message := &pb.Message{
 		Id:      resp.MessageID,
 		ChatId:  req.ChatId,
@@ -54,6 +71,7 @@ func (s *MessagesGRPCServer) SendMessage(ctx context.Context, req *pb.SendMessag
 		Content: req.Content,
 		SentAt:  timestamppb.Now(),
 		Status:  "SENT",
+		Kind:    string(models.MessageKindUser),
 	}
 
 	return &pb.SendMessageResponse{
@@ -61,16 +79,43 @@ func (s *MessagesGRPCServer) SendMessage(ctx context.Context, req *pb.SendMessag
 	}, nil
 }
 
+// toPBCommandResponse answers a message that ran a slash command with the
+// reply, and the message it was posted as if it was public.
+func toPBCommandResponse(chatID string, resp models.SendMessageResponse) *pb.SendMessageResponse {
+	pbResp := &pb.SendMessageResponse{
+		CommandReply: &pb.CommandReply{
+			Text:       resp.Reply.Text,
+			Visibility: string(resp.Reply.Visibility),
+			SenderId:   resp.Reply.SenderID,
+		},
+	}
+
+	if resp.MessageID != "" {
+		pbResp.Message = &pb.Message{
+			Id:      resp.MessageID,
+			ChatId:  chatID,
+			UserId:  resp.Reply.SenderID,
+			Content: resp.Reply.Text,
+			SentAt:  timestamppb.Now(),
+			Status:  "SENT",
+			Kind:    string(models.MessageKindUser),
+		}
+	}
+
+	return pbResp
+}
+
 func (s *MessagesGRPCServer) ListMessages(ctx context.Context, req *pb.ListMessagesRequest) (*pb.ListMessagesResponse, error) {
 	if req.ChatId == "" {
 		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
 	}
 
-	userID, _, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
+	if err := chatAllowed(ctx, req.ChatId); err != nil {
+		return nil, err
 	}
 
+	userID := caller(ctx).UserID
+
 	resp, err := s.messagesService.ListMessages(ctx, models.ListMessagesRequest{
 		UserID: userID,
 		ChatID: req.ChatId,
@@ -92,6 +137,7 @@ func (s *MessagesGRPCServer) ListMessages(ctx context.Context, req *pb.ListMessa
 			Content: msg.Body,
 			SentAt:  timestamppb.New(msg.CreatedAt),
 			Status:  string(msg.Status),
+			Kind:    string(msg.Kind),
 		}
 		messages = append(messages, message)
 	}
@@ -107,10 +153,7 @@ func (s *MessagesGRPCServer) UpdateMessageStatus(ctx context.Context, req *pb.Up
 		return nil, status.Error(codes.InvalidArgument, "message_id and status are required")
 	}
 
-	userID, _, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
-	}
+	userID := caller(ctx).UserID
 
 	resp, err := s.messagesService.UpdateMessageStatus(ctx, models.UpdateMessageStatusRequest{
 		UserID:    userID,
@@ -127,22 +170,150 @@ func (s *MessagesGRPCServer) UpdateMessageStatus(ctx context.Context, req *pb.Up
 	}, nil
 }
 
+func (s *MessagesGRPCServer) ScheduleMessage(ctx context.Context, req *pb.ScheduleMessageRequest) (*pb.ScheduleMessageResponse, error) {
+	if req.ChatId == "" || req.Content == "" || req.SendAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "chat_id, content, and send_at are required")
+	}
+
+	if err := req.SendAt.CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid send_at: %v", err)
+	}
+
+	if err := chatAllowed(ctx, req.ChatId); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := s.scheduledService.ScheduleMessage(ctx, models.ScheduleMessageRequest{
+		UserID:  caller(ctx).UserID,
+		ChatID:  req.ChatId,
+		Content: req.Content,
+		SendAt:  req.SendAt.AsTime(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidScheduleTime):
+			return nil, status.Errorf(codes.InvalidArgument, "send_at must be in the future and at most %s ahead", models.MaxScheduleAhead)
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only channel admins can post")
+		case errors.Is(err, services.ErrLimitReached):
+			return nil, status.Errorf(codes.FailedPrecondition, "at most %d scheduled messages per user", models.MaxScheduledPerUser)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to schedule message: %v", err)
+	}
+
+	return &pb.ScheduleMessageResponse{
+		ScheduledMessage: toPBScheduledMessage(scheduled),
+	}, nil
+}
+
+func (s *MessagesGRPCServer) ListScheduled(ctx context.Context, req *pb.ListScheduledRequest) (*pb.ListScheduledResponse, error) {
+	scheduledStatus := models.ScheduledMessageStatus(req.Status)
+	switch scheduledStatus {
+	case "", models.ScheduledMessagePending, models.ScheduledMessageSent, models.ScheduledMessageCanceled,
+		models.ScheduledMessageSkipped, models.ScheduledMessageFailed:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be PENDING, SENT, CANCELED, SKIPPED or FAILED")
+	}
+
+	if req.ChatId != "" {
+		if err := chatAllowed(ctx, req.ChatId); err != nil {
+			return nil, err
+		}
+	}
+
+	claims := caller(ctx)
+	resp, err := s.scheduledService.ListScheduled(ctx, models.ListScheduledRequest{
+		UserID:  claims.UserID,
+		ChatID:  req.ChatId,
+		ChatIDs: claims.Chats,
+		Status:  scheduledStatus,
+		Pagination: models.Pagination{
+			Page:  req.Page,
+			Limit: req.Limit,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list scheduled messages: %v", err)
+	}
+
+	messages := make([]*pb.ScheduledMessage, len(resp.Messages))
+	for i, scheduled := range resp.Messages {
+		messages[i] = toPBScheduledMessage(scheduled)
+	}
+
+	return &pb.ListScheduledResponse{
+		ScheduledMessages: messages,
+		Total:             resp.Total,
+	}, nil
+}
+
+func (s *MessagesGRPCServer) CancelScheduled(ctx context.Context, req *pb.CancelScheduledRequest) (*pb.CancelScheduledResponse, error) {
+	if req.ScheduledMessageId == "" {
+		return nil, status.Error(codes.InvalidArgument, "scheduled_message_id is required")
+	}
+
+	claims := caller(ctx)
+	err := s.scheduledService.CancelScheduled(ctx, models.CancelScheduledRequest{
+		UserID:      claims.UserID,
+		ScheduledID: req.ScheduledMessageId,
+		ChatIDs:     claims.Chats,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "no pending scheduled message with this id")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to cancel scheduled message: %v", err)
+	}
+
+	return &pb.CancelScheduledResponse{
+		ScheduledMessageId: req.ScheduledMessageId,
+	}, nil
+}
+
+func toPBScheduledMessage(scheduled models.ScheduledMessage) *pb.ScheduledMessage {
+	pbScheduled := &pb.ScheduledMessage{
+		Id:        scheduled.ID,
+		ChatId:    scheduled.ChatID,
+		Content:   scheduled.Content,
+		SendAt:    timestamppb.New(scheduled.SendAt),
+		Status:    string(scheduled.Status),
+		Attempts:  scheduled.Attempts,
+		LastError: scheduled.LastError,
+		CreatedAt: timestamppb.New(scheduled.CreatedAt),
+	}
+	if scheduled.MessageID != nil {
+		pbScheduled.MessageId = *scheduled.MessageID
+	}
+	if scheduled.SentAt != nil {
+		pbScheduled.SentAt = timestamppb.New(*scheduled.SentAt)
+	}
+	return pbScheduled
+}
+
 func (s *MessagesGRPCServer) SubscribeToChat(req *pb.SubscribeToChatRequest, stream pb.MessagesService_SubscribeToChatServer) error {
-	ctx := stream.Context()
+	ctx, release := trackSession(stream.Context(), s.realtimeService)
+	defer release()
 
 	if req.ChatId == "" {
 		return status.Error(codes.InvalidArgument, "chat_id is required")
 	}
 
-	userID, username, _, err := jwt.GetUserFromContext(ctx)
-	if err != nil {
-		return status.Errorf(codes.Unauthenticated, "failed to get user from context: %v", err)
+	if err := chatAllowed(ctx, req.ChatId); err != nil {
+		return err
 	}
 
+	claims := caller(ctx)
+	userID, username := claims.UserID, claims.Username
+
 	slog.Info("User subscribing to chat", "userID", userID, "username", username, "chatID", req.ChatId)
 
-	msgChan, err := s.realtimeService.SubscribeToChat(ctx, req.ChatId, userID)
+	msgChan, err := s.messagesService.SubscribeToChat(ctx, req.ChatId, userID)
 	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return status.Error(codes.NotFound, "chat not found")
+		}
 		return status.Errorf(codes.Internal, "failed to subscribe to chat: %v", err)
 	}
 
@@ -166,7 +337,7 @@ func (s *MessagesGRPCServer) SubscribeToChat(req *pb.SubscribeToChatRequest, str
 		case msg := <-msgChan:
 			if msg == nil {
 				slog.Info("User unsubscribed from chat", "userID", userID, "chatID", req.ChatId)
-				return nil
+				return streamEndError(ctx)
 			}
 
 			pbMsg := &pb.ChatMessage{
@@ -177,6 +348,7 @@ func (s *MessagesGRPCServer) SubscribeToChat(req *pb.SubscribeToChatRequest, str
 				Content:   msg.Content,
 				SentAt:    timestamppb.New(msg.SentAt),
 				Status:    msg.Status,
+				Type:      pb.MessageType(msg.Type),
 			}
 
 			if err := stream.Send(pbMsg); err != nil {
@@ -190,7 +362,7 @@ func (s *MessagesGRPCServer) SubscribeToChat(req *pb.SubscribeToChatRequest, str
 
 		case <-ctx.Done():
 			slog.Info("Context cancelled, user unsubscribing", "userID", userID, "chatID", req.ChatId)
-			return nil
+			return streamEndError(ctx)
 		}
 	}
 }
@@ -0,0 +1,238 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ModerationGRPCServer struct {
+	pb.UnimplementedModerationServiceServer
+	moderationService services.ModerationService
+}
+
+func NewModerationGRPCServer(moderationService services.ModerationService) *ModerationGRPCServer {
+	return &ModerationGRPCServer{
+		moderationService: moderationService,
+	}
+}
+
+func (s *ModerationGRPCServer) BlockUser(ctx context.Context, req *pb.BlockUserRequest) (*pb.BlockUserResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	if req.UserId == userID {
+		return nil, status.Error(codes.InvalidArgument, "cannot block yourself")
+	}
+
+	if err := s.moderationService.BlockUser(ctx, userID, req.UserId); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to block user: %v", err)
+	}
+
+	return &pb.BlockUserResponse{
+		UserId: req.UserId,
+	}, nil
+}
+
+func (s *ModerationGRPCServer) UnblockUser(ctx context.Context, req *pb.UnblockUserRequest) (*pb.UnblockUserResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	if err := s.moderationService.UnblockUser(ctx, userID, req.UserId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unblock user: %v", err)
+	}
+
+	return &pb.UnblockUserResponse{
+		UserId: req.UserId,
+	}, nil
+}
+
+func (s *ModerationGRPCServer) ListBlocked(ctx context.Context, req *pb.ListBlockedRequest) (*pb.ListBlockedResponse, error) {
+	userID := caller(ctx).UserID
+
+	resp, err := s.moderationService.ListBlocked(ctx, models.ListBlockedRequest{
+		UserID: userID,
+		Pagination: models.Pagination{
+			Page:  req.Page,
+			Limit: req.Limit,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list blocked users: %v", err)
+	}
+
+	users := make([]*pb.BlockedUser, len(resp.Users))
+	for i, user := range resp.Users {
+		users[i] = &pb.BlockedUser{
+			User:      toPBUser(user.User),
+			BlockedAt: timestamppb.New(user.BlockedAt),
+		}
+	}
+
+	return &pb.ListBlockedResponse{
+		Users: users,
+		Total: resp.Total,
+	}, nil
+}
+
+func (s *ModerationGRPCServer) ReportUser(ctx context.Context, req *pb.ReportUserRequest) (*pb.ReportUserResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	reason, err := validateReport(req.Reason, req.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := caller(ctx).UserID
+
+	if req.UserId == userID {
+		return nil, status.Error(codes.InvalidArgument, "cannot report yourself")
+	}
+
+	reportID, err := s.moderationService.ReportUser(ctx, models.ReportUserRequest{
+		ReporterID: userID,
+		UserID:     req.UserId,
+		Reason:     reason,
+		Details:    req.Details,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to report user: %v", err)
+	}
+
+	return &pb.ReportUserResponse{
+		ReportId: reportID,
+	}, nil
+}
+
+func (s *ModerationGRPCServer) ReportMessage(ctx context.Context, req *pb.ReportMessageRequest) (*pb.ReportMessageResponse, error) {
+	if req.MessageId == "" {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+
+	reason, err := validateReport(req.Reason, req.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := caller(ctx).UserID
+
+	reportID, err := s.moderationService.ReportMessage(ctx, models.ReportMessageRequest{
+		ReporterID: userID,
+		MessageID:  req.MessageId,
+		Reason:     reason,
+		Details:    req.Details,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "message not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to report message: %v", err)
+	}
+
+	return &pb.ReportMessageResponse{
+		ReportId: reportID,
+	}, nil
+}
+
+func (s *ModerationGRPCServer) ListReports(ctx context.Context, req *pb.ListReportsRequest) (*pb.ListReportsResponse, error) {
+	switch models.ReportStatus(req.Status) {
+	case "", models.ReportStatusOpen, models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be one of OPEN, RESOLVED or DISMISSED")
+	}
+
+	resp, err := s.moderationService.ListReports(ctx, models.ListReportsRequest{
+		Status: models.ReportStatus(req.Status),
+		Pagination: models.Pagination{
+			Page:  req.Page,
+			Limit: req.Limit,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list reports: %v", err)
+	}
+
+	reports := make([]*pb.Report, len(resp.Reports))
+	for i, report := range resp.Reports {
+		reports[i] = toPBReport(report)
+	}
+
+	return &pb.ListReportsResponse{
+		Reports: reports,
+		Total:   resp.Total,
+	}, nil
+}
+
+func (s *ModerationGRPCServer) ResolveReport(ctx context.Context, req *pb.ResolveReportRequest) (*pb.ResolveReportResponse, error) {
+	if req.ReportId == "" {
+		return nil, status.Error(codes.InvalidArgument, "report_id is required")
+	}
+
+	switch models.ReportStatus(req.Status) {
+	case models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be RESOLVED or DISMISSED")
+	}
+
+	report, err := s.moderationService.ResolveReport(ctx, req.ReportId, models.ReportStatus(req.Status))
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "report not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to resolve report: %v", err)
+	}
+
+	return &pb.ResolveReportResponse{
+		Report: toPBReport(report),
+	}, nil
+}
+
+func toPBReport(report models.Report) *pb.Report {
+	return &pb.Report{
+		Id:             report.ID,
+		ReporterId:     report.ReporterID,
+		ReportedUserId: report.ReportedUserID,
+		ChatId:         report.ChatID,
+		MessageId:      report.MessageID,
+		Reason:         string(report.Reason),
+		Details:        report.Details,
+		Status:         string(report.Status),
+		CreatedAt:      timestamppb.New(report.CreatedAt),
+		UpdatedAt:      timestamppb.New(report.UpdatedAt),
+	}
+}
+
+func validateReport(reason, details string) (models.ReportReason, error) {
+	switch models.ReportReason(reason) {
+	case models.ReportReasonSpam, models.ReportReasonHarassment, models.ReportReasonInappropriate, models.ReportReasonOther:
+	default:
+		return "", status.Error(codes.InvalidArgument, "reason must be one of SPAM, HARASSMENT, INAPPROPRIATE or OTHER")
+	}
+
+	if utf8.RuneCountInString(details) > models.MaxReportDetailsLength {
+		return "", status.Errorf(codes.InvalidArgument, "details must be at most %d characters", models.MaxReportDetailsLength)
+	}
+
+	return models.ReportReason(reason), nil
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *UsersGRPCServer) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	if req.CurrentPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "current_password is required")
+	}
+
+	if err := validatePassword("new_password", req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	claims := caller(ctx)
+
+	err := s.usersService.ChangePassword(ctx, models.ChangePasswordRequest{
+		UserID:          claims.UserID,
+		SessionID:       claims.Sid,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPassword):
+			return nil, status.Error(codes.PermissionDenied, "current password is incorrect")
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to change password: %v", err)
+	}
+
+	return &pb.ChangePasswordResponse{}, nil
+}
+
+func (s *UsersGRPCServer) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	err := s.usersService.RequestPasswordReset(ctx, models.RequestPasswordResetRequest{
+		Email:     req.Email,
+		IPAddress: deviceFromContext(ctx, "").IPAddress,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrRateLimited) {
+			return nil, rateLimitedError(ctx, err, "too many password reset requests, try again later")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to request password reset: %v", err)
+	}
+
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+func (s *UsersGRPCServer) ConfirmPasswordReset(ctx context.Context, req *pb.ConfirmPasswordResetRequest) (*pb.ConfirmPasswordResetResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := validatePassword("new_password", req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	err := s.usersService.ConfirmPasswordReset(ctx, models.ConfirmPasswordResetRequest{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			return nil, status.Error(codes.InvalidArgument, "reset token is invalid or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %v", err)
+	}
+
+	return &pb.ConfirmPasswordResetResponse{}, nil
+}
+
+// validatePassword checks the length rules for a password chosen in field;
+// bcrypt ignores everything past MaxPasswordBytes, so longer passwords are
+// rejected rather than truncated.
+func validatePassword(field, password string) error {
+	if len(password) < models.MinPasswordLength {
+		return status.Errorf(codes.InvalidArgument, "%s must be at least %d characters", field, models.MinPasswordLength)
+	}
+
+	if len(password) > models.MaxPasswordBytes {
+		return status.Errorf(codes.InvalidArgument, "%s must be at most %d bytes", field, models.MaxPasswordBytes)
+	}
+
+	return nil
+}
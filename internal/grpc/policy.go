@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	public        = jwt.Policy{Access: jwt.Public}
+	authenticated = jwt.Policy{Access: jwt.Authenticated}
+	adminOnly     = jwt.Policy{Access: jwt.AdminOnly}
+)
+
+// Policies declares how every RPC is authorized. The server refuses to start
+// if a registered RPC is missing here, so new RPCs must be added.
+var Policies = jwt.Policies{
+	pb.MessagesService_SendMessage_FullMethodName:         {Access: jwt.Authenticated, Scopes: []string{models.ScopeMessagesSend}},
+	pb.MessagesService_ListMessages_FullMethodName:        {Access: jwt.Authenticated, Scopes: []string{models.ScopeChatsRead}},
+	pb.MessagesService_UpdateMessageStatus_FullMethodName: authenticated,
+	pb.MessagesService_SubscribeToChat_FullMethodName:     {Access: jwt.Authenticated, Scopes: []string{models.ScopeChatsRead}},
+	pb.MessagesService_ScheduleMessage_FullMethodName:     {Access: jwt.Authenticated, Scopes: []string{models.ScopeMessagesSend}},
+	pb.MessagesService_ListScheduled_FullMethodName:       {Access: jwt.Authenticated, Scopes: []string{models.ScopeMessagesSend}},
+	pb.MessagesService_CancelScheduled_FullMethodName:     {Access: jwt.Authenticated, Scopes: []string{models.ScopeMessagesSend}},
+
+	pb.ChatsService_CreateChat_FullMethodName:         authenticated,
+	pb.ChatsService_GetChat_FullMethodName:            {Access: jwt.Authenticated, Scopes: []string{models.ScopeChatsRead}},
+	pb.ChatsService_ListChats_FullMethodName:          {Access: jwt.Authenticated, Scopes: []string{models.ScopeChatsRead}},
+	pb.ChatsService_UpdateChatSettings_FullMethodName: authenticated,
+	pb.ChatsService_UpdateChat_FullMethodName:         authenticated,
+	pb.ChatsService_CreateChannel_FullMethodName:      authenticated,
+	pb.ChatsService_JoinChannel_FullMethodName:        authenticated,
+	pb.ChatsService_LeaveChannel_FullMethodName:       authenticated,
+	pb.ChatsService_AddChannelMember_FullMethodName:   authenticated,
+	pb.ChatsService_DiscoverChannels_FullMethodName:   authenticated,
+
+	pb.UsersService_CreateUser_FullMethodName:              public,
+	pb.UsersService_Login_FullMethodName:                   public,
+	pb.UsersService_RefreshSession_FullMethodName:          public,
+	pb.UsersService_Logout_FullMethodName:                  authenticated,
+	pb.UsersService_ListSessions_FullMethodName:            authenticated,
+	pb.UsersService_RevokeSession_FullMethodName:           authenticated,
+	pb.UsersService_ChangePassword_FullMethodName:          authenticated,
+	pb.UsersService_RequestPasswordReset_FullMethodName:    public,
+	pb.UsersService_ConfirmPasswordReset_FullMethodName:    public,
+	pb.UsersService_VerifyEmail_FullMethodName:             public,
+	pb.UsersService_ResendVerificationEmail_FullMethodName: authenticated,
+	pb.UsersService_EnrollTOTP_FullMethodName:              authenticated,
+	pb.UsersService_ConfirmTOTP_FullMethodName:             authenticated,
+	pb.UsersService_VerifyLoginChallenge_FullMethodName:    public,
+	pb.UsersService_LoginWithIDToken_FullMethodName:        public,
+	pb.UsersService_ExchangeOIDCCode_FullMethodName:        public,
+	pb.UsersService_GetUser_FullMethodName:                 authenticated,
+	pb.UsersService_GetMe_FullMethodName:                   authenticated,
+	pb.UsersService_UpdateProfile_FullMethodName:           authenticated,
+	pb.UsersService_BatchGetUsers_FullMethodName:           authenticated,
+	pb.UsersService_SearchUsers_FullMethodName:             authenticated,
+	pb.UsersService_SubscribeToUserUpdates_FullMethodName:  authenticated,
+
+	pb.ModerationService_BlockUser_FullMethodName:     authenticated,
+	pb.ModerationService_UnblockUser_FullMethodName:   authenticated,
+	pb.ModerationService_ListBlocked_FullMethodName:   authenticated,
+	pb.ModerationService_ReportUser_FullMethodName:    authenticated,
+	pb.ModerationService_ReportMessage_FullMethodName: authenticated,
+	pb.ModerationService_ListReports_FullMethodName:   adminOnly,
+	pb.ModerationService_ResolveReport_FullMethodName: adminOnly,
+
+	pb.BotsService_CreateBot_FullMethodName:    authenticated,
+	pb.BotsService_ListBots_FullMethodName:     authenticated,
+	pb.BotsService_AddBotToChat_FullMethodName: authenticated,
+	pb.BotsService_CreateAPIKey_FullMethodName: authenticated,
+	pb.BotsService_ListAPIKeys_FullMethodName:  authenticated,
+	pb.BotsService_RevokeAPIKey_FullMethodName: authenticated,
+
+	pb.WebhooksService_CreateWebhook_FullMethodName:         authenticated,
+	pb.WebhooksService_ListWebhooks_FullMethodName:          authenticated,
+	pb.WebhooksService_DeleteWebhook_FullMethodName:         authenticated,
+	pb.WebhooksService_ListWebhookDeliveries_FullMethodName: authenticated,
+	pb.WebhooksService_CreateIncomingWebhook_FullMethodName: authenticated,
+	pb.WebhooksService_ListIncomingWebhooks_FullMethodName:  authenticated,
+	pb.WebhooksService_RevokeIncomingWebhook_FullMethodName: authenticated,
+
+	pb.CommandsService_RegisterCommand_FullMethodName: authenticated,
+	pb.CommandsService_ListCommands_FullMethodName:    {Access: jwt.Authenticated, Scopes: []string{models.ScopeChatsRead}},
+	pb.CommandsService_DeleteCommand_FullMethodName:   authenticated,
+}
+
+// chatAllowed rejects calls on a chat the caller's API key is not
+// restricted to.
+func chatAllowed(ctx context.Context, chatID string) error {
+	if !caller(ctx).AllowsChat(chatID) {
+		return status.Error(codes.PermissionDenied, "the API key is not allowed in this chat")
+	}
+	return nil
+}
+
+// caller returns the claims the auth interceptor put in the context. Every
+// method that is not public has them; public methods get empty claims.
+func caller(ctx context.Context) *jwt.Claims {
+	claims, err := jwt.GetClaimsFromContext(ctx)
+	if err != nil {
+		return &jwt.Claims{}
+	}
+	return claims
+}
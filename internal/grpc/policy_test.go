@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestPolicies_CoverRegisteredServices(t *testing.T) {
+	server := grpc.NewServer()
+	NewGRPCServer(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).RegisterServices(server)
+
+	assert.NoError(t, Policies.Validate(server.GetServiceInfo()))
+}
+
+func TestPolicies_ReportQueueIsAdminOnly(t *testing.T) {
+	key, err := jwt.GenerateKey("test-key", jwt.AlgorithmEdDSA)
+	require.NoError(t, err)
+	jwtService, err := jwt.NewServiceWithConfig(jwt.Config{Keys: []jwt.Key{key}})
+	require.NoError(t, err)
+	interceptor := jwt.NewInterceptor(jwtService, nil, nil, Policies)
+
+	userToken, err := jwtService.GenerateToken(jwt.User{ID: "user1"})
+	require.NoError(t, err)
+	adminToken, err := jwtService.GenerateToken(jwt.User{ID: "admin1", Admin: true})
+	require.NoError(t, err)
+
+	call := func(token, method string) error {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+		_, err := interceptor.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	for _, method := range []string{pb.ModerationService_ListReports_FullMethodName, pb.ModerationService_ResolveReport_FullMethodName} {
+		assert.Equal(t, codes.PermissionDenied, status.Code(call(userToken, method)), method)
+		assert.NoError(t, call(adminToken, method), method)
+	}
+
+	// Reporting stays open to every user.
+	assert.NoError(t, call(userToken, pb.ModerationService_ReportUser_FullMethodName))
+}
@@ -7,21 +7,35 @@ import (
 )
 
 type GRPCServer struct {
-	messagesServer *MessagesGRPCServer
-	chatsServer    *ChatsGRPCServer
-	usersServer    *UsersGRPCServer
+	messagesServer   *MessagesGRPCServer
+	chatsServer      *ChatsGRPCServer
+	usersServer      *UsersGRPCServer
+	moderationServer *ModerationGRPCServer
+	botsServer       *BotsGRPCServer
+	webhooksServer   *WebhooksGRPCServer
+	commandsServer   *CommandsGRPCServer
 }
 
 func NewGRPCServer(
 	messagesService services.MessagesService,
 	chatsService services.ChatsService,
 	usersService services.UsersService,
+	moderationService services.ModerationService,
 	realtimeService services.RealtimeService,
+	botsService services.BotsService,
+	webhooksService services.WebhooksService,
+	incomingService services.IncomingWebhooksService,
+	commandsService services.CommandsService,
+	scheduledService services.ScheduledMessagesService,
 ) *GRPCServer {
 	return &GRPCServer{
-		messagesServer: NewMessagesGRPCServer(messagesService, realtimeService),
-		chatsServer:    NewChatsGRPCServer(chatsService),
-		usersServer:    NewUsersGRPCServer(usersService),
+		messagesServer:   NewMessagesGRPCServer(messagesService, realtimeService, scheduledService),
+		chatsServer:      NewChatsGRPCServer(chatsService),
+		usersServer:      NewUsersGRPCServer(usersService, realtimeService),
+		moderationServer: NewModerationGRPCServer(moderationService),
+		botsServer:       NewBotsGRPCServer(botsService),
+		webhooksServer:   NewWebhooksGRPCServer(webhooksService, incomingService),
+		commandsServer:   NewCommandsGRPCServer(commandsService),
 	}
 }
 
@@ -29,4 +43,8 @@ func (s *GRPCServer) RegisterServices(server *grpc.Server) {
 	pb.RegisterMessagesServiceServer(server, s.messagesServer)
 	pb.RegisterChatsServiceServer(server, s.chatsServer)
 	pb.RegisterUsersServiceServer(server, s.usersServer)
+	pb.RegisterModerationServiceServer(server, s.moderationServer)
+	pb.RegisterBotsServiceServer(server, s.botsServer)
+	pb.RegisterWebhooksServiceServer(server, s.webhooksServer)
+	pb.RegisterCommandsServiceServer(server, s.commandsServer)
 }
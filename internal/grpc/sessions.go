@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *UsersGRPCServer) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	claims := caller(ctx)
+
+	sessions, err := s.usersService.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list sessions: %v", err)
+	}
+
+	pbSessions := make([]*pb.Session, len(sessions))
+	for i, session := range sessions {
+		pbSessions[i] = &pb.Session{
+			Id:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  timestamppb.New(session.CreatedAt),
+			LastUsedAt: timestamppb.New(session.LastUsedAt),
+			Current:    session.ID == claims.Sid,
+		}
+	}
+
+	return &pb.ListSessionsResponse{
+		Sessions: pbSessions,
+	}, nil
+}
+
+func (s *UsersGRPCServer) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	if req.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	if err := s.usersService.RevokeSession(ctx, userID, req.SessionId); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
+	}
+
+	return &pb.RevokeSessionResponse{
+		SessionId: req.SessionId,
+	}, nil
+}
+
+// deviceFromContext describes the calling client from its gRPC metadata and
+// peer address.
+func deviceFromContext(ctx context.Context, deviceName string) models.DeviceInfo {
+	device := models.DeviceInfo{
+		DeviceName: deviceName,
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if userAgents := md.Get("user-agent"); len(userAgents) > 0 {
+			device.UserAgent = truncateRunes(userAgents[0], models.MaxUserAgentLength)
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		device.IPAddress = hostFromAddr(p.Addr.String())
+	}
+
+	return device
+}
+
+func hostFromAddr(addr string) string {
+	if i := strings.LastIndex(addr, ":"); i >= 0 {
+		addr = addr[:i]
+	}
+	return strings.Trim(addr, "[]")
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
+
+// trackSession ties a stream to the caller's login session, so revoking the
+// session ends the stream.
+func trackSession(ctx context.Context, realtimeService services.RealtimeService) (context.Context, func()) {
+	sessionID := ""
+	if claims, err := jwt.GetClaimsFromContext(ctx); err == nil {
+		sessionID = claims.Sid
+	}
+	return realtimeService.TrackSession(ctx, sessionID)
+}
+
+// streamEndError is returned when a stream's subscription ends. A revoked
+// session ends the stream with UNAUTHENTICATED; anything else is a normal end.
+func streamEndError(ctx context.Context) error {
+	if errors.Is(context.Cause(ctx), services.ErrSessionRevoked) {
+		return status.Error(codes.Unauthenticated, "session revoked")
+	}
+	return nil
+}
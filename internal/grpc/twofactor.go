@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxTwoFactorCodeLength is comfortably above both TOTP and recovery codes.
+const maxTwoFactorCodeLength = 64
+
+func (s *UsersGRPCServer) EnrollTOTP(ctx context.Context, req *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	userID := caller(ctx).UserID
+
+	enrollment, err := s.usersService.EnrollTOTP(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTOTPAlreadyEnabled):
+			return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+		case errors.Is(err, services.ErrTwoFactorUnavailable):
+			return nil, status.Error(codes.Unavailable, "two-factor authentication is not available")
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to enroll two-factor authentication: %v", err)
+	}
+
+	return &pb.EnrollTOTPResponse{
+		Secret:        enrollment.Secret,
+		OtpauthUri:    enrollment.URI,
+		RecoveryCodes: enrollment.RecoveryCodes,
+	}, nil
+}
+
+func (s *UsersGRPCServer) ConfirmTOTP(ctx context.Context, req *pb.ConfirmTOTPRequest) (*pb.ConfirmTOTPResponse, error) {
+	if req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	userID := caller(ctx).UserID
+
+	if err := s.usersService.ConfirmTOTP(ctx, userID, req.Code); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTOTPCode):
+			return nil, status.Error(codes.InvalidArgument, "code is invalid")
+		case errors.Is(err, services.ErrTOTPNotEnrolled):
+			return nil, status.Error(codes.FailedPrecondition, "call EnrollTOTP first")
+		case errors.Is(err, services.ErrTOTPAlreadyEnabled):
+			return nil, status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+		case errors.Is(err, services.ErrTwoFactorUnavailable):
+			return nil, status.Error(codes.Unavailable, "two-factor authentication is not available")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to confirm two-factor authentication: %v", err)
+	}
+
+	return &pb.ConfirmTOTPResponse{}, nil
+}
+
+func (s *UsersGRPCServer) VerifyLoginChallenge(ctx context.Context, req *pb.VerifyLoginChallengeRequest) (*pb.VerifyLoginChallengeResponse, error) {
+	if req.ChallengeToken == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge_token and code are required")
+	}
+
+	if len(req.Code) > maxTwoFactorCodeLength {
+		return nil, status.Error(codes.InvalidArgument, "code is too long")
+	}
+
+	loginResp, err := s.usersService.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTOTPCode):
+			return nil, status.Error(codes.Unauthenticated, "invalid code")
+		case errors.Is(err, services.ErrInvalidLoginChallenge):
+			return nil, status.Error(codes.Unauthenticated, "login challenge is invalid or expired, log in again")
+		case errors.Is(err, services.ErrTwoFactorUnavailable):
+			return nil, status.Error(codes.Unavailable, "two-factor authentication is not available")
+		case errors.Is(err, services.ErrRateLimited):
+			return nil, rateLimitedError(ctx, err, "too many wrong codes, try again later")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to verify login challenge: %v", err)
+	}
+
+	user, err := s.usersService.GetByID(ctx, loginResp.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user details")
+	}
+
+	return &pb.VerifyLoginChallengeResponse{
+		User:           toPBUser(user),
+		Token:          loginResp.Token,
+		RefreshToken:   loginResp.RefreshToken,
+		TokenExpiresAt: timestamppb.New(loginResp.TokenExpiresAt),
+	}, nil
+}
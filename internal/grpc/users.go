@@ -2,6 +2,12 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
 	"github.com/brenocoelho/messaging-app-go/internal/services"
@@ -13,12 +19,14 @@ import (
 
 type UsersGRPCServer struct {
 	pb.UnimplementedUsersServiceServer
-	usersService services.UsersService
+	usersService    services.UsersService
+	realtimeService services.RealtimeService
 }
 
-func NewUsersGRPCServer(usersService services.UsersService) *UsersGRPCServer {
+func NewUsersGRPCServer(usersService services.UsersService, realtimeService services.RealtimeService) *UsersGRPCServer {
 	return &UsersGRPCServer{
-		usersService: usersService,
+		usersService:    usersService,
+		realtimeService: realtimeService,
 	}
 }
 
@@ -27,12 +35,35 @@ func (s *UsersGRPCServer) CreateUser(ctx context.Context, req *pb.CreateUserRequ
 		return nil, status.Error(codes.InvalidArgument, "username, email, and password are required")
 	}
 
+	if utf8.RuneCountInString(req.Username) > models.MaxUsernameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "username must be at most %d characters", models.MaxUsernameLength)
+	}
+
+	if !validEmail(req.Email) {
+		return nil, status.Error(codes.InvalidArgument, "email is invalid")
+	}
+
+	if err := validatePassword("password", req.Password); err != nil {
+		return nil, err
+	}
+
+	if utf8.RuneCountInString(req.DeviceName) > models.MaxDeviceNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "device_name must be at most %d characters", models.MaxDeviceNameLength)
+	}
+
 	resp, err := s.usersService.CreateUser(ctx, models.CreateUserRequest{
 		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
+		Device:   deviceFromContext(ctx, req.DeviceName),
 	})
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailTaken):
+			return nil, status.Error(codes.AlreadyExists, "email is already taken")
+		case errors.Is(err, services.ErrUsernameTaken):
+			return nil, status.Error(codes.AlreadyExists, "username is already taken")
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
 	}
 
@@ -41,16 +72,11 @@ func (s *UsersGRPCServer) CreateUser(ctx context.Context, req *pb.CreateUserRequ
 		return nil, status.Errorf(codes.Internal, "failed to get created user: %v", err)
 	}
 
-	pbUser := &pb.User{
-		Id:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: timestamppb.New(user.CreatedAt),
-	}
-
 	return &pb.CreateUserResponse{
-		User:  pbUser,
-		Token: resp.Token,
+		User:           toPBUser(user),
+		Token:          resp.Token,
+		RefreshToken:   resp.RefreshToken,
+		TokenExpiresAt: timestamppb.New(resp.TokenExpiresAt),
 	}, nil
 }
 
@@ -59,38 +85,94 @@ func (s *UsersGRPCServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.
 		return nil, status.Error(codes.InvalidArgument, "email and password are required")
 	}
 
+	if utf8.RuneCountInString(req.DeviceName) > models.MaxDeviceNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "device_name must be at most %d characters", models.MaxDeviceNameLength)
+	}
+
 	loginResp, err := s.usersService.Login(ctx, models.LoginRequest{
 		Email:    req.Email,
 		Password: req.Password,
+		Device:   deviceFromContext(ctx, req.DeviceName),
 	})
 	if err != nil {
+		if errors.Is(err, services.ErrRateLimited) {
+			return nil, rateLimitedError(ctx, err, "too many failed login attempts, try again later")
+		}
 		return nil, status.Errorf(codes.Unauthenticated, "invalid email or password")
 	}
 
+	return s.toPBLoginResponse(ctx, loginResp)
+}
+
+// toPBLoginResponse returns either the challenge of a login with two-factor
+// authentication or the session with its user.
+func (s *UsersGRPCServer) toPBLoginResponse(ctx context.Context, loginResp models.LoginResponse) (*pb.LoginResponse, error) {
+	if loginResp.ChallengeToken != "" {
+		return &pb.LoginResponse{
+			ChallengeToken:     loginResp.ChallengeToken,
+			ChallengeExpiresAt: timestamppb.New(loginResp.ChallengeExpiresAt),
+		}, nil
+	}
+
 	user, err := s.usersService.GetByID(ctx, loginResp.UserID)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get user details")
 	}
 
-	pbUser := &pb.User{
-		Id:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: timestamppb.New(user.CreatedAt),
+	return &pb.LoginResponse{
+		User:           toPBUser(user),
+		Token:          loginResp.Token,
+		RefreshToken:   loginResp.RefreshToken,
+		TokenExpiresAt: timestamppb.New(loginResp.TokenExpiresAt),
+	}, nil
+}
+
+func (s *UsersGRPCServer) RefreshSession(ctx context.Context, req *pb.RefreshSessionRequest) (*pb.RefreshSessionResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
 	}
 
-	return &pb.LoginResponse{
-		User:  pbUser,
-		Token: loginResp.Token,
+	resp, err := s.usersService.RefreshSession(ctx, req.RefreshToken, deviceFromContext(ctx, ""))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to refresh session: %v", err)
+	}
+
+	return &pb.RefreshSessionResponse{
+		Token:          resp.Token,
+		RefreshToken:   resp.RefreshToken,
+		TokenExpiresAt: timestamppb.New(resp.TokenExpiresAt),
 	}, nil
 }
 
+func (s *UsersGRPCServer) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	claims := caller(ctx)
+
+	err := s.usersService.Logout(ctx, models.LogoutRequest{
+		UserID:         claims.UserID,
+		TokenID:        claims.Jti,
+		SessionID:      claims.Sid,
+		TokenExpiresAt: time.Unix(claims.Exp, 0),
+		RefreshToken:   req.RefreshToken,
+		AllSessions:    req.AllSessions,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to log out: %v", err)
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
 func (s *UsersGRPCServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
 
-	user, err := s.usersService.GetByID(ctx, req.UserId)
+	userID := caller(ctx).UserID
+
+	user, err := s.usersService.GetUser(ctx, userID, req.UserId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
 	}
@@ -99,14 +181,201 @@ func (s *UsersGRPCServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (
 		return nil, status.Error(codes.NotFound, "user not found")
 	}
 
-	pbUser := &pb.User{
-		Id:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: timestamppb.New(user.CreatedAt),
+	return &pb.GetUserResponse{
+		User: toPBUser(user),
+	}, nil
+}
+
+func (s *UsersGRPCServer) GetMe(ctx context.Context, req *pb.GetMeRequest) (*pb.GetMeResponse, error) {
+	userID := caller(ctx).UserID
+
+	user, err := s.usersService.GetByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
 	}
 
-	return &pb.GetUserResponse{
-		User: pbUser,
+	if user.ID == "" {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	return &pb.GetMeResponse{
+		User: toPBUser(user),
+	}, nil
+}
+
+func (s *UsersGRPCServer) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.UpdateProfileResponse, error) {
+	if req.Username == nil && req.DisplayName == nil && req.Bio == nil && req.AvatarAttachmentId == nil && req.Discoverable == nil {
+		return nil, status.Error(codes.InvalidArgument, "at least one of username, display_name, bio, avatar_attachment_id or discoverable is required")
+	}
+
+	if req.Username != nil && *req.Username == "" {
+		return nil, status.Error(codes.InvalidArgument, "username cannot be empty")
+	}
+
+	if req.Username != nil && utf8.RuneCountInString(*req.Username) > models.MaxUsernameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "username must be at most %d characters", models.MaxUsernameLength)
+	}
+
+	if req.DisplayName != nil && utf8.RuneCountInString(*req.DisplayName) > models.MaxDisplayNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "display_name must be at most %d characters", models.MaxDisplayNameLength)
+	}
+
+	if req.Bio != nil && utf8.RuneCountInString(*req.Bio) > models.MaxBioLength {
+		return nil, status.Errorf(codes.InvalidArgument, "bio must be at most %d characters", models.MaxBioLength)
+	}
+
+	if req.AvatarAttachmentId != nil && utf8.RuneCountInString(*req.AvatarAttachmentId) > models.MaxAttachmentIDLength {
+		return nil, status.Errorf(codes.InvalidArgument, "avatar_attachment_id must be at most %d characters", models.MaxAttachmentIDLength)
+	}
+
+	userID := caller(ctx).UserID
+
+	user, err := s.usersService.UpdateProfile(ctx, models.UpdateProfileRequest{
+		UserID:             userID,
+		Username:           req.Username,
+		DisplayName:        req.DisplayName,
+		Bio:                req.Bio,
+		AvatarAttachmentID: req.AvatarAttachmentId,
+		Discoverable:       req.Discoverable,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, services.ErrUsernameTaken):
+			return nil, status.Error(codes.AlreadyExists, "username is already taken")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update profile: %v", err)
+	}
+
+	return &pb.UpdateProfileResponse{
+		User: toPBUser(user),
+	}, nil
+}
+
+func (s *UsersGRPCServer) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+	if len(req.UserIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_ids is required")
+	}
+
+	if len(req.UserIds) > models.MaxBatchGetUsers {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d user_ids are allowed", models.MaxBatchGetUsers)
+	}
+
+	userID := caller(ctx).UserID
+
+	users, err := s.usersService.BatchGetUsers(ctx, userID, req.UserIds)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get users: %v", err)
+	}
+
+	pbUsers := make([]*pb.User, len(users))
+	for i, user := range users {
+		pbUsers[i] = toPBUser(user)
+	}
+
+	return &pb.BatchGetUsersResponse{
+		Users: pbUsers,
 	}, nil
 }
+
+func (s *UsersGRPCServer) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	if utf8.RuneCountInString(req.Query) > models.MaxUsernameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "query must be at most %d characters", models.MaxUsernameLength)
+	}
+
+	userID := caller(ctx).UserID
+
+	resp, err := s.usersService.SearchUsers(ctx, models.SearchUsersRequest{
+		UserID: userID,
+		Query:  req.Query,
+		Cursor: req.Cursor,
+		Limit:  req.Limit,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to search users: %v", err)
+	}
+
+	users := make([]*pb.User, len(resp.Users))
+	for i, user := range resp.Users {
+		users[i] = toPBUser(user)
+	}
+
+	return &pb.SearchUsersResponse{
+		Users:      users,
+		NextCursor: resp.NextCursor,
+	}, nil
+}
+
+func (s *UsersGRPCServer) SubscribeToUserUpdates(req *pb.SubscribeToUserUpdatesRequest, stream pb.UsersService_SubscribeToUserUpdatesServer) error {
+	ctx, release := trackSession(stream.Context(), s.realtimeService)
+	defer release()
+
+	userID := caller(ctx).UserID
+
+	updateChan, err := s.realtimeService.SubscribeToUserUpdates(ctx, userID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to subscribe to user updates: %v", err)
+	}
+
+	for {
+		select {
+		case update := <-updateChan:
+			if update == nil {
+				slog.Info("User unsubscribed from user updates", "userID", userID)
+				return streamEndError(ctx)
+			}
+
+			pbUpdate := &pb.UserUpdate{
+				UserId:     update.UserID,
+				UpdateType: string(update.Type),
+				Content:    update.Content,
+				Timestamp:  timestamppb.New(update.Timestamp),
+			}
+			if update.User != nil {
+				pbUpdate.User = toPBUser(*update.User)
+			}
+
+			if err := stream.Send(pbUpdate); err != nil {
+				if err == io.EOF {
+					slog.Info("Client disconnected", "userID", userID)
+					return nil
+				}
+				slog.Error("Failed to send user update to client", "error", err, "userID", userID)
+				return status.Errorf(codes.Internal, "failed to send user update: %v", err)
+			}
+
+		case <-ctx.Done():
+			slog.Info("User update stream context cancelled", "userID", userID)
+			return streamEndError(ctx)
+		}
+	}
+}
+
+func toPBUser(user models.User) *pb.User {
+	pbUser := &pb.User{
+		Id:                 user.ID,
+		Username:           user.Username,
+		Email:              user.Email,
+		CreatedAt:          timestamppb.New(user.CreatedAt),
+		DisplayName:        user.DisplayName,
+		Bio:                user.Bio,
+		AvatarAttachmentId: user.AvatarAttachmentID,
+		Discoverable:       user.Discoverable,
+		EmailVerified:      user.EmailVerifiedAt != nil,
+		Bot:                user.IsBot(),
+		BotOwnerId:         user.BotOwnerID,
+	}
+	// The email of a bot is a placeholder.
+	if user.IsBot() {
+		pbUser.Email = ""
+	}
+	return pbUser
+}
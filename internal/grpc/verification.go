@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/mail"
+	"strconv"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (s *UsersGRPCServer) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.usersService.VerifyEmail(ctx, req.Token); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			return nil, status.Error(codes.InvalidArgument, "verification token is invalid or expired")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to verify email: %v", err)
+	}
+
+	return &pb.VerifyEmailResponse{}, nil
+}
+
+func (s *UsersGRPCServer) ResendVerificationEmail(ctx context.Context, req *pb.ResendVerificationEmailRequest) (*pb.ResendVerificationEmailResponse, error) {
+	userID := caller(ctx).UserID
+
+	if err := s.usersService.ResendVerificationEmail(ctx, userID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			return nil, status.Error(codes.FailedPrecondition, "email is already verified")
+		case errors.Is(err, services.ErrRateLimited):
+			return nil, rateLimitedError(ctx, err, "too many verification emails requested")
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to resend verification email: %v", err)
+	}
+
+	return &pb.ResendVerificationEmailResponse{}, nil
+}
+
+// rateLimitedError returns RESOURCE_EXHAUSTED and, when err carries one, sets
+// a retry-after trailer with the seconds to wait.
+func rateLimitedError(ctx context.Context, err error, message string) error {
+	var retryErr *services.RetryAfterError
+	if errors.As(err, &retryErr) {
+		seconds := int64(math.Ceil(retryErr.RetryAfter.Seconds()))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.FormatInt(seconds, 10)))
+	}
+	return status.Error(codes.ResourceExhausted, message)
+}
+
+// validEmail accepts a bare address such as john@example.com; display names
+// and comments are rejected.
+func validEmail(email string) bool {
+	if len(email) > models.MaxEmailLength {
+		return false
+	}
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
@@ -0,0 +1,288 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/services"
+	pb "github.com/brenocoelho/messaging-app-go/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WebhooksGRPCServer struct {
+	pb.UnimplementedWebhooksServiceServer
+	webhooksService services.WebhooksService
+	incomingService services.IncomingWebhooksService
+}
+
+func NewWebhooksGRPCServer(webhooksService services.WebhooksService, incomingService services.IncomingWebhooksService) *WebhooksGRPCServer {
+	return &WebhooksGRPCServer{
+		webhooksService: webhooksService,
+		incomingService: incomingService,
+	}
+}
+
+func (s *WebhooksGRPCServer) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.CreateWebhookResponse, error) {
+	if (req.ChatId == "") == (req.BotId == "") {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of chat_id and bot_id is required")
+	}
+
+	if req.Url == "" || len(req.Events) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "url and events are required")
+	}
+
+	if len(req.Url) > models.MaxWebhookURLLength {
+		return nil, status.Errorf(codes.InvalidArgument, "url must be at most %d characters", models.MaxWebhookURLLength)
+	}
+
+	resp, err := s.webhooksService.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: caller(ctx).UserID,
+		ChatID: req.ChatId,
+		BotID:  req.BotId,
+		URL:    req.Url,
+		Events: req.Events,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat or bot not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		case errors.Is(err, services.ErrInvalidWebhookURL):
+			return nil, status.Error(codes.InvalidArgument, "url must be an http or https URL")
+		case errors.Is(err, services.ErrInvalidEvent):
+			return nil, status.Errorf(codes.InvalidArgument, "events must be among %v", models.WebhookEvents)
+		case errors.Is(err, services.ErrLimitReached):
+			return nil, status.Errorf(codes.FailedPrecondition, "at most %d webhooks per chat or bot", models.MaxWebhooksPerTarget)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create webhook: %v", err)
+	}
+
+	return &pb.CreateWebhookResponse{
+		Webhook: toPBWebhook(resp.Webhook),
+		Secret:  resp.Secret,
+	}, nil
+}
+
+func (s *WebhooksGRPCServer) ListWebhooks(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	if (req.ChatId == "") == (req.BotId == "") {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of chat_id and bot_id is required")
+	}
+
+	webhooks, err := s.webhooksService.ListWebhooks(ctx, models.ListWebhooksRequest{
+		UserID: caller(ctx).UserID,
+		ChatID: req.ChatId,
+		BotID:  req.BotId,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat or bot not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list webhooks: %v", err)
+	}
+
+	pbWebhooks := make([]*pb.Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		pbWebhooks[i] = toPBWebhook(webhook)
+	}
+
+	return &pb.ListWebhooksResponse{
+		Webhooks: pbWebhooks,
+	}, nil
+}
+
+func (s *WebhooksGRPCServer) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteWebhookResponse, error) {
+	if req.WebhookId == "" {
+		return nil, status.Error(codes.InvalidArgument, "webhook_id is required")
+	}
+
+	err := s.webhooksService.DeleteWebhook(ctx, caller(ctx).UserID, req.WebhookId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "webhook not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to delete webhook: %v", err)
+	}
+
+	return &pb.DeleteWebhookResponse{
+		WebhookId: req.WebhookId,
+	}, nil
+}
+
+func (s *WebhooksGRPCServer) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	if req.WebhookId == "" {
+		return nil, status.Error(codes.InvalidArgument, "webhook_id is required")
+	}
+
+	deliveryStatus := models.WebhookDeliveryStatus(req.Status)
+	switch deliveryStatus {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryDead:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be PENDING, SUCCEEDED or DEAD")
+	}
+
+	resp, err := s.webhooksService.ListWebhookDeliveries(ctx, models.ListWebhookDeliveriesRequest{
+		UserID:    caller(ctx).UserID,
+		WebhookID: req.WebhookId,
+		Status:    deliveryStatus,
+		Pagination: models.Pagination{
+			Page:  req.Page,
+			Limit: req.Limit,
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "webhook not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list webhook deliveries: %v", err)
+	}
+
+	deliveries := make([]*pb.WebhookDelivery, len(resp.Deliveries))
+	for i, delivery := range resp.Deliveries {
+		deliveries[i] = toPBWebhookDelivery(delivery)
+	}
+
+	return &pb.ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      resp.Total,
+	}, nil
+}
+
+func (s *WebhooksGRPCServer) CreateIncomingWebhook(ctx context.Context, req *pb.CreateIncomingWebhookRequest) (*pb.CreateIncomingWebhookResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	if utf8.RuneCountInString(req.Name) > models.MaxDisplayNameLength {
+		return nil, status.Errorf(codes.InvalidArgument, "name must be at most %d characters", models.MaxDisplayNameLength)
+	}
+
+	resp, err := s.incomingService.CreateIncomingWebhook(ctx, models.CreateIncomingWebhookRequest{
+		UserID: caller(ctx).UserID,
+		ChatID: req.ChatId,
+		Name:   req.Name,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		case errors.Is(err, services.ErrLimitReached):
+			return nil, status.Errorf(codes.FailedPrecondition, "at most %d incoming webhooks per chat", models.MaxIncomingWebhooksPerChat)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to create incoming webhook: %v", err)
+	}
+
+	return &pb.CreateIncomingWebhookResponse{
+		IncomingWebhook: toPBIncomingWebhook(resp.IncomingWebhook),
+		Url:             resp.URL,
+	}, nil
+}
+
+func (s *WebhooksGRPCServer) ListIncomingWebhooks(ctx context.Context, req *pb.ListIncomingWebhooksRequest) (*pb.ListIncomingWebhooksResponse, error) {
+	if req.ChatId == "" {
+		return nil, status.Error(codes.InvalidArgument, "chat_id is required")
+	}
+
+	webhooks, err := s.incomingService.ListIncomingWebhooks(ctx, caller(ctx).UserID, req.ChatId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "chat not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to list incoming webhooks: %v", err)
+	}
+
+	pbWebhooks := make([]*pb.IncomingWebhook, len(webhooks))
+	for i, webhook := range webhooks {
+		pbWebhooks[i] = toPBIncomingWebhook(webhook)
+	}
+
+	return &pb.ListIncomingWebhooksResponse{
+		IncomingWebhooks: pbWebhooks,
+	}, nil
+}
+
+func (s *WebhooksGRPCServer) RevokeIncomingWebhook(ctx context.Context, req *pb.RevokeIncomingWebhookRequest) (*pb.RevokeIncomingWebhookResponse, error) {
+	if req.IncomingWebhookId == "" {
+		return nil, status.Error(codes.InvalidArgument, "incoming_webhook_id is required")
+	}
+
+	err := s.incomingService.RevokeIncomingWebhook(ctx, caller(ctx).UserID, req.IncomingWebhookId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "incoming webhook not found")
+		case errors.Is(err, services.ErrPermissionDenied):
+			return nil, status.Error(codes.PermissionDenied, "only chat admins can manage webhooks")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to revoke incoming webhook: %v", err)
+	}
+
+	return &pb.RevokeIncomingWebhookResponse{
+		IncomingWebhookId: req.IncomingWebhookId,
+	}, nil
+}
+
+func toPBWebhook(webhook models.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		Id:        webhook.ID,
+		ChatId:    webhook.ChatID,
+		BotId:     webhook.BotID,
+		Url:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: timestamppb.New(webhook.CreatedAt),
+	}
+}
+
+func toPBWebhookDelivery(delivery models.WebhookDelivery) *pb.WebhookDelivery {
+	pbDelivery := &pb.WebhookDelivery{
+		Id:             delivery.ID,
+		WebhookId:      delivery.WebhookID,
+		Event:          delivery.Event,
+		Payload:        string(delivery.Payload),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      timestamppb.New(delivery.CreatedAt),
+	}
+	if delivery.Status == models.WebhookDeliveryPending {
+		pbDelivery.NextAttemptAt = timestamppb.New(delivery.NextAttemptAt)
+	}
+	if delivery.LastAttemptAt != nil {
+		pbDelivery.LastAttemptAt = timestamppb.New(*delivery.LastAttemptAt)
+	}
+	if delivery.DeliveredAt != nil {
+		pbDelivery.DeliveredAt = timestamppb.New(*delivery.DeliveredAt)
+	}
+	return pbDelivery
+}
+
+func toPBIncomingWebhook(webhook models.IncomingWebhook) *pb.IncomingWebhook {
+	return &pb.IncomingWebhook{
+		Id:          webhook.ID,
+		ChatId:      webhook.ChatID,
+		UserId:      webhook.UserID,
+		Name:        webhook.Name,
+		CreatedBy:   webhook.CreatedBy,
+		TokenPrefix: webhook.TokenPrefix,
+		CreatedAt:   timestamppb.New(webhook.CreatedAt),
+	}
+}
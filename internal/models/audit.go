@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+const (
+	AuditLoginLockout = "login.lockout"
+)
+
+type AuditEvent struct {
+	ID     string `json:"id" db:"id"`
+	Action string `json:"action" db:"action"`
+	// UserID is empty when the event is not tied to a known user.
+	UserID    string            `json:"user_id,omitempty" db:"user_id"`
+	IPAddress string            `json:"ip_address" db:"ip_address"`
+	Details   map[string]string `json:"details" db:"details"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
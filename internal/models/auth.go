@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+type RefreshToken struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	FamilyID  string     `json:"family_id" db:"family_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type RefreshSessionResponse struct {
+	Token          string    `json:"token"`
+	TokenExpiresAt time.Time `json:"token_expires_at"`
+	RefreshToken   string    `json:"refresh_token"`
+	UserID         string    `json:"user_id"`
+}
+
+// LogoutRequest ends the caller's session. TokenID, SessionID and
+// TokenExpiresAt come from the access token used for the call; RefreshToken,
+// when set, revokes that session's refresh tokens too. AllSessions logs the
+// user out everywhere.
+type LogoutRequest struct {
+	UserID         string    `json:"user_id"`
+	TokenID        string    `json:"token_id"`
+	SessionID      string    `json:"session_id"`
+	TokenExpiresAt time.Time `json:"token_expires_at"`
+	RefreshToken   string    `json:"refresh_token"`
+	AllSessions    bool      `json:"all_sessions"`
+}
+
+const (
+	MaxDeviceNameLength = 100
+	MaxUserAgentLength  = 512
+)
+
+// DeviceInfo describes the client a session was opened from.
+type DeviceInfo struct {
+	DeviceName string `json:"device_name"`
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+}
+
+type Session struct {
+	ID         string     `json:"id" db:"id"`
+	UserID     string     `json:"user_id" db:"user_id"`
+	DeviceName string     `json:"device_name" db:"device_name"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IPAddress  string     `json:"ip_address" db:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Length rules for new passwords. bcrypt ignores anything past
+// MaxPasswordBytes.
+const (
+	MinPasswordLength = 6
+	MaxPasswordBytes  = 72
+)
+
+type PasswordResetToken struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type EmailVerificationToken struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type ChangePasswordRequest struct {
+	UserID          string `json:"user_id"`
+	SessionID       string `json:"session_id"`
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type RequestPasswordResetRequest struct {
+	Email     string `json:"email"`
+	IPAddress string `json:"ip_address"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
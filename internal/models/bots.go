@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+const (
+	MaxAPIKeyNameLength = 100
+	MaxAPIKeyChats      = 50
+	MaxBotsPerOwner     = 20
+	MaxAPIKeysPerBot    = 10
+)
+
+// Scopes an API key can hold. Tokens from a login are not restricted.
+const (
+	ScopeMessagesSend = "messages:send"
+	ScopeChatsRead    = "chats:read"
+)
+
+var APIKeyScopes = []string{ScopeMessagesSend, ScopeChatsRead}
+
+// BotEmailDomain holds the placeholder emails of bots. The .invalid TLD
+// never resolves, so no mail reaches anybody.
+const BotEmailDomain = "bots.invalid"
+
+type CreateBotRequest struct {
+	OwnerID     string `json:"owner_id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id" db:"id"`
+	BotID      string     `json:"bot_id" db:"bot_id"`
+	Name       string     `json:"name" db:"name"`
+	KeyPrefix  string     `json:"key_prefix" db:"key_prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	Scopes     []string   `json:"scopes" db:"scopes"`
+	ChatIDs    []string   `json:"chat_ids" db:"chat_ids"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateAPIKeyRequest struct {
+	OwnerID string   `json:"owner_id"`
+	BotID   string   `json:"bot_id"`
+	Name    string   `json:"name"`
+	Scopes  []string `json:"scopes"`
+	ChatIDs []string `json:"chat_ids"`
+}
+
+// CreateAPIKeyResponse holds the only copy of the key; just its hash is
+// stored.
+type CreateAPIKeyResponse struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"api_key"`
+}
@@ -2,24 +2,88 @@ package models
 
 import "time"
 
+const (
+	MaxChatNameLength        = 50
+	MaxChatDescriptionLength = 500
+	MaxAttachmentIDLength    = 255
+)
+
+type ChatRole string
+
+const (
+	ChatRoleAdmin  ChatRole = "ADMIN"
+	ChatRoleMember ChatRole = "MEMBER"
+)
+
+// ChatKind separates regular chats from broadcast-only channels, where only
+// admins post.
+type ChatKind string
+
+const (
+	ChatKindChat    ChatKind = "CHAT"
+	ChatKindChannel ChatKind = "CHANNEL"
+)
+
+type ChatVisibility string
+
+const (
+	ChatVisibilityPublic  ChatVisibility = "PUBLIC"
+	ChatVisibilityPrivate ChatVisibility = "PRIVATE"
+)
+
 type Chat struct {
-	ID        string    `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID                 string         `json:"id" db:"id"`
+	Name               string         `json:"name" db:"name"`
+	Kind               ChatKind       `json:"kind" db:"kind"`
+	Visibility         ChatVisibility `json:"visibility" db:"visibility"`
+	Description        string         `json:"description" db:"description"`
+	AvatarAttachmentID string         `json:"avatar_attachment_id" db:"avatar_attachment_id"`
+	LastActivityAt     time.Time      `json:"last_activity_at" db:"last_activity_at"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+	Settings           ChatSettings   `json:"settings"`
+}
+
+type NotificationLevel string
+
+const (
+	NotificationLevelAll      NotificationLevel = "ALL"
+	NotificationLevelMentions NotificationLevel = "MENTIONS"
+	NotificationLevelNone     NotificationLevel = "NONE"
+)
+
+// ChatSettings holds the per-member preferences stored on users_chats.
+type ChatSettings struct {
+	ChatID            string            `json:"chat_id" db:"chat_id"`
+	MutedUntil        *time.Time        `json:"muted_until,omitempty" db:"muted_until"`
+	Archived          bool              `json:"archived" db:"archived"`
+	PinOrder          *int32            `json:"pin_order,omitempty" db:"pin_order"`
+	NotificationLevel NotificationLevel `json:"notification_level" db:"notification_level"`
 }
 
 type ChatWithLastMessage struct {
 	Chat
 	LastMessage      *Message `json:"last_message,omitempty"`
+	Members          []User   `json:"members,omitempty"`
 	UnreadCount      int      `json:"unread_count" db:"unread_count"`
 	ParticipantCount int      `json:"participant_count" db:"participant_count"`
 }
 
+// ChatMembership describes a chat and the role a user has in it. Role is
+// empty when the user is not a member.
+type ChatMembership struct {
+	ChatID     string         `json:"chat_id"`
+	UserID     string         `json:"user_id"`
+	Kind       ChatKind       `json:"kind"`
+	Visibility ChatVisibility `json:"visibility"`
+	Role       ChatRole       `json:"role"`
+}
+
 type UserChat struct {
 	ID        string    `json:"id" db:"id"`
 	UserID    string    `json:"user_id" db:"user_id"`
 	ChatID    string    `json:"chat_id" db:"chat_id"`
+	Role      ChatRole  `json:"role" db:"role"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -34,14 +98,78 @@ type CreateChatResponse struct {
 	ChatId string `json:"chat_id"`
 }
 
+// UpdateChatRequest only touches the fields that are set.
+type UpdateChatRequest struct {
+	UserID             string  `json:"-"`
+	Username           string  `json:"-"`
+	ChatID             string  `json:"chat_id" validate:"required"`
+	Name               *string `json:"name,omitempty" validate:"omitempty,max=50"`
+	Description        *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	AvatarAttachmentID *string `json:"avatar_attachment_id,omitempty" validate:"omitempty,max=255"`
+}
+
+type CreateChannelRequest struct {
+	UserID      string         `json:"-"`
+	Name        string         `json:"name" validate:"required,max=50"`
+	Description string         `json:"description" validate:"max=500"`
+	Visibility  ChatVisibility `json:"visibility" validate:"required,oneof='PUBLIC' 'PRIVATE'"`
+}
+
+type JoinChannelRequest struct {
+	UserID string `json:"-"`
+	ChatID string `json:"chat_id" validate:"required"`
+}
+
+type LeaveChannelRequest struct {
+	UserID string `json:"-"`
+	ChatID string `json:"chat_id" validate:"required"`
+}
+
+// AddChannelMemberRequest is sent by UserID, who must be an admin of the
+// channel, to add MemberID to it.
+type AddChannelMemberRequest struct {
+	UserID   string `json:"-"`
+	ChatID   string `json:"chat_id" validate:"required"`
+	MemberID string `json:"user_id" validate:"required"`
+}
+
+type DiscoverChannelsRequest struct {
+	Pagination
+	UserID string `json:"-"`
+	Query  string `json:"query"`
+}
+
+type DiscoverChannelsResponse struct {
+	Channels []ChatWithLastMessage `json:"channels"`
+	Total    int32                 `json:"total"`
+}
+
 type GetChatRequest struct {
 	ID     string `json:"id" validate:"required"`
 	UserID string `json:"-"`
 }
 
+// ListChatsRequest lists the chats of UserID. ChatIDs limits the list to
+// these chats, if set.
 type ListChatsRequest struct {
 	Pagination
-	UserID string `json:"-"`
+	UserID          string   `json:"-"`
+	ChatIDs         []string `json:"-"`
+	ArchivedOnly    bool     `json:"archived_only"`
+	ExcludeArchived bool     `json:"exclude_archived"`
+}
+
+// UpdateChatSettingsRequest only touches the fields that are set; Unmute
+// clears MutedUntil.
+type UpdateChatSettingsRequest struct {
+	UserID            string             `json:"-"`
+	ChatID            string             `json:"chat_id" validate:"required"`
+	MutedUntil        *time.Time         `json:"muted_until,omitempty"`
+	Unmute            bool               `json:"unmute"`
+	Archived          *bool              `json:"archived,omitempty"`
+	Pinned            *bool              `json:"pinned,omitempty"`
+	PinOrder          *int32             `json:"pin_order,omitempty"`
+	NotificationLevel *NotificationLevel `json:"notification_level,omitempty" validate:"omitempty,oneof='ALL' 'MENTIONS' 'NONE'"`
 }
 
 type ListChatsResponse struct {
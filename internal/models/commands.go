@@ -0,0 +1,89 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	MaxCommandNameLength        = 32
+	MaxCommandDescriptionLength = 200
+	MaxCommandUsageLength       = 200
+	MaxCommandsPerBot           = 25
+
+	// MaxCommandReplyLength limits the text of a reply, and with it the
+	// response a bot may send.
+	MaxCommandReplyLength = 4000
+)
+
+// CommandNamePattern is what may follow the slash. Messages whose first word
+// does not match, such as "/usr/bin is full", are sent as text.
+var CommandNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// CommandReplyVisibility tells who sees the reply to a command.
+type CommandReplyVisibility string
+
+const (
+	// CommandReplyEphemeral replies are streamed to the invoker only and not
+	// stored.
+	CommandReplyEphemeral CommandReplyVisibility = "ephemeral"
+	// CommandReplyPublic replies are posted to the chat.
+	CommandReplyPublic CommandReplyVisibility = "public"
+)
+
+// Command is a slash command. Built-in commands have no BotID.
+type Command struct {
+	ID          string    `json:"id" db:"id"`
+	BotID       string    `json:"bot_id,omitempty" db:"bot_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	Usage       string    `json:"usage" db:"usage"`
+	URL         string    `json:"url,omitempty" db:"url"`
+	Secret      string    `json:"-" db:"secret"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type RegisterCommandRequest struct {
+	OwnerID     string `json:"owner_id"`
+	BotID       string `json:"bot_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Usage       string `json:"usage"`
+	URL         string `json:"url"`
+}
+
+// RegisterCommandResponse holds the secret that signs the calls of the
+// command; it is not returned again.
+type RegisterCommandResponse struct {
+	Command Command `json:"command"`
+	Secret  string  `json:"secret"`
+}
+
+// ListCommandsRequest lists the commands of ChatID or of BotID; exactly one
+// is set.
+type ListCommandsRequest struct {
+	UserID string `json:"-"`
+	ChatID string `json:"chat_id"`
+	BotID  string `json:"bot_id"`
+}
+
+// CommandInvocation is a command sent as a message. It is the body of the
+// call to a bot command.
+type CommandInvocation struct {
+	ID       string `json:"id"`
+	Command  string `json:"command"`
+	Args     string `json:"args"`
+	ChatID   string `json:"chat_id"`
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// CommandReply is what a command answers. Bots respond with it as JSON; an
+// empty text means no reply.
+type CommandReply struct {
+	Text       string                 `json:"text"`
+	Visibility CommandReplyVisibility `json:"visibility"`
+	// SenderID posts public replies: the bot for bot commands, the invoker
+	// for built-in ones.
+	SenderID string `json:"-"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ExternalIdentity links a user to an account of an OpenID Connect issuer.
+type ExternalIdentity struct {
+	ID          string    `json:"id" db:"id"`
+	UserID      string    `json:"user_id" db:"user_id"`
+	Issuer      string    `json:"issuer" db:"issuer"`
+	Subject     string    `json:"subject" db:"subject"`
+	Email       string    `json:"email" db:"email"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at" db:"last_login_at"`
+}
+
+type LoginWithIDTokenRequest struct {
+	IDToken string     `json:"id_token"`
+	Nonce   string     `json:"nonce"`
+	Device  DeviceInfo `json:"device"`
+}
+
+type ExchangeOIDCCodeRequest struct {
+	Code string `json:"code"`
+	// CodeVerifier is the PKCE verifier; RedirectURI defaults to the
+	// configured one.
+	CodeVerifier string     `json:"code_verifier"`
+	RedirectURI  string     `json:"redirect_uri"`
+	Device       DeviceInfo `json:"device"`
+}
@@ -10,12 +10,22 @@ const (
 	MessageStatusDelivered MessageStatus = "DELIVERED"
 )
 
+// MessageKind tells messages written by users apart from the ones the
+// server records on their behalf, such as chat renames.
+type MessageKind string
+
+const (
+	MessageKindUser   MessageKind = "USER"
+	MessageKindSystem MessageKind = "SYSTEM"
+)
+
 type Message struct {
 	ID             string        `json:"id" db:"id"`
 	IdempotencyKey string        `json:"idempotency_key" db:"idempotency_key"`
 	UserID         string        `json:"user_id" db:"user_id"`
 	ChatID         string        `json:"chat_id" db:"chat_id"`
 	Body           string        `json:"content" db:"content"`
+	Kind           MessageKind   `json:"kind" db:"kind"`
 	Status         MessageStatus `json:"status" db:"status"`
 	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
@@ -29,8 +39,11 @@ type SendMessageRequest struct {
 	IdempotencyKey string `json:"idempotency_key" validate:"required"`
 }
 
+// SendMessageResponse holds the ID of the message sent. For a slash command
+// it holds the reply, and the ID of the reply if it was posted.
 type SendMessageResponse struct {
-	MessageID string `json:"message_id"`
+	MessageID string        `json:"message_id"`
+	Reply     *CommandReply `json:"reply,omitempty"`
 }
 
 type ReadMessageRequest struct {
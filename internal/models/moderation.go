@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+const MaxReportDetailsLength = 1000
+
+type ReportReason string
+
+const (
+	ReportReasonSpam          ReportReason = "SPAM"
+	ReportReasonHarassment    ReportReason = "HARASSMENT"
+	ReportReasonInappropriate ReportReason = "INAPPROPRIATE"
+	ReportReasonOther         ReportReason = "OTHER"
+)
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "OPEN"
+	ReportStatusResolved  ReportStatus = "RESOLVED"
+	ReportStatusDismissed ReportStatus = "DISMISSED"
+)
+
+type Report struct {
+	ID             string       `json:"id" db:"id"`
+	ReporterID     string       `json:"reporter_id" db:"reporter_id"`
+	ReportedUserID string       `json:"reported_user_id" db:"reported_user_id"`
+	ChatID         string       `json:"chat_id,omitempty" db:"chat_id"`
+	MessageID      string       `json:"message_id,omitempty" db:"message_id"`
+	Reason         ReportReason `json:"reason" db:"reason"`
+	Details        string       `json:"details" db:"details"`
+	Status         ReportStatus `json:"status" db:"status"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+type BlockedUser struct {
+	User
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+type ListBlockedRequest struct {
+	Pagination
+	UserID string `json:"-"`
+}
+
+type ListBlockedResponse struct {
+	Users []BlockedUser `json:"users"`
+	Total int32         `json:"total"`
+}
+
+type ReportUserRequest struct {
+	ReporterID string       `json:"-"`
+	UserID     string       `json:"user_id"`
+	Reason     ReportReason `json:"reason"`
+	Details    string       `json:"details"`
+}
+
+type ReportMessageRequest struct {
+	ReporterID string       `json:"-"`
+	MessageID  string       `json:"message_id"`
+	Reason     ReportReason `json:"reason"`
+	Details    string       `json:"details"`
+}
+
+type ListReportsRequest struct {
+	Pagination
+	Status ReportStatus `json:"status"`
+}
+
+type ListReportsResponse struct {
+	Reports []Report `json:"reports"`
+	Total   int32    `json:"total"`
+}
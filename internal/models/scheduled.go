@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+const (
+	MaxScheduledPerUser           = 100
+	MaxScheduleAhead              = 365 * 24 * time.Hour
+	MaxListScheduledMessagesLimit = 100
+)
+
+type ScheduledMessageStatus string
+
+const (
+	ScheduledMessagePending  ScheduledMessageStatus = "PENDING"
+	ScheduledMessageSent     ScheduledMessageStatus = "SENT"
+	ScheduledMessageCanceled ScheduledMessageStatus = "CANCELED"
+	// ScheduledMessageSkipped messages were due while no scheduler ran and
+	// were dropped by the catch-up policy.
+	ScheduledMessageSkipped ScheduledMessageStatus = "SKIPPED"
+	// ScheduledMessageFailed messages could not be sent, for example because
+	// the sender left the chat.
+	ScheduledMessageFailed ScheduledMessageStatus = "FAILED"
+)
+
+type ScheduledMessage struct {
+	ID            string                 `json:"id" db:"id"`
+	UserID        string                 `json:"user_id" db:"user_id"`
+	ChatID        string                 `json:"chat_id" db:"chat_id"`
+	Content       string                 `json:"content" db:"content"`
+	SendAt        time.Time              `json:"send_at" db:"send_at"`
+	Status        ScheduledMessageStatus `json:"status" db:"status"`
+	Attempts      int32                  `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time              `json:"-" db:"next_attempt_at"`
+	MessageID     *string                `json:"message_id,omitempty" db:"message_id"`
+	LastError     string                 `json:"last_error,omitempty" db:"last_error"`
+	SentAt        *time.Time             `json:"sent_at,omitempty" db:"sent_at"`
+	CreatedAt     time.Time              `json:"created_at" db:"created_at"`
+}
+
+type ScheduleMessageRequest struct {
+	UserID  string    `json:"-"`
+	ChatID  string    `json:"chat_id"`
+	Content string    `json:"content"`
+	SendAt  time.Time `json:"send_at"`
+}
+
+// ListScheduledRequest lists the scheduled messages of UserID with Status,
+// by default the pending ones, optionally only in ChatID. ChatIDs limits
+// the list to these chats, if set.
+type ListScheduledRequest struct {
+	Pagination
+	UserID  string                 `json:"-"`
+	ChatID  string                 `json:"chat_id"`
+	ChatIDs []string               `json:"-"`
+	Status  ScheduledMessageStatus `json:"status"`
+}
+
+type ListScheduledResponse struct {
+	Messages []ScheduledMessage `json:"messages"`
+	Total    int32              `json:"total"`
+}
+
+// CancelScheduledRequest cancels a pending message of UserID. ChatIDs
+// limits it to messages in these chats, if set.
+type CancelScheduledRequest struct {
+	UserID      string   `json:"-"`
+	ScheduledID string   `json:"scheduled_message_id"`
+	ChatIDs     []string `json:"-"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+const (
+	RecoveryCodeCount      = 10
+	MaxLoginChallengeTries = 5
+)
+
+type TOTP struct {
+	UserID           string     `json:"user_id" db:"user_id"`
+	SecretCiphertext []byte     `json:"-" db:"secret_ciphertext"`
+	ConfirmedAt      *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	LastUsedStep     int64      `json:"-" db:"last_used_step"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+type EnrollTOTPResponse struct {
+	// Secret is the base32 secret for typing into an authenticator app, URI
+	// the otpauth:// URI for a QR code.
+	Secret        string   `json:"secret"`
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type LoginChallenge struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	Device    DeviceInfo `json:"device"`
+	Attempts  int        `json:"attempts" db:"attempts"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type VerifyLoginChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	// Code is a TOTP code or a recovery code.
+	Code string `json:"code"`
+}
@@ -2,32 +2,103 @@ package models
 
 import "time"
 
+const (
+	MaxUsernameLength    = 100
+	MaxEmailLength       = 255
+	MaxDisplayNameLength = 100
+	MaxBioLength         = 500
+	MaxBatchGetUsers     = 100
+	MaxSearchUsersLimit  = 50
+)
+
 type User struct {
-	ID           string    `json:"id" db:"id"`
-	Username     string    `json:"username" db:"username"`
-	Email        string    `json:"email" db:"email"`
-	PasswordHash string    `json:"-" db:"password_hash"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	ID                 string     `json:"id" db:"id"`
+	Username           string     `json:"username" db:"username"`
+	Email              string     `json:"email" db:"email"`
+	PasswordHash       string     `json:"-" db:"password_hash"`
+	DisplayName        string     `json:"display_name" db:"display_name"`
+	Bio                string     `json:"bio" db:"bio"`
+	AvatarAttachmentID string     `json:"avatar_attachment_id" db:"avatar_attachment_id"`
+	Discoverable       bool       `json:"discoverable" db:"discoverable"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	IsAdmin            bool       `json:"is_admin" db:"is_admin"`
+	BotOwnerID         string     `json:"bot_owner_id,omitempty" db:"bot_owner_id"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IsBot reports whether the user is a bot, which authenticates with API keys
+// only.
+func (u User) IsBot() bool {
+	return u.BotOwnerID != ""
 }
 
+// CreateUserRequest is validated by the gRPC handler: an email as accepted by
+// net/mail and a password of MinPasswordLength to MaxPasswordBytes.
 type CreateUserRequest struct {
-	Username string `json:"username" validate:"required"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Username string     `json:"username"`
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+	Device   DeviceInfo `json:"device"`
 }
 
 type CreateUserResponse struct {
-	Token  string `json:"token"`
-	UserID string `json:"user_id"`
+	Token          string    `json:"token"`
+	TokenExpiresAt time.Time `json:"token_expires_at"`
+	RefreshToken   string    `json:"refresh_token"`
+	UserID         string    `json:"user_id"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+	Device   DeviceInfo `json:"device"`
 }
 
+// LoginResponse holds either a session or, for accounts with two-factor
+// authentication, a ChallengeToken to complete with VerifyLoginChallenge.
 type LoginResponse struct {
-	Token  string `json:"token"`
-	UserID string `json:"user_id"`
+	Token          string    `json:"token"`
+	TokenExpiresAt time.Time `json:"token_expires_at"`
+	RefreshToken   string    `json:"refresh_token"`
+	UserID         string    `json:"user_id"`
+
+	ChallengeToken     string    `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt time.Time `json:"challenge_expires_at,omitempty"`
+}
+
+type UpdateProfileRequest struct {
+	UserID             string  `json:"user_id"`
+	Username           *string `json:"username"`
+	DisplayName        *string `json:"display_name"`
+	Bio                *string `json:"bio"`
+	AvatarAttachmentID *string `json:"avatar_attachment_id"`
+	Discoverable       *bool   `json:"discoverable"`
+}
+
+type SearchUsersRequest struct {
+	UserID string `json:"-"`
+	Query  string `json:"query"`
+	Cursor string `json:"cursor"`
+	Limit  int32  `json:"limit"`
+}
+
+type SearchUsersResponse struct {
+	Users      []User `json:"users"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// UserSearchCursor is the position of the last returned result. Results are
+// ordered by Rank (0 for prefix matches, 1 for fuzzy ones), then Score
+// descending, then ID.
+type UserSearchCursor struct {
+	Rank  int32   `json:"r"`
+	Score float32 `json:"s"`
+	ID    string  `json:"id"`
+}
+
+type UserSearchResult struct {
+	User
+	Rank  int32
+	Score float32
 }
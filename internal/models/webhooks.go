@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	MaxWebhookURLLength           = 2048
+	MaxWebhooksPerTarget          = 10
+	MaxListWebhookDeliveriesLimit = 100
+)
+
+// Events webhooks can subscribe to.
+const (
+	WebhookEventMessageCreated = "message.created"
+	WebhookEventMemberJoined   = "member.joined"
+	WebhookEventMemberLeft     = "member.left"
+	WebhookEventChatUpdated    = "chat.updated"
+)
+
+var WebhookEvents = []string{
+	WebhookEventMessageCreated,
+	WebhookEventMemberJoined,
+	WebhookEventMemberLeft,
+	WebhookEventChatUpdated,
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	// WebhookDeliveryDead is the dead-letter state of deliveries that failed
+	// every attempt.
+	WebhookDeliveryDead WebhookDeliveryStatus = "DEAD"
+)
+
+// Webhook posts events of one chat, or of every chat its bot is in, to URL.
+// Exactly one of ChatID and BotID is set.
+type Webhook struct {
+	ID        string    `json:"id" db:"id"`
+	OwnerID   string    `json:"owner_id" db:"owner_id"`
+	ChatID    string    `json:"chat_id,omitempty" db:"chat_id"`
+	BotID     string    `json:"bot_id,omitempty" db:"bot_id"`
+	URL       string    `json:"url" db:"url"`
+	Secret    string    `json:"-" db:"secret"`
+	Events    []string  `json:"events" db:"events"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateWebhookRequest struct {
+	UserID string   `json:"-"`
+	ChatID string   `json:"chat_id"`
+	BotID  string   `json:"bot_id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// CreateWebhookResponse returns the secret receivers verify signatures
+// with. It is not returned again.
+type CreateWebhookResponse struct {
+	Webhook Webhook `json:"webhook"`
+	Secret  string  `json:"secret"`
+}
+
+type ListWebhooksRequest struct {
+	UserID string `json:"-"`
+	ChatID string `json:"chat_id"`
+	BotID  string `json:"bot_id"`
+}
+
+// WebhookEvent is something that happened in a chat, posted to the
+// webhooks subscribed to its type.
+type WebhookEvent struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"event"`
+	ChatID    string         `json:"chat_id"`
+	ActorID   string         `json:"actor_id"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      map[string]any `json:"data"`
+}
+
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	WebhookID      string                `json:"webhook_id" db:"webhook_id"`
+	Event          string                `json:"event" db:"event"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int32                 `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	LastStatusCode int32                 `json:"last_status_code" db:"last_status_code"`
+	LastError      string                `json:"last_error" db:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+}
+
+// WebhookJob is a claimed delivery with where to send it.
+type WebhookJob struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Pagination
+	UserID    string                `json:"-"`
+	WebhookID string                `json:"webhook_id"`
+	Status    WebhookDeliveryStatus `json:"status"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int32             `json:"total"`
+}
+
+const (
+	MaxIncomingWebhooksPerChat = 10
+	// MaxIncomingWebhookBodySize limits the JSON body posted to an incoming
+	// webhook, in bytes.
+	MaxIncomingWebhookBodySize = 64 << 10
+)
+
+// IncomingWebhook posts the requests to its URL into ChatID as UserID, a
+// bot that shows as Name.
+type IncomingWebhook struct {
+	ID          string     `json:"id" db:"id"`
+	ChatID      string     `json:"chat_id" db:"chat_id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	Name        string     `json:"name" db:"name"`
+	CreatedBy   string     `json:"created_by" db:"created_by"`
+	TokenPrefix string     `json:"token_prefix" db:"token_prefix"`
+	TokenHash   string     `json:"-" db:"token_hash"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+type CreateIncomingWebhookRequest struct {
+	UserID string `json:"-"`
+	ChatID string `json:"chat_id"`
+	Name   string `json:"name"`
+}
+
+// CreateIncomingWebhookResponse holds the only copy of the webhook URL;
+// just the hash of its token is stored.
+type CreateIncomingWebhookResponse struct {
+	IncomingWebhook IncomingWebhook `json:"incoming_webhook"`
+	URL             string          `json:"url"`
+}
+
+// IncomingWebhookMessage is a message posted to an incoming webhook.
+type IncomingWebhookMessage struct {
+	Text           string `json:"text"`
+	IdempotencyKey string `json:"-"`
+}
@@ -0,0 +1,195 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+// touchInterval limits how often last_used_at is written for a busy key.
+const touchInterval = time.Minute
+
+const apiKeyColumns = "id, bot_id, name, key_prefix, key_hash, scopes, chat_ids, last_used_at, revoked_at, created_at"
+
+type APIKeysRepository interface {
+	Create(ctx context.Context, key models.APIKey) (models.APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (models.APIKey, error)
+	ListByBot(ctx context.Context, botID string) ([]models.APIKey, error)
+	CountActive(ctx context.Context, botID string) (int, error)
+	Revoke(ctx context.Context, botID, keyID string) (bool, error)
+	Touch(ctx context.Context, keyID string) error
+}
+
+type apiKeysRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewAPIKeysRepository(reader, writer *pgxpool.Pool) APIKeysRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &apiKeysRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+func (r *apiKeysRepository) Create(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	slog.Info("Create API key", "botID", key.BotID, "name", key.Name)
+
+	key.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+	if key.ChatIDs == nil {
+		key.ChatIDs = []string{}
+	}
+
+	query := `INSERT INTO api_keys (id, bot_id, name, key_prefix, key_hash, scopes, chat_ids)
+			  VALUES (@id, @bot_id, @name, @key_prefix, @key_hash, @scopes, @chat_ids)
+			  RETURNING created_at`
+	args := pgx.NamedArgs{
+		"id":         key.ID,
+		"bot_id":     key.BotID,
+		"name":       key.Name,
+		"key_prefix": key.KeyPrefix,
+		"key_hash":   key.KeyHash,
+		"scopes":     key.Scopes,
+		"chat_ids":   key.ChatIDs,
+	}
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&key.CreatedAt); err != nil {
+		slog.Error("Error creating API key", "error", err)
+		return models.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// GetByHash returns the key with keyHash, revoked or not. It reads from the
+// writer, so a revoked key stops working at once.
+func (r *apiKeysRepository) GetByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	slog.Info("Get API key by hash")
+
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = @key_hash"
+	args := pgx.NamedArgs{
+		"key_hash": keyHash,
+	}
+
+	key, err := scanAPIKey(r.writer.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("API key not found")
+			return models.APIKey{}, nil
+		}
+		slog.Error("Error getting API key", "error", err)
+		return models.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// ListByBot returns the keys of botID that are not revoked, newest first.
+func (r *apiKeysRepository) ListByBot(ctx context.Context, botID string) ([]models.APIKey, error) {
+	slog.Info("List API keys", "botID", botID)
+
+	query := "SELECT " + apiKeyColumns + ` FROM api_keys
+			  WHERE bot_id = @bot_id AND revoked_at IS NULL
+			  ORDER BY id DESC`
+	args := pgx.NamedArgs{
+		"bot_id": botID,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error listing API keys", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			slog.Error("Error scanning API key", "error", err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating API keys", "error", err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeysRepository) CountActive(ctx context.Context, botID string) (int, error) {
+	slog.Info("Count API keys", "botID", botID)
+
+	query := "SELECT COUNT(*) FROM api_keys WHERE bot_id = @bot_id AND revoked_at IS NULL"
+	args := pgx.NamedArgs{
+		"bot_id": botID,
+	}
+
+	var count int
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&count); err != nil {
+		slog.Error("Error counting API keys", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Revoke revokes keyID of botID. It reports false if there was no such
+// active key.
+func (r *apiKeysRepository) Revoke(ctx context.Context, botID, keyID string) (bool, error) {
+	slog.Info("Revoke API key", "botID", botID, "keyID", keyID)
+
+	query := `UPDATE api_keys
+			  SET revoked_at = NOW()
+			  WHERE id = @id AND bot_id = @bot_id AND revoked_at IS NULL`
+	args := pgx.NamedArgs{
+		"id":     keyID,
+		"bot_id": botID,
+	}
+
+	tag, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error revoking API key", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Touch records that keyID was used, at most once per touchInterval.
+func (r *apiKeysRepository) Touch(ctx context.Context, keyID string) error {
+	query := `UPDATE api_keys
+			  SET last_used_at = NOW()
+			  WHERE id = @id AND (last_used_at IS NULL OR last_used_at < NOW() - @interval::interval)`
+	args := pgx.NamedArgs{
+		"id":       keyID,
+		"interval": touchInterval.String(),
+	}
+
+	if _, err := r.writer.Exec(ctx, query, args); err != nil {
+		slog.Error("Error touching API key", "keyID", keyID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func scanAPIKey(row pgx.Row) (models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID, &key.BotID, &key.Name, &key.KeyPrefix, &key.KeyHash, &key.Scopes, &key.ChatIDs,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	return key, err
+}
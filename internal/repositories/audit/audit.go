@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+type AuditRepository interface {
+	Record(ctx context.Context, event models.AuditEvent) error
+}
+
+type auditRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewAuditRepository(reader, writer *pgxpool.Pool) AuditRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &auditRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+func (r *auditRepository) Record(ctx context.Context, event models.AuditEvent) error {
+	slog.Info("Record audit event", "action", event.Action, "userID", event.UserID)
+	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
+
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	query := `INSERT INTO audit_events (id, action, user_id, ip_address, details)
+			  VALUES (@id, @action, NULLIF(@user_id, ''), @ip_address, @details)`
+	args := pgx.NamedArgs{
+		"id":         id.String(),
+		"action":     event.Action,
+		"user_id":    event.UserID,
+		"ip_address": event.IPAddress,
+		"details":    details,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error recording audit event", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/pgutil"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/oklog/ulid/v2"
@@ -16,10 +17,44 @@ import (
 type ChatsRepository interface {
 	Create(ctx context.Context, req models.Chat) (string, error)
 	List(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error)
-	Get(ctx context.Context, req models.GetChatRequest) (models.Chat, error)
-	AddUserToChat(ctx context.Context, userID, chatID string) error
+	Get(ctx context.Context, req models.GetChatRequest) (models.ChatWithLastMessage, error)
+	Update(ctx context.Context, req models.UpdateChatRequest) error
+	AddUserToChat(ctx context.Context, userID, chatID string, role models.ChatRole) error
 	RemoveUserFromChat(ctx context.Context, userID, chatID string) error
 	GetChatUsers(ctx context.Context, chatID string) ([]models.User, error)
+	GetMembership(ctx context.Context, chatID, userID string) (models.ChatMembership, error)
+	CountMembersWithRole(ctx context.Context, chatID string, role models.ChatRole) (int, error)
+	DiscoverChannels(ctx context.Context, req models.DiscoverChannelsRequest) (models.DiscoverChannelsResponse, error)
+	GetUsersByChatIDs(ctx context.Context, chatIDs []string) (map[string][]models.User, error)
+	UpdateSettings(ctx context.Context, req models.UpdateChatSettingsRequest) (models.ChatSettings, error)
+}
+
+// chatWithLastMessageSelect reads a chat as seen by @userID, so callers must
+// filter on uc.user_id.
+const chatWithLastMessageSelect = `SELECT c.id, c.name, c.kind, c.visibility, c.description, c.avatar_attachment_id,
+		c.created_at, c.updated_at, c.last_activity_at,
+		uc.muted_until, uc.archived, uc.pin_order, uc.notification_level,
+		m.id as last_message_id, m.user_id as last_message_user_id, m.content as last_content,
+		m.created_at as last_message_created_at, m.status as last_message_status, m.kind as last_message_kind,
+		u.username as last_message_username,
+		(SELECT COUNT(*) FROM messages m2
+		 WHERE m2.chat_id = c.id AND m2.status != 'READ' AND m2.user_id != @userID) as unread_count,
+		c.member_count as participant_count
+	  FROM chats c
+	  JOIN users_chats uc ON c.id = uc.chat_id
+	  LEFT JOIN messages m ON m.id = c.last_message_id
+	  LEFT JOIN users u ON m.user_id = u.id`
+
+// listChatsOrderBy puts pinned chats first, then the most recently active.
+// The memberships of the user are read from the covering
+// idx_users_chats_inbox and joined to chats, so only their chats are sorted.
+const listChatsOrderBy = "uc.pin_order IS NULL, uc.pin_order, c.last_activity_at DESC, c.id DESC"
+
+func listChatsQuery(whereClause string) string {
+	return chatWithLastMessageSelect + `
+		  WHERE uc.user_id = @userID` + whereClause + `
+		  ORDER BY ` + listChatsOrderBy + `
+		  LIMIT @limit OFFSET @offset`
 }
 
 type chatsRepository struct {
@@ -41,10 +76,25 @@ func (r *chatsRepository) Create(ctx context.Context, req models.Chat) (string,
 	slog.Info("Create chat", "name", req.Name)
 	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
 
-	query := "INSERT INTO chats (id, name) VALUES (@id, @name)"
+	kind := req.Kind
+	if kind == "" {
+		kind = models.ChatKindChat
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = models.ChatVisibilityPrivate
+	}
+
+	query := `INSERT INTO chats (id, name, kind, visibility, description, avatar_attachment_id)
+			  VALUES (@id, @name, @kind, @visibility, @description, @avatar_attachment_id)`
 	args := pgx.NamedArgs{
-		"id":   id.String(),
-		"name": req.Name,
+		"id":                   id.String(),
+		"name":                 req.Name,
+		"kind":                 kind,
+		"visibility":           visibility,
+		"description":          req.Description,
+		"avatar_attachment_id": req.AvatarAttachmentID,
 	}
 	_, err := r.writer.Exec(ctx, query, args)
 	if err != nil {
@@ -61,7 +111,7 @@ func (r *chatsRepository) List(ctx context.Context, req models.ListChatsRequest)
 		total   int32
 		page    = req.Page
 		limit   = req.Limit
-		orderBy = "created_at DESC"
+		orderBy = listChatsOrderBy
 	)
 
 	if page < 1 {
@@ -72,30 +122,25 @@ func (r *chatsRepository) List(ctx context.Context, req models.ListChatsRequest)
 		limit = 20
 	}
 
-	slog.Info("Listing chats", "page", page, "limit", limit, "orderBy", orderBy, "userID", req.UserID)
+	slog.Info("Listing chats", "page", page, "limit", limit, "orderBy", orderBy, "userID", req.UserID,
+		"archivedOnly", req.ArchivedOnly, "excludeArchived", req.ExcludeArchived)
+
+	whereClause := " AND (@chat_ids::text[] IS NULL OR uc.chat_id = ANY(@chat_ids::text[]))"
+	if req.ArchivedOnly {
+		whereClause += " AND uc.archived"
+	}
+	if req.ExcludeArchived {
+		whereClause += " AND NOT uc.archived"
+	}
 
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		query := `SELECT c.id, c.name, c.created_at, c.updated_at,
-					m.id as last_message_id, m.content as last_content, 
-					m.created_at as last_message_created_at, m.status as last_message_status,
-					u.username as last_message_username,
-					(SELECT COUNT(*) FROM messages m2 
-					 WHERE m2.chat_id = c.id AND m2.status != 'READ' AND m2.user_id != @userID) as unread_count,
-					(SELECT COUNT(DISTINCT uc2.user_id) FROM users_chats uc2 WHERE uc2.chat_id = c.id) as participant_count
-				  FROM chats c
-				  JOIN users_chats uc ON c.id = uc.chat_id
-				  LEFT JOIN messages m ON c.id = m.chat_id AND m.created_at = (
-					  SELECT MAX(m3.created_at) FROM messages m3 WHERE m3.chat_id = c.id
-				  )
-				  LEFT JOIN users u ON m.user_id = u.id
-				  WHERE uc.user_id = @userID
-				  ORDER BY c.` + orderBy + `
-				  LIMIT @limit OFFSET @offset`
+		query := listChatsQuery(whereClause)
 		args := pgx.NamedArgs{
-			"userID": req.UserID,
-			"limit":  limit,
-			"offset": (page - 1) * limit,
+			"userID":   req.UserID,
+			"chat_ids": pgutil.NullIfEmpty(req.ChatIDs),
+			"limit":    limit,
+			"offset":   (page - 1) * limit,
 		}
 		rows, err := r.reader.Query(ctx, query, args)
 		if err != nil {
@@ -106,32 +151,11 @@ func (r *chatsRepository) List(ctx context.Context, req models.ListChatsRequest)
 
 		result := []models.ChatWithLastMessage{}
 		for rows.Next() {
-			var chat models.ChatWithLastMessage
-			var lastMessage models.Message
-			var lastMessageID, lastContent, lastMessageUsername *string
-			var lastMessageCreatedAt *time.Time
-			var lastMessageStatus *string
-
-			if err := rows.Scan(
-				&chat.ID, &chat.Name, &chat.CreatedAt, &chat.UpdatedAt,
-				&lastMessageID, &lastContent, &lastMessageCreatedAt, &lastMessageStatus,
-				&lastMessageUsername, &chat.UnreadCount, &chat.ParticipantCount,
-			); err != nil {
+			chat, err := scanChatWithLastMessage(rows)
+			if err != nil {
 				slog.Error("Error scanning chat", "error", err)
 				return err
 			}
-
-			if lastMessageID != nil {
-				lastMessage.ID = *lastMessageID
-				lastMessage.Body = *lastContent
-				lastMessage.CreatedAt = *lastMessageCreatedAt
-				lastMessage.Status = models.MessageStatus(*lastMessageStatus)
-				if lastMessageUsername != nil {
-					lastMessage.User = &models.User{Username: *lastMessageUsername}
-				}
-				chat.LastMessage = &lastMessage
-			}
-
 			result = append(result, chat)
 		}
 		if err := rows.Err(); err != nil {
@@ -146,9 +170,10 @@ func (r *chatsRepository) List(ctx context.Context, req models.ListChatsRequest)
 		query := `SELECT COUNT(DISTINCT c.id)
 				  FROM chats c
 				  JOIN users_chats uc ON c.id = uc.chat_id
-				  WHERE uc.user_id = @userID`
+				  WHERE uc.user_id = @userID` + whereClause
 		args := pgx.NamedArgs{
-			"userID": req.UserID,
+			"userID":   req.UserID,
+			"chat_ids": pgutil.NullIfEmpty(req.ChatIDs),
 		}
 		if err := r.reader.QueryRow(ctx, query, args).Scan(&total); err != nil {
 			slog.Error("Error counting chats", "error", err)
@@ -167,43 +192,78 @@ func (r *chatsRepository) List(ctx context.Context, req models.ListChatsRequest)
 	}, nil
 }
 
-func (r *chatsRepository) Get(ctx context.Context, req models.GetChatRequest) (models.Chat, error) {
+func (r *chatsRepository) Get(ctx context.Context, req models.GetChatRequest) (models.ChatWithLastMessage, error) {
 	slog.Info("Get chat", "id", req.ID, "userID", req.UserID)
 
-	query := `SELECT c.id, c.name, c.created_at, c.updated_at
-			  FROM chats c
-			  JOIN users_chats uc ON c.id = uc.chat_id
+	query := chatWithLastMessageSelect + `
 			  WHERE c.id = @id AND uc.user_id = @userID`
 	args := pgx.NamedArgs{
 		"id":     req.ID,
 		"userID": req.UserID,
 	}
 
-	var chat models.Chat
-	if err := r.reader.QueryRow(ctx, query, args).Scan(
-		&chat.ID, &chat.Name, &chat.CreatedAt, &chat.UpdatedAt,
-	); err != nil {
+	chat, err := scanChatWithLastMessage(r.reader.QueryRow(ctx, query, args))
+	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Info("Chat not found", "id", req.ID)
-			return models.Chat{}, nil
+			return models.ChatWithLastMessage{}, nil
 		}
 		slog.Error("Error getting chat", "error", err)
-		return models.Chat{}, err
+		return models.ChatWithLastMessage{}, err
 	}
 
 	return chat, nil
 }
 
-func (r *chatsRepository) AddUserToChat(ctx context.Context, userID, chatID string) error {
-	slog.Info("Add user to chat", "userID", userID, "chatID", chatID)
+func (r *chatsRepository) Update(ctx context.Context, req models.UpdateChatRequest) error {
+	slog.Info("Update chat", "chatID", req.ChatID, "userID", req.UserID)
+
+	setClause := "updated_at = CURRENT_TIMESTAMP"
+	args := pgx.NamedArgs{
+		"id": req.ChatID,
+	}
+
+	if req.Name != nil {
+		setClause += ", name = @name"
+		args["name"] = *req.Name
+	}
+
+	if req.Description != nil {
+		setClause += ", description = @description"
+		args["description"] = *req.Description
+	}
+
+	if req.AvatarAttachmentID != nil {
+		setClause += ", avatar_attachment_id = @avatar_attachment_id"
+		args["avatar_attachment_id"] = *req.AvatarAttachmentID
+	}
+
+	query := "UPDATE chats SET " + setClause + " WHERE id = @id"
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error updating chat", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddUserToChat adds userID to chatID with role. Adding a member again is a
+// no-op that keeps their role, so concurrent joins don't fail.
+func (r *chatsRepository) AddUserToChat(ctx context.Context, userID, chatID string, role models.ChatRole) error {
+	slog.Info("Add user to chat", "userID", userID, "chatID", chatID, "role", role)
 
 	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
 
-	query := "INSERT INTO users_chats (id, user_id, chat_id) VALUES (@id, @user_id, @chat_id)"
+	query := `INSERT INTO users_chats (id, user_id, chat_id, role)
+			  VALUES (@id, @user_id, @chat_id, @role)
+			  ON CONFLICT (user_id, chat_id) DO NOTHING`
 	args := pgx.NamedArgs{
 		"id":      id.String(),
 		"user_id": userID,
 		"chat_id": chatID,
+		"role":    role,
 	}
 
 	_, err := r.writer.Exec(ctx, query, args)
@@ -236,7 +296,7 @@ func (r *chatsRepository) RemoveUserFromChat(ctx context.Context, userID, chatID
 func (r *chatsRepository) GetChatUsers(ctx context.Context, chatID string) ([]models.User, error) {
 	slog.Info("Get chat users", "chatID", chatID)
 
-	query := `SELECT u.id, u.username, u.email, u.created_at, u.updated_at
+	query := `SELECT u.id, u.username, u.email, u.display_name, u.bio, u.avatar_attachment_id, u.discoverable, u.created_at, u.updated_at
 			  FROM users u
 			  JOIN users_chats uc ON u.id = uc.user_id
 			  WHERE uc.chat_id = @chat_id
@@ -256,7 +316,8 @@ func (r *chatsRepository) GetChatUsers(ctx context.Context, chatID string) ([]mo
 	for rows.Next() {
 		var user models.User
 		if err := rows.Scan(
-			&user.ID, &user.Username, &user.Email, &user.CreatedAt, &user.UpdatedAt,
+			&user.ID, &user.Username, &user.Email, &user.DisplayName, &user.Bio, &user.AvatarAttachmentID,
+			&user.Discoverable, &user.CreatedAt, &user.UpdatedAt,
 		); err != nil {
 			slog.Error("Error scanning user", "error", err)
 			return nil, err
@@ -271,3 +332,270 @@ func (r *chatsRepository) GetChatUsers(ctx context.Context, chatID string) ([]mo
 
 	return users, nil
 }
+
+func (r *chatsRepository) UpdateSettings(ctx context.Context, req models.UpdateChatSettingsRequest) (models.ChatSettings, error) {
+	slog.Info("Update chat settings", "chatID", req.ChatID, "userID", req.UserID)
+
+	setClause := "updated_at = CURRENT_TIMESTAMP"
+	args := pgx.NamedArgs{
+		"chat_id": req.ChatID,
+		"user_id": req.UserID,
+	}
+
+	if req.Unmute {
+		setClause += ", muted_until = NULL"
+	} else if req.MutedUntil != nil {
+		setClause += ", muted_until = @muted_until"
+		args["muted_until"] = *req.MutedUntil
+	}
+
+	if req.Archived != nil {
+		setClause += ", archived = @archived"
+		args["archived"] = *req.Archived
+	}
+
+	if req.Pinned != nil && !*req.Pinned {
+		setClause += ", pin_order = NULL"
+	} else if req.PinOrder != nil {
+		setClause += ", pin_order = @pin_order"
+		args["pin_order"] = *req.PinOrder
+	} else if req.Pinned != nil {
+		setClause += ", pin_order = COALESCE(pin_order, 0)"
+	}
+
+	if req.NotificationLevel != nil {
+		setClause += ", notification_level = @notification_level"
+		args["notification_level"] = *req.NotificationLevel
+	}
+
+	query := `UPDATE users_chats SET ` + setClause + `
+			  WHERE chat_id = @chat_id AND user_id = @user_id
+			  RETURNING chat_id, muted_until, archived, pin_order, notification_level`
+
+	var settings models.ChatSettings
+	err := r.writer.QueryRow(ctx, query, args).Scan(
+		&settings.ChatID, &settings.MutedUntil, &settings.Archived, &settings.PinOrder, &settings.NotificationLevel,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Chat membership not found", "chatID", req.ChatID, "userID", req.UserID)
+			return models.ChatSettings{}, nil
+		}
+		slog.Error("Error updating chat settings", "error", err)
+		return models.ChatSettings{}, err
+	}
+
+	return settings, nil
+}
+
+func (r *chatsRepository) GetUsersByChatIDs(ctx context.Context, chatIDs []string) (map[string][]models.User, error) {
+	slog.Info("Get users by chat IDs", "chats", len(chatIDs))
+
+	result := make(map[string][]models.User, len(chatIDs))
+	if len(chatIDs) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT uc.chat_id, u.id, u.username, u.email, u.display_name, u.bio, u.avatar_attachment_id, u.discoverable, u.created_at, u.updated_at
+			  FROM users u
+			  JOIN users_chats uc ON u.id = uc.user_id
+			  WHERE uc.chat_id = ANY(@chat_ids)
+			  ORDER BY uc.chat_id, u.username`
+	args := pgx.NamedArgs{
+		"chat_ids": chatIDs,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error getting users by chat IDs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var chatID string
+		var user models.User
+		if err := rows.Scan(
+			&chatID, &user.ID, &user.Username, &user.Email, &user.DisplayName, &user.Bio, &user.AvatarAttachmentID,
+			&user.Discoverable, &user.CreatedAt, &user.UpdatedAt,
+		); err != nil {
+			slog.Error("Error scanning user", "error", err)
+			return nil, err
+		}
+		result[chatID] = append(result[chatID], user)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating users", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func scanChatWithLastMessage(row pgx.Row) (models.ChatWithLastMessage, error) {
+	var chat models.ChatWithLastMessage
+	var lastMessageID, lastMessageUserID, lastContent, lastMessageUsername *string
+	var lastMessageCreatedAt *time.Time
+	var lastMessageStatus, lastMessageKind *string
+
+	if err := row.Scan(
+		&chat.ID, &chat.Name, &chat.Kind, &chat.Visibility, &chat.Description, &chat.AvatarAttachmentID,
+		&chat.CreatedAt, &chat.UpdatedAt, &chat.LastActivityAt,
+		&chat.Settings.MutedUntil, &chat.Settings.Archived, &chat.Settings.PinOrder, &chat.Settings.NotificationLevel,
+		&lastMessageID, &lastMessageUserID, &lastContent, &lastMessageCreatedAt, &lastMessageStatus,
+		&lastMessageKind, &lastMessageUsername, &chat.UnreadCount, &chat.ParticipantCount,
+	); err != nil {
+		return models.ChatWithLastMessage{}, err
+	}
+	chat.Settings.ChatID = chat.ID
+
+	if lastMessageID != nil {
+		lastMessage := models.Message{
+			ID:        *lastMessageID,
+			ChatID:    chat.ID,
+			UserID:    *lastMessageUserID,
+			Body:      *lastContent,
+			CreatedAt: *lastMessageCreatedAt,
+			Status:    models.MessageStatus(*lastMessageStatus),
+			Kind:      models.MessageKind(*lastMessageKind),
+		}
+		if lastMessageUsername != nil {
+			lastMessage.User = &models.User{ID: *lastMessageUserID, Username: *lastMessageUsername}
+		}
+		chat.LastMessage = &lastMessage
+	}
+
+	return chat, nil
+}
+
+func (r *chatsRepository) GetMembership(ctx context.Context, chatID, userID string) (models.ChatMembership, error) {
+	slog.Info("Get chat membership", "chatID", chatID, "userID", userID)
+
+	query := `SELECT c.id, c.kind, c.visibility, uc.role
+			  FROM chats c
+			  LEFT JOIN users_chats uc ON uc.chat_id = c.id AND uc.user_id = @user_id
+			  WHERE c.id = @chat_id`
+	args := pgx.NamedArgs{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	membership := models.ChatMembership{UserID: userID}
+	var role *models.ChatRole
+	if err := r.reader.QueryRow(ctx, query, args).Scan(
+		&membership.ChatID, &membership.Kind, &membership.Visibility, &role,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Chat not found", "id", chatID)
+			return models.ChatMembership{}, nil
+		}
+		slog.Error("Error getting chat membership", "error", err)
+		return models.ChatMembership{}, err
+	}
+
+	if role != nil {
+		membership.Role = *role
+	}
+
+	return membership, nil
+}
+
+func (r *chatsRepository) CountMembersWithRole(ctx context.Context, chatID string, role models.ChatRole) (int, error) {
+	query := "SELECT COUNT(*) FROM users_chats WHERE chat_id = @chat_id AND role = @role"
+	args := pgx.NamedArgs{
+		"chat_id": chatID,
+		"role":    role,
+	}
+
+	var count int
+	if err := r.reader.QueryRow(ctx, query, args).Scan(&count); err != nil {
+		slog.Error("Error counting chat members", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *chatsRepository) DiscoverChannels(ctx context.Context, req models.DiscoverChannelsRequest) (models.DiscoverChannelsResponse, error) {
+	var (
+		channels []models.ChatWithLastMessage
+		total    int32
+		page     = req.Page
+		limit    = req.Limit
+	)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 20
+	}
+
+	slog.Info("Discovering channels", "page", page, "limit", limit, "query", req.Query, "userID", req.UserID)
+
+	whereClause := " WHERE c.kind = 'CHANNEL' AND c.visibility = 'PUBLIC'"
+	args := pgx.NamedArgs{
+		"limit":  limit,
+		"offset": (page - 1) * limit,
+	}
+
+	if req.Query != "" {
+		whereClause += " AND c.name ILIKE @query"
+		args["query"] = "%" + pgutil.EscapeLike(req.Query) + "%"
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		query := `SELECT c.id, c.name, c.kind, c.visibility, c.description, c.avatar_attachment_id,
+					c.created_at, c.updated_at, c.last_activity_at, c.member_count
+				  FROM chats c` + whereClause + `
+				  ORDER BY c.member_count DESC, c.id
+				  LIMIT @limit OFFSET @offset`
+
+		rows, err := r.reader.Query(ctx, query, args)
+		if err != nil {
+			slog.Error("Error discovering channels", "error", err)
+			return err
+		}
+		defer rows.Close()
+
+		result := []models.ChatWithLastMessage{}
+		for rows.Next() {
+			var channel models.ChatWithLastMessage
+			if err := rows.Scan(
+				&channel.ID, &channel.Name, &channel.Kind, &channel.Visibility, &channel.Description, &channel.AvatarAttachmentID,
+				&channel.CreatedAt, &channel.UpdatedAt, &channel.LastActivityAt, &channel.ParticipantCount,
+			); err != nil {
+				slog.Error("Error scanning channel", "error", err)
+				return err
+			}
+			result = append(result, channel)
+		}
+		if err := rows.Err(); err != nil {
+			slog.Error("Error iterating channels", "error", err)
+			return err
+		}
+		channels = result
+		return nil
+	})
+
+	g.Go(func() error {
+		query := "SELECT COUNT(*) FROM chats c" + whereClause
+		if err := r.reader.QueryRow(ctx, query, args).Scan(&total); err != nil {
+			slog.Error("Error counting channels", "error", err)
+			return err
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return models.DiscoverChannelsResponse{}, err
+	}
+
+	return models.DiscoverChannelsResponse{
+		Channels: channels,
+		Total:    total,
+	}, nil
+}
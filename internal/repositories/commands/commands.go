@@ -0,0 +1,184 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+// ErrCommandTaken means the bot already has a command with the name.
+var ErrCommandTaken = errors.New("command already registered")
+
+const commandColumns = "c.id, c.bot_id, c.name, c.description, c.usage, c.url, c.secret, c.created_at"
+
+type CommandsRepository interface {
+	Create(ctx context.Context, command models.Command) (models.Command, error)
+	ListByBot(ctx context.Context, botID string) ([]models.Command, error)
+	ListForChat(ctx context.Context, chatID string) ([]models.Command, error)
+	FindForChat(ctx context.Context, chatID, name string) (models.Command, error)
+	Delete(ctx context.Context, botID, commandID string) (bool, error)
+}
+
+type commandsRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewCommandsRepository(reader, writer *pgxpool.Pool) CommandsRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &commandsRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+func (r *commandsRepository) Create(ctx context.Context, command models.Command) (models.Command, error) {
+	slog.Info("Create command", "botID", command.BotID, "name", command.Name)
+
+	command.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+
+	query := `INSERT INTO bot_commands (id, bot_id, name, description, usage, url, secret)
+			  VALUES (@id, @bot_id, @name, @description, @usage, @url, @secret)
+			  RETURNING created_at`
+	args := pgx.NamedArgs{
+		"id":          command.ID,
+		"bot_id":      command.BotID,
+		"name":        command.Name,
+		"description": command.Description,
+		"usage":       command.Usage,
+		"url":         command.URL,
+		"secret":      command.Secret,
+	}
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&command.CreatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return models.Command{}, ErrCommandTaken
+		}
+		slog.Error("Error creating command", "error", err)
+		return models.Command{}, err
+	}
+
+	return command, nil
+}
+
+// ListByBot returns the commands of botID by name.
+func (r *commandsRepository) ListByBot(ctx context.Context, botID string) ([]models.Command, error) {
+	slog.Info("List bot commands", "botID", botID)
+
+	query := "SELECT " + commandColumns + ` FROM bot_commands c
+			  WHERE c.bot_id = @bot_id
+			  ORDER BY c.name`
+	args := pgx.NamedArgs{
+		"bot_id": botID,
+	}
+
+	return r.list(ctx, r.writer, query, args)
+}
+
+// ListForChat returns the commands of the bots in chatID by name. Commands
+// of several bots can share a name; the oldest comes first.
+func (r *commandsRepository) ListForChat(ctx context.Context, chatID string) ([]models.Command, error) {
+	slog.Info("List chat commands", "chatID", chatID)
+
+	query := "SELECT " + commandColumns + ` FROM bot_commands c
+			  JOIN users_chats uc ON uc.user_id = c.bot_id
+			  WHERE uc.chat_id = @chat_id
+			  ORDER BY c.name, c.id`
+	args := pgx.NamedArgs{
+		"chat_id": chatID,
+	}
+
+	return r.list(ctx, r.reader, query, args)
+}
+
+// FindForChat returns the oldest command called name of a bot in chatID.
+func (r *commandsRepository) FindForChat(ctx context.Context, chatID, name string) (models.Command, error) {
+	slog.Info("Find chat command", "chatID", chatID, "name", name)
+
+	query := "SELECT " + commandColumns + ` FROM bot_commands c
+			  JOIN users_chats uc ON uc.user_id = c.bot_id
+			  WHERE uc.chat_id = @chat_id AND c.name = @name
+			  ORDER BY c.id
+			  LIMIT 1`
+	args := pgx.NamedArgs{
+		"chat_id": chatID,
+		"name":    name,
+	}
+
+	command, err := scanCommand(r.reader.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Command not found", "chatID", chatID, "name", name)
+			return models.Command{}, nil
+		}
+		slog.Error("Error finding command", "error", err)
+		return models.Command{}, err
+	}
+
+	return command, nil
+}
+
+// Delete deletes commandID of botID. It reports false if there was no such
+// command.
+func (r *commandsRepository) Delete(ctx context.Context, botID, commandID string) (bool, error) {
+	slog.Info("Delete command", "botID", botID, "commandID", commandID)
+
+	query := "DELETE FROM bot_commands WHERE id = @id AND bot_id = @bot_id"
+	args := pgx.NamedArgs{
+		"id":     commandID,
+		"bot_id": botID,
+	}
+
+	tag, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error deleting command", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *commandsRepository) list(ctx context.Context, pool *pgxpool.Pool, query string, args pgx.NamedArgs) ([]models.Command, error) {
+	rows, err := pool.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error listing commands", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	commands := []models.Command{}
+	for rows.Next() {
+		command, err := scanCommand(rows)
+		if err != nil {
+			slog.Error("Error scanning command", "error", err)
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating commands", "error", err)
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+func scanCommand(row pgx.Row) (models.Command, error) {
+	var command models.Command
+	err := row.Scan(
+		&command.ID, &command.BotID, &command.Name, &command.Description, &command.Usage,
+		&command.URL, &command.Secret, &command.CreatedAt,
+	)
+	return command, err
+}
@@ -42,23 +42,57 @@ func (r *messagesRepository) Send(ctx context.Context, req models.Message) (stri
 
 	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
 
-	query := `INSERT INTO messages (id, idempotency_key, user_id, chat_id, content, status) 
-			  VALUES (@id, @idempotency_key, @user_id, @chat_id, @content, @status)`
+	query := `INSERT INTO messages (id, idempotency_key, user_id, chat_id, content, kind, status) 
+			  VALUES (@id, @idempotency_key, @user_id, @chat_id, @content, @kind, @status)`
 	args := pgx.NamedArgs{
 		"id":              id.String(),
 		"idempotency_key": req.IdempotencyKey,
 		"user_id":         req.UserID,
 		"chat_id":         req.ChatID,
 		"content":         req.Body,
+		"kind":            req.Kind,
 		"status":          req.Status,
 	}
 
-	_, err := r.writer.Exec(ctx, query, args)
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return id.String(), err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query, args)
 	if err != nil {
 		slog.Error("Error sending message", "error", err)
 		return id.String(), err
 	}
 
+	activityQuery := `UPDATE chats
+					  SET last_message_id = @id, last_activity_at = NOW()
+					  WHERE id = @chat_id`
+	_, err = tx.Exec(ctx, activityQuery, pgx.NamedArgs{"id": id.String(), "chat_id": req.ChatID})
+	if err != nil {
+		slog.Error("Error updating chat activity", "error", err)
+		return id.String(), err
+	}
+
+	// A new message brings the chat back out of the archive, except for
+	// members who muted it.
+	unarchiveQuery := `UPDATE users_chats
+					   SET archived = FALSE, updated_at = CURRENT_TIMESTAMP
+					   WHERE chat_id = @chat_id AND archived
+					   AND (muted_until IS NULL OR muted_until <= NOW())`
+	_, err = tx.Exec(ctx, unarchiveQuery, pgx.NamedArgs{"chat_id": req.ChatID})
+	if err != nil {
+		slog.Error("Error unarchiving chat", "error", err)
+		return id.String(), err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing message", "error", err)
+		return id.String(), err
+	}
+
 	return id.String(), nil
 }
 
@@ -83,7 +117,7 @@ func (r *messagesRepository) List(ctx context.Context, req models.ListMessagesRe
 
 	g, ctx := errgroup.WithContext(ctx)
 	g.Go(func() error {
-		baseQuery := `SELECT m.id, m.idempotency_key, m.user_id, m.chat_id, m.content, m.status, 
+		baseQuery := `SELECT m.id, m.idempotency_key, m.user_id, m.chat_id, m.content, m.kind, m.status, 
 						m.created_at, m.updated_at, u.username
 					  FROM messages m
 					  JOIN users u ON m.user_id = u.id
@@ -123,7 +157,7 @@ func (r *messagesRepository) List(ctx context.Context, req models.ListMessagesRe
 			var username string
 			if err := rows.Scan(
 				&message.ID, &message.IdempotencyKey, &message.UserID, &message.ChatID,
-				&message.Body, &message.Status, &message.CreatedAt, &message.UpdatedAt,
+				&message.Body, &message.Kind, &message.Status, &message.CreatedAt, &message.UpdatedAt,
 				&username,
 			); err != nil {
 				slog.Error("Error scanning message", "error", err)
@@ -184,7 +218,7 @@ func (r *messagesRepository) List(ctx context.Context, req models.ListMessagesRe
 func (r *messagesRepository) Get(ctx context.Context, messageID string) (models.Message, error) {
 	slog.Info("Get message", "messageID", messageID)
 
-	query := `SELECT m.id, m.idempotency_key, m.user_id, m.chat_id, m.content, m.status, 
+	query := `SELECT m.id, m.idempotency_key, m.user_id, m.chat_id, m.content, m.kind, m.status, 
 				m.created_at, m.updated_at, u.username
 			  FROM messages m
 			  JOIN users u ON m.user_id = u.id
@@ -197,7 +231,7 @@ func (r *messagesRepository) Get(ctx context.Context, messageID string) (models.
 	var username string
 	err := r.reader.QueryRow(ctx, query, args).Scan(
 		&message.ID, &message.IdempotencyKey, &message.UserID, &message.ChatID,
-		&message.Body, &message.Status, &message.CreatedAt, &message.UpdatedAt,
+		&message.Body, &message.Kind, &message.Status, &message.CreatedAt, &message.UpdatedAt,
 		&username,
 	)
 	if err != nil {
@@ -259,7 +293,7 @@ func (r *messagesRepository) MarkAsDelivered(ctx context.Context, messageID stri
 }
 
 func (r *messagesRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (models.Message, error) {
-	query := `SELECT m.id, m.idempotency_key, m.user_id, m.chat_id, m.content, m.status, 
+	query := `SELECT m.id, m.idempotency_key, m.user_id, m.chat_id, m.content, m.kind, m.status, 
 				m.created_at, m.updated_at, u.username
 			  FROM messages m
 			  JOIN users u ON m.user_id = u.id
@@ -272,7 +306,7 @@ func (r *messagesRepository) GetByIdempotencyKey(ctx context.Context, idempotenc
 	var username string
 	err := r.reader.QueryRow(ctx, query, args).Scan(
 		&message.ID, &message.IdempotencyKey, &message.UserID, &message.ChatID,
-		&message.Body, &message.Status, &message.CreatedAt, &message.UpdatedAt,
+		&message.Body, &message.Kind, &message.Status, &message.CreatedAt, &message.UpdatedAt,
 		&username,
 	)
 	if err != nil {
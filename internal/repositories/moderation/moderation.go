@@ -0,0 +1,392 @@
+package moderation
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+type ModerationRepository interface {
+	Block(ctx context.Context, blockerID, blockedID string) error
+	Unblock(ctx context.Context, blockerID, blockedID string) error
+	ListBlocked(ctx context.Context, req models.ListBlockedRequest) (models.ListBlockedResponse, error)
+	IsBlocked(ctx context.Context, userID, otherUserID string) (bool, error)
+	HasBlockWithChatMembers(ctx context.Context, chatID, userID string) (bool, error)
+	FilterBlocked(ctx context.Context, userID string, ids []string) (map[string]bool, error)
+	CreateReport(ctx context.Context, report models.Report) (string, error)
+	ListReports(ctx context.Context, req models.ListReportsRequest) (models.ListReportsResponse, error)
+	UpdateReportStatus(ctx context.Context, reportID string, status models.ReportStatus) (models.Report, error)
+}
+
+type moderationRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewModerationRepository(reader, writer *pgxpool.Pool) ModerationRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &moderationRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+func (r *moderationRepository) Block(ctx context.Context, blockerID, blockedID string) error {
+	slog.Info("Block user", "blockerID", blockerID, "blockedID", blockedID)
+
+	query := `INSERT INTO user_blocks (blocker_id, blocked_id)
+			  VALUES (@blocker_id, @blocked_id)
+			  ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+	args := pgx.NamedArgs{
+		"blocker_id": blockerID,
+		"blocked_id": blockedID,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error blocking user", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *moderationRepository) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	slog.Info("Unblock user", "blockerID", blockerID, "blockedID", blockedID)
+
+	query := `DELETE FROM user_blocks WHERE blocker_id = @blocker_id AND blocked_id = @blocked_id`
+	args := pgx.NamedArgs{
+		"blocker_id": blockerID,
+		"blocked_id": blockedID,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error unblocking user", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *moderationRepository) ListBlocked(ctx context.Context, req models.ListBlockedRequest) (models.ListBlockedResponse, error) {
+	var (
+		users []models.BlockedUser
+		total int32
+		page  = req.Page
+		limit = req.Limit
+	)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 20
+	}
+
+	slog.Info("Listing blocked users", "page", page, "limit", limit, "userID", req.UserID)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		query := `SELECT u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at, u.updated_at,
+					b.created_at
+				  FROM user_blocks b
+				  JOIN users u ON u.id = b.blocked_id
+				  WHERE b.blocker_id = @user_id
+				  ORDER BY b.created_at DESC, u.id
+				  LIMIT @limit OFFSET @offset`
+		args := pgx.NamedArgs{
+			"user_id": req.UserID,
+			"limit":   limit,
+			"offset":  (page - 1) * limit,
+		}
+		rows, err := r.reader.Query(ctx, query, args)
+		if err != nil {
+			slog.Error("Error listing blocked users", "error", err)
+			return err
+		}
+		defer rows.Close()
+
+		result := []models.BlockedUser{}
+		for rows.Next() {
+			var user models.BlockedUser
+			if err := rows.Scan(
+				&user.ID, &user.Username, &user.DisplayName, &user.AvatarAttachmentID, &user.CreatedAt, &user.UpdatedAt,
+				&user.BlockedAt,
+			); err != nil {
+				slog.Error("Error scanning blocked user", "error", err)
+				return err
+			}
+			result = append(result, user)
+		}
+		if err := rows.Err(); err != nil {
+			slog.Error("Error iterating blocked users", "error", err)
+			return err
+		}
+		users = result
+		return nil
+	})
+
+	g.Go(func() error {
+		query := `SELECT COUNT(*) FROM user_blocks WHERE blocker_id = @user_id`
+		args := pgx.NamedArgs{
+			"user_id": req.UserID,
+		}
+		if err := r.reader.QueryRow(ctx, query, args).Scan(&total); err != nil {
+			slog.Error("Error counting blocked users", "error", err)
+			return err
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return models.ListBlockedResponse{}, err
+	}
+
+	return models.ListBlockedResponse{
+		Users: users,
+		Total: total,
+	}, nil
+}
+
+// IsBlocked reports whether either user has blocked the other.
+func (r *moderationRepository) IsBlocked(ctx context.Context, userID, otherUserID string) (bool, error) {
+	slog.Info("Check block", "userID", userID, "otherUserID", otherUserID)
+
+	query := `SELECT EXISTS (
+				SELECT 1 FROM user_blocks
+				WHERE (blocker_id = @user_id AND blocked_id = @other_user_id)
+				   OR (blocker_id = @other_user_id AND blocked_id = @user_id)
+			  )`
+	args := pgx.NamedArgs{
+		"user_id":       userID,
+		"other_user_id": otherUserID,
+	}
+
+	var blocked bool
+	if err := r.reader.QueryRow(ctx, query, args).Scan(&blocked); err != nil {
+		slog.Error("Error checking block", "error", err)
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+// HasBlockWithChatMembers reports whether userID and any other member of
+// chatID have blocked each other, in either direction.
+func (r *moderationRepository) HasBlockWithChatMembers(ctx context.Context, chatID, userID string) (bool, error) {
+	slog.Info("Check blocks in chat", "chatID", chatID, "userID", userID)
+
+	query := `SELECT EXISTS (
+				SELECT 1
+				FROM users_chats uc
+				JOIN user_blocks b
+				  ON (b.blocker_id = @user_id AND b.blocked_id = uc.user_id)
+				  OR (b.blocker_id = uc.user_id AND b.blocked_id = @user_id)
+				WHERE uc.chat_id = @chat_id AND uc.user_id != @user_id
+			  )`
+	args := pgx.NamedArgs{
+		"chat_id": chatID,
+		"user_id": userID,
+	}
+
+	var blocked bool
+	if err := r.reader.QueryRow(ctx, query, args).Scan(&blocked); err != nil {
+		slog.Error("Error checking blocks in chat", "error", err)
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+// FilterBlocked reports which of ids have a block with userID in either
+// direction.
+func (r *moderationRepository) FilterBlocked(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT blocked_id FROM user_blocks WHERE blocker_id = @user_id AND blocked_id = ANY(@ids)
+			  UNION
+			  SELECT blocker_id FROM user_blocks WHERE blocked_id = @user_id AND blocker_id = ANY(@ids)`
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"ids":     ids,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error filtering blocked users", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			slog.Error("Error scanning blocked user ID", "error", err)
+			return nil, err
+		}
+		result[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating blocked users", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *moderationRepository) CreateReport(ctx context.Context, report models.Report) (string, error) {
+	slog.Info("Create report", "reporterID", report.ReporterID, "reportedUserID", report.ReportedUserID,
+		"messageID", report.MessageID, "reason", report.Reason)
+	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
+
+	query := `INSERT INTO moderation_reports (id, reporter_id, reported_user_id, chat_id, message_id, reason, details)
+			  VALUES (@id, @reporter_id, @reported_user_id, NULLIF(@chat_id, ''), NULLIF(@message_id, ''), @reason, @details)`
+	args := pgx.NamedArgs{
+		"id":               id.String(),
+		"reporter_id":      report.ReporterID,
+		"reported_user_id": report.ReportedUserID,
+		"chat_id":          report.ChatID,
+		"message_id":       report.MessageID,
+		"reason":           report.Reason,
+		"details":          report.Details,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error creating report", "error", err)
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+const reportColumns = `id, reporter_id, reported_user_id, COALESCE(chat_id, ''), COALESCE(message_id, ''),
+			reason, details, status, created_at, updated_at`
+
+func scanReport(row pgx.Row) (models.Report, error) {
+	var report models.Report
+	err := row.Scan(
+		&report.ID, &report.ReporterID, &report.ReportedUserID, &report.ChatID, &report.MessageID,
+		&report.Reason, &report.Details, &report.Status, &report.CreatedAt, &report.UpdatedAt,
+	)
+	return report, err
+}
+
+// ListReports lists the reports with a status, oldest first, the order
+// moderators work the queue in.
+func (r *moderationRepository) ListReports(ctx context.Context, req models.ListReportsRequest) (models.ListReportsResponse, error) {
+	var (
+		reports []models.Report
+		total   int32
+		page    = req.Page
+		limit   = req.Limit
+	)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 20
+	}
+
+	slog.Info("Listing reports", "page", page, "limit", limit, "status", req.Status)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		query := `SELECT ` + reportColumns + `
+				  FROM moderation_reports
+				  WHERE status = @status
+				  ORDER BY created_at, id
+				  LIMIT @limit OFFSET @offset`
+		args := pgx.NamedArgs{
+			"status": req.Status,
+			"limit":  limit,
+			"offset": (page - 1) * limit,
+		}
+		rows, err := r.reader.Query(ctx, query, args)
+		if err != nil {
+			slog.Error("Error listing reports", "error", err)
+			return err
+		}
+		defer rows.Close()
+
+		result := []models.Report{}
+		for rows.Next() {
+			report, err := scanReport(rows)
+			if err != nil {
+				slog.Error("Error scanning report", "error", err)
+				return err
+			}
+			result = append(result, report)
+		}
+		if err := rows.Err(); err != nil {
+			slog.Error("Error iterating reports", "error", err)
+			return err
+		}
+		reports = result
+		return nil
+	})
+
+	g.Go(func() error {
+		query := `SELECT COUNT(*) FROM moderation_reports WHERE status = @status`
+		args := pgx.NamedArgs{
+			"status": req.Status,
+		}
+		if err := r.reader.QueryRow(ctx, query, args).Scan(&total); err != nil {
+			slog.Error("Error counting reports", "error", err)
+			return err
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return models.ListReportsResponse{}, err
+	}
+
+	return models.ListReportsResponse{
+		Reports: reports,
+		Total:   total,
+	}, nil
+}
+
+// UpdateReportStatus sets the status of a report and returns it, or an
+// empty report if it does not exist.
+func (r *moderationRepository) UpdateReportStatus(ctx context.Context, reportID string, status models.ReportStatus) (models.Report, error) {
+	slog.Info("Update report status", "reportID", reportID, "status", status)
+
+	query := `UPDATE moderation_reports SET status = @status
+			  WHERE id = @id
+			  RETURNING ` + reportColumns
+	args := pgx.NamedArgs{
+		"id":     reportID,
+		"status": status,
+	}
+
+	report, err := scanReport(r.writer.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Report not found", "id", reportID)
+			return models.Report{}, nil
+		}
+		slog.Error("Error updating report status", "error", err)
+		return models.Report{}, err
+	}
+
+	return report, nil
+}
@@ -0,0 +1,21 @@
+// Package pgutil holds the SQL helpers shared by the repositories.
+package pgutil
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike escapes the LIKE wildcards in value, so that it only matches
+// itself once wrapped in % or _.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// NullIfEmpty passes values as NULL when there are none, which the queries
+// using it read as unrestricted, e.g. @chat_ids::text[] IS NULL.
+func NullIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
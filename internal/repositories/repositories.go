@@ -1,22 +1,46 @@
 package repositories
 
 import (
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/apikeys"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/audit"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/commands"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/messages"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/moderation"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/scheduled"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/tokens"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/twofactor"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/webhooks"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Repositories struct {
-	Users    users.UsersRepository
-	Messages messages.MessagesRepository
-	Chats    chats.ChatsRepository
+	Users      users.UsersRepository
+	Messages   messages.MessagesRepository
+	Chats      chats.ChatsRepository
+	Moderation moderation.ModerationRepository
+	Tokens     tokens.TokensRepository
+	TwoFactor  twofactor.TwoFactorRepository
+	Audit      audit.AuditRepository
+	APIKeys    apikeys.APIKeysRepository
+	Webhooks   webhooks.WebhooksRepository
+	Commands   commands.CommandsRepository
+	Scheduled  scheduled.ScheduledRepository
 }
 
 func NewRepositories(reader, writer *pgxpool.Pool) *Repositories {
 	return &Repositories{
-		Users:    users.NewUsersRepository(reader, writer),
-		Messages: messages.NewMessagesRepository(reader, writer),
-		Chats:    chats.NewChatsRepository(reader, writer),
+		Users:      users.NewUsersRepository(reader, writer),
+		Messages:   messages.NewMessagesRepository(reader, writer),
+		Chats:      chats.NewChatsRepository(reader, writer),
+		Moderation: moderation.NewModerationRepository(reader, writer),
+		Tokens:     tokens.NewTokensRepository(reader, writer),
+		TwoFactor:  twofactor.NewTwoFactorRepository(reader, writer),
+		Audit:      audit.NewAuditRepository(reader, writer),
+		APIKeys:    apikeys.NewAPIKeysRepository(reader, writer),
+		Webhooks:   webhooks.NewWebhooksRepository(reader, writer),
+		Commands:   commands.NewCommandsRepository(reader, writer),
+		Scheduled:  scheduled.NewScheduledRepository(reader, writer),
 	}
 }
@@ -0,0 +1,276 @@
+package scheduled
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/pgutil"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+const scheduledColumns = "id, user_id, chat_id, content, send_at, status, attempts, next_attempt_at, message_id, last_error, sent_at, created_at"
+
+type ScheduledRepository interface {
+	Create(ctx context.Context, msg models.ScheduledMessage) (models.ScheduledMessage, error)
+	List(ctx context.Context, req models.ListScheduledRequest) (models.ListScheduledResponse, error)
+	CountPending(ctx context.Context, userID string) (int, error)
+	Cancel(ctx context.Context, req models.CancelScheduledRequest) (bool, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.ScheduledMessage, error)
+	RecordAttempt(ctx context.Context, msg models.ScheduledMessage) error
+}
+
+type scheduledRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewScheduledRepository(reader, writer *pgxpool.Pool) ScheduledRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &scheduledRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+func (r *scheduledRepository) Create(ctx context.Context, msg models.ScheduledMessage) (models.ScheduledMessage, error) {
+	slog.Info("Create scheduled message", "userID", msg.UserID, "chatID", msg.ChatID, "sendAt", msg.SendAt)
+
+	msg.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+	msg.Status = models.ScheduledMessagePending
+	msg.NextAttemptAt = msg.SendAt
+
+	query := `INSERT INTO scheduled_messages (id, user_id, chat_id, content, send_at, next_attempt_at)
+			  VALUES (@id, @user_id, @chat_id, @content, @send_at, @send_at)
+			  RETURNING created_at`
+	args := pgx.NamedArgs{
+		"id":      msg.ID,
+		"user_id": msg.UserID,
+		"chat_id": msg.ChatID,
+		"content": msg.Content,
+		"send_at": msg.SendAt,
+	}
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&msg.CreatedAt); err != nil {
+		slog.Error("Error creating scheduled message", "error", err)
+		return models.ScheduledMessage{}, err
+	}
+
+	return msg, nil
+}
+
+// List pages through the scheduled messages of a user with a status, the
+// next to be sent first.
+func (r *scheduledRepository) List(ctx context.Context, req models.ListScheduledRequest) (models.ListScheduledResponse, error) {
+	var (
+		messages []models.ScheduledMessage
+		total    int32
+		page     = req.Page
+		limit    = req.Limit
+	)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 20
+	}
+
+	slog.Info("Listing scheduled messages", "page", page, "limit", limit, "userID", req.UserID, "chatID", req.ChatID, "status", req.Status)
+
+	filter := `user_id = @user_id AND status = @status AND (@chat_id = '' OR chat_id = @chat_id)
+			   AND (@chat_ids::text[] IS NULL OR chat_id = ANY(@chat_ids::text[]))`
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		query := "SELECT " + scheduledColumns + ` FROM scheduled_messages
+				  WHERE ` + filter + `
+				  ORDER BY send_at, id
+				  LIMIT @limit OFFSET @offset`
+		args := pgx.NamedArgs{
+			"user_id":  req.UserID,
+			"chat_id":  req.ChatID,
+			"chat_ids": pgutil.NullIfEmpty(req.ChatIDs),
+			"status":   string(req.Status),
+			"limit":    limit,
+			"offset":   (page - 1) * limit,
+		}
+		rows, err := r.reader.Query(ctx, query, args)
+		if err != nil {
+			slog.Error("Error listing scheduled messages", "error", err)
+			return err
+		}
+		defer rows.Close()
+
+		result := []models.ScheduledMessage{}
+		for rows.Next() {
+			msg, err := scanScheduledMessage(rows)
+			if err != nil {
+				slog.Error("Error scanning scheduled message", "error", err)
+				return err
+			}
+			result = append(result, msg)
+		}
+		if err := rows.Err(); err != nil {
+			slog.Error("Error iterating scheduled messages", "error", err)
+			return err
+		}
+		messages = result
+		return nil
+	})
+
+	g.Go(func() error {
+		query := "SELECT COUNT(*) FROM scheduled_messages WHERE " + filter
+		args := pgx.NamedArgs{
+			"user_id":  req.UserID,
+			"chat_id":  req.ChatID,
+			"chat_ids": pgutil.NullIfEmpty(req.ChatIDs),
+			"status":   string(req.Status),
+		}
+		if err := r.reader.QueryRow(ctx, query, args).Scan(&total); err != nil {
+			slog.Error("Error counting scheduled messages", "error", err)
+			return err
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return models.ListScheduledResponse{}, err
+	}
+
+	return models.ListScheduledResponse{
+		Messages: messages,
+		Total:    total,
+	}, nil
+}
+
+func (r *scheduledRepository) CountPending(ctx context.Context, userID string) (int, error) {
+	slog.Info("Count scheduled messages", "userID", userID)
+
+	query := "SELECT COUNT(*) FROM scheduled_messages WHERE user_id = @user_id AND status = 'PENDING'"
+	args := pgx.NamedArgs{
+		"user_id": userID,
+	}
+
+	var count int
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&count); err != nil {
+		slog.Error("Error counting scheduled messages", "error", err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Cancel cancels the scheduled message in req. It reports false if there
+// was no such pending message, or a scheduler is sending it right now.
+func (r *scheduledRepository) Cancel(ctx context.Context, req models.CancelScheduledRequest) (bool, error) {
+	slog.Info("Cancel scheduled message", "userID", req.UserID, "scheduledID", req.ScheduledID)
+
+	query := `UPDATE scheduled_messages
+			  SET status = 'CANCELED'
+			  WHERE id = @id AND user_id = @user_id AND status = 'PENDING'
+				AND (claimed_until IS NULL OR claimed_until < NOW())
+				AND (@chat_ids::text[] IS NULL OR chat_id = ANY(@chat_ids::text[]))`
+	args := pgx.NamedArgs{
+		"id":       req.ScheduledID,
+		"user_id":  req.UserID,
+		"chat_ids": pgutil.NullIfEmpty(req.ChatIDs),
+	}
+
+	tag, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error canceling scheduled message", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// ClaimDue reserves up to limit due messages for lease and counts the
+// attempt. Rows claimed by another scheduler are skipped, not waited for.
+func (r *scheduledRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.ScheduledMessage, error) {
+	query := `UPDATE scheduled_messages
+			  SET claimed_until = NOW() + @lease::interval, attempts = attempts + 1
+			  WHERE id IN (
+				SELECT id FROM scheduled_messages
+				WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+				  AND (claimed_until IS NULL OR claimed_until < NOW())
+				ORDER BY next_attempt_at
+				LIMIT @limit
+				FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING ` + scheduledColumns
+	args := pgx.NamedArgs{
+		"lease": lease.String(),
+		"limit": limit,
+	}
+
+	rows, err := r.writer.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error claiming scheduled messages", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.ScheduledMessage{}
+	for rows.Next() {
+		msg, err := scanScheduledMessage(rows)
+		if err != nil {
+			slog.Error("Error scanning scheduled message", "error", err)
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating scheduled messages", "error", err)
+		return nil, err
+	}
+
+	if len(messages) > 0 {
+		slog.Info("Claimed scheduled messages", "count", len(messages))
+	}
+
+	return messages, nil
+}
+
+// RecordAttempt stores the outcome of sending msg and releases its claim.
+func (r *scheduledRepository) RecordAttempt(ctx context.Context, msg models.ScheduledMessage) error {
+	slog.Info("Record scheduled message attempt", "scheduledID", msg.ID, "status", msg.Status, "attempts", msg.Attempts)
+
+	query := `UPDATE scheduled_messages
+			  SET status = @status, next_attempt_at = @next_attempt_at, claimed_until = NULL,
+				message_id = @message_id, last_error = @last_error, sent_at = @sent_at
+			  WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":              msg.ID,
+		"status":          string(msg.Status),
+		"next_attempt_at": msg.NextAttemptAt,
+		"message_id":      msg.MessageID,
+		"last_error":      msg.LastError,
+		"sent_at":         msg.SentAt,
+	}
+
+	if _, err := r.writer.Exec(ctx, query, args); err != nil {
+		slog.Error("Error recording scheduled message attempt", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func scanScheduledMessage(row pgx.Row) (models.ScheduledMessage, error) {
+	var msg models.ScheduledMessage
+	err := row.Scan(
+		&msg.ID, &msg.UserID, &msg.ChatID, &msg.Content, &msg.SendAt, &msg.Status, &msg.Attempts,
+		&msg.NextAttemptAt, &msg.MessageID, &msg.LastError, &msg.SentAt, &msg.CreatedAt,
+	)
+	return msg, err
+}
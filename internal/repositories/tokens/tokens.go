@@ -0,0 +1,549 @@
+package tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+type TokensRepository interface {
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, tokenHash string, next models.RefreshToken) (models.RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeFamilyByTokenHash(ctx context.Context, userID, tokenHash string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+	CreateSession(ctx context.Context, session models.Session) (models.Session, error)
+	TouchSession(ctx context.Context, sessionID string, device models.DeviceInfo) error
+	ListSessions(ctx context.Context, userID string, activeSince time.Time) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) (bool, error)
+	CreatePasswordResetToken(ctx context.Context, token models.PasswordResetToken) error
+	ConsumePasswordResetToken(ctx context.Context, tokenHash, passwordHash string) (models.PasswordResetToken, error)
+	CreateEmailVerificationToken(ctx context.Context, token models.EmailVerificationToken) error
+	ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (models.EmailVerificationToken, error)
+}
+
+type tokensRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewTokensRepository(reader, writer *pgxpool.Pool) TokensRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &tokensRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+// CreateRefreshToken stores a refresh token. A token without a FamilyID starts
+// a new family named after its own ID.
+func (r *tokensRepository) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	slog.Info("Create refresh token", "userID", token.UserID, "familyID", token.FamilyID)
+
+	token.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+	if token.FamilyID == "" {
+		token.FamilyID = token.ID
+	}
+
+	if err := insertRefreshToken(ctx, r.writer, token); err != nil {
+		slog.Error("Error creating refresh token", "error", err)
+		return models.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// RotateRefreshToken uses up the token with tokenHash and stores next in its
+// family, atomically. It returns the used token. An unknown hash returns an
+// empty token. Presenting a token that was already used revokes its whole
+// family and returns ErrRefreshTokenReused.
+func (r *tokensRepository) RotateRefreshToken(ctx context.Context, tokenHash string, next models.RefreshToken) (models.RefreshToken, error) {
+	slog.Info("Rotate refresh token")
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return models.RefreshToken{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+			  FROM refresh_tokens
+			  WHERE token_hash = @token_hash
+			  FOR UPDATE`
+
+	var current models.RefreshToken
+	err = tx.QueryRow(ctx, query, pgx.NamedArgs{"token_hash": tokenHash}).Scan(
+		&current.ID, &current.UserID, &current.FamilyID, &current.TokenHash, &current.ExpiresAt,
+		&current.UsedAt, &current.RevokedAt, &current.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Refresh token not found")
+			return models.RefreshToken{}, nil
+		}
+		slog.Error("Error getting refresh token", "error", err)
+		return models.RefreshToken{}, err
+	}
+
+	if current.UsedAt != nil {
+		slog.Warn("Refresh token reused, revoking family", "userID", current.UserID, "familyID", current.FamilyID)
+		if err := revokeFamily(ctx, tx, current.FamilyID); err != nil {
+			return current, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			slog.Error("Error committing family revocation", "error", err)
+			return current, err
+		}
+		return current, ErrRefreshTokenReused
+	}
+
+	if current.RevokedAt != nil {
+		return current, ErrRefreshTokenRevoked
+	}
+
+	if !current.ExpiresAt.After(time.Now()) {
+		return current, ErrRefreshTokenExpired
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE id = @id`, pgx.NamedArgs{"id": current.ID})
+	if err != nil {
+		slog.Error("Error marking refresh token as used", "error", err)
+		return current, err
+	}
+
+	next.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		slog.Error("Error creating rotated refresh token", "error", err)
+		return current, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing refresh token rotation", "error", err)
+		return current, err
+	}
+
+	return current, nil
+}
+
+func (r *tokensRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	slog.Info("Revoke refresh token family", "familyID", familyID)
+
+	return revokeFamily(ctx, r.writer, familyID)
+}
+
+// RevokeFamilyByTokenHash revokes the family of the token with tokenHash, as
+// long as it belongs to userID. Unknown hashes are ignored.
+func (r *tokensRepository) RevokeFamilyByTokenHash(ctx context.Context, userID, tokenHash string) error {
+	slog.Info("Revoke refresh token family by token", "userID", userID)
+
+	query := `UPDATE refresh_tokens
+			  SET revoked_at = NOW()
+			  WHERE revoked_at IS NULL
+			    AND family_id = (
+			      SELECT family_id FROM refresh_tokens
+			      WHERE token_hash = @token_hash AND user_id = @user_id
+			    )`
+	args := pgx.NamedArgs{
+		"token_hash": tokenHash,
+		"user_id":    userID,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error revoking refresh token family by token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every session and refresh token of userID.
+func (r *tokensRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	slog.Info("Revoke all refresh tokens", "userID", userID)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	args := pgx.NamedArgs{"user_id": userID}
+
+	_, err = tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = @user_id AND revoked_at IS NULL`, args)
+	if err != nil {
+		slog.Error("Error revoking refresh tokens for user", "error", err)
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE sessions SET revoked_at = NOW() WHERE user_id = @user_id AND revoked_at IS NULL`, args)
+	if err != nil {
+		slog.Error("Error revoking sessions for user", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing revocation", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *tokensRepository) CreateSession(ctx context.Context, session models.Session) (models.Session, error) {
+	slog.Info("Create session", "userID", session.UserID, "deviceName", session.DeviceName)
+
+	session.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+
+	query := `INSERT INTO sessions (id, user_id, device_name, user_agent, ip_address)
+			  VALUES (@id, @user_id, @device_name, @user_agent, @ip_address)
+			  RETURNING created_at, last_used_at`
+	args := pgx.NamedArgs{
+		"id":          session.ID,
+		"user_id":     session.UserID,
+		"device_name": session.DeviceName,
+		"user_agent":  session.UserAgent,
+		"ip_address":  session.IPAddress,
+	}
+
+	err := r.writer.QueryRow(ctx, query, args).Scan(&session.CreatedAt, &session.LastUsedAt)
+	if err != nil {
+		slog.Error("Error creating session", "error", err)
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+// TouchSession records that the session was used again, from the given
+// client. The device name is kept from login.
+func (r *tokensRepository) TouchSession(ctx context.Context, sessionID string, device models.DeviceInfo) error {
+	slog.Info("Touch session", "sessionID", sessionID)
+
+	query := `UPDATE sessions
+			  SET last_used_at = NOW(), user_agent = @user_agent, ip_address = @ip_address
+			  WHERE id = @id AND revoked_at IS NULL`
+	args := pgx.NamedArgs{
+		"id":         sessionID,
+		"user_agent": device.UserAgent,
+		"ip_address": device.IPAddress,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error touching session", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListSessions returns the sessions of userID that are not revoked and were
+// used after activeSince, most recently used first.
+func (r *tokensRepository) ListSessions(ctx context.Context, userID string, activeSince time.Time) ([]models.Session, error) {
+	slog.Info("List sessions", "userID", userID)
+
+	query := `SELECT id, user_id, device_name, user_agent, ip_address, created_at, last_used_at, revoked_at
+			  FROM sessions
+			  WHERE user_id = @user_id AND revoked_at IS NULL AND last_used_at > @active_since
+			  ORDER BY last_used_at DESC, id`
+	args := pgx.NamedArgs{
+		"user_id":      userID,
+		"active_since": activeSince,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error listing sessions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(
+			&session.ID, &session.UserID, &session.DeviceName, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastUsedAt, &session.RevokedAt,
+		); err != nil {
+			slog.Error("Error scanning session", "error", err)
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating sessions", "error", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes a session of userID and its refresh tokens. It
+// reports false when userID has no active session with that ID.
+func (r *tokensRepository) RevokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	slog.Info("Revoke session", "userID", userID, "sessionID", sessionID)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE sessions
+			  SET revoked_at = NOW()
+			  WHERE id = @id AND user_id = @user_id AND revoked_at IS NULL`
+	args := pgx.NamedArgs{
+		"id":      sessionID,
+		"user_id": userID,
+	}
+
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error revoking session", "error", err)
+		return false, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := revokeFamily(ctx, tx, sessionID); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing session revocation", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreatePasswordResetToken stores a reset token and invalidates the user's
+// earlier ones, so only the latest email works.
+func (r *tokensRepository) CreatePasswordResetToken(ctx context.Context, token models.PasswordResetToken) error {
+	slog.Info("Create password reset token", "userID", token.UserID)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = @user_id AND used_at IS NULL`,
+		pgx.NamedArgs{"user_id": token.UserID})
+	if err != nil {
+		slog.Error("Error invalidating password reset tokens", "error", err)
+		return err
+	}
+
+	query := `INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at)
+			  VALUES (@id, @user_id, @token_hash, @expires_at)`
+	args := pgx.NamedArgs{
+		"id":         ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String(),
+		"user_id":    token.UserID,
+		"token_hash": token.TokenHash,
+		"expires_at": token.ExpiresAt,
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		slog.Error("Error creating password reset token", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing password reset token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ConsumePasswordResetToken marks the token with tokenHash as used, sets
+// passwordHash as the owner's password, and returns the token. Unknown, used
+// and expired tokens return an empty token and change nothing.
+func (r *tokensRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash, passwordHash string) (models.PasswordResetToken, error) {
+	slog.Info("Consume password reset token")
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return models.PasswordResetToken{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE password_reset_tokens
+			  SET used_at = NOW()
+			  WHERE token_hash = @token_hash AND used_at IS NULL AND expires_at > NOW()
+			  RETURNING id, user_id, token_hash, expires_at, used_at, created_at`
+
+	var token models.PasswordResetToken
+	err = tx.QueryRow(ctx, query, pgx.NamedArgs{"token_hash": tokenHash}).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Password reset token not found or no longer valid")
+			return models.PasswordResetToken{}, nil
+		}
+		slog.Error("Error consuming password reset token", "error", err)
+		return models.PasswordResetToken{}, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET password_hash = @password_hash, updated_at = CURRENT_TIMESTAMP WHERE id = @user_id`,
+		pgx.NamedArgs{"user_id": token.UserID, "password_hash": passwordHash})
+	if err != nil {
+		slog.Error("Error updating password", "error", err)
+		return models.PasswordResetToken{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing password reset", "error", err)
+		return models.PasswordResetToken{}, err
+	}
+
+	return token, nil
+}
+
+// CreateEmailVerificationToken stores a verification token and invalidates
+// the user's earlier ones, so only the latest email works.
+func (r *tokensRepository) CreateEmailVerificationToken(ctx context.Context, token models.EmailVerificationToken) error {
+	slog.Info("Create email verification token", "userID", token.UserID)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = @user_id AND used_at IS NULL`,
+		pgx.NamedArgs{"user_id": token.UserID})
+	if err != nil {
+		slog.Error("Error invalidating email verification tokens", "error", err)
+		return err
+	}
+
+	query := `INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at)
+			  VALUES (@id, @user_id, @token_hash, @expires_at)`
+	args := pgx.NamedArgs{
+		"id":         ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String(),
+		"user_id":    token.UserID,
+		"token_hash": token.TokenHash,
+		"expires_at": token.ExpiresAt,
+	}
+
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		slog.Error("Error creating email verification token", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing email verification token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeEmailVerificationToken marks the token with tokenHash as used and
+// the owner's email as verified, and returns the token. Unknown, used and
+// expired tokens return an empty token.
+func (r *tokensRepository) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (models.EmailVerificationToken, error) {
+	slog.Info("Consume email verification token")
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return models.EmailVerificationToken{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE email_verification_tokens
+			  SET used_at = NOW()
+			  WHERE token_hash = @token_hash AND used_at IS NULL AND expires_at > NOW()
+			  RETURNING id, user_id, token_hash, expires_at, used_at, created_at`
+
+	var token models.EmailVerificationToken
+	err = tx.QueryRow(ctx, query, pgx.NamedArgs{"token_hash": tokenHash}).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Email verification token not found or no longer valid")
+			return models.EmailVerificationToken{}, nil
+		}
+		slog.Error("Error consuming email verification token", "error", err)
+		return models.EmailVerificationToken{}, err
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE users SET email_verified_at = COALESCE(email_verified_at, NOW()) WHERE id = @user_id`,
+		pgx.NamedArgs{"user_id": token.UserID})
+	if err != nil {
+		slog.Error("Error marking email verified", "error", err)
+		return models.EmailVerificationToken{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing email verification", "error", err)
+		return models.EmailVerificationToken{}, err
+	}
+
+	return token, nil
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+func insertRefreshToken(ctx context.Context, db execer, token models.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at)
+			  VALUES (@id, @user_id, @family_id, @token_hash, @expires_at)`
+	args := pgx.NamedArgs{
+		"id":         token.ID,
+		"user_id":    token.UserID,
+		"family_id":  token.FamilyID,
+		"token_hash": token.TokenHash,
+		"expires_at": token.ExpiresAt,
+	}
+
+	_, err := db.Exec(ctx, query, args)
+	return err
+}
+
+func revokeFamily(ctx context.Context, db execer, familyID string) error {
+	query := `UPDATE refresh_tokens
+			  SET revoked_at = NOW()
+			  WHERE family_id = @family_id AND revoked_at IS NULL`
+
+	_, err := db.Exec(ctx, query, pgx.NamedArgs{"family_id": familyID})
+	if err != nil {
+		slog.Error("Error revoking refresh token family", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package twofactor
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+)
+
+type TwoFactorRepository interface {
+	GetTOTP(ctx context.Context, userID string) (models.TOTP, error)
+	SaveTOTPEnrollment(ctx context.Context, totp models.TOTP, recoveryCodeHashes []string) (bool, error)
+	ConfirmTOTP(ctx context.Context, userID string, step int64) (bool, error)
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CreateLoginChallenge(ctx context.Context, challenge models.LoginChallenge) (models.LoginChallenge, error)
+	GetLoginChallenge(ctx context.Context, tokenHash string) (models.LoginChallenge, error)
+	RecordChallengeAttempt(ctx context.Context, challengeID string, maxAttempts int) (bool, error)
+	ConsumeLoginChallenge(ctx context.Context, challengeID string) (bool, error)
+}
+
+type twoFactorRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewTwoFactorRepository(reader, writer *pgxpool.Pool) TwoFactorRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &twoFactorRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+// GetTOTP reads from the writer: it is called right after enrollment and
+// confirmation, and a lagging replica would report the wrong state.
+func (r *twoFactorRepository) GetTOTP(ctx context.Context, userID string) (models.TOTP, error) {
+	slog.Info("Get TOTP", "userID", userID)
+
+	query := `SELECT user_id, secret_ciphertext, confirmed_at, last_used_step, created_at
+			  FROM user_totp
+			  WHERE user_id = @user_id`
+
+	var totp models.TOTP
+	err := r.writer.QueryRow(ctx, query, pgx.NamedArgs{"user_id": userID}).Scan(
+		&totp.UserID, &totp.SecretCiphertext, &totp.ConfirmedAt, &totp.LastUsedStep, &totp.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.TOTP{}, nil
+		}
+		slog.Error("Error getting TOTP", "error", err)
+		return models.TOTP{}, err
+	}
+
+	return totp, nil
+}
+
+// SaveTOTPEnrollment stores a new secret and replaces the user's recovery
+// codes. It reports false, and changes nothing, when the user already has a
+// confirmed secret.
+func (r *twoFactorRepository) SaveTOTPEnrollment(ctx context.Context, totp models.TOTP, recoveryCodeHashes []string) (bool, error) {
+	slog.Info("Save TOTP enrollment", "userID", totp.UserID)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO user_totp (user_id, secret_ciphertext)
+			  VALUES (@user_id, @secret_ciphertext)
+			  ON CONFLICT (user_id) DO UPDATE
+			  SET secret_ciphertext = EXCLUDED.secret_ciphertext, last_used_step = 0, created_at = NOW()
+			  WHERE user_totp.confirmed_at IS NULL`
+	args := pgx.NamedArgs{
+		"user_id":           totp.UserID,
+		"secret_ciphertext": totp.SecretCiphertext,
+	}
+
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error saving TOTP secret", "error", err)
+		return false, err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = @user_id`, pgx.NamedArgs{"user_id": totp.UserID}); err != nil {
+		slog.Error("Error deleting recovery codes", "error", err)
+		return false, err
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		args := pgx.NamedArgs{
+			"id":        ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String(),
+			"user_id":   totp.UserID,
+			"code_hash": codeHash,
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO totp_recovery_codes (id, user_id, code_hash) VALUES (@id, @user_id, @code_hash)`, args)
+		if err != nil {
+			slog.Error("Error storing recovery code", "error", err)
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing TOTP enrollment", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ConfirmTOTP turns a pending enrollment on and records step as used.
+func (r *twoFactorRepository) ConfirmTOTP(ctx context.Context, userID string, step int64) (bool, error) {
+	slog.Info("Confirm TOTP", "userID", userID)
+
+	query := `UPDATE user_totp
+			  SET confirmed_at = NOW(), last_used_step = @step
+			  WHERE user_id = @user_id AND confirmed_at IS NULL`
+
+	tag, err := r.writer.Exec(ctx, query, pgx.NamedArgs{"user_id": userID, "step": step})
+	if err != nil {
+		slog.Error("Error confirming TOTP", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// UseTOTPStep records step as used. It reports false when a code of the same
+// or a later step was already accepted.
+func (r *twoFactorRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	slog.Info("Use TOTP step", "userID", userID)
+
+	query := `UPDATE user_totp
+			  SET last_used_step = @step
+			  WHERE user_id = @user_id AND confirmed_at IS NOT NULL AND last_used_step < @step`
+
+	tag, err := r.writer.Exec(ctx, query, pgx.NamedArgs{"user_id": userID, "step": step})
+	if err != nil {
+		slog.Error("Error using TOTP step", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	slog.Info("Use recovery code", "userID", userID)
+
+	query := `UPDATE totp_recovery_codes
+			  SET used_at = NOW()
+			  WHERE user_id = @user_id AND code_hash = @code_hash AND used_at IS NULL`
+
+	tag, err := r.writer.Exec(ctx, query, pgx.NamedArgs{"user_id": userID, "code_hash": codeHash})
+	if err != nil {
+		slog.Error("Error using recovery code", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *twoFactorRepository) CreateLoginChallenge(ctx context.Context, challenge models.LoginChallenge) (models.LoginChallenge, error) {
+	slog.Info("Create login challenge", "userID", challenge.UserID)
+
+	challenge.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+
+	query := `INSERT INTO login_challenges (id, user_id, token_hash, device_name, user_agent, ip_address, expires_at)
+			  VALUES (@id, @user_id, @token_hash, @device_name, @user_agent, @ip_address, @expires_at)
+			  RETURNING created_at`
+	args := pgx.NamedArgs{
+		"id":          challenge.ID,
+		"user_id":     challenge.UserID,
+		"token_hash":  challenge.TokenHash,
+		"device_name": challenge.Device.DeviceName,
+		"user_agent":  challenge.Device.UserAgent,
+		"ip_address":  challenge.Device.IPAddress,
+		"expires_at":  challenge.ExpiresAt,
+	}
+
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&challenge.CreatedAt); err != nil {
+		slog.Error("Error creating login challenge", "error", err)
+		return models.LoginChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// GetLoginChallenge returns the unused, unexpired challenge with tokenHash.
+// Unknown, used and expired challenges return an empty challenge.
+func (r *twoFactorRepository) GetLoginChallenge(ctx context.Context, tokenHash string) (models.LoginChallenge, error) {
+	slog.Info("Get login challenge")
+
+	query := `SELECT id, user_id, token_hash, device_name, user_agent, ip_address, attempts, expires_at, used_at, created_at
+			  FROM login_challenges
+			  WHERE token_hash = @token_hash AND used_at IS NULL AND expires_at > NOW()`
+
+	var challenge models.LoginChallenge
+	err := r.writer.QueryRow(ctx, query, pgx.NamedArgs{"token_hash": tokenHash}).Scan(
+		&challenge.ID, &challenge.UserID, &challenge.TokenHash, &challenge.Device.DeviceName, &challenge.Device.UserAgent,
+		&challenge.Device.IPAddress, &challenge.Attempts, &challenge.ExpiresAt, &challenge.UsedAt, &challenge.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.LoginChallenge{}, nil
+		}
+		slog.Error("Error getting login challenge", "error", err)
+		return models.LoginChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// RecordChallengeAttempt counts an attempt to complete the challenge before
+// its code is checked. It reports false, and counts nothing, once
+// maxAttempts were made or the challenge was used or expired, so concurrent
+// attempts cannot get past the limit.
+func (r *twoFactorRepository) RecordChallengeAttempt(ctx context.Context, challengeID string, maxAttempts int) (bool, error) {
+	slog.Info("Record login challenge attempt", "challengeID", challengeID)
+
+	query := `UPDATE login_challenges
+			  SET attempts = attempts + 1
+			  WHERE id = @id AND attempts < @max_attempts AND used_at IS NULL AND expires_at > NOW()
+			  RETURNING attempts`
+	args := pgx.NamedArgs{
+		"id":           challengeID,
+		"max_attempts": maxAttempts,
+	}
+
+	var attempts int
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&attempts); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		slog.Error("Error recording login challenge attempt", "error", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ConsumeLoginChallenge marks the challenge as used. It reports false when it
+// already was or has expired, so a challenge completes at most one login.
+func (r *twoFactorRepository) ConsumeLoginChallenge(ctx context.Context, challengeID string) (bool, error) {
+	slog.Info("Consume login challenge", "challengeID", challengeID)
+
+	tag, err := r.writer.Exec(ctx, `UPDATE login_challenges SET used_at = NOW() WHERE id = @id AND used_at IS NULL AND expires_at > NOW()`,
+		pgx.NamedArgs{"id": challengeID})
+	if err != nil {
+		slog.Error("Error consuming login challenge", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
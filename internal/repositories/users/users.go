@@ -3,22 +3,46 @@ package users
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/pgutil"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/oklog/ulid/v2"
 )
 
+var (
+	ErrEmailTaken    = errors.New("email already taken")
+	ErrUsernameTaken = errors.New("username already taken")
+	// ErrIdentityTaken means the external identity is linked to another user.
+	ErrIdentityTaken = errors.New("external identity already linked")
+)
+
 type UsersRepository interface {
 	Create(ctx context.Context, req models.User) (string, error)
 	GetByID(ctx context.Context, id string) (models.User, error)
+	GetByIDs(ctx context.Context, ids []string) ([]models.User, error)
 	GetByEmail(ctx context.Context, email string) (models.User, error)
+	GetByUsername(ctx context.Context, username string) (models.User, error)
 	Update(ctx context.Context, req models.User) error
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	GetByExternalIdentity(ctx context.Context, issuer, subject string) (models.User, error)
+	LinkExternalIdentity(ctx context.Context, identity models.ExternalIdentity, emailVerified bool) error
+	CreateWithExternalIdentity(ctx context.Context, user models.User, identity models.ExternalIdentity) (string, error)
+	CreateBot(ctx context.Context, bot models.User) (string, error)
+	ListBots(ctx context.Context, ownerID string) ([]models.User, error)
+	GetCoMemberIDs(ctx context.Context, userID string) ([]string, error)
+	FilterCoMembers(ctx context.Context, userID string, ids []string) (map[string]bool, error)
+	Search(ctx context.Context, req models.SearchUsersRequest, after *models.UserSearchCursor) ([]models.UserSearchResult, error)
 }
 
+const userColumns = "id, username, email, password_hash, display_name, bio, avatar_attachment_id, discoverable, email_verified_at, is_admin, COALESCE(bot_owner_id, '') AS bot_owner_id, created_at, updated_at"
+
 type usersRepository struct {
 	reader  *pgxpool.Pool
 	writer  *pgxpool.Pool
@@ -38,17 +62,19 @@ func (r *usersRepository) Create(ctx context.Context, req models.User) (string,
 	slog.Info("Create user", "username", req.Username, "email", req.Email)
 	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
 
-	query := "INSERT INTO users (id, username, email, password_hash) VALUES (@id, @username, @email, @password_hash)"
+	query := `INSERT INTO users (id, username, email, password_hash, display_name)
+			  VALUES (@id, @username, @email, @password_hash, @display_name)`
 	args := pgx.NamedArgs{
 		"id":            id.String(),
 		"username":      req.Username,
 		"email":         req.Email,
 		"password_hash": req.PasswordHash,
+		"display_name":  req.DisplayName,
 	}
 	_, err := r.writer.Exec(ctx, query, args)
 	if err != nil {
 		slog.Error("Error creating user", "error", err)
-		return id.String(), err
+		return id.String(), uniqueViolation(err)
 	}
 
 	return id.String(), nil
@@ -57,15 +83,12 @@ func (r *usersRepository) Create(ctx context.Context, req models.User) (string,
 func (r *usersRepository) GetByID(ctx context.Context, id string) (models.User, error) {
 	slog.Info("Get user by ID", "id", id)
 
-	query := "SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE id = @id"
+	query := "SELECT " + userColumns + " FROM users WHERE id = @id"
 	args := pgx.NamedArgs{
 		"id": id,
 	}
 
-	var user models.User
-	err := r.reader.QueryRow(ctx, query, args).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt,
-	)
+	user, err := scanUser(r.reader.QueryRow(ctx, query, args))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Info("User not found", "id", id)
@@ -78,18 +101,52 @@ func (r *usersRepository) GetByID(ctx context.Context, id string) (models.User,
 	return user, nil
 }
 
+func (r *usersRepository) GetByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	slog.Info("Get users by IDs", "count", len(ids))
+
+	if len(ids) == 0 {
+		return []models.User{}, nil
+	}
+
+	query := "SELECT " + userColumns + " FROM users WHERE id = ANY(@ids)"
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error getting users by IDs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			slog.Error("Error scanning user", "error", err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating users", "error", err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (r *usersRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
 	slog.Info("Get user by email", "email", email)
 
-	query := "SELECT id, username, email, password_hash, created_at, updated_at FROM users WHERE email = @email"
+	query := "SELECT " + userColumns + " FROM users WHERE email = @email"
 	args := pgx.NamedArgs{
 		"email": email,
 	}
 
-	var user models.User
-	err := r.reader.QueryRow(ctx, query, args).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt,
-	)
+	user, err := scanUser(r.reader.QueryRow(ctx, query, args))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			slog.Info("User not found", "email", email)
@@ -102,21 +159,432 @@ func (r *usersRepository) GetByEmail(ctx context.Context, email string) (models.
 	return user, nil
 }
 
+func (r *usersRepository) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	slog.Info("Get user by username", "username", username)
+
+	query := "SELECT " + userColumns + " FROM users WHERE LOWER(username) = LOWER(@username)"
+	args := pgx.NamedArgs{
+		"username": username,
+	}
+
+	user, err := scanUser(r.reader.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("User not found", "username", username)
+			return models.User{}, nil
+		}
+		slog.Error("Error getting user by username", "error", err)
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
 func (r *usersRepository) Update(ctx context.Context, req models.User) error {
 	slog.Info("Update user", "id", req.ID)
 
-	query := "UPDATE users SET username = @username, email = @email, updated_at = CURRENT_TIMESTAMP WHERE id = @id"
+	query := `UPDATE users
+			  SET username = @username, email = @email, display_name = @display_name, bio = @bio,
+				  avatar_attachment_id = @avatar_attachment_id, discoverable = @discoverable, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = @id`
 	args := pgx.NamedArgs{
-		"id":       req.ID,
-		"username": req.Username,
-		"email":    req.Email,
+		"id":                   req.ID,
+		"username":             req.Username,
+		"email":                req.Email,
+		"display_name":         req.DisplayName,
+		"bio":                  req.Bio,
+		"avatar_attachment_id": req.AvatarAttachmentID,
+		"discoverable":         req.Discoverable,
 	}
 
 	_, err := r.writer.Exec(ctx, query, args)
 	if err != nil {
 		slog.Error("Error updating user", "error", err)
+		return uniqueViolation(err)
+	}
+
+	return nil
+}
+
+func (r *usersRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	slog.Info("Update password", "id", userID)
+
+	query := `UPDATE users
+			  SET password_hash = @password_hash, updated_at = CURRENT_TIMESTAMP
+			  WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":            userID,
+		"password_hash": passwordHash,
+	}
+
+	_, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error updating password", "error", err)
 		return err
 	}
 
 	return nil
 }
+
+// GetByExternalIdentity returns the user linked to subject of issuer. It
+// reads from the writer, since the link may have been made moments ago.
+func (r *usersRepository) GetByExternalIdentity(ctx context.Context, issuer, subject string) (models.User, error) {
+	slog.Info("Get user by external identity", "issuer", issuer, "subject", subject)
+
+	query := "SELECT " + userColumns + ` FROM users
+			  WHERE id = (SELECT user_id FROM external_identities WHERE issuer = @issuer AND subject = @subject)`
+	args := pgx.NamedArgs{
+		"issuer":  issuer,
+		"subject": subject,
+	}
+
+	user, err := scanUser(r.writer.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.User{}, nil
+		}
+		slog.Error("Error getting user by external identity", "error", err)
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// LinkExternalIdentity links identity to identity.UserID, or records a login
+// if it is linked already. With emailVerified the user's email, if it is
+// still the identity's email, is marked as verified too.
+func (r *usersRepository) LinkExternalIdentity(ctx context.Context, identity models.ExternalIdentity, emailVerified bool) error {
+	slog.Info("Link external identity", "userID", identity.UserID, "issuer", identity.Issuer, "subject", identity.Subject)
+	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO external_identities (id, user_id, issuer, subject, email)
+			  VALUES (@id, @user_id, @issuer, @subject, @email)
+			  ON CONFLICT (issuer, subject) DO UPDATE
+			  SET email = EXCLUDED.email, last_login_at = NOW()
+			  WHERE external_identities.user_id = EXCLUDED.user_id`
+	args := pgx.NamedArgs{
+		"id":      id.String(),
+		"user_id": identity.UserID,
+		"issuer":  identity.Issuer,
+		"subject": identity.Subject,
+		"email":   identity.Email,
+	}
+
+	tag, err := tx.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error linking external identity", "error", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrIdentityTaken
+	}
+
+	if emailVerified {
+		query = `UPDATE users
+				 SET email_verified_at = COALESCE(email_verified_at, NOW())
+				 WHERE id = @user_id AND email = @email`
+		_, err = tx.Exec(ctx, query, pgx.NamedArgs{"user_id": identity.UserID, "email": identity.Email})
+		if err != nil {
+			slog.Error("Error marking email as verified", "error", err)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CreateWithExternalIdentity creates user, who has no password, together with
+// its link to identity. user.EmailVerifiedAt is stored as given.
+func (r *usersRepository) CreateWithExternalIdentity(ctx context.Context, user models.User, identity models.ExternalIdentity) (string, error) {
+	slog.Info("Create user with external identity", "username", user.Username, "issuer", identity.Issuer, "subject", identity.Subject)
+	userID := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
+	identityID := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `INSERT INTO users (id, username, email, password_hash, display_name, email_verified_at)
+			  VALUES (@id, @username, @email, '', @display_name, @email_verified_at)`
+	args := pgx.NamedArgs{
+		"id":                userID.String(),
+		"username":          user.Username,
+		"email":             user.Email,
+		"display_name":      user.DisplayName,
+		"email_verified_at": user.EmailVerifiedAt,
+	}
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		slog.Error("Error creating user", "error", err)
+		return "", uniqueViolation(err)
+	}
+
+	query = `INSERT INTO external_identities (id, user_id, issuer, subject, email)
+			 VALUES (@id, @user_id, @issuer, @subject, @email)`
+	args = pgx.NamedArgs{
+		"id":      identityID.String(),
+		"user_id": userID.String(),
+		"issuer":  identity.Issuer,
+		"subject": identity.Subject,
+		"email":   identity.Email,
+	}
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		slog.Error("Error creating external identity", "error", err)
+		return "", uniqueViolation(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing transaction", "error", err)
+		return "", err
+	}
+
+	return userID.String(), nil
+}
+
+// CreateBot creates a bot owned by bot.BotOwnerID. Bots have no password, and
+// their email is a placeholder under models.BotEmailDomain.
+func (r *usersRepository) CreateBot(ctx context.Context, bot models.User) (string, error) {
+	slog.Info("Create bot", "username", bot.Username, "ownerID", bot.BotOwnerID)
+	id := ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy)
+
+	query := `INSERT INTO users (id, username, email, password_hash, display_name, discoverable, bot_owner_id)
+			  VALUES (@id, @username, @email, '', @display_name, FALSE, @bot_owner_id)`
+	args := pgx.NamedArgs{
+		"id":           id.String(),
+		"username":     bot.Username,
+		"email":        strings.ToLower(id.String()) + "@" + models.BotEmailDomain,
+		"display_name": bot.DisplayName,
+		"bot_owner_id": bot.BotOwnerID,
+	}
+	if _, err := r.writer.Exec(ctx, query, args); err != nil {
+		slog.Error("Error creating bot", "error", err)
+		return "", uniqueViolation(err)
+	}
+
+	return id.String(), nil
+}
+
+func (r *usersRepository) ListBots(ctx context.Context, ownerID string) ([]models.User, error) {
+	slog.Info("List bots", "ownerID", ownerID)
+
+	// The bots that incoming webhooks post as are not listed.
+	query := "SELECT " + userColumns + ` FROM users
+			  WHERE bot_owner_id = @owner_id
+			    AND NOT EXISTS (SELECT 1 FROM incoming_webhooks w WHERE w.user_id = users.id)
+			  ORDER BY id`
+	args := pgx.NamedArgs{
+		"owner_id": ownerID,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error listing bots", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	bots := []models.User{}
+	for rows.Next() {
+		bot, err := scanUser(rows)
+		if err != nil {
+			slog.Error("Error scanning bot", "error", err)
+			return nil, err
+		}
+		bots = append(bots, bot)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating bots", "error", err)
+		return nil, err
+	}
+
+	return bots, nil
+}
+
+// GetCoMemberIDs returns the users who share a regular chat with userID.
+// Channel subscribers are left out, since a channel can have thousands.
+func (r *usersRepository) GetCoMemberIDs(ctx context.Context, userID string) ([]string, error) {
+	slog.Info("Get co-member IDs", "userID", userID)
+
+	query := `SELECT DISTINCT other.user_id
+			  FROM users_chats own
+			  JOIN chats c ON c.id = own.chat_id AND c.kind = 'CHAT'
+			  JOIN users_chats other ON other.chat_id = own.chat_id
+			  WHERE own.user_id = @user_id AND other.user_id != @user_id`
+	args := pgx.NamedArgs{
+		"user_id": userID,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error getting co-member IDs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			slog.Error("Error scanning co-member ID", "error", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating co-member IDs", "error", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// FilterCoMembers reports which of ids share at least one regular chat with
+// userID. Like in GetCoMemberIDs, channels don't count: anyone can join one.
+func (r *usersRepository) FilterCoMembers(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	result := make(map[string]bool, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := `SELECT DISTINCT other.user_id
+			  FROM users_chats own
+			  JOIN chats c ON c.id = own.chat_id AND c.kind = 'CHAT'
+			  JOIN users_chats other ON other.chat_id = own.chat_id
+			  WHERE own.user_id = @user_id AND other.user_id = ANY(@ids)`
+	args := pgx.NamedArgs{
+		"user_id": userID,
+		"ids":     ids,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error filtering co-members", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			slog.Error("Error scanning co-member ID", "error", err)
+			return nil, err
+		}
+		result[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating co-members", "error", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Search matches discoverable users by username or display name prefix first,
+// then by trigram similarity. Users who blocked the caller or were blocked
+// by them are left out. req.Query is expected to be lower case.
+func (r *usersRepository) Search(ctx context.Context, req models.SearchUsersRequest, after *models.UserSearchCursor) ([]models.UserSearchResult, error) {
+	slog.Info("Search users", "userID", req.UserID, "query", req.Query)
+
+	args := pgx.NamedArgs{
+		"user_id": req.UserID,
+		"query":   req.Query,
+		"prefix":  pgutil.EscapeLike(req.Query) + "%",
+		"limit":   req.Limit,
+	}
+
+	afterClause := ""
+	if after != nil {
+		afterClause = " WHERE (rank, -score, id) > (@after_rank, -@after_score::real, @after_id)"
+		args["after_rank"] = after.Rank
+		args["after_score"] = after.Score
+		args["after_id"] = after.ID
+	}
+
+	query := `WITH matches AS (
+				SELECT ` + userColumns + `,
+					CASE WHEN LOWER(username) LIKE @prefix OR LOWER(display_name) LIKE @prefix THEN 0 ELSE 1 END AS rank,
+					GREATEST(similarity(LOWER(username), @query), similarity(LOWER(display_name), @query)) AS score
+				FROM users u
+				WHERE u.discoverable AND u.id != @user_id
+				  AND (LOWER(u.username) LIKE @prefix OR LOWER(u.display_name) LIKE @prefix
+					OR LOWER(u.username) % @query OR LOWER(u.display_name) % @query)
+				  AND NOT EXISTS (
+					SELECT 1 FROM user_blocks b
+					WHERE (b.blocker_id = @user_id AND b.blocked_id = u.id)
+					   OR (b.blocker_id = u.id AND b.blocked_id = @user_id)
+				  )
+			  )
+			  SELECT ` + userColumns + `, rank, score
+			  FROM matches` + afterClause + `
+			  ORDER BY rank, score DESC, id
+			  LIMIT @limit`
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error searching users", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.UserSearchResult{}
+	for rows.Next() {
+		var result models.UserSearchResult
+		if err := rows.Scan(
+			&result.ID, &result.Username, &result.Email, &result.PasswordHash, &result.DisplayName, &result.Bio,
+			&result.AvatarAttachmentID, &result.Discoverable, &result.EmailVerifiedAt, &result.IsAdmin, &result.BotOwnerID, &result.CreatedAt, &result.UpdatedAt,
+			&result.Rank, &result.Score,
+		); err != nil {
+			slog.Error("Error scanning user search result", "error", err)
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating user search results", "error", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func scanUser(row pgx.Row) (models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.DisplayName, &user.Bio,
+		&user.AvatarAttachmentID, &user.Discoverable, &user.EmailVerifiedAt, &user.IsAdmin, &user.BotOwnerID, &user.CreatedAt, &user.UpdatedAt,
+	)
+	return user, err
+}
+
+// uniqueViolation turns a unique constraint error on users or their external
+// identities into the matching sentinel error.
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case "users_email_key":
+		return ErrEmailTaken
+	case "users_username_key":
+		return ErrUsernameTaken
+	case "external_identities_issuer_subject_key":
+		return ErrIdentityTaken
+	}
+
+	return err
+}
@@ -0,0 +1,581 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"golang.org/x/sync/errgroup"
+)
+
+const webhookColumns = "id, owner_id, COALESCE(chat_id, ''), COALESCE(bot_id, ''), url, secret, events, created_at"
+
+const incomingWebhookColumns = "w.id, w.chat_id, w.user_id, u.display_name, w.created_by, w.token_prefix, w.token_hash, w.revoked_at, w.created_at"
+
+const deliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.last_attempt_at, d.last_status_code, d.last_error, d.delivered_at, d.created_at`
+
+type WebhooksRepository interface {
+	Create(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	Get(ctx context.Context, webhookID string) (models.Webhook, error)
+	List(ctx context.Context, chatID, botID string) ([]models.Webhook, error)
+	Delete(ctx context.Context, webhookID string) (bool, error)
+
+	// Enqueue queues a delivery of payload to every webhook subscribed to the
+	// event, and returns how many were queued.
+	Enqueue(ctx context.Context, event models.WebhookEvent, payload json.RawMessage) (int, error)
+	// ClaimDue claims up to limit due deliveries for lease. Claimed
+	// deliveries are not claimed again until the lease expires, so a
+	// delivery whose worker died is retried.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookJob, error)
+	// RecordAttempt stores the outcome of an attempt: the status, attempts,
+	// next attempt and last response of delivery.
+	RecordAttempt(ctx context.Context, delivery models.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, req models.ListWebhookDeliveriesRequest) (models.ListWebhookDeliveriesResponse, error)
+
+	// CreateIncoming creates bot, adds it to webhook.ChatID with role and
+	// stores webhook as posting as it, all or nothing.
+	CreateIncoming(ctx context.Context, webhook models.IncomingWebhook, bot models.User, role models.ChatRole) (models.IncomingWebhook, error)
+	GetIncoming(ctx context.Context, webhookID string) (models.IncomingWebhook, error)
+	GetIncomingByHash(ctx context.Context, tokenHash string) (models.IncomingWebhook, error)
+	ListIncoming(ctx context.Context, chatID string) ([]models.IncomingWebhook, error)
+	RevokeIncoming(ctx context.Context, webhookID string) (bool, error)
+}
+
+type webhooksRepository struct {
+	reader  *pgxpool.Pool
+	writer  *pgxpool.Pool
+	entropy *ulid.MonotonicEntropy
+}
+
+func NewWebhooksRepository(reader, writer *pgxpool.Pool) WebhooksRepository {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	return &webhooksRepository{
+		reader:  reader,
+		writer:  writer,
+		entropy: entropy,
+	}
+}
+
+func (r *webhooksRepository) Create(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	slog.Info("Create webhook", "ownerID", webhook.OwnerID, "chatID", webhook.ChatID, "botID", webhook.BotID)
+
+	webhook.ID = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+
+	query := `INSERT INTO webhooks (id, owner_id, chat_id, bot_id, url, secret, events)
+			  VALUES (@id, @owner_id, NULLIF(@chat_id, ''), NULLIF(@bot_id, ''), @url, @secret, @events)
+			  RETURNING created_at`
+	args := pgx.NamedArgs{
+		"id":       webhook.ID,
+		"owner_id": webhook.OwnerID,
+		"chat_id":  webhook.ChatID,
+		"bot_id":   webhook.BotID,
+		"url":      webhook.URL,
+		"secret":   webhook.Secret,
+		"events":   webhook.Events,
+	}
+	if err := r.writer.QueryRow(ctx, query, args).Scan(&webhook.CreatedAt); err != nil {
+		slog.Error("Error creating webhook", "error", err)
+		return models.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+func (r *webhooksRepository) Get(ctx context.Context, webhookID string) (models.Webhook, error) {
+	slog.Info("Get webhook", "webhookID", webhookID)
+
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE id = @id"
+	args := pgx.NamedArgs{
+		"id": webhookID,
+	}
+
+	webhook, err := scanWebhook(r.reader.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Webhook not found", "webhookID", webhookID)
+			return models.Webhook{}, nil
+		}
+		slog.Error("Error getting webhook", "error", err)
+		return models.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// List returns the webhooks of chatID, or of botID if chatID is empty,
+// newest first.
+func (r *webhooksRepository) List(ctx context.Context, chatID, botID string) ([]models.Webhook, error) {
+	slog.Info("List webhooks", "chatID", chatID, "botID", botID)
+
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE bot_id = @bot_id ORDER BY id DESC"
+	args := pgx.NamedArgs{
+		"bot_id": botID,
+	}
+	if chatID != "" {
+		query = "SELECT " + webhookColumns + " FROM webhooks WHERE chat_id = @chat_id ORDER BY id DESC"
+		args = pgx.NamedArgs{
+			"chat_id": chatID,
+		}
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error listing webhooks", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			slog.Error("Error scanning webhook", "error", err)
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating webhooks", "error", err)
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Delete deletes webhookID and its deliveries. It reports false if there
+// was no such webhook.
+func (r *webhooksRepository) Delete(ctx context.Context, webhookID string) (bool, error) {
+	slog.Info("Delete webhook", "webhookID", webhookID)
+
+	query := "DELETE FROM webhooks WHERE id = @id"
+	args := pgx.NamedArgs{
+		"id": webhookID,
+	}
+
+	tag, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error deleting webhook", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Enqueue matches the webhooks of the event's chat and those of bots in
+// that chat. A bot's webhooks do not get the events the bot caused itself,
+// so a bot replying to messages does not feed on its own replies.
+func (r *webhooksRepository) Enqueue(ctx context.Context, event models.WebhookEvent, payload json.RawMessage) (int, error) {
+	slog.Info("Enqueue webhook deliveries", "event", event.Type, "chatID", event.ChatID)
+
+	query := `SELECT id FROM webhooks
+			  WHERE @event = ANY(events)
+			    AND (chat_id = @chat_id OR bot_id IN (SELECT user_id FROM users_chats WHERE chat_id = @chat_id))
+			    AND bot_id IS DISTINCT FROM @actor_id`
+	args := pgx.NamedArgs{
+		"event":    event.Type,
+		"chat_id":  event.ChatID,
+		"actor_id": event.ActorID,
+	}
+
+	rows, err := r.writer.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error finding webhooks", "error", err)
+		return 0, err
+	}
+
+	webhookIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		slog.Error("Error scanning webhooks", "error", err)
+		return 0, err
+	}
+
+	if len(webhookIDs) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]string, len(webhookIDs))
+	for i := range ids {
+		ids[i] = ulid.MustNew(ulid.Timestamp(time.Now()), r.entropy).String()
+	}
+
+	query = `INSERT INTO webhook_deliveries (id, webhook_id, event, payload)
+			 SELECT unnest(@ids::text[]), unnest(@webhook_ids::text[]), @event, @payload::jsonb`
+	args = pgx.NamedArgs{
+		"ids":         ids,
+		"webhook_ids": webhookIDs,
+		"event":       event.Type,
+		"payload":     string(payload),
+	}
+
+	if _, err := r.writer.Exec(ctx, query, args); err != nil {
+		slog.Error("Error enqueueing webhook deliveries", "error", err)
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+func (r *webhooksRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookJob, error) {
+	query := `UPDATE webhook_deliveries d
+			  SET next_attempt_at = NOW() + @lease::interval
+			  FROM webhooks w
+			  WHERE w.id = d.webhook_id AND d.id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT @limit
+				FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING ` + deliveryColumns + `, w.url, w.secret`
+	args := pgx.NamedArgs{
+		"lease": lease.String(),
+		"limit": limit,
+	}
+
+	rows, err := r.writer.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error claiming webhook deliveries", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.WebhookJob{}
+	for rows.Next() {
+		var job models.WebhookJob
+		d := &job.Delivery
+		if err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt,
+			&job.URL, &job.Secret,
+		); err != nil {
+			slog.Error("Error scanning webhook delivery", "error", err)
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating webhook deliveries", "error", err)
+		return nil, err
+	}
+
+	if len(jobs) > 0 {
+		slog.Info("Claimed webhook deliveries", "count", len(jobs))
+	}
+
+	return jobs, nil
+}
+
+func (r *webhooksRepository) RecordAttempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	slog.Info("Record webhook attempt", "deliveryID", delivery.ID, "status", delivery.Status, "attempts", delivery.Attempts)
+
+	query := `UPDATE webhook_deliveries
+			  SET status = @status, attempts = @attempts, next_attempt_at = @next_attempt_at,
+				last_attempt_at = @last_attempt_at, last_status_code = @last_status_code,
+				last_error = @last_error, delivered_at = @delivered_at
+			  WHERE id = @id`
+	args := pgx.NamedArgs{
+		"id":               delivery.ID,
+		"status":           delivery.Status,
+		"attempts":         delivery.Attempts,
+		"next_attempt_at":  delivery.NextAttemptAt,
+		"last_attempt_at":  delivery.LastAttemptAt,
+		"last_status_code": delivery.LastStatusCode,
+		"last_error":       delivery.LastError,
+		"delivered_at":     delivery.DeliveredAt,
+	}
+
+	if _, err := r.writer.Exec(ctx, query, args); err != nil {
+		slog.Error("Error recording webhook attempt", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListDeliveries pages through the deliveries of a webhook, newest first,
+// optionally only those with a status.
+func (r *webhooksRepository) ListDeliveries(ctx context.Context, req models.ListWebhookDeliveriesRequest) (models.ListWebhookDeliveriesResponse, error) {
+	var (
+		deliveries []models.WebhookDelivery
+		total      int32
+		page       = req.Page
+		limit      = req.Limit
+	)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 {
+		limit = 20
+	}
+
+	slog.Info("Listing webhook deliveries", "page", page, "limit", limit, "webhookID", req.WebhookID, "status", req.Status)
+
+	filter := "d.webhook_id = @webhook_id AND (@status = '' OR d.status::text = @status)"
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		query := "SELECT " + deliveryColumns + ` FROM webhook_deliveries d
+				  WHERE ` + filter + `
+				  ORDER BY d.id DESC
+				  LIMIT @limit OFFSET @offset`
+		args := pgx.NamedArgs{
+			"webhook_id": req.WebhookID,
+			"status":     string(req.Status),
+			"limit":      limit,
+			"offset":     (page - 1) * limit,
+		}
+		rows, err := r.reader.Query(ctx, query, args)
+		if err != nil {
+			slog.Error("Error listing webhook deliveries", "error", err)
+			return err
+		}
+		defer rows.Close()
+
+		result := []models.WebhookDelivery{}
+		for rows.Next() {
+			var d models.WebhookDelivery
+			if err := rows.Scan(
+				&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+				&d.LastAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt,
+			); err != nil {
+				slog.Error("Error scanning webhook delivery", "error", err)
+				return err
+			}
+			result = append(result, d)
+		}
+		if err := rows.Err(); err != nil {
+			slog.Error("Error iterating webhook deliveries", "error", err)
+			return err
+		}
+		deliveries = result
+		return nil
+	})
+
+	g.Go(func() error {
+		query := "SELECT COUNT(*) FROM webhook_deliveries d WHERE " + filter
+		args := pgx.NamedArgs{
+			"webhook_id": req.WebhookID,
+			"status":     string(req.Status),
+		}
+		if err := r.reader.QueryRow(ctx, query, args).Scan(&total); err != nil {
+			slog.Error("Error counting webhook deliveries", "error", err)
+			return err
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return models.ListWebhookDeliveriesResponse{}, err
+	}
+
+	return models.ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+	}, nil
+}
+
+func (r *webhooksRepository) CreateIncoming(ctx context.Context, webhook models.IncomingWebhook, bot models.User, role models.ChatRole) (models.IncomingWebhook, error) {
+	slog.Info("Create incoming webhook", "chatID", webhook.ChatID, "createdBy", webhook.CreatedBy)
+
+	tx, err := r.writer.Begin(ctx)
+	if err != nil {
+		slog.Error("Error starting transaction", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	webhook.ID = ulid.MustNew(ulid.Timestamp(now), r.entropy).String()
+	webhook.UserID = ulid.MustNew(ulid.Timestamp(now), r.entropy).String()
+
+	// Like users.CreateBot: no password and a placeholder email.
+	query := `INSERT INTO users (id, username, email, password_hash, display_name, discoverable, bot_owner_id)
+			  VALUES (@id, @username, @email, '', @display_name, FALSE, @bot_owner_id)`
+	args := pgx.NamedArgs{
+		"id":           webhook.UserID,
+		"username":     bot.Username,
+		"email":        strings.ToLower(webhook.UserID) + "@" + models.BotEmailDomain,
+		"display_name": bot.DisplayName,
+		"bot_owner_id": bot.BotOwnerID,
+	}
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		slog.Error("Error creating incoming webhook bot", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+
+	query = `INSERT INTO users_chats (id, user_id, chat_id, role) VALUES (@id, @user_id, @chat_id, @role)`
+	args = pgx.NamedArgs{
+		"id":      ulid.MustNew(ulid.Timestamp(now), r.entropy).String(),
+		"user_id": webhook.UserID,
+		"chat_id": webhook.ChatID,
+		"role":    role,
+	}
+	if _, err := tx.Exec(ctx, query, args); err != nil {
+		slog.Error("Error adding incoming webhook bot to chat", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+
+	query = `INSERT INTO incoming_webhooks (id, chat_id, user_id, created_by, token_prefix, token_hash)
+			 VALUES (@id, @chat_id, @user_id, @created_by, @token_prefix, @token_hash)
+			 RETURNING created_at`
+	args = pgx.NamedArgs{
+		"id":           webhook.ID,
+		"chat_id":      webhook.ChatID,
+		"user_id":      webhook.UserID,
+		"created_by":   webhook.CreatedBy,
+		"token_prefix": webhook.TokenPrefix,
+		"token_hash":   webhook.TokenHash,
+	}
+	if err := tx.QueryRow(ctx, query, args).Scan(&webhook.CreatedAt); err != nil {
+		slog.Error("Error creating incoming webhook", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		slog.Error("Error committing incoming webhook", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+
+	webhook.Name = bot.DisplayName
+	return webhook, nil
+}
+
+func (r *webhooksRepository) GetIncoming(ctx context.Context, webhookID string) (models.IncomingWebhook, error) {
+	slog.Info("Get incoming webhook", "webhookID", webhookID)
+
+	query := "SELECT " + incomingWebhookColumns + `
+			  FROM incoming_webhooks w
+			  JOIN users u ON u.id = w.user_id
+			  WHERE w.id = @id`
+	args := pgx.NamedArgs{
+		"id": webhookID,
+	}
+
+	webhook, err := scanIncomingWebhook(r.reader.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Incoming webhook not found", "webhookID", webhookID)
+			return models.IncomingWebhook{}, nil
+		}
+		slog.Error("Error getting incoming webhook", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// GetIncomingByHash returns the incoming webhook with tokenHash, revoked or
+// not. It reads from the writer, so a revoked webhook stops working at once.
+func (r *webhooksRepository) GetIncomingByHash(ctx context.Context, tokenHash string) (models.IncomingWebhook, error) {
+	slog.Info("Get incoming webhook by hash")
+
+	query := "SELECT " + incomingWebhookColumns + `
+			  FROM incoming_webhooks w
+			  JOIN users u ON u.id = w.user_id
+			  WHERE w.token_hash = @token_hash`
+	args := pgx.NamedArgs{
+		"token_hash": tokenHash,
+	}
+
+	webhook, err := scanIncomingWebhook(r.writer.QueryRow(ctx, query, args))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			slog.Info("Incoming webhook not found")
+			return models.IncomingWebhook{}, nil
+		}
+		slog.Error("Error getting incoming webhook", "error", err)
+		return models.IncomingWebhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// ListIncoming returns the incoming webhooks of chatID that are not
+// revoked, newest first.
+func (r *webhooksRepository) ListIncoming(ctx context.Context, chatID string) ([]models.IncomingWebhook, error) {
+	slog.Info("List incoming webhooks", "chatID", chatID)
+
+	query := "SELECT " + incomingWebhookColumns + `
+			  FROM incoming_webhooks w
+			  JOIN users u ON u.id = w.user_id
+			  WHERE w.chat_id = @chat_id AND w.revoked_at IS NULL
+			  ORDER BY w.id DESC`
+	args := pgx.NamedArgs{
+		"chat_id": chatID,
+	}
+
+	rows, err := r.reader.Query(ctx, query, args)
+	if err != nil {
+		slog.Error("Error listing incoming webhooks", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.IncomingWebhook{}
+	for rows.Next() {
+		webhook, err := scanIncomingWebhook(rows)
+		if err != nil {
+			slog.Error("Error scanning incoming webhook", "error", err)
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("Error iterating incoming webhooks", "error", err)
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// RevokeIncoming revokes webhookID. It reports false if there was no such
+// active webhook.
+func (r *webhooksRepository) RevokeIncoming(ctx context.Context, webhookID string) (bool, error) {
+	slog.Info("Revoke incoming webhook", "webhookID", webhookID)
+
+	query := `UPDATE incoming_webhooks
+			  SET revoked_at = NOW()
+			  WHERE id = @id AND revoked_at IS NULL`
+	args := pgx.NamedArgs{
+		"id": webhookID,
+	}
+
+	tag, err := r.writer.Exec(ctx, query, args)
+	if err != nil {
+		slog.Error("Error revoking incoming webhook", "error", err)
+		return false, err
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanIncomingWebhook(row pgx.Row) (models.IncomingWebhook, error) {
+	var webhook models.IncomingWebhook
+	err := row.Scan(
+		&webhook.ID, &webhook.ChatID, &webhook.UserID, &webhook.Name, &webhook.CreatedBy, &webhook.TokenPrefix,
+		&webhook.TokenHash, &webhook.RevokedAt, &webhook.CreatedAt,
+	)
+	return webhook, err
+}
+
+func scanWebhook(row pgx.Row) (models.Webhook, error) {
+	var webhook models.Webhook
+	err := row.Scan(
+		&webhook.ID, &webhook.OwnerID, &webhook.ChatID, &webhook.BotID, &webhook.URL, &webhook.Secret,
+		&webhook.Events, &webhook.CreatedAt,
+	)
+	return webhook, err
+}
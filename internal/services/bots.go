@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/apikeys"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognize.
+const APIKeyPrefix = "mak_"
+
+// apiKeyDisplayLength is how much of a key is stored in clear to tell keys
+// apart.
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+type BotsService interface {
+	CreateBot(ctx context.Context, req models.CreateBotRequest) (models.User, error)
+	ListBots(ctx context.Context, ownerID string) ([]models.User, error)
+	AddBotToChat(ctx context.Context, ownerID, botID, chatID string) error
+	CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (models.CreateAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, ownerID, botID string) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, ownerID, botID, keyID string) error
+	// AuthenticateAPIKey resolves a key from the x-api-key header to the
+	// bot it acts for, restricted to the key's scopes and chats.
+	AuthenticateAPIKey(ctx context.Context, key string) (*jwt.Claims, error)
+}
+
+type botsService struct {
+	usersRepo   users.UsersRepository
+	apiKeysRepo apikeys.APIKeysRepository
+	chatsRepo   chats.ChatsRepository
+	webhooks    WebhookPublisher
+}
+
+func NewBotsService(usersRepo users.UsersRepository, apiKeysRepo apikeys.APIKeysRepository, chatsRepo chats.ChatsRepository, webhooks WebhookPublisher) BotsService {
+	return &botsService{
+		usersRepo:   usersRepo,
+		apiKeysRepo: apiKeysRepo,
+		chatsRepo:   chatsRepo,
+		webhooks:    webhooks,
+	}
+}
+
+func (s *botsService) CreateBot(ctx context.Context, req models.CreateBotRequest) (models.User, error) {
+	slog.Info("CreateBot service", "ownerID", req.OwnerID, "username", req.Username)
+
+	owner, err := s.usersRepo.GetByID(ctx, req.OwnerID)
+	if err != nil {
+		slog.Error("Error getting owner", "error", err)
+		return models.User{}, err
+	}
+
+	// Bots cannot own bots.
+	if owner.ID == "" || owner.IsBot() {
+		return models.User{}, ErrPermissionDenied
+	}
+
+	bots, err := s.usersRepo.ListBots(ctx, req.OwnerID)
+	if err != nil {
+		slog.Error("Error listing bots", "error", err)
+		return models.User{}, err
+	}
+
+	if len(bots) >= models.MaxBotsPerOwner {
+		slog.Warn("Too many bots", "ownerID", req.OwnerID, "count", len(bots))
+		return models.User{}, ErrLimitReached
+	}
+
+	existing, err := s.usersRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		slog.Error("Error getting user by username", "error", err)
+		return models.User{}, err
+	}
+
+	if existing.ID != "" {
+		return models.User{}, ErrUsernameTaken
+	}
+
+	botID, err := s.usersRepo.CreateBot(ctx, models.User{
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		BotOwnerID:  req.OwnerID,
+	})
+	if err != nil {
+		slog.Error("Error creating bot", "error", err)
+		return models.User{}, err
+	}
+
+	return s.usersRepo.GetByID(ctx, botID)
+}
+
+func (s *botsService) ListBots(ctx context.Context, ownerID string) ([]models.User, error) {
+	slog.Info("ListBots service", "ownerID", ownerID)
+
+	return s.usersRepo.ListBots(ctx, ownerID)
+}
+
+// AddBotToChat adds a bot to a chat its owner is in. Bots join channels as
+// admins, since only admins post there, so only channel admins add them.
+func (s *botsService) AddBotToChat(ctx context.Context, ownerID, botID, chatID string) error {
+	slog.Info("AddBotToChat service", "ownerID", ownerID, "botID", botID, "chatID", chatID)
+
+	if _, err := ownedBot(ctx, s.usersRepo, ownerID, botID); err != nil {
+		return err
+	}
+
+	membership, err := s.chatsRepo.GetMembership(ctx, chatID, ownerID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	if membership.Role == "" {
+		return ErrNotFound
+	}
+
+	role := models.ChatRoleMember
+	if membership.Kind == models.ChatKindChannel {
+		if membership.Role != models.ChatRoleAdmin {
+			slog.Warn("Only channel admins can add bots", "ownerID", ownerID, "chatID", chatID)
+			return ErrPermissionDenied
+		}
+		role = models.ChatRoleAdmin
+	}
+
+	botMembership, err := s.chatsRepo.GetMembership(ctx, chatID, botID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	if botMembership.Role != "" {
+		return nil
+	}
+
+	if err := s.chatsRepo.AddUserToChat(ctx, botID, chatID, role); err != nil {
+		slog.Error("Error adding bot to chat", "error", err)
+		return err
+	}
+
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, models.WebhookEvent{
+			Type:    models.WebhookEventMemberJoined,
+			ChatID:  chatID,
+			ActorID: ownerID,
+			Data: map[string]any{
+				"user_id": botID,
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s *botsService) CreateAPIKey(ctx context.Context, req models.CreateAPIKeyRequest) (models.CreateAPIKeyResponse, error) {
+	slog.Info("CreateAPIKey service", "ownerID", req.OwnerID, "botID", req.BotID, "scopes", req.Scopes)
+
+	if _, err := ownedBot(ctx, s.usersRepo, req.OwnerID, req.BotID); err != nil {
+		return models.CreateAPIKeyResponse{}, err
+	}
+
+	// Keys without scopes would not be restricted at all.
+	if len(req.Scopes) == 0 {
+		return models.CreateAPIKeyResponse{}, ErrInvalidScope
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return models.CreateAPIKeyResponse{}, ErrInvalidScope
+		}
+	}
+
+	count, err := s.apiKeysRepo.CountActive(ctx, req.BotID)
+	if err != nil {
+		slog.Error("Error counting API keys", "error", err)
+		return models.CreateAPIKeyResponse{}, err
+	}
+
+	if count >= models.MaxAPIKeysPerBot {
+		slog.Warn("Too many API keys", "botID", req.BotID, "count", count)
+		return models.CreateAPIKeyResponse{}, ErrLimitReached
+	}
+
+	secret, _, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating API key", "error", err)
+		return models.CreateAPIKeyResponse{}, err
+	}
+	key := APIKeyPrefix + secret
+
+	apiKey, err := s.apiKeysRepo.Create(ctx, models.APIKey{
+		BotID:     req.BotID,
+		Name:      req.Name,
+		KeyPrefix: key[:apiKeyDisplayLength],
+		KeyHash:   hashToken(key),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+		ChatIDs:   slices.Compact(slices.Sorted(slices.Values(req.ChatIDs))),
+	})
+	if err != nil {
+		slog.Error("Error creating API key", "error", err)
+		return models.CreateAPIKeyResponse{}, err
+	}
+
+	return models.CreateAPIKeyResponse{
+		Key:    key,
+		APIKey: apiKey,
+	}, nil
+}
+
+func (s *botsService) ListAPIKeys(ctx context.Context, ownerID, botID string) ([]models.APIKey, error) {
+	slog.Info("ListAPIKeys service", "ownerID", ownerID, "botID", botID)
+
+	if _, err := ownedBot(ctx, s.usersRepo, ownerID, botID); err != nil {
+		return nil, err
+	}
+
+	return s.apiKeysRepo.ListByBot(ctx, botID)
+}
+
+func (s *botsService) RevokeAPIKey(ctx context.Context, ownerID, botID, keyID string) error {
+	slog.Info("RevokeAPIKey service", "ownerID", ownerID, "botID", botID, "keyID", keyID)
+
+	if _, err := ownedBot(ctx, s.usersRepo, ownerID, botID); err != nil {
+		return err
+	}
+
+	revoked, err := s.apiKeysRepo.Revoke(ctx, botID, keyID)
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *botsService) AuthenticateAPIKey(ctx context.Context, key string) (*jwt.Claims, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeysRepo.GetByHash(ctx, hashToken(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.ID == "" || apiKey.RevokedAt != nil || len(apiKey.Scopes) == 0 {
+		slog.Warn("Invalid API key", "keyID", apiKey.ID)
+		return nil, ErrInvalidAPIKey
+	}
+
+	bot, err := s.usersRepo.GetByID(ctx, apiKey.BotID)
+	if err != nil {
+		slog.Error("Error getting bot", "error", err)
+		return nil, err
+	}
+
+	if bot.ID == "" || !bot.IsBot() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// The key works even if recording its use fails.
+	_ = s.apiKeysRepo.Touch(ctx, apiKey.ID)
+
+	return &jwt.Claims{
+		UserID:   bot.ID,
+		Username: bot.Username,
+		Email:    bot.Email,
+		Scopes:   apiKey.Scopes,
+		Chats:    apiKey.ChatIDs,
+	}, nil
+}
+
+// ownedBot returns the bot botID if ownerID owns it, and ErrNotFound
+// otherwise, so bots of others cannot be told apart from missing ones.
+func ownedBot(ctx context.Context, usersRepo users.UsersRepository, ownerID, botID string) (models.User, error) {
+	bot, err := usersRepo.GetByID(ctx, botID)
+	if err != nil {
+		slog.Error("Error getting bot", "error", err)
+		return models.User{}, err
+	}
+
+	if bot.ID == "" || bot.BotOwnerID != ownerID {
+		return models.User{}, ErrNotFound
+	}
+
+	return bot, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBotsService() (*botsService, *fakeUsersRepository, *fakeAPIKeysRepository, *fakeChatsRepository) {
+	usersRepo := newFakeUsersRepository(
+		models.User{ID: "alice", Username: "alice"},
+		models.User{ID: "bob", Username: "bob"},
+	)
+	apiKeysRepo := newFakeAPIKeysRepository()
+	chatsRepo := newFakeChatsRepository()
+
+	service := NewBotsService(usersRepo, apiKeysRepo, chatsRepo, nil).(*botsService)
+	return service, usersRepo, apiKeysRepo, chatsRepo
+}
+
+func createTestBot(t *testing.T, service *botsService) models.User {
+	t.Helper()
+
+	bot, err := service.CreateBot(context.Background(), models.CreateBotRequest{OwnerID: "alice", Username: "ci-bot", DisplayName: "CI"})
+	require.NoError(t, err)
+	return bot
+}
+
+func TestBotsService_CreateBot(t *testing.T) {
+	service, _, _, _ := newTestBotsService()
+	ctx := context.Background()
+
+	bot := createTestBot(t, service)
+	assert.True(t, bot.IsBot())
+	assert.Equal(t, "alice", bot.BotOwnerID)
+	assert.Empty(t, bot.PasswordHash)
+	assert.True(t, strings.HasSuffix(bot.Email, "@"+models.BotEmailDomain))
+
+	_, err := service.CreateBot(ctx, models.CreateBotRequest{OwnerID: "bob", Username: "CI-Bot"})
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+
+	// Bots cannot own bots.
+	_, err = service.CreateBot(ctx, models.CreateBotRequest{OwnerID: bot.ID, Username: "sub-bot"})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	bots, err := service.ListBots(ctx, "alice")
+	require.NoError(t, err)
+	assert.Len(t, bots, 1)
+}
+
+func TestBotsService_AddBotToChat(t *testing.T) {
+	service, _, _, chatsRepo := newTestBotsService()
+	ctx := context.Background()
+	bot := createTestBot(t, service)
+
+	chatsRepo.addChat(models.ChatWithLastMessage{Chat: models.Chat{ID: "group", Kind: models.ChatKindChat}}, map[string]models.ChatRole{"alice": models.ChatRoleMember})
+	chatsRepo.addChat(models.ChatWithLastMessage{Chat: models.Chat{ID: "news", Kind: models.ChatKindChannel}}, map[string]models.ChatRole{"alice": models.ChatRoleMember, "bob": models.ChatRoleAdmin})
+	chatsRepo.addChat(models.ChatWithLastMessage{Chat: models.Chat{ID: "private"}}, map[string]models.ChatRole{"bob": models.ChatRoleMember})
+
+	require.NoError(t, service.AddBotToChat(ctx, "alice", bot.ID, "group"))
+	assert.Equal(t, models.ChatRoleMember, chatsRepo.roles["group"][bot.ID])
+
+	// Only channel admins add bots to channels.
+	assert.ErrorIs(t, service.AddBotToChat(ctx, "alice", bot.ID, "news"), ErrPermissionDenied)
+	assert.ErrorIs(t, service.AddBotToChat(ctx, "alice", bot.ID, "private"), ErrNotFound)
+	// Only the owner adds the bot anywhere.
+	assert.ErrorIs(t, service.AddBotToChat(ctx, "bob", bot.ID, "news"), ErrNotFound)
+}
+
+func TestBotsService_AddBotToChannelAsAdmin(t *testing.T) {
+	service, _, _, chatsRepo := newTestBotsService()
+	bot := createTestBot(t, service)
+
+	chatsRepo.addChat(models.ChatWithLastMessage{Chat: models.Chat{ID: "news", Kind: models.ChatKindChannel}}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin})
+
+	require.NoError(t, service.AddBotToChat(context.Background(), "alice", bot.ID, "news"))
+	assert.Equal(t, models.ChatRoleAdmin, chatsRepo.roles["news"][bot.ID])
+}
+
+func TestBotsService_APIKeyAuthenticates(t *testing.T) {
+	service, _, apiKeysRepo, _ := newTestBotsService()
+	ctx := context.Background()
+	bot := createTestBot(t, service)
+
+	resp, err := service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{
+		OwnerID: "alice",
+		BotID:   bot.ID,
+		Name:    "deploys",
+		Scopes:  []string{models.ScopeMessagesSend, models.ScopeChatsRead, models.ScopeMessagesSend},
+		ChatIDs: []string{"chat1"},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Key, APIKeyPrefix))
+	assert.Equal(t, resp.Key[:apiKeyDisplayLength], resp.APIKey.KeyPrefix)
+	assert.Equal(t, []string{models.ScopeChatsRead, models.ScopeMessagesSend}, resp.APIKey.Scopes)
+
+	// Only the hash is stored.
+	stored := apiKeysRepo.keys[resp.APIKey.ID]
+	assert.NotContains(t, stored.KeyHash, resp.Key)
+	assert.Equal(t, hashToken(resp.Key), stored.KeyHash)
+
+	claims, err := service.AuthenticateAPIKey(ctx, resp.Key)
+	require.NoError(t, err)
+	assert.Equal(t, bot.ID, claims.UserID)
+	assert.Equal(t, "ci-bot", claims.Username)
+	assert.Equal(t, []string{models.ScopeChatsRead, models.ScopeMessagesSend}, claims.Scopes)
+	assert.True(t, claims.AllowsChat("chat1"))
+	assert.False(t, claims.AllowsChat("chat2"))
+	assert.Equal(t, []string{resp.APIKey.ID}, apiKeysRepo.touched)
+
+	_, err = service.AuthenticateAPIKey(ctx, APIKeyPrefix+"unknown")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	_, err = service.AuthenticateAPIKey(ctx, "not-a-key")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestBotsService_RevokedAPIKeyIsRejected(t *testing.T) {
+	service, _, _, _ := newTestBotsService()
+	ctx := context.Background()
+	bot := createTestBot(t, service)
+
+	resp, err := service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "alice", BotID: bot.ID, Scopes: []string{models.ScopeChatsRead}})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, service.RevokeAPIKey(ctx, "bob", bot.ID, resp.APIKey.ID), ErrNotFound)
+	require.NoError(t, service.RevokeAPIKey(ctx, "alice", bot.ID, resp.APIKey.ID))
+	assert.ErrorIs(t, service.RevokeAPIKey(ctx, "alice", bot.ID, resp.APIKey.ID), ErrNotFound)
+
+	_, err = service.AuthenticateAPIKey(ctx, resp.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	keys, err := service.ListAPIKeys(ctx, "alice", bot.ID)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestBotsService_CreateAPIKeyValidates(t *testing.T) {
+	service, _, _, _ := newTestBotsService()
+	ctx := context.Background()
+	bot := createTestBot(t, service)
+
+	_, err := service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "alice", BotID: bot.ID})
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	_, err = service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "alice", BotID: bot.ID, Scopes: []string{"users:admin"}})
+	assert.ErrorIs(t, err, ErrInvalidScope)
+
+	_, err = service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "bob", BotID: bot.ID, Scopes: []string{models.ScopeChatsRead}})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Keys are only created for bots.
+	_, err = service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "alice", BotID: "alice", Scopes: []string{models.ScopeChatsRead}})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for range models.MaxAPIKeysPerBot {
+		_, err = service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "alice", BotID: bot.ID, Scopes: []string{models.ScopeChatsRead}})
+		require.NoError(t, err)
+	}
+	_, err = service.CreateAPIKey(ctx, models.CreateAPIKeyRequest{OwnerID: "alice", BotID: bot.ID, Scopes: []string{models.ScopeChatsRead}})
+	assert.ErrorIs(t, err, ErrLimitReached)
+}
+
+func TestUsersService_Login_RejectsBots(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	usersRepo := service.usersRepo.(*fakeUsersRepository)
+
+	alice := usersRepo.users["alice"]
+	alice.BotOwnerID = "bob"
+	usersRepo.users["alice"] = alice
+
+	_, err := service.Login(context.Background(), models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	assert.ErrorContains(t, err, "invalid credentials")
+}
@@ -2,34 +2,59 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/messages"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/moderation"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
 )
 
 type ChatsService interface {
 	CreateChat(ctx context.Context, req models.CreateChatRequest) (models.CreateChatResponse, error)
-	GetChat(ctx context.Context, req models.GetChatRequest) (models.Chat, error)
+	GetChat(ctx context.Context, req models.GetChatRequest) (models.ChatWithLastMessage, error)
 	ListChats(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error)
+	UpdateChatSettings(ctx context.Context, req models.UpdateChatSettingsRequest) (models.ChatSettings, error)
+	UpdateChat(ctx context.Context, req models.UpdateChatRequest) (models.ChatWithLastMessage, error)
+	CreateChannel(ctx context.Context, req models.CreateChannelRequest) (models.CreateChatResponse, error)
+	JoinChannel(ctx context.Context, req models.JoinChannelRequest) error
+	LeaveChannel(ctx context.Context, req models.LeaveChannelRequest) error
+	AddChannelMember(ctx context.Context, req models.AddChannelMemberRequest) error
+	DiscoverChannels(ctx context.Context, req models.DiscoverChannelsRequest) (models.DiscoverChannelsResponse, error)
 }
 
 type chatsService struct {
-	chatsRepo chats.ChatsRepository
-	usersRepo users.UsersRepository
+	chatsRepo      chats.ChatsRepository
+	usersRepo      users.UsersRepository
+	messagesRepo   messages.MessagesRepository
+	moderationRepo moderation.ModerationRepository
+	realtime       RealtimeService
+	verification   EmailVerificationPolicy
+	webhooks       WebhookPublisher
 }
 
-func NewChatsService(chatsRepo chats.ChatsRepository, usersRepo users.UsersRepository) ChatsService {
+func NewChatsService(chatsRepo chats.ChatsRepository, usersRepo users.UsersRepository, messagesRepo messages.MessagesRepository, moderationRepo moderation.ModerationRepository, realtime RealtimeService, verification EmailVerificationPolicy, webhooks WebhookPublisher) ChatsService {
 	return &chatsService{
-		chatsRepo: chatsRepo,
-		usersRepo: usersRepo,
+		chatsRepo:      chatsRepo,
+		usersRepo:      usersRepo,
+		messagesRepo:   messagesRepo,
+		moderationRepo: moderationRepo,
+		realtime:       realtime,
+		verification:   verification,
+		webhooks:       webhooks,
 	}
 }
 
 func (s *chatsService) CreateChat(ctx context.Context, req models.CreateChatRequest) (models.CreateChatResponse, error) {
 	slog.Info("CreateChat service", "userID", req.UserID, "Email", req.Email)
 
+	if err := s.requireVerifiedEmail(ctx, req.UserID, s.verification.CreateChats); err != nil {
+		return models.CreateChatResponse{}, err
+	}
+
 	toUser, err := s.usersRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		slog.Error("Error getting user by email", "error", err)
@@ -41,6 +66,17 @@ func (s *chatsService) CreateChat(ctx context.Context, req models.CreateChatRequ
 		return models.CreateChatResponse{}, err
 	}
 
+	blocked, err := s.moderationRepo.IsBlocked(ctx, req.UserID, toUser.ID)
+	if err != nil {
+		slog.Error("Error checking blocks", "error", err)
+		return models.CreateChatResponse{}, err
+	}
+
+	if blocked {
+		slog.Warn("Cannot create a chat with a blocked user", "userID", req.UserID, "otherUserID", toUser.ID)
+		return models.CreateChatResponse{}, ErrBlocked
+	}
+
 	chat := models.Chat{
 		Name: req.Name,
 	}
@@ -51,13 +87,13 @@ func (s *chatsService) CreateChat(ctx context.Context, req models.CreateChatRequ
 		return models.CreateChatResponse{}, err
 	}
 
-	err = s.chatsRepo.AddUserToChat(ctx, req.UserID, chatID)
+	err = s.chatsRepo.AddUserToChat(ctx, req.UserID, chatID, models.ChatRoleAdmin)
 	if err != nil {
 		slog.Error("Error adding user to chat", "error", err)
 		return models.CreateChatResponse{}, err
 	}
 
-	err = s.chatsRepo.AddUserToChat(ctx, toUser.ID, chatID)
+	err = s.chatsRepo.AddUserToChat(ctx, toUser.ID, chatID, models.ChatRoleMember)
 	if err != nil {
 		slog.Error("Error adding user to chat", "error", err)
 		return models.CreateChatResponse{}, err
@@ -68,14 +104,354 @@ func (s *chatsService) CreateChat(ctx context.Context, req models.CreateChatRequ
 	}, nil
 }
 
-func (s *chatsService) GetChat(ctx context.Context, req models.GetChatRequest) (models.Chat, error) {
+func (s *chatsService) GetChat(ctx context.Context, req models.GetChatRequest) (models.ChatWithLastMessage, error) {
 	slog.Info("GetChat service", "chatID", req.ID, "userID", req.UserID)
 
-	return s.chatsRepo.Get(ctx, req)
+	chat, err := s.chatsRepo.Get(ctx, req)
+	if err != nil || chat.ID == "" {
+		return chat, err
+	}
+
+	// Channels can have thousands of subscribers, so their members are not
+	// loaded with the chat.
+	if chat.Kind == models.ChatKindChannel {
+		return chat, nil
+	}
+
+	chat.Members, err = s.chatsRepo.GetChatUsers(ctx, chat.ID)
+	if err != nil {
+		slog.Error("Error getting chat members", "error", err)
+		return models.ChatWithLastMessage{}, err
+	}
+
+	return chat, nil
 }
 
 func (s *chatsService) ListChats(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error) {
 	slog.Info("ListChats service", "userID", req.UserID)
 
-	return s.chatsRepo.List(ctx, req)
+	resp, err := s.chatsRepo.List(ctx, req)
+	if err != nil {
+		return models.ListChatsResponse{}, err
+	}
+
+	chatIDs := make([]string, 0, len(resp.Chats))
+	for _, chat := range resp.Chats {
+		if chat.Kind != models.ChatKindChannel {
+			chatIDs = append(chatIDs, chat.ID)
+		}
+	}
+
+	members, err := s.chatsRepo.GetUsersByChatIDs(ctx, chatIDs)
+	if err != nil {
+		slog.Error("Error getting chat members", "error", err)
+		return models.ListChatsResponse{}, err
+	}
+
+	for i := range resp.Chats {
+		resp.Chats[i].Members = members[resp.Chats[i].ID]
+	}
+
+	return resp, nil
+}
+
+func (s *chatsService) UpdateChatSettings(ctx context.Context, req models.UpdateChatSettingsRequest) (models.ChatSettings, error) {
+	slog.Info("UpdateChatSettings service", "chatID", req.ChatID, "userID", req.UserID)
+
+	return s.chatsRepo.UpdateSettings(ctx, req)
+}
+
+func (s *chatsService) UpdateChat(ctx context.Context, req models.UpdateChatRequest) (models.ChatWithLastMessage, error) {
+	slog.Info("UpdateChat service", "chatID", req.ChatID, "userID", req.UserID)
+
+	chat, err := s.chatsRepo.Get(ctx, models.GetChatRequest{ID: req.ChatID, UserID: req.UserID})
+	if err != nil {
+		slog.Error("Error getting chat", "error", err)
+		return models.ChatWithLastMessage{}, err
+	}
+
+	if chat.ID == "" {
+		return models.ChatWithLastMessage{}, ErrNotFound
+	}
+
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return models.ChatWithLastMessage{}, err
+	}
+
+	if membership.Role != models.ChatRoleAdmin {
+		slog.Warn("User is not allowed to update chat", "chatID", req.ChatID, "userID", req.UserID, "role", membership.Role)
+		return models.ChatWithLastMessage{}, ErrPermissionDenied
+	}
+
+	var changes []string
+	if req.Name != nil && *req.Name != chat.Name {
+		changes = append(changes, fmt.Sprintf("%s renamed the chat to %q", req.Username, *req.Name))
+	} else {
+		req.Name = nil
+	}
+	if req.Description != nil && *req.Description != chat.Description {
+		changes = append(changes, fmt.Sprintf("%s changed the chat description", req.Username))
+	} else {
+		req.Description = nil
+	}
+	if req.AvatarAttachmentID != nil && *req.AvatarAttachmentID != chat.AvatarAttachmentID {
+		changes = append(changes, fmt.Sprintf("%s changed the chat avatar", req.Username))
+	} else {
+		req.AvatarAttachmentID = nil
+	}
+
+	if len(changes) == 0 {
+		return chat, nil
+	}
+
+	if err := s.chatsRepo.Update(ctx, req); err != nil {
+		slog.Error("Error updating chat", "error", err)
+		return models.ChatWithLastMessage{}, err
+	}
+
+	for _, change := range changes {
+		s.recordSystemMessage(ctx, req.ChatID, req.UserID, change)
+	}
+
+	updated, err := s.GetChat(ctx, models.GetChatRequest{ID: req.ChatID, UserID: req.UserID})
+	if err != nil {
+		return models.ChatWithLastMessage{}, err
+	}
+
+	if s.webhooks != nil {
+		s.webhooks.Publish(ctx, models.WebhookEvent{
+			Type:    models.WebhookEventChatUpdated,
+			ChatID:  req.ChatID,
+			ActorID: req.UserID,
+			Data: map[string]any{
+				"chat": map[string]any{
+					"id":                   updated.ID,
+					"name":                 updated.Name,
+					"description":          updated.Description,
+					"avatar_attachment_id": updated.AvatarAttachmentID,
+				},
+				"changes": changes,
+			},
+		})
+	}
+
+	return updated, nil
+}
+
+func (s *chatsService) CreateChannel(ctx context.Context, req models.CreateChannelRequest) (models.CreateChatResponse, error) {
+	slog.Info("CreateChannel service", "userID", req.UserID, "name", req.Name, "visibility", req.Visibility)
+
+	if err := s.requireVerifiedEmail(ctx, req.UserID, s.verification.CreateChats); err != nil {
+		return models.CreateChatResponse{}, err
+	}
+
+	chatID, err := s.chatsRepo.Create(ctx, models.Chat{
+		Name:        req.Name,
+		Kind:        models.ChatKindChannel,
+		Visibility:  req.Visibility,
+		Description: req.Description,
+	})
+	if err != nil {
+		slog.Error("Error creating channel", "error", err)
+		return models.CreateChatResponse{}, err
+	}
+
+	err = s.chatsRepo.AddUserToChat(ctx, req.UserID, chatID, models.ChatRoleAdmin)
+	if err != nil {
+		slog.Error("Error adding user to channel", "error", err)
+		return models.CreateChatResponse{}, err
+	}
+
+	return models.CreateChatResponse{
+		ChatId: chatID,
+	}, nil
+}
+
+func (s *chatsService) JoinChannel(ctx context.Context, req models.JoinChannelRequest) error {
+	slog.Info("JoinChannel service", "chatID", req.ChatID, "userID", req.UserID)
+
+	if err := s.requireVerifiedEmail(ctx, req.UserID, s.verification.JoinChannels); err != nil {
+		return err
+	}
+
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	// Private channels are invite-only and must not be revealed to outsiders.
+	if membership.ChatID == "" || membership.Kind != models.ChatKindChannel ||
+		(membership.Visibility != models.ChatVisibilityPublic && membership.Role == "") {
+		return ErrNotFound
+	}
+
+	if membership.Role != "" {
+		return nil
+	}
+
+	if err := s.chatsRepo.AddUserToChat(ctx, req.UserID, req.ChatID, models.ChatRoleMember); err != nil {
+		slog.Error("Error adding user to channel", "error", err)
+		return err
+	}
+
+	s.publishMemberEvent(ctx, models.WebhookEventMemberJoined, req.ChatID, req.UserID)
+
+	return nil
+}
+
+func (s *chatsService) LeaveChannel(ctx context.Context, req models.LeaveChannelRequest) error {
+	slog.Info("LeaveChannel service", "chatID", req.ChatID, "userID", req.UserID)
+
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	if membership.Kind != models.ChatKindChannel || membership.Role == "" {
+		return ErrNotFound
+	}
+
+	if membership.Role == models.ChatRoleAdmin {
+		admins, err := s.chatsRepo.CountMembersWithRole(ctx, req.ChatID, models.ChatRoleAdmin)
+		if err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	if err := s.chatsRepo.RemoveUserFromChat(ctx, req.UserID, req.ChatID); err != nil {
+		slog.Error("Error removing user from channel", "error", err)
+		return err
+	}
+
+	if s.realtime != nil {
+		s.realtime.UnsubscribeFromChat(req.ChatID, req.UserID)
+	}
+
+	s.publishMemberEvent(ctx, models.WebhookEventMemberLeft, req.ChatID, req.UserID)
+
+	return nil
+}
+
+// AddChannelMember lets a channel admin add someone to the channel. It is
+// the only way into private channels, which cannot be joined.
+func (s *chatsService) AddChannelMember(ctx context.Context, req models.AddChannelMemberRequest) error {
+	slog.Info("AddChannelMember service", "chatID", req.ChatID, "userID", req.UserID, "memberID", req.MemberID)
+
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	if membership.Kind != models.ChatKindChannel || membership.Role == "" {
+		return ErrNotFound
+	}
+
+	if membership.Role != models.ChatRoleAdmin {
+		slog.Warn("Only channel admins can add members", "userID", req.UserID, "chatID", req.ChatID)
+		return ErrPermissionDenied
+	}
+
+	member, err := s.usersRepo.GetByID(ctx, req.MemberID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return err
+	}
+
+	if member.ID == "" {
+		return ErrNotFound
+	}
+
+	blocked, err := s.moderationRepo.IsBlocked(ctx, req.UserID, req.MemberID)
+	if err != nil {
+		slog.Error("Error checking blocks", "error", err)
+		return err
+	}
+
+	if blocked {
+		slog.Warn("Cannot add a blocked user to a channel", "userID", req.UserID, "memberID", req.MemberID)
+		return ErrBlocked
+	}
+
+	memberMembership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.MemberID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	if memberMembership.Role != "" {
+		return nil
+	}
+
+	if err := s.chatsRepo.AddUserToChat(ctx, req.MemberID, req.ChatID, models.ChatRoleMember); err != nil {
+		slog.Error("Error adding user to channel", "error", err)
+		return err
+	}
+
+	s.publishMemberEvent(ctx, models.WebhookEventMemberJoined, req.ChatID, req.MemberID)
+
+	return nil
+}
+
+func (s *chatsService) DiscoverChannels(ctx context.Context, req models.DiscoverChannelsRequest) (models.DiscoverChannelsResponse, error) {
+	slog.Info("DiscoverChannels service", "userID", req.UserID, "query", req.Query)
+
+	return s.chatsRepo.DiscoverChannels(ctx, req)
+}
+
+// recordSystemMessage stores a system message in the chat and broadcasts it
+// so that open clients refresh the chat header. Failures are logged only,
+// since the change itself has already been applied.
+func (s *chatsService) recordSystemMessage(ctx context.Context, chatID, userID, content string) {
+	message := models.Message{
+		IdempotencyKey: fmt.Sprintf("system:%s:%d", chatID, time.Now().UnixNano()),
+		ChatID:         chatID,
+		UserID:         userID,
+		Body:           content,
+		Kind:           models.MessageKindSystem,
+		Status:         models.MessageStatusSent,
+	}
+
+	messageID, err := s.messagesRepo.Send(ctx, message)
+	if err != nil {
+		slog.Error("Error recording system message", "error", err, "chatID", chatID)
+		return
+	}
+
+	if s.realtime == nil {
+		return
+	}
+
+	msg, err := s.messagesRepo.Get(ctx, messageID)
+	if err != nil {
+		slog.Warn("Failed to get system message for broadcasting", "error", err, "messageID", messageID)
+		return
+	}
+
+	chatMsg := s.realtime.ConvertToChatMessage(msg)
+	chatMsg.Type = MessageTypeChatUpdated
+	s.realtime.BroadcastMessage(chatID, chatMsg)
+}
+
+// publishMemberEvent tells webhooks that userID joined or left chatID.
+func (s *chatsService) publishMemberEvent(ctx context.Context, eventType, chatID, userID string) {
+	if s.webhooks == nil {
+		return
+	}
+
+	s.webhooks.Publish(ctx, models.WebhookEvent{
+		Type:    eventType,
+		ChatID:  chatID,
+		ActorID: userID,
+		Data: map[string]any{
+			"user_id": userID,
+		},
+	})
 }
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChatsService() (*chatsService, *fakeChatsRepository, *fakeMessagesRepository, RealtimeService) {
+	chatsRepo := newFakeChatsRepository()
+	messagesRepo := &fakeMessagesRepository{}
+	realtime := NewRealtimeService(nil, nil)
+
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "chat1", Name: "Old name"},
+	}, map[string]models.ChatRole{
+		"admin":  models.ChatRoleAdmin,
+		"member": models.ChatRoleMember,
+	})
+
+	service := NewChatsService(chatsRepo, newFakeUsersRepository(), messagesRepo, newFakeModerationRepository(chatsRepo), realtime, EmailVerificationPolicy{}, nil).(*chatsService)
+	return service, chatsRepo, messagesRepo, realtime
+}
+
+func TestChatsService_UpdateChat_RequiresAdmin(t *testing.T) {
+	service, chatsRepo, messagesRepo, _ := newTestChatsService()
+	name := "New name"
+
+	_, err := service.UpdateChat(context.Background(), models.UpdateChatRequest{
+		UserID: "member",
+		ChatID: "chat1",
+		Name:   &name,
+	})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+	assert.Empty(t, chatsRepo.updates)
+	assert.Empty(t, messagesRepo.sent)
+
+	_, err = service.UpdateChat(context.Background(), models.UpdateChatRequest{
+		UserID: "stranger",
+		ChatID: "chat1",
+		Name:   &name,
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestChatsService_UpdateChat_RecordsSystemMessage(t *testing.T) {
+	service, chatsRepo, messagesRepo, realtime := newTestChatsService()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	msgChan, err := realtime.SubscribeToChat(ctx, "chat1", "member")
+	require.NoError(t, err)
+
+	name := "New name"
+	description := "Old name"
+	chat, err := service.UpdateChat(ctx, models.UpdateChatRequest{
+		UserID:      "admin",
+		Username:    "alice",
+		ChatID:      "chat1",
+		Name:        &name,
+		Description: &description,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "New name", chat.Name)
+	require.Len(t, chatsRepo.updates, 1)
+
+	require.Len(t, messagesRepo.sent, 2)
+	assert.Equal(t, models.MessageKindSystem, messagesRepo.sent[0].Kind)
+	assert.Equal(t, `alice renamed the chat to "New name"`, messagesRepo.sent[0].Body)
+	assert.Equal(t, "alice changed the chat description", messagesRepo.sent[1].Body)
+
+	select {
+	case msg := <-msgChan:
+		assert.Equal(t, MessageTypeChatUpdated, msg.Type)
+		assert.Equal(t, messagesRepo.sent[0].Body, msg.Content)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Member did not receive chat update")
+	}
+}
+
+func TestChatsService_UpdateChat_NoChanges(t *testing.T) {
+	service, chatsRepo, messagesRepo, _ := newTestChatsService()
+	name := "Old name"
+
+	chat, err := service.UpdateChat(context.Background(), models.UpdateChatRequest{
+		UserID: "admin",
+		ChatID: "chat1",
+		Name:   &name,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "chat1", chat.ID)
+	assert.Empty(t, chatsRepo.updates)
+	assert.Empty(t, messagesRepo.sent)
+}
+
+func TestChatsService_ListChats_LoadsMembersInOneBatch(t *testing.T) {
+	service, chatsRepo, _, _ := newTestChatsService()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "chat2", Name: "Other"},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin})
+	chatsRepo.members["chat1"] = []models.User{{ID: "admin"}, {ID: "member"}}
+	chatsRepo.members["chat2"] = []models.User{{ID: "admin"}}
+
+	resp, err := service.ListChats(context.Background(), models.ListChatsRequest{UserID: "admin"})
+	require.NoError(t, err)
+	require.Len(t, resp.Chats, 2)
+	assert.Equal(t, 1, chatsRepo.batchMemberCalls)
+
+	for _, chat := range resp.Chats {
+		assert.Equal(t, chatsRepo.members[chat.ID], chat.Members)
+	}
+}
+
+func TestChatsService_JoinChannel(t *testing.T) {
+	service, chatsRepo, _, _ := newTestChatsService()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "public", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPublic},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin})
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "private", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPrivate},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin})
+
+	ctx := context.Background()
+
+	err := service.JoinChannel(ctx, models.JoinChannelRequest{UserID: "reader", ChatID: "public"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ChatRoleMember, chatsRepo.roles["public"]["reader"])
+
+	// Joining twice is a no-op
+	err = service.JoinChannel(ctx, models.JoinChannelRequest{UserID: "reader", ChatID: "public"})
+	require.NoError(t, err)
+
+	err = service.JoinChannel(ctx, models.JoinChannelRequest{UserID: "reader", ChatID: "private"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Regular chats are not channels
+	err = service.JoinChannel(ctx, models.JoinChannelRequest{UserID: "reader", ChatID: "chat1"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestChatsService_AddChannelMember(t *testing.T) {
+	service, chatsRepo, _, _ := newTestChatsService()
+	service.usersRepo = newFakeUsersRepository(models.User{ID: "reader"}, models.User{ID: "blocked"})
+	moderationRepo := newFakeModerationRepository(chatsRepo)
+	moderationRepo.blocks[[2]string{"admin", "blocked"}] = true
+	service.moderationRepo = moderationRepo
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "private", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPrivate},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin, "member": models.ChatRoleMember})
+
+	ctx := context.Background()
+
+	err := service.AddChannelMember(ctx, models.AddChannelMemberRequest{UserID: "member", ChatID: "private", MemberID: "reader"})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	err = service.AddChannelMember(ctx, models.AddChannelMemberRequest{UserID: "stranger", ChatID: "private", MemberID: "reader"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = service.AddChannelMember(ctx, models.AddChannelMemberRequest{UserID: "admin", ChatID: "private", MemberID: "nobody"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	err = service.AddChannelMember(ctx, models.AddChannelMemberRequest{UserID: "admin", ChatID: "private", MemberID: "blocked"})
+	assert.ErrorIs(t, err, ErrBlocked)
+
+	err = service.AddChannelMember(ctx, models.AddChannelMemberRequest{UserID: "admin", ChatID: "private", MemberID: "reader"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ChatRoleMember, chatsRepo.roles["private"]["reader"])
+
+	// Regular chats are not channels
+	err = service.AddChannelMember(ctx, models.AddChannelMemberRequest{UserID: "admin", ChatID: "chat1", MemberID: "reader"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestChatsService_EmailVerificationPolicy(t *testing.T) {
+	service, chatsRepo, _, _ := newTestChatsService()
+	verifiedAt := time.Now()
+	service.usersRepo = newFakeUsersRepository(
+		models.User{ID: "unverified", Email: "unverified@example.com"},
+		models.User{ID: "verified", Email: "verified@example.com", EmailVerifiedAt: &verifiedAt},
+	)
+	service.verification = EmailVerificationPolicy{CreateChats: true, JoinChannels: true}
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "public", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPublic},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin})
+
+	ctx := context.Background()
+
+	// The fake chats repository has no Create, so reaching it would panic.
+	_, err := service.CreateChat(ctx, models.CreateChatRequest{UserID: "unverified", Email: "verified@example.com"})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+	_, err = service.CreateChannel(ctx, models.CreateChannelRequest{UserID: "unverified", Name: "news"})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+
+	err = service.JoinChannel(ctx, models.JoinChannelRequest{UserID: "unverified", ChatID: "public"})
+	assert.ErrorIs(t, err, ErrEmailNotVerified)
+	assert.NotContains(t, chatsRepo.roles["public"], "unverified")
+
+	err = service.JoinChannel(ctx, models.JoinChannelRequest{UserID: "verified", ChatID: "public"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ChatRoleMember, chatsRepo.roles["public"]["verified"])
+}
+
+func TestParseEmailVerificationPolicy(t *testing.T) {
+	policy, err := ParseEmailVerificationPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, EmailVerificationPolicy{}, policy)
+
+	policy, err = ParseEmailVerificationPolicy("create_chats, join_channels")
+	require.NoError(t, err)
+	assert.Equal(t, EmailVerificationPolicy{CreateChats: true, JoinChannels: true}, policy)
+
+	_, err = ParseEmailVerificationPolicy("create_chats,send_messages")
+	assert.Error(t, err)
+}
+
+func TestChatsService_LeaveChannel_KeepsLastAdmin(t *testing.T) {
+	service, chatsRepo, _, _ := newTestChatsService()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "news", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPublic},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin, "reader": models.ChatRoleMember})
+
+	ctx := context.Background()
+
+	err := service.LeaveChannel(ctx, models.LeaveChannelRequest{UserID: "admin", ChatID: "news"})
+	assert.ErrorIs(t, err, ErrLastAdmin)
+
+	err = service.LeaveChannel(ctx, models.LeaveChannelRequest{UserID: "reader", ChatID: "news"})
+	require.NoError(t, err)
+	assert.NotContains(t, chatsRepo.roles["news"], "reader")
+}
+
+func TestChatsService_CreateChat_Blocked(t *testing.T) {
+	chatsRepo := newFakeChatsRepository()
+	usersRepo := newFakeUsersRepository(models.User{ID: "bob", Email: "bob@example.com"})
+	moderationRepo := newFakeModerationRepository(chatsRepo)
+	require.NoError(t, moderationRepo.Block(context.Background(), "bob", "alice"))
+
+	service := NewChatsService(chatsRepo, usersRepo, &fakeMessagesRepository{}, moderationRepo, NewRealtimeService(nil, nil), EmailVerificationPolicy{}, nil)
+
+	// The fake chats repository has no Create, so reaching it would panic.
+	_, err := service.CreateChat(context.Background(), models.CreateChatRequest{
+		UserID: "alice",
+		Name:   "Hello",
+		Email:  "bob@example.com",
+	})
+	assert.ErrorIs(t, err, ErrBlocked)
+}
+
+func TestChatsService_ListChats_RestrictedToChatIDs(t *testing.T) {
+	pool := newTestPool(t)
+	chatsRepo := chats.NewChatsRepository(pool, pool)
+	service := &chatsService{chatsRepo: chatsRepo}
+
+	args := pgx.NamedArgs{
+		"user_id": ulid.Make().String(),
+		"chat1":   ulid.Make().String(),
+		"chat2":   ulid.Make().String(),
+		"chat3":   ulid.Make().String(),
+		"uc1":     ulid.Make().String(),
+		"uc2":     ulid.Make().String(),
+		"uc3":     ulid.Make().String(),
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES (@user_id, @user_id, @user_id || '@example.com', 'x')`,
+		`INSERT INTO chats (id, name, last_activity_at) VALUES
+			(@chat1, 'newest', NOW()),
+			(@chat2, 'older', NOW() - INTERVAL '1 hour'),
+			(@chat3, 'oldest', NOW() - INTERVAL '2 hours')`,
+		`INSERT INTO users_chats (id, user_id, chat_id) VALUES
+			(@uc1, @user_id, @chat1),
+			(@uc2, @user_id, @chat2),
+			(@uc3, @user_id, @chat3)`,
+	}, []string{
+		"DELETE FROM chats WHERE id IN (@chat1, @chat2, @chat3)",
+		"DELETE FROM users WHERE id = @user_id",
+	})
+
+	// The allowed chat is last in the inbox, but still on the first page.
+	resp, err := service.ListChats(context.Background(), models.ListChatsRequest{
+		UserID:     args["user_id"].(string),
+		ChatIDs:    []string{args["chat3"].(string)},
+		Pagination: models.Pagination{Page: 1, Limit: 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Chats, 1)
+	assert.Equal(t, args["chat3"], resp.Chats[0].ID)
+	assert.Equal(t, int32(1), resp.Total)
+}
+
+func TestChatsRepository_Get_LastMessageKind(t *testing.T) {
+	pool := newTestPool(t)
+	chatsRepo := chats.NewChatsRepository(pool, pool)
+
+	args := pgx.NamedArgs{
+		"user_id":    ulid.Make().String(),
+		"chat_id":    ulid.Make().String(),
+		"uc_id":      ulid.Make().String(),
+		"message_id": ulid.Make().String(),
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES (@user_id, @user_id, @user_id || '@example.com', 'x')`,
+		`INSERT INTO chats (id, name) VALUES (@chat_id, 'chat')`,
+		`INSERT INTO users_chats (id, user_id, chat_id) VALUES (@uc_id, @user_id, @chat_id)`,
+		`INSERT INTO messages (id, idempotency_key, user_id, chat_id, content, kind)
+			VALUES (@message_id, @message_id, @user_id, @chat_id, 'renamed the chat', 'SYSTEM')`,
+		`UPDATE chats SET last_message_id = @message_id WHERE id = @chat_id`,
+	}, []string{
+		"DELETE FROM chats WHERE id = @chat_id",
+		"DELETE FROM users WHERE id = @user_id",
+	})
+
+	// System messages are told apart from user messages in chat lists.
+	chat, err := chatsRepo.Get(context.Background(), models.GetChatRequest{ID: args["chat_id"].(string), UserID: args["user_id"].(string)})
+	require.NoError(t, err)
+	require.NotNil(t, chat.LastMessage)
+	assert.Equal(t, models.MessageKindSystem, chat.LastMessage.Kind)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/messages"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The chat settings live in SQL, so these tests need the database of
+// newTestPool.
+type chatSettingsFixture struct {
+	service  *chatsService
+	messages messages.MessagesRepository
+	userID   string
+	peerID   string
+	chatID   string
+}
+
+func newChatSettingsFixture(t *testing.T) chatSettingsFixture {
+	t.Helper()
+
+	pool := newTestPool(t)
+	f := chatSettingsFixture{
+		service:  &chatsService{chatsRepo: chats.NewChatsRepository(pool, pool)},
+		messages: messages.NewMessagesRepository(pool, pool),
+		userID:   ulid.Make().String(),
+		peerID:   ulid.Make().String(),
+		chatID:   ulid.Make().String(),
+	}
+
+	args := pgx.NamedArgs{
+		"user_id":      f.userID,
+		"peer_id":      f.peerID,
+		"chat_id":      f.chatID,
+		"user_chat_id": ulid.Make().String(),
+		"peer_chat_id": ulid.Make().String(),
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES
+			(@user_id, @user_id, @user_id || '@example.com', 'x'),
+			(@peer_id, @peer_id, @peer_id || '@example.com', 'x')`,
+		`INSERT INTO chats (id, name) VALUES (@chat_id, 'settings')`,
+		`INSERT INTO users_chats (id, user_id, chat_id) VALUES
+			(@user_chat_id, @user_id, @chat_id),
+			(@peer_chat_id, @peer_id, @chat_id)`,
+	}, []string{
+		"DELETE FROM chats WHERE id = @chat_id",
+		"DELETE FROM users WHERE id IN (@user_id, @peer_id)",
+	})
+
+	return f
+}
+
+func (f chatSettingsFixture) update(t *testing.T, req models.UpdateChatSettingsRequest) models.ChatSettings {
+	t.Helper()
+
+	req.UserID = f.userID
+	req.ChatID = f.chatID
+	settings, err := f.service.UpdateChatSettings(context.Background(), req)
+	require.NoError(t, err)
+	return settings
+}
+
+func (f chatSettingsFixture) settings(t *testing.T) models.ChatSettings {
+	t.Helper()
+
+	chat, err := f.service.chatsRepo.Get(context.Background(), models.GetChatRequest{ID: f.chatID, UserID: f.userID})
+	require.NoError(t, err)
+	require.Equal(t, f.chatID, chat.ID)
+	return chat.Settings
+}
+
+func (f chatSettingsFixture) sendFromPeer(t *testing.T) {
+	t.Helper()
+
+	_, err := f.messages.Send(context.Background(), models.Message{
+		IdempotencyKey: ulid.Make().String(),
+		UserID:         f.peerID,
+		ChatID:         f.chatID,
+		Body:           "hello",
+		Kind:           models.MessageKindUser,
+		Status:         models.MessageStatusSent,
+	})
+	require.NoError(t, err)
+}
+
+func TestChatsService_UpdateChatSettings_MuteAndUnmute(t *testing.T) {
+	f := newChatSettingsFixture(t)
+
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	settings := f.update(t, models.UpdateChatSettingsRequest{MutedUntil: &until})
+	require.NotNil(t, settings.MutedUntil)
+	assert.True(t, until.Equal(*settings.MutedUntil))
+	stored := f.settings(t).MutedUntil
+	require.NotNil(t, stored)
+	assert.True(t, until.Equal(*stored))
+
+	settings = f.update(t, models.UpdateChatSettingsRequest{Unmute: true})
+	assert.Nil(t, settings.MutedUntil)
+	assert.Nil(t, f.settings(t).MutedUntil)
+}
+
+func TestChatsService_UpdateChatSettings_Pin(t *testing.T) {
+	f := newChatSettingsFixture(t)
+	pinned, unpinned := true, false
+
+	settings := f.update(t, models.UpdateChatSettingsRequest{Pinned: &pinned})
+	require.NotNil(t, settings.PinOrder)
+	assert.Equal(t, int32(0), *settings.PinOrder)
+
+	order := int32(3)
+	settings = f.update(t, models.UpdateChatSettingsRequest{PinOrder: &order})
+	require.NotNil(t, settings.PinOrder)
+	assert.Equal(t, order, *settings.PinOrder)
+
+	// Pinning a pinned chat again keeps its place.
+	settings = f.update(t, models.UpdateChatSettingsRequest{Pinned: &pinned})
+	require.NotNil(t, settings.PinOrder)
+	assert.Equal(t, order, *settings.PinOrder)
+
+	settings = f.update(t, models.UpdateChatSettingsRequest{Pinned: &unpinned})
+	assert.Nil(t, settings.PinOrder)
+	assert.Nil(t, f.settings(t).PinOrder)
+}
+
+func TestChatsService_UpdateChatSettings_Archive(t *testing.T) {
+	f := newChatSettingsFixture(t)
+	archived, unarchived := true, false
+
+	settings := f.update(t, models.UpdateChatSettingsRequest{Archived: &archived})
+	assert.True(t, settings.Archived)
+
+	list, err := f.service.chatsRepo.List(context.Background(), models.ListChatsRequest{UserID: f.userID, ArchivedOnly: true})
+	require.NoError(t, err)
+	require.Len(t, list.Chats, 1)
+	assert.Equal(t, f.chatID, list.Chats[0].ID)
+
+	list, err = f.service.chatsRepo.List(context.Background(), models.ListChatsRequest{UserID: f.userID, ExcludeArchived: true})
+	require.NoError(t, err)
+	assert.Empty(t, list.Chats)
+
+	settings = f.update(t, models.UpdateChatSettingsRequest{Archived: &unarchived})
+	assert.False(t, settings.Archived)
+	assert.False(t, f.settings(t).Archived)
+}
+
+func TestChatsService_UpdateChatSettings_NotAMember(t *testing.T) {
+	f := newChatSettingsFixture(t)
+	archived := true
+
+	settings, err := f.service.UpdateChatSettings(context.Background(), models.UpdateChatSettingsRequest{
+		UserID:   ulid.Make().String(),
+		ChatID:   f.chatID,
+		Archived: &archived,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, settings.ChatID)
+}
+
+func TestChatsService_NewMessageUnarchivesChat(t *testing.T) {
+	f := newChatSettingsFixture(t)
+	archived := true
+
+	f.update(t, models.UpdateChatSettingsRequest{Archived: &archived})
+	f.sendFromPeer(t)
+
+	assert.False(t, f.settings(t).Archived)
+}
+
+func TestChatsService_NewMessageKeepsMutedChatArchived(t *testing.T) {
+	f := newChatSettingsFixture(t)
+	archived := true
+	until := time.Now().Add(time.Hour)
+
+	f.update(t, models.UpdateChatSettingsRequest{Archived: &archived, MutedUntil: &until})
+	f.sendFromPeer(t)
+
+	assert.True(t, f.settings(t).Archived)
+}
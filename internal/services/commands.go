@@ -0,0 +1,381 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/commands"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/pkg/webhook"
+	"github.com/oklog/ulid/v2"
+)
+
+// CommandEvent is the event header of calls to bot commands.
+const CommandEvent = "command"
+
+const (
+	// DefaultCommandTimeout is how long a bot may take to answer a command.
+	// The invoker is waiting, so it is short.
+	DefaultCommandTimeout = 3 * time.Second
+
+	// maxCommandResponseSize limits the body a bot may answer with.
+	maxCommandResponseSize = 16 << 10
+)
+
+// CommandHandler runs a built-in command.
+type CommandHandler func(ctx context.Context, inv models.CommandInvocation) (models.CommandReply, error)
+
+// CommandRouter runs slash commands sent as messages.
+type CommandRouter interface {
+	// Route runs the command req's content starts with. It returns false if
+	// the content is not a command, and the message is to be sent as usual.
+	Route(ctx context.Context, req models.SendMessageRequest) (models.CommandReply, bool, error)
+}
+
+type CommandsService interface {
+	CommandRouter
+	// Handle registers a built-in command. It panics if the name is invalid
+	// or taken, like http.ServeMux does.
+	Handle(command models.Command, handler CommandHandler)
+	RegisterCommand(ctx context.Context, req models.RegisterCommandRequest) (models.RegisterCommandResponse, error)
+	ListCommands(ctx context.Context, req models.ListCommandsRequest) ([]models.Command, error)
+	DeleteCommand(ctx context.Context, ownerID, botID, commandID string) error
+}
+
+type builtinCommand struct {
+	command models.Command
+	handler CommandHandler
+}
+
+type commandsService struct {
+	commandsRepo commands.CommandsRepository
+	usersRepo    users.UsersRepository
+	chatsRepo    chats.ChatsRepository
+	sender       webhook.Sender
+	builtins     map[string]builtinCommand
+}
+
+// NewCommandsService returns the command router with the built-in commands
+// registered. Bot commands are called with sender, which defaults to one
+// that only calls public addresses, with a DefaultCommandTimeout timeout.
+func NewCommandsService(commandsRepo commands.CommandsRepository, usersRepo users.UsersRepository, chatsRepo chats.ChatsRepository, sender webhook.Sender) CommandsService {
+	if sender == nil {
+		sender = webhook.NewSender(webhook.NewClient(DefaultCommandTimeout), nil)
+	}
+
+	s := &commandsService{
+		commandsRepo: commandsRepo,
+		usersRepo:    usersRepo,
+		chatsRepo:    chatsRepo,
+		sender:       sender,
+		builtins:     make(map[string]builtinCommand),
+	}
+
+	s.Handle(models.Command{Name: "help", Description: "List the commands of this chat"}, s.help)
+	s.Handle(models.Command{Name: "me", Description: "Describe what you are doing", Usage: "<action>"}, me)
+	s.Handle(models.Command{Name: "shrug", Description: `Append ¯\_(ツ)_/¯ to your message`, Usage: "[message]"}, shrug)
+
+	return s
+}
+
+func (s *commandsService) Handle(command models.Command, handler CommandHandler) {
+	if !models.CommandNamePattern.MatchString(command.Name) {
+		panic(fmt.Sprintf("invalid command name %q", command.Name))
+	}
+	if _, exists := s.builtins[command.Name]; exists {
+		panic(fmt.Sprintf("command /%s registered twice", command.Name))
+	}
+	s.builtins[command.Name] = builtinCommand{command: command, handler: handler}
+}
+
+func (s *commandsService) Route(ctx context.Context, req models.SendMessageRequest) (models.CommandReply, bool, error) {
+	name, args, ok := parseCommand(req.Content)
+	if !ok {
+		return models.CommandReply{}, false, nil
+	}
+
+	slog.Info("Route command", "userID", req.UserID, "chatID", req.ChatID, "command", name)
+
+	user, err := s.usersRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return models.CommandReply{}, true, err
+	}
+
+	inv := models.CommandInvocation{
+		ID:       ulid.Make().String(),
+		Command:  name,
+		Args:     args,
+		ChatID:   req.ChatID,
+		UserID:   req.UserID,
+		Username: user.Username,
+	}
+
+	if builtin, exists := s.builtins[name]; exists {
+		reply, err := builtin.handler(ctx, inv)
+		if err != nil {
+			slog.Error("Error running command", "command", name, "error", err)
+			return models.CommandReply{}, true, err
+		}
+		reply.SenderID = req.UserID
+		return normalizeReply(reply), true, nil
+	}
+
+	command, err := s.commandsRepo.FindForChat(ctx, req.ChatID, name)
+	if err != nil {
+		return models.CommandReply{}, true, err
+	}
+
+	if command.ID == "" {
+		return ephemeralReply(fmt.Sprintf("Unknown command /%s. Send /help for the commands of this chat.", name)), true, nil
+	}
+
+	reply, err := s.call(ctx, command, inv)
+	if err != nil {
+		// The invoker cannot fix a broken bot, so the failure is a reply
+		// rather than an error.
+		slog.Warn("Bot command failed", "command", name, "botID", command.BotID, "error", err)
+		return ephemeralReply(fmt.Sprintf("/%s did not respond.", name)), true, nil
+	}
+	reply.SenderID = command.BotID
+
+	return normalizeReply(reply), true, nil
+}
+
+// call posts inv to the bot and decodes its reply. An empty response means
+// no reply.
+func (s *commandsService) call(ctx context.Context, command models.Command, inv models.CommandInvocation) (models.CommandReply, error) {
+	body, err := json.Marshal(inv)
+	if err != nil {
+		return models.CommandReply{}, err
+	}
+
+	respBody, err := s.sender.Call(ctx, webhook.Request{
+		URL:        command.URL,
+		Secret:     command.Secret,
+		Event:      CommandEvent,
+		DeliveryID: inv.ID,
+		Body:       body,
+	}, maxCommandResponseSize)
+	if err != nil {
+		return models.CommandReply{}, err
+	}
+
+	var reply models.CommandReply
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return reply, nil
+	}
+
+	if err := json.Unmarshal(respBody, &reply); err != nil {
+		return models.CommandReply{}, fmt.Errorf("invalid reply: %w", err)
+	}
+
+	return reply, nil
+}
+
+func (s *commandsService) RegisterCommand(ctx context.Context, req models.RegisterCommandRequest) (models.RegisterCommandResponse, error) {
+	slog.Info("RegisterCommand service", "ownerID", req.OwnerID, "botID", req.BotID, "name", req.Name)
+
+	if !models.CommandNamePattern.MatchString(req.Name) {
+		return models.RegisterCommandResponse{}, ErrInvalidCommand
+	}
+
+	if _, exists := s.builtins[req.Name]; exists {
+		return models.RegisterCommandResponse{}, ErrCommandTaken
+	}
+
+	if !validWebhookURL(req.URL) {
+		return models.RegisterCommandResponse{}, ErrInvalidWebhookURL
+	}
+
+	if _, err := ownedBot(ctx, s.usersRepo, req.OwnerID, req.BotID); err != nil {
+		return models.RegisterCommandResponse{}, err
+	}
+
+	existing, err := s.commandsRepo.ListByBot(ctx, req.BotID)
+	if err != nil {
+		return models.RegisterCommandResponse{}, err
+	}
+
+	if len(existing) >= models.MaxCommandsPerBot {
+		slog.Warn("Too many commands", "botID", req.BotID, "count", len(existing))
+		return models.RegisterCommandResponse{}, ErrLimitReached
+	}
+
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating command secret", "error", err)
+		return models.RegisterCommandResponse{}, err
+	}
+	secret := WebhookSecretPrefix + token
+
+	created, err := s.commandsRepo.Create(ctx, models.Command{
+		BotID:       req.BotID,
+		Name:        req.Name,
+		Description: req.Description,
+		Usage:       req.Usage,
+		URL:         req.URL,
+		Secret:      secret,
+	})
+	if err != nil {
+		return models.RegisterCommandResponse{}, err
+	}
+
+	return models.RegisterCommandResponse{
+		Command: created,
+		Secret:  secret,
+	}, nil
+}
+
+// ListCommands lists the commands of a chat, for autocomplete, or those a
+// bot registered. In a chat, built-in commands come first, and of the bot
+// commands sharing a name only the one that runs is listed.
+func (s *commandsService) ListCommands(ctx context.Context, req models.ListCommandsRequest) ([]models.Command, error) {
+	slog.Info("ListCommands service", "userID", req.UserID, "chatID", req.ChatID, "botID", req.BotID)
+
+	if (req.ChatID == "") == (req.BotID == "") {
+		return nil, ErrNotFound
+	}
+
+	if req.BotID != "" {
+		if _, err := ownedBot(ctx, s.usersRepo, req.UserID, req.BotID); err != nil {
+			return nil, err
+		}
+		return s.commandsRepo.ListByBot(ctx, req.BotID)
+	}
+
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return nil, err
+	}
+
+	if membership.Role == "" {
+		return nil, ErrNotFound
+	}
+
+	return s.chatCommands(ctx, req.ChatID)
+}
+
+func (s *commandsService) chatCommands(ctx context.Context, chatID string) ([]models.Command, error) {
+	botCommands, err := s.commandsRepo.ListForChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Command, 0, len(s.builtins)+len(botCommands))
+	for _, name := range slices.Sorted(maps.Keys(s.builtins)) {
+		result = append(result, s.builtins[name].command)
+	}
+
+	for _, command := range botCommands {
+		if _, exists := s.builtins[command.Name]; exists {
+			continue
+		}
+		if n := len(result); n > 0 && result[n-1].Name == command.Name {
+			continue
+		}
+		// Members see what a command does, not where the bot runs.
+		command.URL = ""
+		result = append(result, command)
+	}
+
+	return result, nil
+}
+
+func (s *commandsService) DeleteCommand(ctx context.Context, ownerID, botID, commandID string) error {
+	slog.Info("DeleteCommand service", "ownerID", ownerID, "botID", botID, "commandID", commandID)
+
+	if _, err := ownedBot(ctx, s.usersRepo, ownerID, botID); err != nil {
+		return err
+	}
+
+	deleted, err := s.commandsRepo.Delete(ctx, botID, commandID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *commandsService) help(ctx context.Context, inv models.CommandInvocation) (models.CommandReply, error) {
+	chatCommands, err := s.chatCommands(ctx, inv.ChatID)
+	if err != nil {
+		return models.CommandReply{}, err
+	}
+
+	var text strings.Builder
+	text.WriteString("Commands of this chat:")
+	for _, command := range chatCommands {
+		text.WriteString("\n/" + command.Name)
+		if command.Usage != "" {
+			text.WriteString(" " + command.Usage)
+		}
+		if command.Description != "" {
+			text.WriteString(" - " + command.Description)
+		}
+	}
+
+	return ephemeralReply(text.String()), nil
+}
+
+func me(ctx context.Context, inv models.CommandInvocation) (models.CommandReply, error) {
+	if inv.Args == "" {
+		return ephemeralReply("Usage: /me <action>"), nil
+	}
+	return models.CommandReply{Text: "_" + inv.Username + " " + inv.Args + "_", Visibility: models.CommandReplyPublic}, nil
+}
+
+func shrug(ctx context.Context, inv models.CommandInvocation) (models.CommandReply, error) {
+	return models.CommandReply{Text: strings.TrimSpace(inv.Args + ` ¯\_(ツ)_/¯`), Visibility: models.CommandReplyPublic}, nil
+}
+
+// parseCommand splits content like "/remind me at 5" into the command name
+// and its arguments. Content whose first word is not a command name is not
+// a command.
+func parseCommand(content string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(content, "/")
+	if !ok {
+		return "", "", false
+	}
+
+	end := strings.IndexFunc(rest, unicode.IsSpace)
+	if end < 0 {
+		end = len(rest)
+	}
+
+	name := strings.ToLower(rest[:end])
+	if !models.CommandNamePattern.MatchString(name) {
+		return "", "", false
+	}
+
+	return name, strings.TrimSpace(rest[end:]), true
+}
+
+func ephemeralReply(text string) models.CommandReply {
+	return models.CommandReply{Text: text, Visibility: models.CommandReplyEphemeral}
+}
+
+// normalizeReply makes replies of unknown visibility ephemeral, so that a
+// bot never posts by mistake, and cuts long replies.
+func normalizeReply(reply models.CommandReply) models.CommandReply {
+	if reply.Visibility != models.CommandReplyPublic {
+		reply.Visibility = models.CommandReplyEphemeral
+	}
+	reply.Text = truncate(strings.TrimSpace(reply.Text), models.MaxCommandReplyLength)
+	return reply
+}
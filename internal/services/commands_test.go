@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type commandsFixture struct {
+	service  CommandsService
+	commands *fakeCommandsRepository
+	chats    *fakeChatsRepository
+}
+
+// newCommandsFixture has a chat "team" of alice, bob and alice's bot, and a
+// bot endpoint that answers with the reply handler returns.
+func newCommandsFixture(t *testing.T, handler func(inv models.CommandInvocation) (int, string)) commandsFixture {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify("whsec_test", r.Header.Get(webhook.SignatureHeader), body, 0, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var inv models.CommandInvocation
+		require.NoError(t, json.Unmarshal(body, &inv))
+		status, reply := handler(inv)
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, reply)
+	}))
+	t.Cleanup(server.Close)
+
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "team", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember, "bot": models.ChatRoleMember})
+	usersRepo := newFakeUsersRepository(
+		models.User{ID: "alice", Username: "alice"},
+		models.User{ID: "bob", Username: "bob"},
+		models.User{ID: "bot", Username: "deploy-bot", BotOwnerID: "alice"},
+		models.User{ID: "other-bot", Username: "other-bot", BotOwnerID: "alice"},
+	)
+	commandsRepo := newFakeCommandsRepository(chatsRepo)
+	commandsRepo.commands = []models.Command{
+		{ID: "deploy", BotID: "bot", Name: "deploy", Description: "Deploy a branch", Usage: "<branch>", URL: server.URL, Secret: "whsec_test"},
+		// Registered later by a bot that is not in the chat.
+		{ID: "deploy2", BotID: "other-bot", Name: "deploy", URL: server.URL, Secret: "whsec_test"},
+	}
+
+	return commandsFixture{
+		service:  NewCommandsService(commandsRepo, usersRepo, chatsRepo, webhook.NewSender(server.Client(), nil)),
+		commands: commandsRepo,
+		chats:    chatsRepo,
+	}
+}
+
+func noBotReply(models.CommandInvocation) (int, string) {
+	return http.StatusOK, ""
+}
+
+func (f commandsFixture) route(t *testing.T, userID, content string) (models.CommandReply, bool) {
+	t.Helper()
+	reply, ok, err := f.service.Route(context.Background(), models.SendMessageRequest{
+		UserID:  userID,
+		ChatID:  "team",
+		Content: content,
+	})
+	require.NoError(t, err)
+	return reply, ok
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		content string
+		name    string
+		args    string
+		ok      bool
+	}{
+		{"/help", "help", "", true},
+		{"/remind me at 5", "remind", "me at 5", true},
+		{"/Poll\n  Lunch?", "poll", "Lunch?", true},
+		{"hello /help", "", "", false},
+		{"/usr/bin is full", "", "", false},
+		{"/ nothing", "", "", false},
+		{"//help", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, args, ok := parseCommand(tt.content)
+		assert.Equal(t, tt.ok, ok, tt.content)
+		assert.Equal(t, tt.name, name, tt.content)
+		assert.Equal(t, tt.args, args, tt.content)
+	}
+}
+
+func TestCommandsService_Route_Builtins(t *testing.T) {
+	f := newCommandsFixture(t, noBotReply)
+
+	_, ok := f.route(t, "bob", "Hello there")
+	assert.False(t, ok)
+
+	reply, ok := f.route(t, "bob", "/shrug no idea")
+	require.True(t, ok)
+	assert.Equal(t, models.CommandReply{Text: `no idea ¯\_(ツ)_/¯`, Visibility: models.CommandReplyPublic, SenderID: "bob"}, reply)
+
+	reply, _ = f.route(t, "bob", "/me waves")
+	assert.Equal(t, "_bob waves_", reply.Text)
+	assert.Equal(t, models.CommandReplyPublic, reply.Visibility)
+
+	reply, _ = f.route(t, "bob", "/me")
+	assert.Equal(t, models.CommandReplyEphemeral, reply.Visibility)
+
+	reply, _ = f.route(t, "bob", "/help")
+	assert.Equal(t, models.CommandReplyEphemeral, reply.Visibility)
+	assert.Contains(t, reply.Text, "/deploy <branch> - Deploy a branch")
+	assert.Contains(t, reply.Text, "/shrug [message]")
+
+	reply, ok = f.route(t, "bob", "/giphy cats")
+	require.True(t, ok)
+	assert.Equal(t, models.CommandReplyEphemeral, reply.Visibility)
+	assert.Contains(t, reply.Text, "Unknown command /giphy")
+}
+
+func TestCommandsService_Route_CallsBot(t *testing.T) {
+	var received models.CommandInvocation
+	f := newCommandsFixture(t, func(inv models.CommandInvocation) (int, string) {
+		received = inv
+		return http.StatusOK, `{"text": "Deploying ` + inv.Args + `", "visibility": "public"}`
+	})
+
+	reply, ok := f.route(t, "bob", "/deploy main")
+	require.True(t, ok)
+	assert.Equal(t, models.CommandReply{Text: "Deploying main", Visibility: models.CommandReplyPublic, SenderID: "bot"}, reply)
+
+	assert.NotEmpty(t, received.ID)
+	assert.Equal(t, "deploy", received.Command)
+	assert.Equal(t, "main", received.Args)
+	assert.Equal(t, "team", received.ChatID)
+	assert.Equal(t, "bob", received.UserID)
+	assert.Equal(t, "bob", received.Username)
+}
+
+func TestCommandsService_Route_BotReplies(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		reply    models.CommandReply
+	}{
+		{"no visibility is ephemeral", http.StatusOK, `{"text": "Only you see this"}`, models.CommandReply{Text: "Only you see this", Visibility: models.CommandReplyEphemeral, SenderID: "bot"}},
+		{"empty body is no reply", http.StatusOK, "", models.CommandReply{Visibility: models.CommandReplyEphemeral, SenderID: "bot"}},
+		{"failure", http.StatusInternalServerError, "boom", models.CommandReply{Text: "/deploy did not respond.", Visibility: models.CommandReplyEphemeral}},
+		{"invalid JSON", http.StatusOK, "Deploying", models.CommandReply{Text: "/deploy did not respond.", Visibility: models.CommandReplyEphemeral}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCommandsFixture(t, func(models.CommandInvocation) (int, string) {
+				return tt.status, tt.response
+			})
+
+			reply, ok := f.route(t, "bob", "/deploy main")
+			require.True(t, ok)
+			assert.Equal(t, tt.reply, reply)
+		})
+	}
+}
+
+func TestCommandsService_Route_DefaultSenderOnlyCallsPublicAddresses(t *testing.T) {
+	called := false
+	f := newCommandsFixture(t, func(models.CommandInvocation) (int, string) {
+		called = true
+		return http.StatusOK, `{"text": "Deploying"}`
+	})
+
+	// The test bot listens on loopback, which the default sender refuses.
+	f.service.(*commandsService).sender = NewCommandsService(nil, nil, nil, nil).(*commandsService).sender
+
+	reply, ok := f.route(t, "bob", "/deploy main")
+	require.True(t, ok)
+	assert.Equal(t, "/deploy did not respond.", reply.Text)
+	assert.False(t, called)
+}
+
+func TestCommandsService_RegisterCommand(t *testing.T) {
+	f := newCommandsFixture(t, noBotReply)
+	ctx := context.Background()
+
+	resp, err := f.service.RegisterCommand(ctx, models.RegisterCommandRequest{
+		OwnerID:     "alice",
+		BotID:       "bot",
+		Name:        "poll",
+		Description: "Start a poll",
+		URL:         "https://example.com/commands/poll",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "poll", resp.Command.Name)
+	assert.Contains(t, resp.Secret, WebhookSecretPrefix)
+	assert.Equal(t, resp.Secret, f.commands.commands[2].Secret)
+
+	tests := []struct {
+		name string
+		req  models.RegisterCommandRequest
+		err  error
+	}{
+		{"invalid name", models.RegisterCommandRequest{OwnerID: "alice", BotID: "bot", Name: "Poll!", URL: "https://example.com"}, ErrInvalidCommand},
+		{"built-in name", models.RegisterCommandRequest{OwnerID: "alice", BotID: "bot", Name: "help", URL: "https://example.com"}, ErrCommandTaken},
+		{"registered twice", models.RegisterCommandRequest{OwnerID: "alice", BotID: "bot", Name: "poll", URL: "https://example.com"}, ErrCommandTaken},
+		{"invalid URL", models.RegisterCommandRequest{OwnerID: "alice", BotID: "bot", Name: "remind", URL: "ftp://example.com"}, ErrInvalidWebhookURL},
+		{"internal URL", models.RegisterCommandRequest{OwnerID: "alice", BotID: "bot", Name: "remind", URL: "http://169.254.169.254/latest"}, ErrInvalidWebhookURL},
+		{"not the owner", models.RegisterCommandRequest{OwnerID: "bob", BotID: "bot", Name: "remind", URL: "https://example.com"}, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.RegisterCommand(ctx, tt.req)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCommandsService_ListCommands(t *testing.T) {
+	f := newCommandsFixture(t, noBotReply)
+	ctx := context.Background()
+
+	commands, err := f.service.ListCommands(ctx, models.ListCommandsRequest{UserID: "bob", ChatID: "team"})
+	require.NoError(t, err)
+
+	var names []string
+	for _, command := range commands {
+		names = append(names, command.Name)
+	}
+	assert.Equal(t, []string{"help", "me", "shrug", "deploy"}, names)
+	assert.Equal(t, "bot", commands[3].BotID)
+	assert.Empty(t, commands[3].URL)
+
+	_, err = f.service.ListCommands(ctx, models.ListCommandsRequest{UserID: "mallory", ChatID: "team"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Owners see the commands of their bots with the URLs.
+	commands, err = f.service.ListCommands(ctx, models.ListCommandsRequest{UserID: "alice", BotID: "other-bot"})
+	require.NoError(t, err)
+	require.Len(t, commands, 1)
+	assert.NotEmpty(t, commands[0].URL)
+
+	_, err = f.service.ListCommands(ctx, models.ListCommandsRequest{UserID: "bob", BotID: "other-bot"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCommandsService_DeleteCommand(t *testing.T) {
+	f := newCommandsFixture(t, noBotReply)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, f.service.DeleteCommand(ctx, "bob", "bot", "deploy"), ErrNotFound)
+	require.NoError(t, f.service.DeleteCommand(ctx, "alice", "bot", "deploy"))
+	assert.ErrorIs(t, f.service.DeleteCommand(ctx, "alice", "bot", "deploy"), ErrNotFound)
+
+	reply, ok := f.route(t, "bob", "/deploy main")
+	require.True(t, ok)
+	assert.Contains(t, reply.Text, "Unknown command /deploy")
+}
+
+func TestMessagesService_EphemeralReply(t *testing.T) {
+	realtime := NewRealtimeService(nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bobChan, err := realtime.SubscribeToChat(ctx, "team", "bob")
+	require.NoError(t, err)
+	aliceChan, err := realtime.SubscribeToChat(ctx, "team", "alice")
+	require.NoError(t, err)
+
+	messagesRepo := &fakeMessagesRepository{}
+	service := &messagesService{messagesRepo: messagesRepo, realtime: realtime}
+
+	reply := models.CommandReply{Text: "Only you see this", Visibility: models.CommandReplyEphemeral, SenderID: "bot"}
+	resp, err := service.reply(context.Background(), models.SendMessageRequest{UserID: "bob", ChatID: "team"}, "key1", reply)
+	require.NoError(t, err)
+	assert.Empty(t, resp.MessageID)
+	assert.Equal(t, &reply, resp.Reply)
+	assert.Empty(t, messagesRepo.sent)
+
+	select {
+	case msg := <-bobChan:
+		assert.Equal(t, "Only you see this", msg.Content)
+		assert.Equal(t, "bot", msg.SenderID)
+		assert.Equal(t, MessageTypeEphemeral, msg.Type)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Bob did not receive the reply")
+	}
+
+	select {
+	case msg := <-aliceChan:
+		t.Errorf("Alice received %q", msg.Content)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/commands"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrLastAdmin        = errors.New("the last admin cannot leave")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrBlocked          = errors.New("blocked")
+
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrSessionRevoked      = errors.New("session revoked")
+	ErrInvalidPassword     = errors.New("invalid password")
+	ErrInvalidResetToken   = errors.New("invalid password reset token")
+
+	ErrEmailNotVerified         = errors.New("email not verified")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrInvalidVerificationToken = errors.New("invalid email verification token")
+	ErrRateLimited              = errors.New("rate limited")
+
+	ErrTwoFactorUnavailable  = errors.New("two-factor authentication is not configured")
+	ErrTOTPAlreadyEnabled    = errors.New("two-factor authentication already enabled")
+	ErrTOTPNotEnrolled       = errors.New("two-factor authentication not enrolled")
+	ErrInvalidTOTPCode       = errors.New("invalid two-factor code")
+	ErrInvalidLoginChallenge = errors.New("invalid login challenge")
+
+	ErrExternalLoginUnavailable = errors.New("external login is not configured")
+	ErrExternalEmailNotVerified = errors.New("the identity provider has not verified the email")
+	ErrLocalEmailNotVerified    = errors.New("the account with this email has not verified it")
+
+	ErrLimitReached  = errors.New("limit reached")
+	ErrInvalidScope  = errors.New("invalid scope")
+	ErrInvalidAPIKey = errors.New("invalid API key")
+
+	ErrInvalidWebhookURL   = errors.New("invalid webhook URL")
+	ErrInvalidEvent        = errors.New("invalid webhook event")
+	ErrInvalidWebhookToken = errors.New("invalid incoming webhook token")
+
+	ErrDuplicateMessage = errors.New("message with idempotency key already exists")
+	ErrInvalidCommand   = errors.New("invalid command")
+
+	ErrInvalidScheduleTime = errors.New("invalid schedule time")
+
+	// The users and commands repositories report unique violations with
+	// these, so the service pre-check and a lost race surface the same error.
+	ErrEmailTaken    = users.ErrEmailTaken
+	ErrUsernameTaken = users.ErrUsernameTaken
+	ErrIdentityTaken = users.ErrIdentityTaken
+	ErrCommandTaken  = commands.ErrCommandTaken
+
+	// Errors of the identity provider.
+	ErrInvalidIDToken = oidc.ErrInvalidIDToken
+	ErrExchangeFailed = oidc.ErrExchangeFailed
+)
+
+// RetryAfterError is returned for calls that were rate limited. It matches
+// ErrRateLimited with errors.Is and tells when the call may be retried.
+type RetryAfterError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
+}
+
+func (e *RetryAfterError) Is(target error) bool {
+	return target == ErrRateLimited
+}
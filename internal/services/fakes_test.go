@@ -0,0 +1,1372 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/apikeys"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/commands"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/messages"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/moderation"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/scheduled"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/tokens"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/twofactor"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/webhooks"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
+)
+
+// The fakes embed the repository interfaces so that calling a method a test
+// did not expect panics instead of silently succeeding.
+
+type fakeChatsRepository struct {
+	chats.ChatsRepository
+
+	chats   map[string]models.ChatWithLastMessage
+	roles   map[string]map[string]models.ChatRole // chatID -> userID -> role
+	members map[string][]models.User
+
+	updates          []models.UpdateChatRequest
+	batchMemberCalls int
+}
+
+func newFakeChatsRepository() *fakeChatsRepository {
+	return &fakeChatsRepository{
+		chats:   make(map[string]models.ChatWithLastMessage),
+		roles:   make(map[string]map[string]models.ChatRole),
+		members: make(map[string][]models.User),
+	}
+}
+
+func (r *fakeChatsRepository) addChat(chat models.ChatWithLastMessage, roles map[string]models.ChatRole) {
+	r.chats[chat.ID] = chat
+	r.roles[chat.ID] = roles
+}
+
+func (r *fakeChatsRepository) Get(ctx context.Context, req models.GetChatRequest) (models.ChatWithLastMessage, error) {
+	if _, ok := r.roles[req.ID][req.UserID]; !ok {
+		return models.ChatWithLastMessage{}, nil
+	}
+	return r.chats[req.ID], nil
+}
+
+func (r *fakeChatsRepository) List(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error) {
+	var result []models.ChatWithLastMessage
+	for chatID, roles := range r.roles {
+		if len(req.ChatIDs) > 0 && !slices.Contains(req.ChatIDs, chatID) {
+			continue
+		}
+		if _, ok := roles[req.UserID]; ok {
+			result = append(result, r.chats[chatID])
+		}
+	}
+	return models.ListChatsResponse{Chats: result, Total: int32(len(result))}, nil
+}
+
+func (r *fakeChatsRepository) Update(ctx context.Context, req models.UpdateChatRequest) error {
+	r.updates = append(r.updates, req)
+	chat := r.chats[req.ChatID]
+	if req.Name != nil {
+		chat.Name = *req.Name
+	}
+	if req.Description != nil {
+		chat.Description = *req.Description
+	}
+	if req.AvatarAttachmentID != nil {
+		chat.AvatarAttachmentID = *req.AvatarAttachmentID
+	}
+	r.chats[req.ChatID] = chat
+	return nil
+}
+
+func (r *fakeChatsRepository) GetMembership(ctx context.Context, chatID, userID string) (models.ChatMembership, error) {
+	chat, ok := r.chats[chatID]
+	if !ok {
+		return models.ChatMembership{}, nil
+	}
+	return models.ChatMembership{
+		ChatID:     chatID,
+		UserID:     userID,
+		Kind:       chat.Kind,
+		Visibility: chat.Visibility,
+		Role:       r.roles[chatID][userID],
+	}, nil
+}
+
+func (r *fakeChatsRepository) CountMembersWithRole(ctx context.Context, chatID string, role models.ChatRole) (int, error) {
+	count := 0
+	for _, memberRole := range r.roles[chatID] {
+		if memberRole == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeChatsRepository) AddUserToChat(ctx context.Context, userID, chatID string, role models.ChatRole) error {
+	if r.roles[chatID] == nil {
+		r.roles[chatID] = make(map[string]models.ChatRole)
+	}
+	if _, ok := r.roles[chatID][userID]; !ok {
+		r.roles[chatID][userID] = role
+	}
+	return nil
+}
+
+func (r *fakeChatsRepository) RemoveUserFromChat(ctx context.Context, userID, chatID string) error {
+	delete(r.roles[chatID], userID)
+	return nil
+}
+
+func (r *fakeChatsRepository) GetChatUsers(ctx context.Context, chatID string) ([]models.User, error) {
+	return r.members[chatID], nil
+}
+
+func (r *fakeChatsRepository) GetUsersByChatIDs(ctx context.Context, chatIDs []string) (map[string][]models.User, error) {
+	r.batchMemberCalls++
+	result := make(map[string][]models.User, len(chatIDs))
+	for _, chatID := range chatIDs {
+		result[chatID] = r.members[chatID]
+	}
+	return result, nil
+}
+
+// fakeMessagesRepository records sent messages. Sends fail with err, if set.
+type fakeMessagesRepository struct {
+	messages.MessagesRepository
+
+	sent []models.Message
+	err  error
+}
+
+func (r *fakeMessagesRepository) Send(ctx context.Context, req models.Message) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	req.ID = fmt.Sprintf("msg%d", len(r.sent)+1)
+	r.sent = append(r.sent, req)
+	return req.ID, nil
+}
+
+func (r *fakeMessagesRepository) Get(ctx context.Context, messageID string) (models.Message, error) {
+	for _, msg := range r.sent {
+		if msg.ID == messageID {
+			return msg, nil
+		}
+	}
+	return models.Message{}, nil
+}
+
+// fakeIdempotencyStore keeps idempotency keys in memory, like Redis does.
+type fakeIdempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func newFakeIdempotencyStore() *fakeIdempotencyStore {
+	return &fakeIdempotencyStore{keys: make(map[string]bool)}
+}
+
+func (s *fakeIdempotencyStore) CheckAndSetIdempotency(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.keys[key] {
+		return true, nil
+	}
+	s.keys[key] = true
+	return false, nil
+}
+
+func (s *fakeIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+// fakeMessagesService records sent messages and rejects reused idempotency
+// keys like the real service. Sends fail with err, if set.
+type fakeMessagesService struct {
+	MessagesService
+
+	mu   sync.Mutex
+	sent []models.SendMessageRequest
+	err  error
+}
+
+func (s *fakeMessagesService) SendMessage(ctx context.Context, req models.SendMessageRequest) (models.SendMessageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return models.SendMessageResponse{}, s.err
+	}
+	for _, sent := range s.sent {
+		if sent.IdempotencyKey == req.IdempotencyKey {
+			return models.SendMessageResponse{}, ErrDuplicateMessage
+		}
+	}
+	s.sent = append(s.sent, req)
+	return models.SendMessageResponse{MessageID: fmt.Sprintf("msg%d", len(s.sent))}, nil
+}
+
+type fakeUsersRepository struct {
+	users.UsersRepository
+
+	users     map[string]models.User
+	coMembers map[string][]string // userID -> users sharing a chat
+
+	updates []models.User
+
+	searchResults []models.UserSearchResult // already in search order
+	searches      []models.SearchUsersRequest
+
+	identities map[[2]string]string // [issuer, subject] -> userID
+}
+
+func newFakeUsersRepository(users ...models.User) *fakeUsersRepository {
+	r := &fakeUsersRepository{users: make(map[string]models.User), coMembers: make(map[string][]string), identities: make(map[[2]string]string)}
+	for _, user := range users {
+		r.users[user.ID] = user
+	}
+	return r
+}
+
+func (r *fakeUsersRepository) Create(ctx context.Context, req models.User) (string, error) {
+	req.ID = fmt.Sprintf("user%d", len(r.users)+1)
+	r.users[req.ID] = req
+	return req.ID, nil
+}
+
+func (r *fakeUsersRepository) GetByID(ctx context.Context, id string) (models.User, error) {
+	return r.users[id], nil
+}
+
+func (r *fakeUsersRepository) addCoMembers(a, b string) {
+	r.coMembers[a] = append(r.coMembers[a], b)
+	r.coMembers[b] = append(r.coMembers[b], a)
+}
+
+func (r *fakeUsersRepository) GetByIDs(ctx context.Context, ids []string) ([]models.User, error) {
+	var result []models.User
+	for _, id := range ids {
+		if user, ok := r.users[id]; ok {
+			result = append(result, user)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeUsersRepository) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	for _, user := range r.users {
+		if strings.EqualFold(user.Username, username) {
+			return user, nil
+		}
+	}
+	return models.User{}, nil
+}
+
+func (r *fakeUsersRepository) Update(ctx context.Context, req models.User) error {
+	r.updates = append(r.updates, req)
+	r.users[req.ID] = req
+	return nil
+}
+
+func (r *fakeUsersRepository) GetCoMemberIDs(ctx context.Context, userID string) ([]string, error) {
+	return r.coMembers[userID], nil
+}
+
+func (r *fakeUsersRepository) FilterCoMembers(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for _, id := range r.coMembers[userID] {
+		if slices.Contains(ids, id) {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeUsersRepository) Search(ctx context.Context, req models.SearchUsersRequest, after *models.UserSearchCursor) ([]models.UserSearchResult, error) {
+	r.searches = append(r.searches, req)
+
+	results := r.searchResults
+	if after != nil {
+		for i, result := range results {
+			if result.ID == after.ID {
+				results = results[i+1:]
+				break
+			}
+		}
+	}
+	if len(results) > int(req.Limit) {
+		results = results[:req.Limit]
+	}
+	return results, nil
+}
+
+func (r *fakeUsersRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	user := r.users[userID]
+	user.PasswordHash = passwordHash
+	r.users[userID] = user
+	return nil
+}
+
+func (r *fakeUsersRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, nil
+}
+
+func (r *fakeUsersRepository) GetByExternalIdentity(ctx context.Context, issuer, subject string) (models.User, error) {
+	return r.users[r.identities[[2]string{issuer, subject}]], nil
+}
+
+func (r *fakeUsersRepository) LinkExternalIdentity(ctx context.Context, identity models.ExternalIdentity, emailVerified bool) error {
+	key := [2]string{identity.Issuer, identity.Subject}
+	if userID, ok := r.identities[key]; ok && userID != identity.UserID {
+		return users.ErrIdentityTaken
+	}
+	r.identities[key] = identity.UserID
+
+	user := r.users[identity.UserID]
+	if emailVerified && user.Email == identity.Email && user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		r.users[identity.UserID] = user
+	}
+	return nil
+}
+
+func (r *fakeUsersRepository) CreateWithExternalIdentity(ctx context.Context, user models.User, identity models.ExternalIdentity) (string, error) {
+	if _, ok := r.identities[[2]string{identity.Issuer, identity.Subject}]; ok {
+		return "", users.ErrIdentityTaken
+	}
+
+	userID, err := r.Create(ctx, user)
+	if err != nil {
+		return "", err
+	}
+	r.identities[[2]string{identity.Issuer, identity.Subject}] = userID
+	return userID, nil
+}
+
+func (r *fakeUsersRepository) CreateBot(ctx context.Context, bot models.User) (string, error) {
+	if existing, _ := r.GetByUsername(ctx, bot.Username); existing.ID != "" {
+		return "", users.ErrUsernameTaken
+	}
+	bot.Email = fmt.Sprintf("bot%d@%s", len(r.users)+1, models.BotEmailDomain)
+	return r.Create(ctx, bot)
+}
+
+func (r *fakeUsersRepository) ListBots(ctx context.Context, ownerID string) ([]models.User, error) {
+	var bots []models.User
+	for _, user := range r.users {
+		if user.BotOwnerID == ownerID {
+			bots = append(bots, user)
+		}
+	}
+	return bots, nil
+}
+
+type fakeModerationRepository struct {
+	moderation.ModerationRepository
+
+	chatsRepo *fakeChatsRepository
+	blocks    map[[2]string]bool // [blockerID, blockedID]
+	reports   []models.Report
+}
+
+func newFakeModerationRepository(chatsRepo *fakeChatsRepository) *fakeModerationRepository {
+	return &fakeModerationRepository{
+		chatsRepo: chatsRepo,
+		blocks:    make(map[[2]string]bool),
+	}
+}
+
+func (r *fakeModerationRepository) Block(ctx context.Context, blockerID, blockedID string) error {
+	r.blocks[[2]string{blockerID, blockedID}] = true
+	return nil
+}
+
+func (r *fakeModerationRepository) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	delete(r.blocks, [2]string{blockerID, blockedID})
+	return nil
+}
+
+func (r *fakeModerationRepository) IsBlocked(ctx context.Context, userID, otherUserID string) (bool, error) {
+	return r.blocks[[2]string{userID, otherUserID}] || r.blocks[[2]string{otherUserID, userID}], nil
+}
+
+func (r *fakeModerationRepository) HasBlockWithChatMembers(ctx context.Context, chatID, userID string) (bool, error) {
+	for memberID := range r.chatsRepo.roles[chatID] {
+		if memberID == userID {
+			continue
+		}
+		if blocked, _ := r.IsBlocked(ctx, userID, memberID); blocked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeModerationRepository) FilterBlocked(ctx context.Context, userID string, ids []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for _, id := range ids {
+		if blocked, _ := r.IsBlocked(ctx, userID, id); blocked {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeModerationRepository) CreateReport(ctx context.Context, report models.Report) (string, error) {
+	report.ID = fmt.Sprintf("report%d", len(r.reports)+1)
+	report.Status = models.ReportStatusOpen
+	r.reports = append(r.reports, report)
+	return report.ID, nil
+}
+
+func (r *fakeModerationRepository) ListReports(ctx context.Context, req models.ListReportsRequest) (models.ListReportsResponse, error) {
+	resp := models.ListReportsResponse{Reports: []models.Report{}}
+	for _, report := range r.reports {
+		if report.Status == req.Status {
+			resp.Reports = append(resp.Reports, report)
+		}
+	}
+	resp.Total = int32(len(resp.Reports))
+	return resp, nil
+}
+
+func (r *fakeModerationRepository) UpdateReportStatus(ctx context.Context, reportID string, status models.ReportStatus) (models.Report, error) {
+	for i := range r.reports {
+		if r.reports[i].ID == reportID {
+			r.reports[i].Status = status
+			return r.reports[i], nil
+		}
+	}
+	return models.Report{}, nil
+}
+
+// fakeTokensRepository mirrors the rotation rules of the Postgres
+// implementation.
+type fakeTokensRepository struct {
+	tokens.TokensRepository
+
+	refreshTokens map[string]*models.RefreshToken // tokenHash -> token
+	sessions      map[string]*models.Session
+
+	// Reset and verification tokens are created from a goroutine.
+	mu                 sync.Mutex
+	resetTokens        map[string]*models.PasswordResetToken     // tokenHash -> token
+	verificationTokens map[string]*models.EmailVerificationToken // tokenHash -> token
+	verifiedUsers      []string
+
+	// users, when set, gets the password of a consumed reset token.
+	users *fakeUsersRepository
+}
+
+func newFakeTokensRepository() *fakeTokensRepository {
+	return &fakeTokensRepository{
+		refreshTokens:      make(map[string]*models.RefreshToken),
+		sessions:           make(map[string]*models.Session),
+		resetTokens:        make(map[string]*models.PasswordResetToken),
+		verificationTokens: make(map[string]*models.EmailVerificationToken),
+	}
+}
+
+func (r *fakeTokensRepository) CreateRefreshToken(ctx context.Context, token models.RefreshToken) (models.RefreshToken, error) {
+	token.ID = fmt.Sprintf("rt%d", len(r.refreshTokens)+1)
+	if token.FamilyID == "" {
+		token.FamilyID = token.ID
+	}
+	r.refreshTokens[token.TokenHash] = &token
+	return token, nil
+}
+
+func (r *fakeTokensRepository) RotateRefreshToken(ctx context.Context, tokenHash string, next models.RefreshToken) (models.RefreshToken, error) {
+	current, ok := r.refreshTokens[tokenHash]
+	if !ok {
+		return models.RefreshToken{}, nil
+	}
+
+	now := time.Now()
+	switch {
+	case current.UsedAt != nil:
+		_ = r.RevokeFamily(ctx, current.FamilyID)
+		return *current, tokens.ErrRefreshTokenReused
+	case current.RevokedAt != nil:
+		return *current, tokens.ErrRefreshTokenRevoked
+	case !current.ExpiresAt.After(now):
+		return *current, tokens.ErrRefreshTokenExpired
+	}
+
+	current.UsedAt = &now
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+	if _, err := r.CreateRefreshToken(ctx, next); err != nil {
+		return *current, err
+	}
+	return *current, nil
+}
+
+func (r *fakeTokensRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	now := time.Now()
+	for _, token := range r.refreshTokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokensRepository) RevokeFamilyByTokenHash(ctx context.Context, userID, tokenHash string) error {
+	token, ok := r.refreshTokens[tokenHash]
+	if !ok || token.UserID != userID {
+		return nil
+	}
+	return r.RevokeFamily(ctx, token.FamilyID)
+}
+
+func (r *fakeTokensRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	now := time.Now()
+	for _, token := range r.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokensRepository) CreateSession(ctx context.Context, session models.Session) (models.Session, error) {
+	session.ID = fmt.Sprintf("session%d", len(r.sessions)+1)
+	session.CreatedAt = time.Now()
+	session.LastUsedAt = session.CreatedAt
+	r.sessions[session.ID] = &session
+	return session, nil
+}
+
+func (r *fakeTokensRepository) TouchSession(ctx context.Context, sessionID string, device models.DeviceInfo) error {
+	if session, ok := r.sessions[sessionID]; ok && session.RevokedAt == nil {
+		session.LastUsedAt = time.Now()
+		session.UserAgent = device.UserAgent
+		session.IPAddress = device.IPAddress
+	}
+	return nil
+}
+
+func (r *fakeTokensRepository) ListSessions(ctx context.Context, userID string, activeSince time.Time) ([]models.Session, error) {
+	result := []models.Session{}
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.LastUsedAt.After(activeSince) {
+			result = append(result, *session)
+		}
+	}
+	slices.SortFunc(result, func(a, b models.Session) int { return strings.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+func (r *fakeTokensRepository) RevokeSession(ctx context.Context, userID, sessionID string) (bool, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok || session.UserID != userID || session.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	return true, r.RevokeFamily(ctx, sessionID)
+}
+
+func (r *fakeTokensRepository) CreatePasswordResetToken(ctx context.Context, token models.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, existing := range r.resetTokens {
+		if existing.UserID == token.UserID && existing.UsedAt == nil {
+			existing.UsedAt = &now
+		}
+	}
+	token.ID = fmt.Sprintf("reset%d", len(r.resetTokens)+1)
+	token.CreatedAt = now
+	r.resetTokens[token.TokenHash] = &token
+	return nil
+}
+
+func (r *fakeTokensRepository) ConsumePasswordResetToken(ctx context.Context, tokenHash, passwordHash string) (models.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.resetTokens[tokenHash]
+	now := time.Now()
+	if !ok || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+		return models.PasswordResetToken{}, nil
+	}
+	token.UsedAt = &now
+	if r.users != nil {
+		return *token, r.users.UpdatePassword(ctx, token.UserID, passwordHash)
+	}
+	return *token, nil
+}
+
+func (r *fakeTokensRepository) CreateEmailVerificationToken(ctx context.Context, token models.EmailVerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, existing := range r.verificationTokens {
+		if existing.UserID == token.UserID && existing.UsedAt == nil {
+			existing.UsedAt = &now
+		}
+	}
+	token.ID = fmt.Sprintf("verification%d", len(r.verificationTokens)+1)
+	token.CreatedAt = now
+	r.verificationTokens[token.TokenHash] = &token
+	return nil
+}
+
+func (r *fakeTokensRepository) ConsumeEmailVerificationToken(ctx context.Context, tokenHash string) (models.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.verificationTokens[tokenHash]
+	now := time.Now()
+	if !ok || token.UsedAt != nil || !token.ExpiresAt.After(now) {
+		return models.EmailVerificationToken{}, nil
+	}
+	token.UsedAt = &now
+	r.verifiedUsers = append(r.verifiedUsers, token.UserID)
+	return *token, nil
+}
+
+type fakeTwoFactorRepository struct {
+	twofactor.TwoFactorRepository
+
+	totp          map[string]*models.TOTP
+	recoveryCodes map[string]map[string]bool // userID -> codeHash -> used
+	challenges    map[string]*models.LoginChallenge
+}
+
+func newFakeTwoFactorRepository() *fakeTwoFactorRepository {
+	return &fakeTwoFactorRepository{
+		totp:          make(map[string]*models.TOTP),
+		recoveryCodes: make(map[string]map[string]bool),
+		challenges:    make(map[string]*models.LoginChallenge),
+	}
+}
+
+func (r *fakeTwoFactorRepository) GetTOTP(ctx context.Context, userID string) (models.TOTP, error) {
+	if totp, ok := r.totp[userID]; ok {
+		return *totp, nil
+	}
+	return models.TOTP{}, nil
+}
+
+func (r *fakeTwoFactorRepository) SaveTOTPEnrollment(ctx context.Context, totp models.TOTP, recoveryCodeHashes []string) (bool, error) {
+	if existing, ok := r.totp[totp.UserID]; ok && existing.ConfirmedAt != nil {
+		return false, nil
+	}
+	r.totp[totp.UserID] = &totp
+	r.recoveryCodes[totp.UserID] = make(map[string]bool)
+	for _, codeHash := range recoveryCodeHashes {
+		r.recoveryCodes[totp.UserID][codeHash] = false
+	}
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) ConfirmTOTP(ctx context.Context, userID string, step int64) (bool, error) {
+	totp, ok := r.totp[userID]
+	if !ok || totp.ConfirmedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	totp.ConfirmedAt = &now
+	totp.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	totp, ok := r.totp[userID]
+	if !ok || totp.ConfirmedAt == nil || totp.LastUsedStep >= step {
+		return false, nil
+	}
+	totp.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	used, ok := r.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) CreateLoginChallenge(ctx context.Context, challenge models.LoginChallenge) (models.LoginChallenge, error) {
+	challenge.ID = fmt.Sprintf("challenge%d", len(r.challenges)+1)
+	r.challenges[challenge.TokenHash] = &challenge
+	return challenge, nil
+}
+
+func (r *fakeTwoFactorRepository) GetLoginChallenge(ctx context.Context, tokenHash string) (models.LoginChallenge, error) {
+	if challenge, ok := r.challenges[tokenHash]; ok && challenge.UsedAt == nil {
+		return *challenge, nil
+	}
+	return models.LoginChallenge{}, nil
+}
+
+func (r *fakeTwoFactorRepository) challenge(id string) *models.LoginChallenge {
+	for _, challenge := range r.challenges {
+		if challenge.ID == id {
+			return challenge
+		}
+	}
+	return nil
+}
+
+func (r *fakeTwoFactorRepository) RecordChallengeAttempt(ctx context.Context, challengeID string, maxAttempts int) (bool, error) {
+	challenge := r.challenge(challengeID)
+	if challenge == nil || challenge.Attempts >= maxAttempts || challenge.UsedAt != nil {
+		return false, nil
+	}
+	challenge.Attempts++
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) ConsumeLoginChallenge(ctx context.Context, challengeID string) (bool, error) {
+	challenge := r.challenge(challengeID)
+	if challenge == nil || challenge.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	challenge.UsedAt = &now
+	return true, nil
+}
+
+// fakeRateLimiter counts events per key in a single window that never resets.
+type fakeRateLimiter struct {
+	counts map[string]int
+}
+
+func newFakeRateLimiter() *fakeRateLimiter {
+	return &fakeRateLimiter{counts: make(map[string]int)}
+}
+
+func (l *fakeRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	l.counts[key]++
+	if l.counts[key] > limit {
+		return false, window, nil
+	}
+	return true, 0, nil
+}
+
+// fakeLoginThrottle keeps failures in memory and reads time from now, which
+// tests can move.
+type fakeLoginThrottle struct {
+	now         time.Time
+	failures    map[string][]time.Time
+	lockedUntil map[string]time.Time
+}
+
+func newFakeLoginThrottle() *fakeLoginThrottle {
+	return &fakeLoginThrottle{
+		now:         time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		failures:    make(map[string][]time.Time),
+		lockedUntil: make(map[string]time.Time),
+	}
+}
+
+func (t *fakeLoginThrottle) recent(key string, window time.Duration) []time.Time {
+	var recent []time.Time
+	for _, at := range t.failures[key] {
+		if at.After(t.now.Add(-window)) {
+			recent = append(recent, at)
+		}
+	}
+	return recent
+}
+
+func (t *fakeLoginThrottle) Check(ctx context.Context, key string, policy redisconn.ThrottlePolicy) (time.Duration, error) {
+	if until := t.lockedUntil[key]; t.now.Before(until) {
+		return until.Sub(t.now), nil
+	}
+
+	recent := t.recent(key, policy.Window)
+	delay := policy.Backoff(len(recent))
+	if delay == 0 {
+		return 0, nil
+	}
+	if wait := recent[len(recent)-1].Add(delay).Sub(t.now); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+func (t *fakeLoginThrottle) RecordFailure(ctx context.Context, key string, policy redisconn.ThrottlePolicy) (redisconn.FailureResult, error) {
+	t.failures[key] = append(t.recent(key, policy.Window), t.now)
+
+	result := redisconn.FailureResult{Failures: len(t.failures[key])}
+	if policy.LockoutAfter > 0 && result.Failures >= policy.LockoutAfter && !t.now.Before(t.lockedUntil[key]) {
+		t.lockedUntil[key] = t.now.Add(policy.LockoutDuration)
+		result.Locked = true
+	}
+	return result, nil
+}
+
+func (t *fakeLoginThrottle) Reset(ctx context.Context, key string) error {
+	delete(t.failures, key)
+	return nil
+}
+
+type fakeAPIKeysRepository struct {
+	apikeys.APIKeysRepository
+
+	keys    map[string]models.APIKey // ID -> key
+	touched []string
+}
+
+func newFakeAPIKeysRepository() *fakeAPIKeysRepository {
+	return &fakeAPIKeysRepository{keys: make(map[string]models.APIKey)}
+}
+
+func (r *fakeAPIKeysRepository) Create(ctx context.Context, key models.APIKey) (models.APIKey, error) {
+	key.ID = fmt.Sprintf("key%d", len(r.keys)+1)
+	key.CreatedAt = time.Now()
+	r.keys[key.ID] = key
+	return key, nil
+}
+
+func (r *fakeAPIKeysRepository) GetByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			return key, nil
+		}
+	}
+	return models.APIKey{}, nil
+}
+
+func (r *fakeAPIKeysRepository) ListByBot(ctx context.Context, botID string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	for _, key := range r.keys {
+		if key.BotID == botID && key.RevokedAt == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeysRepository) CountActive(ctx context.Context, botID string) (int, error) {
+	keys, _ := r.ListByBot(ctx, botID)
+	return len(keys), nil
+}
+
+func (r *fakeAPIKeysRepository) Revoke(ctx context.Context, botID, keyID string) (bool, error) {
+	key, ok := r.keys[keyID]
+	if !ok || key.BotID != botID || key.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	r.keys[keyID] = key
+	return true, nil
+}
+
+func (r *fakeAPIKeysRepository) Touch(ctx context.Context, keyID string) error {
+	r.touched = append(r.touched, keyID)
+	return nil
+}
+
+// fakeWebhooksRepository is safe for concurrent use, since deliveries are
+// sent concurrently.
+type fakeWebhooksRepository struct {
+	webhooks.WebhooksRepository
+
+	mu         sync.Mutex
+	chatsRepo  *fakeChatsRepository
+	now        func() time.Time
+	webhooks   map[string]models.Webhook // ID -> webhook
+	deliveries []models.WebhookDelivery
+	incoming   map[string]models.IncomingWebhook // ID -> incoming webhook
+
+	// users gets the bots of incoming webhooks.
+	users *fakeUsersRepository
+}
+
+func newFakeWebhooksRepository(chatsRepo *fakeChatsRepository, now func() time.Time) *fakeWebhooksRepository {
+	return &fakeWebhooksRepository{
+		chatsRepo: chatsRepo,
+		now:       now,
+		webhooks:  make(map[string]models.Webhook),
+		incoming:  make(map[string]models.IncomingWebhook),
+	}
+}
+
+func (r *fakeWebhooksRepository) Create(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook.ID = fmt.Sprintf("webhook%d", len(r.webhooks)+1)
+	webhook.CreatedAt = r.now()
+	r.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r *fakeWebhooksRepository) Get(ctx context.Context, webhookID string) (models.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.webhooks[webhookID], nil
+}
+
+func (r *fakeWebhooksRepository) List(ctx context.Context, chatID, botID string) ([]models.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []models.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.ChatID == chatID && webhook.BotID == botID {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeWebhooksRepository) Delete(ctx context.Context, webhookID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[webhookID]; !ok {
+		return false, nil
+	}
+	delete(r.webhooks, webhookID)
+	return true, nil
+}
+
+func (r *fakeWebhooksRepository) Enqueue(ctx context.Context, event models.WebhookEvent, payload json.RawMessage) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queued := 0
+	for _, webhook := range r.webhooks {
+		inChat := webhook.ChatID == event.ChatID ||
+			(webhook.BotID != "" && r.chatsRepo.roles[event.ChatID][webhook.BotID] != "")
+		if !inChat || webhook.BotID == event.ActorID || !slices.Contains(webhook.Events, event.Type) {
+			continue
+		}
+
+		r.deliveries = append(r.deliveries, models.WebhookDelivery{
+			ID:            fmt.Sprintf("delivery%d", len(r.deliveries)+1),
+			WebhookID:     webhook.ID,
+			Event:         event.Type,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: r.now(),
+			CreatedAt:     r.now(),
+		})
+		queued++
+	}
+	return queued, nil
+}
+
+func (r *fakeWebhooksRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []models.WebhookJob
+	for i, delivery := range r.deliveries {
+		if len(jobs) == limit {
+			break
+		}
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt.After(r.now()) {
+			continue
+		}
+
+		r.deliveries[i].NextAttemptAt = r.now().Add(lease)
+		webhook := r.webhooks[delivery.WebhookID]
+		jobs = append(jobs, models.WebhookJob{Delivery: delivery, URL: webhook.URL, Secret: webhook.Secret})
+	}
+	return jobs, nil
+}
+
+func (r *fakeWebhooksRepository) RecordAttempt(ctx context.Context, delivery models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			r.deliveries[i] = delivery
+		}
+	}
+	return nil
+}
+
+func (r *fakeWebhooksRepository) ListDeliveries(ctx context.Context, req models.ListWebhookDeliveriesRequest) (models.ListWebhookDeliveriesResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == req.WebhookID && (req.Status == "" || delivery.Status == req.Status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return models.ListWebhookDeliveriesResponse{Deliveries: deliveries, Total: int32(len(deliveries))}, nil
+}
+
+// delivery returns the delivery with id as it is now.
+func (r *fakeWebhooksRepository) delivery(id string) models.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return delivery
+		}
+	}
+	return models.WebhookDelivery{}
+}
+
+func (r *fakeWebhooksRepository) CreateIncoming(ctx context.Context, webhook models.IncomingWebhook, bot models.User, role models.ChatRole) (models.IncomingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	botID, err := r.users.CreateBot(ctx, bot)
+	if err != nil {
+		return models.IncomingWebhook{}, err
+	}
+	if err := r.chatsRepo.AddUserToChat(ctx, botID, webhook.ChatID, role); err != nil {
+		return models.IncomingWebhook{}, err
+	}
+
+	webhook.UserID = botID
+	webhook.Name = bot.DisplayName
+	webhook.ID = fmt.Sprintf("incoming%d", len(r.incoming)+1)
+	webhook.CreatedAt = r.now()
+	r.incoming[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r *fakeWebhooksRepository) GetIncoming(ctx context.Context, webhookID string) (models.IncomingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.incoming[webhookID], nil
+}
+
+func (r *fakeWebhooksRepository) GetIncomingByHash(ctx context.Context, tokenHash string) (models.IncomingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, webhook := range r.incoming {
+		if webhook.TokenHash == tokenHash {
+			return webhook, nil
+		}
+	}
+	return models.IncomingWebhook{}, nil
+}
+
+func (r *fakeWebhooksRepository) ListIncoming(ctx context.Context, chatID string) ([]models.IncomingWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []models.IncomingWebhook
+	for _, webhook := range r.incoming {
+		if webhook.ChatID == chatID && webhook.RevokedAt == nil {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeWebhooksRepository) RevokeIncoming(ctx context.Context, webhookID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.incoming[webhookID]
+	if !ok || webhook.RevokedAt != nil {
+		return false, nil
+	}
+	now := r.now()
+	webhook.RevokedAt = &now
+	r.incoming[webhookID] = webhook
+	return true, nil
+}
+
+// fakeWebhookPublisher records published events.
+type fakeWebhookPublisher struct {
+	events []models.WebhookEvent
+}
+
+func (p *fakeWebhookPublisher) Publish(ctx context.Context, event models.WebhookEvent) {
+	p.events = append(p.events, event)
+}
+
+type fakeCommandsRepository struct {
+	commands.CommandsRepository
+
+	chatsRepo *fakeChatsRepository
+	commands  []models.Command // in registration order
+}
+
+func newFakeCommandsRepository(chatsRepo *fakeChatsRepository) *fakeCommandsRepository {
+	return &fakeCommandsRepository{chatsRepo: chatsRepo}
+}
+
+func (r *fakeCommandsRepository) Create(ctx context.Context, command models.Command) (models.Command, error) {
+	for _, existing := range r.commands {
+		if existing.BotID == command.BotID && existing.Name == command.Name {
+			return models.Command{}, commands.ErrCommandTaken
+		}
+	}
+	command.ID = fmt.Sprintf("command%d", len(r.commands)+1)
+	command.CreatedAt = time.Now()
+	r.commands = append(r.commands, command)
+	return command, nil
+}
+
+func (r *fakeCommandsRepository) ListByBot(ctx context.Context, botID string) ([]models.Command, error) {
+	var result []models.Command
+	for _, command := range r.commands {
+		if command.BotID == botID {
+			result = append(result, command)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeCommandsRepository) ListForChat(ctx context.Context, chatID string) ([]models.Command, error) {
+	var result []models.Command
+	for _, command := range r.commands {
+		if _, ok := r.chatsRepo.roles[chatID][command.BotID]; ok {
+			result = append(result, command)
+		}
+	}
+	slices.SortStableFunc(result, func(a, b models.Command) int { return strings.Compare(a.Name, b.Name) })
+	return result, nil
+}
+
+func (r *fakeCommandsRepository) FindForChat(ctx context.Context, chatID, name string) (models.Command, error) {
+	commands, _ := r.ListForChat(ctx, chatID)
+	for _, command := range commands {
+		if command.Name == name {
+			return command, nil
+		}
+	}
+	return models.Command{}, nil
+}
+
+func (r *fakeCommandsRepository) Delete(ctx context.Context, botID, commandID string) (bool, error) {
+	for i, command := range r.commands {
+		if command.ID == commandID && command.BotID == botID {
+			r.commands = slices.Delete(r.commands, i, i+1)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeScheduledRepository struct {
+	scheduled.ScheduledRepository
+
+	mu       sync.Mutex
+	now      func() time.Time
+	messages []models.ScheduledMessage
+	claimed  map[string]time.Time // ID -> claimed until
+}
+
+func newFakeScheduledRepository(now func() time.Time) *fakeScheduledRepository {
+	return &fakeScheduledRepository{now: now, claimed: make(map[string]time.Time)}
+}
+
+func (r *fakeScheduledRepository) Create(ctx context.Context, msg models.ScheduledMessage) (models.ScheduledMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg.ID = fmt.Sprintf("sched%d", len(r.messages)+1)
+	msg.Status = models.ScheduledMessagePending
+	msg.NextAttemptAt = msg.SendAt
+	msg.CreatedAt = r.now()
+	r.messages = append(r.messages, msg)
+	return msg, nil
+}
+
+func (r *fakeScheduledRepository) List(ctx context.Context, req models.ListScheduledRequest) (models.ListScheduledResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := []models.ScheduledMessage{}
+	for _, msg := range r.messages {
+		if msg.UserID != req.UserID || msg.Status != req.Status || (req.ChatID != "" && msg.ChatID != req.ChatID) {
+			continue
+		}
+		if len(req.ChatIDs) > 0 && !slices.Contains(req.ChatIDs, msg.ChatID) {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return models.ListScheduledResponse{Messages: messages, Total: int32(len(messages))}, nil
+}
+
+func (r *fakeScheduledRepository) CountPending(ctx context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, msg := range r.messages {
+		if msg.UserID == userID && msg.Status == models.ScheduledMessagePending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeScheduledRepository) Cancel(ctx context.Context, req models.CancelScheduledRequest) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, msg := range r.messages {
+		if msg.ID != req.ScheduledID || msg.UserID != req.UserID || msg.Status != models.ScheduledMessagePending {
+			continue
+		}
+		if r.claimed[msg.ID].After(r.now()) || (len(req.ChatIDs) > 0 && !slices.Contains(req.ChatIDs, msg.ChatID)) {
+			return false, nil
+		}
+		r.messages[i].Status = models.ScheduledMessageCanceled
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *fakeScheduledRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.ScheduledMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []models.ScheduledMessage
+	for i, msg := range r.messages {
+		if len(due) == limit {
+			break
+		}
+		if msg.Status != models.ScheduledMessagePending || msg.NextAttemptAt.After(r.now()) || r.claimed[msg.ID].After(r.now()) {
+			continue
+		}
+
+		r.claimed[msg.ID] = r.now().Add(lease)
+		r.messages[i].Attempts++
+		due = append(due, r.messages[i])
+	}
+	return due, nil
+}
+
+func (r *fakeScheduledRepository) RecordAttempt(ctx context.Context, msg models.ScheduledMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.claimed, msg.ID)
+	for i := range r.messages {
+		if r.messages[i].ID == msg.ID {
+			r.messages[i] = msg
+		}
+	}
+	return nil
+}
+
+// message returns the scheduled message with id as it is now.
+func (r *fakeScheduledRepository) message(id string) models.ScheduledMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.messages {
+		if msg.ID == id {
+			return msg
+		}
+	}
+	return models.ScheduledMessage{}
+}
+
+type fakeAuditRepository struct {
+	events []models.AuditEvent
+}
+
+func newFakeAuditRepository() *fakeAuditRepository {
+	return &fakeAuditRepository{}
+}
+
+func (r *fakeAuditRepository) Record(ctx context.Context, event models.AuditEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+type fakeRevocationStore struct {
+	revoked         map[string]time.Duration // tokenID -> ttl
+	revokedSessions map[string]time.Duration // sessionID -> ttl
+	generations     map[string]int64
+}
+
+func newFakeRevocationStore() *fakeRevocationStore {
+	return &fakeRevocationStore{
+		revoked:         make(map[string]time.Duration),
+		revokedSessions: make(map[string]time.Duration),
+		generations:     make(map[string]int64),
+	}
+}
+
+func (s *fakeRevocationStore) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl > 0 {
+		s.revoked[tokenID] = ttl
+	}
+	return nil
+}
+
+func (s *fakeRevocationStore) RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error {
+	if ttl > 0 {
+		s.revokedSessions[sessionID] = ttl
+	}
+	return nil
+}
+
+func (s *fakeRevocationStore) IsRevoked(ctx context.Context, tokenID, sessionID, userID string, generation int64) (bool, error) {
+	_, tokenRevoked := s.revoked[tokenID]
+	_, sessionRevoked := s.revokedSessions[sessionID]
+	return tokenRevoked || sessionRevoked || generation < s.generations[userID], nil
+}
+
+func (s *fakeRevocationStore) GetGeneration(ctx context.Context, userID string) (int64, error) {
+	return s.generations[userID], nil
+}
+
+func (s *fakeRevocationStore) IncrementGeneration(ctx context.Context, userID string) (int64, error) {
+	s.generations[userID]++
+	return s.generations[userID], nil
+}
+
+// newTestJWTService signs with a throwaway Ed25519 key.
+func newTestJWTService(cfg jwt.Config) jwt.Service {
+	key, err := jwt.GenerateKey("test-key", jwt.AlgorithmEdDSA)
+	if err != nil {
+		panic(err)
+	}
+	cfg.Keys = append(cfg.Keys, key)
+
+	service, err := jwt.NewServiceWithConfig(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
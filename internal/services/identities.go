@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+)
+
+// usernameCandidates is how many numbered usernames are tried for a new
+// external user before falling back to a random suffix.
+const usernameCandidates = 5
+
+// LoginWithIDToken logs in with an ID token the client got from the
+// configured identity provider.
+func (s *usersService) LoginWithIDToken(ctx context.Context, req models.LoginWithIDTokenRequest) (models.LoginResponse, error) {
+	slog.Info("LoginWithIDToken service")
+
+	if s.identities == nil {
+		return models.LoginResponse{}, ErrExternalLoginUnavailable
+	}
+
+	identity, err := s.identities.VerifyIDToken(ctx, req.IDToken, req.Nonce)
+	if err != nil {
+		slog.Warn("ID token rejected", "error", err)
+		return models.LoginResponse{}, err
+	}
+
+	return s.loginWithIdentity(ctx, identity, req.Device)
+}
+
+// ExchangeOIDCCode logs in with an authorization code of the configured
+// identity provider.
+func (s *usersService) ExchangeOIDCCode(ctx context.Context, req models.ExchangeOIDCCodeRequest) (models.LoginResponse, error) {
+	slog.Info("ExchangeOIDCCode service")
+
+	if s.identities == nil {
+		return models.LoginResponse{}, ErrExternalLoginUnavailable
+	}
+
+	identity, err := s.identities.Exchange(ctx, req.Code, req.CodeVerifier, req.RedirectURI)
+	if err != nil {
+		slog.Warn("Authorization code rejected", "error", err)
+		return models.LoginResponse{}, err
+	}
+
+	return s.loginWithIdentity(ctx, identity, req.Device)
+}
+
+// loginWithIdentity finds the user linked to identity. An identity seen for
+// the first time is linked to the user with the same email, or gets a new
+// user, but only if the provider verified the email: otherwise anyone could
+// take over an account by registering its email with the provider. The
+// existing user must have verified the email too, or whoever signed up with
+// someone else's address would get their provider login.
+func (s *usersService) loginWithIdentity(ctx context.Context, identity oidc.Identity, device models.DeviceInfo) (models.LoginResponse, error) {
+	link := models.ExternalIdentity{
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	}
+
+	user, err := s.usersRepo.GetByExternalIdentity(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	if user.ID == "" {
+		if identity.Email == "" || !identity.EmailVerified {
+			slog.Warn("External identity without a verified email", "issuer", identity.Issuer, "subject", identity.Subject)
+			return models.LoginResponse{}, ErrExternalEmailNotVerified
+		}
+
+		user, err = s.usersRepo.GetByEmail(ctx, identity.Email)
+		if err != nil {
+			slog.Error("Error getting user by email", "error", err)
+			return models.LoginResponse{}, err
+		}
+
+		if user.ID == "" {
+			user, err = s.createExternalUser(ctx, identity, link)
+			if err != nil {
+				return models.LoginResponse{}, err
+			}
+			return s.completeLogin(ctx, user, device)
+		}
+
+		if user.EmailVerifiedAt == nil {
+			slog.Warn("Not linking external identity to an unverified user", "userID", user.ID, "issuer", identity.Issuer)
+			return models.LoginResponse{}, ErrLocalEmailNotVerified
+		}
+		slog.Info("Linking external identity to existing user", "userID", user.ID, "issuer", identity.Issuer)
+	}
+
+	link.UserID = user.ID
+	err = s.usersRepo.LinkExternalIdentity(ctx, link, identity.EmailVerified && identity.Email == user.Email)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return s.completeLogin(ctx, user, device)
+}
+
+// createExternalUser creates a passwordless user for identity, named after
+// its preferred username or email. Its email counts as verified.
+func (s *usersService) createExternalUser(ctx context.Context, identity oidc.Identity, link models.ExternalIdentity) (models.User, error) {
+	username, err := s.availableUsername(ctx, externalUsername(identity))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	verifiedAt := s.clock()
+	user := models.User{
+		Username:        username,
+		Email:           identity.Email,
+		DisplayName:     identity.Name,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if user.DisplayName == "" {
+		user.DisplayName = username
+	}
+	if len([]rune(user.DisplayName)) > models.MaxDisplayNameLength {
+		user.DisplayName = string([]rune(user.DisplayName)[:models.MaxDisplayNameLength])
+	}
+
+	userID, err := s.usersRepo.CreateWithExternalIdentity(ctx, user, link)
+	if errors.Is(err, ErrIdentityTaken) {
+		// A concurrent first login with the same identity won.
+		return s.usersRepo.GetByExternalIdentity(ctx, identity.Issuer, identity.Subject)
+	}
+	if err != nil {
+		slog.Error("Error creating external user", "error", err)
+		return models.User{}, err
+	}
+
+	user.ID = userID
+	slog.Info("Created user for external identity", "userID", userID, "issuer", identity.Issuer)
+	return user, nil
+}
+
+// availableUsername returns base, or base with the first free number or a
+// random suffix.
+func (s *usersService) availableUsername(ctx context.Context, base string) (string, error) {
+	for i := 1; i <= usernameCandidates; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = base + strconv.Itoa(i)
+		}
+
+		existing, err := s.usersRepo.GetByUsername(ctx, candidate)
+		if err != nil {
+			slog.Error("Error checking if username is taken", "error", err)
+			return "", err
+		}
+		if existing.ID == "" {
+			return candidate, nil
+		}
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return base + "-" + hex.EncodeToString(suffix), nil
+}
+
+// externalUsername derives a username from the identity's preferred username
+// or the local part of its email, keeping letters, digits, '.', '_' and '-'.
+func externalUsername(identity oidc.Identity) string {
+	source := identity.PreferredUsername
+	if source == "" {
+		source, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	var b strings.Builder
+	for _, r := range source {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		}
+	}
+
+	// Leave room for the suffixes added by availableUsername.
+	username := []rune(b.String())
+	if maxLength := models.MaxUsernameLength - 9; len(username) > maxLength {
+		username = username[:maxLength]
+	}
+	if len(username) == 0 {
+		return "user"
+	}
+	return string(username)
+}
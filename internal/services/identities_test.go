@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestIdentityService returns a service that trusts a fake identity
+// provider. Its users repository has alice, whose email is not verified.
+func newTestIdentityService(t *testing.T) (*usersService, *fakeUsersRepository, *oidctest.Provider) {
+	service, _ := newTestSessionsService(t)
+
+	idp := oidctest.NewProvider(t)
+	provider, err := oidc.NewProvider(idp.Config())
+	require.NoError(t, err)
+	service.identities = provider
+
+	return service, service.usersRepo.(*fakeUsersRepository), idp
+}
+
+func TestUsersService_LoginWithIDToken_CreatesUser(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+	ctx := context.Background()
+
+	token := idp.IDToken(oidctest.Claims{
+		Subject:           "sub-bob",
+		Email:             "bob@example.com",
+		EmailVerified:     true,
+		Name:              "Bob Builder",
+		PreferredUsername: "bob",
+	})
+
+	resp, err := service.LoginWithIDToken(ctx, models.LoginWithIDTokenRequest{IDToken: token})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+
+	user := usersRepo.users[resp.UserID]
+	assert.Equal(t, "bob", user.Username)
+	assert.Equal(t, "bob@example.com", user.Email)
+	assert.Equal(t, "Bob Builder", user.DisplayName)
+	assert.Empty(t, user.PasswordHash)
+	assert.NotNil(t, user.EmailVerifiedAt)
+
+	// The next login finds the same user.
+	again, err := service.LoginWithIDToken(ctx, models.LoginWithIDTokenRequest{IDToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, resp.UserID, again.UserID)
+	assert.Len(t, usersRepo.users, 2)
+
+	// Without a password, password login fails.
+	_, err = service.Login(ctx, models.LoginRequest{Email: "bob@example.com", Password: ""})
+	assert.Error(t, err)
+}
+
+func TestUsersService_LoginWithIDToken_LinksByVerifiedEmail(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+	alice := usersRepo.users["alice"]
+	verifiedAt := time.Now()
+	alice.EmailVerifiedAt = &verifiedAt
+	usersRepo.users["alice"] = alice
+
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-alice", Email: "alice@example.com", EmailVerified: true})
+
+	resp, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", resp.UserID)
+	assert.Equal(t, "alice", usersRepo.identities[[2]string{idp.Issuer(), "sub-alice"}])
+	assert.Len(t, usersRepo.users, 1)
+}
+
+func TestUsersService_LoginWithIDToken_RefusesUnverifiedLocalAccount(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+
+	// Whoever signed up as alice never proved they own the address, so the
+	// provider account must not be linked to theirs.
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-alice", Email: "alice@example.com", EmailVerified: true})
+
+	_, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token})
+	assert.ErrorIs(t, err, ErrLocalEmailNotVerified)
+	assert.Empty(t, usersRepo.identities)
+	assert.Nil(t, usersRepo.users["alice"].EmailVerifiedAt)
+	assert.Len(t, usersRepo.users, 1)
+}
+
+func TestUsersService_LoginWithIDToken_RequiresVerifiedEmail(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-alice", Email: "alice@example.com"})
+
+	_, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token})
+	assert.ErrorIs(t, err, ErrExternalEmailNotVerified)
+	assert.Empty(t, usersRepo.identities)
+	assert.Nil(t, usersRepo.users["alice"].EmailVerifiedAt)
+}
+
+func TestUsersService_LoginWithIDToken_LinkedIdentityIgnoresEmail(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+	usersRepo.identities[[2]string{idp.Issuer(), "sub-alice"}] = "alice"
+
+	// The provider's email changed and is no longer verified, but the
+	// identity is linked already.
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-alice", Email: "alice@elsewhere.example"})
+
+	resp, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", resp.UserID)
+	assert.Nil(t, usersRepo.users["alice"].EmailVerifiedAt)
+}
+
+func TestUsersService_LoginWithIDToken_PicksFreeUsername(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+
+	token := idp.IDToken(oidctest.Claims{
+		Subject:           "sub-other-alice",
+		Email:             "alice@other.example",
+		EmailVerified:     true,
+		PreferredUsername: "Alice",
+	})
+
+	resp, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice2", usersRepo.users[resp.UserID].Username)
+}
+
+func TestUsersService_LoginWithIDToken_RejectsInvalidToken(t *testing.T) {
+	service, _, idp := newTestIdentityService(t)
+
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-bob", Email: "bob@example.com", EmailVerified: true, Nonce: "n-1"})
+
+	_, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token, Nonce: "n-2"})
+	assert.ErrorIs(t, err, ErrInvalidIDToken)
+}
+
+func TestUsersService_LoginWithIDToken_TwoFactor(t *testing.T) {
+	service, _, _ := newTestTwoFactorService(t)
+	enableTOTP(t, service)
+
+	idp := oidctest.NewProvider(t)
+	provider, err := oidc.NewProvider(idp.Config())
+	require.NoError(t, err)
+	service.identities = provider
+	service.usersRepo.(*fakeUsersRepository).identities[[2]string{idp.Issuer(), "sub-alice"}] = "alice"
+
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-alice", Email: "alice@example.com", EmailVerified: true})
+
+	resp, err := service.LoginWithIDToken(context.Background(), models.LoginWithIDTokenRequest{IDToken: token})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.ChallengeToken)
+	assert.Empty(t, resp.Token)
+}
+
+func TestUsersService_ExchangeOIDCCode(t *testing.T) {
+	service, usersRepo, idp := newTestIdentityService(t)
+	ctx := context.Background()
+
+	idp.Code("code-1", oidctest.Claims{Subject: "sub-carol", Email: "carol@example.com", EmailVerified: true})
+
+	resp, err := service.ExchangeOIDCCode(ctx, models.ExchangeOIDCCodeRequest{Code: "code-1", CodeVerifier: "verifier"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.Equal(t, "carol", usersRepo.users[resp.UserID].Username)
+
+	_, err = service.ExchangeOIDCCode(ctx, models.ExchangeOIDCCodeRequest{Code: "code-1"})
+	assert.ErrorIs(t, err, ErrExchangeFailed)
+}
+
+func TestUsersService_ExternalLoginUnavailable(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	_, err := service.LoginWithIDToken(ctx, models.LoginWithIDTokenRequest{IDToken: "token"})
+	assert.ErrorIs(t, err, ErrExternalLoginUnavailable)
+
+	_, err = service.ExchangeOIDCCode(ctx, models.ExchangeOIDCCodeRequest{Code: "code"})
+	assert.ErrorIs(t, err, ErrExternalLoginUnavailable)
+}
+
+func TestExternalUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity oidc.Identity
+		want     string
+	}{
+		{"preferred username", oidc.Identity{PreferredUsername: "bob.smith", Email: "b@example.com"}, "bob.smith"},
+		{"email local part", oidc.Identity{Email: "carol_1@example.com"}, "carol_1"},
+		{"drops other characters", oidc.Identity{PreferredUsername: "dave o'brien!"}, "daveobrien"},
+		{"nothing usable", oidc.Identity{PreferredUsername: "!!!"}, "user"},
+		{"truncated", oidc.Identity{PreferredUsername: strings.Repeat("a", 200)}, strings.Repeat("a", models.MaxUsernameLength-9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, externalUsername(tt.identity))
+		})
+	}
+}
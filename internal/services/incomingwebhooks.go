@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/webhooks"
+)
+
+// IncomingWebhookPath is where the HTTP listener serves incoming webhooks;
+// the token follows it.
+const IncomingWebhookPath = "/hooks/incoming/"
+
+// IdempotencyKeyHeader makes retried incoming webhook requests post their
+// message once.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IncomingWebhookTokenPrefix starts every incoming webhook token.
+const IncomingWebhookTokenPrefix = "inh_"
+
+// incomingWebhookTokenDisplayLength is how much of a token is stored in
+// clear to tell webhooks apart.
+const incomingWebhookTokenDisplayLength = len(IncomingWebhookTokenPrefix) + 8
+
+const defaultIncomingWebhookName = "Webhook"
+
+type IncomingWebhooksService interface {
+	CreateIncomingWebhook(ctx context.Context, req models.CreateIncomingWebhookRequest) (models.CreateIncomingWebhookResponse, error)
+	ListIncomingWebhooks(ctx context.Context, userID, chatID string) ([]models.IncomingWebhook, error)
+	RevokeIncomingWebhook(ctx context.Context, userID, webhookID string) error
+	// Post sends msg to the chat of the incoming webhook with token, as the
+	// webhook's bot.
+	Post(ctx context.Context, token string, msg models.IncomingWebhookMessage) (models.SendMessageResponse, error)
+}
+
+type incomingWebhooksService struct {
+	webhooksRepo webhooks.WebhooksRepository
+	chatsRepo    chats.ChatsRepository
+	messages     MessagesService
+	baseURL      string
+}
+
+// NewIncomingWebhooksService returns the service for incoming webhooks,
+// whose URLs start with baseURL, the public URL of the HTTP listener.
+func NewIncomingWebhooksService(webhooksRepo webhooks.WebhooksRepository, chatsRepo chats.ChatsRepository, messages MessagesService, baseURL string) IncomingWebhooksService {
+	return &incomingWebhooksService{
+		webhooksRepo: webhooksRepo,
+		chatsRepo:    chatsRepo,
+		messages:     messages,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// CreateIncomingWebhook creates a bot owned by the caller for the webhook to
+// post as, and adds it to the chat, in one transaction with the webhook.
+// Bots post to channels as admins.
+func (s *incomingWebhooksService) CreateIncomingWebhook(ctx context.Context, req models.CreateIncomingWebhookRequest) (models.CreateIncomingWebhookResponse, error) {
+	slog.Info("CreateIncomingWebhook service", "userID", req.UserID, "chatID", req.ChatID)
+
+	membership, err := s.chatAdmin(ctx, req.UserID, req.ChatID)
+	if err != nil {
+		return models.CreateIncomingWebhookResponse{}, err
+	}
+
+	existing, err := s.webhooksRepo.ListIncoming(ctx, req.ChatID)
+	if err != nil {
+		slog.Error("Error listing incoming webhooks", "error", err)
+		return models.CreateIncomingWebhookResponse{}, err
+	}
+
+	if len(existing) >= models.MaxIncomingWebhooksPerChat {
+		slog.Warn("Too many incoming webhooks", "chatID", req.ChatID, "count", len(existing))
+		return models.CreateIncomingWebhookResponse{}, ErrLimitReached
+	}
+
+	secret, _, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating incoming webhook token", "error", err)
+		return models.CreateIncomingWebhookResponse{}, err
+	}
+	token := IncomingWebhookTokenPrefix + secret
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return models.CreateIncomingWebhookResponse{}, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultIncomingWebhookName
+	}
+
+	role := models.ChatRoleMember
+	if membership.Kind == models.ChatKindChannel {
+		role = models.ChatRoleAdmin
+	}
+
+	created, err := s.webhooksRepo.CreateIncoming(ctx, models.IncomingWebhook{
+		ChatID:      req.ChatID,
+		CreatedBy:   req.UserID,
+		TokenPrefix: token[:incomingWebhookTokenDisplayLength],
+		TokenHash:   hashToken(token),
+	}, models.User{
+		Username:    "webhook-" + hex.EncodeToString(suffix),
+		DisplayName: name,
+		BotOwnerID:  req.UserID,
+	}, role)
+	if err != nil {
+		slog.Error("Error creating incoming webhook", "error", err)
+		return models.CreateIncomingWebhookResponse{}, err
+	}
+
+	return models.CreateIncomingWebhookResponse{
+		IncomingWebhook: created,
+		URL:             s.baseURL + IncomingWebhookPath + token,
+	}, nil
+}
+
+func (s *incomingWebhooksService) ListIncomingWebhooks(ctx context.Context, userID, chatID string) ([]models.IncomingWebhook, error) {
+	slog.Info("ListIncomingWebhooks service", "userID", userID, "chatID", chatID)
+
+	if _, err := s.chatAdmin(ctx, userID, chatID); err != nil {
+		return nil, err
+	}
+
+	return s.webhooksRepo.ListIncoming(ctx, chatID)
+}
+
+// RevokeIncomingWebhook stops the webhook's URL from working. Its bot stays
+// in the chat, so its messages keep their sender.
+func (s *incomingWebhooksService) RevokeIncomingWebhook(ctx context.Context, userID, webhookID string) error {
+	slog.Info("RevokeIncomingWebhook service", "userID", userID, "webhookID", webhookID)
+
+	webhook, err := s.webhooksRepo.GetIncoming(ctx, webhookID)
+	if err != nil {
+		slog.Error("Error getting incoming webhook", "error", err)
+		return err
+	}
+
+	if webhook.ID == "" {
+		return ErrNotFound
+	}
+
+	if _, err := s.chatAdmin(ctx, userID, webhook.ChatID); err != nil {
+		return err
+	}
+
+	revoked, err := s.webhooksRepo.RevokeIncoming(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *incomingWebhooksService) Post(ctx context.Context, token string, msg models.IncomingWebhookMessage) (models.SendMessageResponse, error) {
+	if !strings.HasPrefix(token, IncomingWebhookTokenPrefix) {
+		return models.SendMessageResponse{}, ErrInvalidWebhookToken
+	}
+
+	webhook, err := s.webhooksRepo.GetIncomingByHash(ctx, hashToken(token))
+	if err != nil {
+		return models.SendMessageResponse{}, err
+	}
+
+	if webhook.ID == "" || webhook.RevokedAt != nil {
+		slog.Warn("Invalid incoming webhook token", "webhookID", webhook.ID)
+		return models.SendMessageResponse{}, ErrInvalidWebhookToken
+	}
+
+	slog.Info("Post incoming webhook service", "webhookID", webhook.ID, "chatID", webhook.ChatID)
+
+	// Keys are scoped to the webhook, and hashed to fit the messages table.
+	// Without a key every request is a new message, even if the text
+	// repeats.
+	var idempotencyKey string
+	if msg.IdempotencyKey != "" {
+		idempotencyKey = "incoming:" + hashToken(webhook.ID+":"+msg.IdempotencyKey)
+	} else {
+		random, _, err := newOpaqueToken()
+		if err != nil {
+			return models.SendMessageResponse{}, err
+		}
+		idempotencyKey = "incoming:" + random
+	}
+
+	return s.messages.SendMessage(ctx, models.SendMessageRequest{
+		UserID:         webhook.UserID,
+		ChatID:         webhook.ChatID,
+		Content:        msg.Text,
+		IdempotencyKey: idempotencyKey,
+	})
+}
+
+// chatAdmin returns the membership of userID in chatID if userID is an
+// admin there. Only admins manage incoming webhooks.
+func (s *incomingWebhooksService) chatAdmin(ctx context.Context, userID, chatID string) (models.ChatMembership, error) {
+	membership, err := s.chatsRepo.GetMembership(ctx, chatID, userID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return models.ChatMembership{}, err
+	}
+
+	if membership.Role == "" {
+		return models.ChatMembership{}, ErrNotFound
+	}
+
+	if membership.Role != models.ChatRoleAdmin {
+		slog.Warn("Only chat admins manage incoming webhooks", "userID", userID, "chatID", chatID)
+		return models.ChatMembership{}, ErrPermissionDenied
+	}
+
+	return membership, nil
+}
+
+// IncomingWebhookHandler serves incoming webhooks: a POST to
+// IncomingWebhookPath followed by the token, with a JSON body like
+// {"text": "Deploy finished"}, posts the text to the webhook's chat.
+func IncomingWebhookHandler(service IncomingWebhooksService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := strings.TrimPrefix(r.URL.Path, IncomingWebhookPath)
+		if token == "" || strings.Contains(token, "/") {
+			http.NotFound(w, r)
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			http.Error(w, "the body must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, models.MaxIncomingWebhookBodySize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "the body is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "could not read the body", http.StatusBadRequest)
+			return
+		}
+
+		var msg models.IncomingWebhookMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			http.Error(w, "the body is not valid JSON", http.StatusBadRequest)
+			return
+		}
+
+		if strings.TrimSpace(msg.Text) == "" {
+			http.Error(w, "text is required", http.StatusBadRequest)
+			return
+		}
+		msg.IdempotencyKey = r.Header.Get(IdempotencyKeyHeader)
+
+		resp, err := service.Post(r.Context(), token, msg)
+		switch {
+		case err == nil:
+			writeIncomingWebhookResponse(w, map[string]any{"ok": true, "message_id": resp.MessageID})
+		case errors.Is(err, ErrDuplicateMessage):
+			writeIncomingWebhookResponse(w, map[string]any{"ok": true, "duplicate": true})
+		case errors.Is(err, ErrInvalidWebhookToken):
+			http.NotFound(w, r)
+		case errors.Is(err, ErrNotFound), errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrBlocked):
+			http.Error(w, "the webhook cannot post to its chat", http.StatusForbidden)
+		default:
+			slog.Error("Error posting incoming webhook", "error", err)
+			http.Error(w, "failed to post the message", http.StatusInternalServerError)
+		}
+	})
+}
+
+func writeIncomingWebhookResponse(w http.ResponseWriter, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		slog.Error("Failed to write incoming webhook response", "error", err)
+	}
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/webhooks"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type incomingWebhooksFixture struct {
+	service  IncomingWebhooksService
+	handler  http.Handler
+	users    *fakeUsersRepository
+	chats    *fakeChatsRepository
+	messages *fakeMessagesService
+}
+
+func newIncomingWebhooksFixture() incomingWebhooksFixture {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "team", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember})
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "news", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPublic},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin})
+	usersRepo := newFakeUsersRepository()
+	webhooksRepo := newFakeWebhooksRepository(chatsRepo, time.Now)
+	webhooksRepo.users = usersRepo
+	messages := &fakeMessagesService{}
+
+	service := NewIncomingWebhooksService(webhooksRepo, chatsRepo, messages, "https://chat.example.com/")
+	return incomingWebhooksFixture{
+		service:  service,
+		handler:  IncomingWebhookHandler(service),
+		users:    usersRepo,
+		chats:    chatsRepo,
+		messages: messages,
+	}
+}
+
+func (f incomingWebhooksFixture) create(t *testing.T, chatID string) models.CreateIncomingWebhookResponse {
+	t.Helper()
+	resp, err := f.service.CreateIncomingWebhook(context.Background(), models.CreateIncomingWebhookRequest{
+		UserID: "alice",
+		ChatID: chatID,
+		Name:   "CI",
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+// post sends body to the path of url, like a client of the webhook would.
+func (f incomingWebhooksFixture) post(url, body, idempotencyKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, strings.TrimPrefix(url, "https://chat.example.com"), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	}
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIncomingWebhooksService_CreateIncomingWebhook(t *testing.T) {
+	f := newIncomingWebhooksFixture()
+
+	resp := f.create(t, "team")
+	assert.True(t, strings.HasPrefix(resp.URL, "https://chat.example.com"+IncomingWebhookPath+IncomingWebhookTokenPrefix))
+	assert.True(t, strings.HasPrefix(strings.TrimPrefix(resp.URL, "https://chat.example.com"+IncomingWebhookPath), resp.IncomingWebhook.TokenPrefix))
+	assert.Equal(t, "CI", resp.IncomingWebhook.Name)
+
+	// The webhook posts as a bot of the creator that is a member of the chat.
+	bot, err := f.users.GetByID(context.Background(), resp.IncomingWebhook.UserID)
+	require.NoError(t, err)
+	assert.True(t, bot.IsBot())
+	assert.Equal(t, "alice", bot.BotOwnerID)
+	assert.Equal(t, "CI", bot.DisplayName)
+	assert.Equal(t, models.ChatRoleMember, f.chats.roles["team"][bot.ID])
+
+	// Only admins post to channels.
+	resp = f.create(t, "news")
+	assert.Equal(t, models.ChatRoleAdmin, f.chats.roles["news"][resp.IncomingWebhook.UserID])
+}
+
+func TestIncomingWebhooksService_CreateIncomingWebhook_RequiresAdmin(t *testing.T) {
+	f := newIncomingWebhooksFixture()
+
+	_, err := f.service.CreateIncomingWebhook(context.Background(), models.CreateIncomingWebhookRequest{UserID: "bob", ChatID: "team"})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	_, err = f.service.CreateIncomingWebhook(context.Background(), models.CreateIncomingWebhookRequest{UserID: "mallory", ChatID: "team"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for range models.MaxIncomingWebhooksPerChat {
+		f.create(t, "team")
+	}
+	_, err = f.service.CreateIncomingWebhook(context.Background(), models.CreateIncomingWebhookRequest{UserID: "alice", ChatID: "team"})
+	assert.ErrorIs(t, err, ErrLimitReached)
+}
+
+func TestIncomingWebhookHandler_PostsMessages(t *testing.T) {
+	f := newIncomingWebhooksFixture()
+	webhook := f.create(t, "team")
+
+	rec := f.post(webhook.URL, `{"text": "Deploy finished"}`, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, true, body["ok"])
+	assert.Equal(t, "msg1", body["message_id"])
+
+	require.Len(t, f.messages.sent, 1)
+	assert.Equal(t, webhook.IncomingWebhook.UserID, f.messages.sent[0].UserID)
+	assert.Equal(t, "team", f.messages.sent[0].ChatID)
+	assert.Equal(t, "Deploy finished", f.messages.sent[0].Content)
+
+	// Without an idempotency key, the same text is posted again.
+	rec = f.post(webhook.URL, `{"text": "Deploy finished"}`, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, f.messages.sent, 2)
+}
+
+func TestIncomingWebhookHandler_IdempotencyKey(t *testing.T) {
+	f := newIncomingWebhooksFixture()
+	webhook := f.create(t, "team")
+	other := f.create(t, "team")
+
+	rec := f.post(webhook.URL, `{"text": "Build 42 passed"}`, "build-42")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// A retry is acknowledged without posting again.
+	rec = f.post(webhook.URL, `{"text": "Build 42 passed"}`, "build-42")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"ok": true, "duplicate": true}`, rec.Body.String())
+	assert.Len(t, f.messages.sent, 1)
+
+	// Keys are scoped to the webhook.
+	rec = f.post(other.URL, `{"text": "Build 42 passed"}`, "build-42")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, f.messages.sent, 2)
+}
+
+func TestIncomingWebhookHandler_RejectsInvalidRequests(t *testing.T) {
+	f := newIncomingWebhooksFixture()
+	webhook := f.create(t, "team")
+	path := strings.TrimPrefix(webhook.URL, "https://chat.example.com")
+
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("text=hi"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	tooLarge := `{"text": "` + strings.Repeat("a", models.MaxIncomingWebhookBodySize) + `"}`
+	assert.Equal(t, http.StatusRequestEntityTooLarge, f.post(webhook.URL, tooLarge, "").Code)
+	assert.Equal(t, http.StatusBadRequest, f.post(webhook.URL, `{"text": `, "").Code)
+	assert.Equal(t, http.StatusBadRequest, f.post(webhook.URL, `{"text": "  "}`, "").Code)
+	assert.Equal(t, http.StatusNotFound, f.post("https://chat.example.com"+IncomingWebhookPath+"inh_unknown", `{"text": "hi"}`, "").Code)
+	assert.Empty(t, f.messages.sent)
+}
+
+func TestIncomingWebhooksService_RevokeIncomingWebhook(t *testing.T) {
+	f := newIncomingWebhooksFixture()
+	webhook := f.create(t, "team")
+
+	err := f.service.RevokeIncomingWebhook(context.Background(), "bob", webhook.IncomingWebhook.ID)
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	require.NoError(t, f.service.RevokeIncomingWebhook(context.Background(), "alice", webhook.IncomingWebhook.ID))
+
+	webhooks, err := f.service.ListIncomingWebhooks(context.Background(), "alice", "team")
+	require.NoError(t, err)
+	assert.Empty(t, webhooks)
+
+	assert.Equal(t, http.StatusNotFound, f.post(webhook.URL, `{"text": "hi"}`, "").Code)
+	assert.Empty(t, f.messages.sent)
+
+	err = f.service.RevokeIncomingWebhook(context.Background(), "alice", webhook.IncomingWebhook.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestIncomingWebhooksService_CreateIncomingWebhook_LeavesNothingOnFailure(t *testing.T) {
+	pool := newTestPool(t)
+	repo := webhooks.NewWebhooksRepository(pool, pool)
+	ctx := context.Background()
+
+	args := pgx.NamedArgs{
+		"admin_id": ulid.Make().String(),
+		"chat_id":  ulid.Make().String(),
+		"uc_id":    ulid.Make().String(),
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES (@admin_id, @admin_id, @admin_id || '@example.com', 'x')`,
+		`INSERT INTO chats (id, name, kind, visibility) VALUES (@chat_id, 'team', 'CHAT', 'PRIVATE')`,
+		`INSERT INTO users_chats (id, user_id, chat_id, role) VALUES (@uc_id, @admin_id, @chat_id, 'ADMIN')`,
+	}, []string{
+		"DELETE FROM chats WHERE id = @chat_id",
+		"DELETE FROM users WHERE bot_owner_id = @admin_id",
+		"DELETE FROM users WHERE id = @admin_id",
+	})
+
+	adminID := args["admin_id"].(string)
+	webhook := models.IncomingWebhook{
+		ChatID:      args["chat_id"].(string),
+		CreatedBy:   adminID,
+		TokenPrefix: "iwh_test",
+		TokenHash:   strings.Repeat("a", 64),
+	}
+	bot := func(username string) models.User {
+		return models.User{Username: username + "-" + adminID, DisplayName: "CI", BotOwnerID: adminID}
+	}
+
+	_, err := repo.CreateIncoming(ctx, webhook, bot("first"), models.ChatRoleMember)
+	require.NoError(t, err)
+
+	// The token hash is taken, so the last insert fails; the bot and its
+	// membership must go with it.
+	_, err = repo.CreateIncoming(ctx, webhook, bot("second"), models.ChatRoleMember)
+	require.Error(t, err)
+
+	var bots, members int
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE bot_owner_id = @admin_id", args).Scan(&bots))
+	require.NoError(t, pool.QueryRow(ctx, "SELECT COUNT(*) FROM users_chats WHERE chat_id = @chat_id", args).Scan(&members))
+	assert.Equal(t, 1, bots)
+	assert.Equal(t, 2, members)
+}
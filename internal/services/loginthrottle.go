@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
+)
+
+// loginThrottleScope is one of the keys failed logins are counted under.
+type loginThrottleScope struct {
+	name   string
+	key    string
+	policy redisconn.ThrottlePolicy
+}
+
+// loginThrottleScopes counts failures per email, so one account cannot be
+// guessed from many addresses, and per client IP, so one client cannot guess
+// many accounts.
+func (s *usersService) loginThrottleScopes(email, ipAddress string) []loginThrottleScope {
+	scopes := []loginThrottleScope{
+		{name: "email", key: "email:" + strings.ToLower(strings.TrimSpace(email)), policy: s.loginEmailThrottle},
+	}
+	if ipAddress != "" {
+		scopes = append(scopes, loginThrottleScope{name: "ip", key: "ip:" + ipAddress, policy: s.loginIPThrottle})
+	}
+	return scopes
+}
+
+// secondFactorThrottleScope counts wrong two-factor codes per user. Unlike
+// the email scope, it is not reset by the right password, so logging in
+// again for a new challenge does not give more guesses.
+func (s *usersService) secondFactorThrottleScope(userID string) loginThrottleScope {
+	return loginThrottleScope{name: "second_factor", key: "2fa:" + userID, policy: s.secondFactorThrottle}
+}
+
+// checkLoginThrottle fails with a RetryAfterError while the email or the IP
+// is backing off or locked out.
+func (s *usersService) checkLoginThrottle(ctx context.Context, email, ipAddress string) error {
+	return s.checkThrottle(ctx, s.loginThrottleScopes(email, ipAddress))
+}
+
+func (s *usersService) checkThrottle(ctx context.Context, scopes []loginThrottleScope) error {
+	for _, scope := range scopes {
+		retryAfter, err := s.loginThrottle.Check(ctx, scope.key, scope.policy)
+		if err != nil {
+			return err
+		}
+
+		if retryAfter > 0 {
+			slog.Warn("Login throttled", "scope", scope.name, "key", scope.key, "retryAfter", retryAfter)
+			return &RetryAfterError{RetryAfter: retryAfter}
+		}
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a wrong email or password and records an audit
+// event when it locks the email or IP out. userID is empty for unknown
+// emails. Errors are only logged; the login fails either way.
+func (s *usersService) recordLoginFailure(ctx context.Context, email, ipAddress, userID string) {
+	s.recordThrottleFailure(ctx, s.loginThrottleScopes(email, ipAddress), email, ipAddress, userID)
+}
+
+// recordThrottleFailure counts a failure in scopes and audits the lockouts
+// it starts. email is left out of the audit event when empty.
+func (s *usersService) recordThrottleFailure(ctx context.Context, scopes []loginThrottleScope, email, ipAddress, userID string) {
+	for _, scope := range scopes {
+		result, err := s.loginThrottle.RecordFailure(ctx, scope.key, scope.policy)
+		if err != nil {
+			continue
+		}
+
+		if !result.Locked {
+			continue
+		}
+
+		details := map[string]string{
+			"scope":      scope.name,
+			"failures":   strconv.Itoa(result.Failures),
+			"locked_for": scope.policy.LockoutDuration.String(),
+		}
+		if email != "" {
+			details["email"] = email
+		}
+
+		err = s.auditRepo.Record(ctx, models.AuditEvent{
+			Action:    models.AuditLoginLockout,
+			UserID:    userID,
+			IPAddress: ipAddress,
+			Details:   details,
+		})
+		if err != nil {
+			slog.Error("Error recording login lockout", "scope", scope.name, "error", err)
+		}
+	}
+}
+
+// resetLoginThrottle forgets the failures of user after a login completed,
+// including the second factor. The IP keeps its count, or one valid account
+// would let a client guess others indefinitely.
+func (s *usersService) resetLoginThrottle(ctx context.Context, user models.User) {
+	scopes := []loginThrottleScope{s.loginThrottleScopes(user.Email, "")[0], s.secondFactorThrottleScope(user.ID)}
+	for _, scope := range scopes {
+		if err := s.loginThrottle.Reset(ctx, scope.key); err != nil {
+			slog.Error("Error resetting login throttle", "scope", scope.name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestThrottledService(t *testing.T) (*usersService, *fakeLoginThrottle, *fakeAuditRepository) {
+	service, _ := newTestSessionsService(t)
+	service.loginEmailThrottle = redisconn.ThrottlePolicy{
+		Window:          15 * time.Minute,
+		BackoffAfter:    2,
+		BackoffBase:     time.Second,
+		BackoffMax:      4 * time.Second,
+		LockoutAfter:    4,
+		LockoutDuration: 10 * time.Minute,
+	}
+	service.loginIPThrottle = redisconn.ThrottlePolicy{
+		Window:          15 * time.Minute,
+		LockoutAfter:    6,
+		LockoutDuration: 10 * time.Minute,
+	}
+
+	return service, service.loginThrottle.(*fakeLoginThrottle), service.auditRepo.(*fakeAuditRepository)
+}
+
+func loginAttempt(service *usersService, email, password, ip string) (models.LoginResponse, error) {
+	return service.Login(context.Background(), models.LoginRequest{
+		Email:    email,
+		Password: password,
+		Device:   models.DeviceInfo{IPAddress: ip},
+	})
+}
+
+func TestUsersService_Login_BacksOff(t *testing.T) {
+	service, throttle, _ := newTestThrottledService(t)
+
+	for range 2 {
+		_, err := loginAttempt(service, "alice@example.com", "wrong", "10.0.0.1")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrRateLimited)
+	}
+
+	// Even the right password has to wait for the backoff.
+	_, err := loginAttempt(service, "alice@example.com", "password123", "10.0.0.1")
+	var retryErr *RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, time.Second, retryErr.RetryAfter)
+
+	throttle.now = throttle.now.Add(time.Second)
+	_, err = loginAttempt(service, "alice@example.com", "wrong", "10.0.0.1")
+	require.Error(t, err)
+
+	// The delay doubles with each further failure.
+	_, err = loginAttempt(service, "alice@example.com", "password123", "10.0.0.1")
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 2*time.Second, retryErr.RetryAfter)
+
+	throttle.now = throttle.now.Add(2 * time.Second)
+	resp, err := loginAttempt(service, "alice@example.com", "password123", "10.0.0.1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+
+	// A successful login forgets the email's failures.
+	_, err = loginAttempt(service, "alice@example.com", "wrong", "10.0.0.1")
+	require.Error(t, err)
+	_, err = loginAttempt(service, "alice@example.com", "password123", "10.0.0.1")
+	require.NoError(t, err)
+}
+
+func TestUsersService_Login_LocksOutAndAudits(t *testing.T) {
+	service, throttle, auditRepo := newTestThrottledService(t)
+
+	for range 4 {
+		_, err := loginAttempt(service, "alice@example.com", "wrong", "10.0.0.1")
+		require.Error(t, err)
+		throttle.now = throttle.now.Add(time.Minute)
+	}
+
+	require.Len(t, auditRepo.events, 1)
+	event := auditRepo.events[0]
+	assert.Equal(t, models.AuditLoginLockout, event.Action)
+	assert.Equal(t, "alice", event.UserID)
+	assert.Equal(t, "10.0.0.1", event.IPAddress)
+	assert.Equal(t, "email", event.Details["scope"])
+	assert.Equal(t, "4", event.Details["failures"])
+
+	_, err := loginAttempt(service, "alice@example.com", "password123", "10.0.0.2")
+	var retryErr *RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 9*time.Minute, retryErr.RetryAfter)
+
+	throttle.now = throttle.now.Add(9 * time.Minute)
+	_, err = loginAttempt(service, "alice@example.com", "password123", "10.0.0.2")
+	require.NoError(t, err)
+}
+
+func TestUsersService_Login_ThrottlesIP(t *testing.T) {
+	service, throttle, auditRepo := newTestThrottledService(t)
+
+	// Unknown emails count too, so guessing many accounts locks the IP.
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com", "f@example.com"} {
+		_, err := loginAttempt(service, email, "wrong", "10.0.0.1")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrRateLimited)
+		throttle.now = throttle.now.Add(time.Second)
+	}
+
+	require.Len(t, auditRepo.events, 1)
+	assert.Equal(t, "ip", auditRepo.events[0].Details["scope"])
+	assert.Empty(t, auditRepo.events[0].UserID)
+
+	_, err := loginAttempt(service, "alice@example.com", "password123", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	// Other clients are not affected.
+	_, err = loginAttempt(service, "alice@example.com", "password123", "10.0.0.2")
+	assert.NoError(t, err)
+}
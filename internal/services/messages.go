@@ -4,12 +4,17 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/messages"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/moderation"
 	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
+	"github.com/oklog/ulid/v2"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -17,27 +22,84 @@ type MessagesService interface {
 	SendMessage(ctx context.Context, req models.SendMessageRequest) (models.SendMessageResponse, error)
 	ListMessages(ctx context.Context, req models.ListMessagesRequest) (models.ListMessagesResponse, error)
 	UpdateMessageStatus(ctx context.Context, req models.UpdateMessageStatusRequest) (models.UpdateMessageStatusResponse, error)
+	// SubscribeToChat streams the messages of a chat userID is a member of
+	// until ctx is done or they leave it.
+	SubscribeToChat(ctx context.Context, chatID, userID string) (<-chan *ChatMessage, error)
+}
+
+// idempotencyStore claims idempotency keys for sends. A key is released when
+// its send fails, so the sender can retry with it.
+type idempotencyStore interface {
+	CheckAndSetIdempotency(ctx context.Context, key string) (bool, error)
+	Release(ctx context.Context, key string) error
 }
 
 type messagesService struct {
-	messagesRepo messages.MessagesRepository
-	cache        *redis.Client
-	idempotency  *redisconn.IdempotencyService
-	realtime     RealtimeService
+	messagesRepo   messages.MessagesRepository
+	chatsRepo      chats.ChatsRepository
+	moderationRepo moderation.ModerationRepository
+	cache          *redis.Client
+	idempotency    idempotencyStore
+	realtime       RealtimeService
+	webhooks       WebhookPublisher
+	commands       CommandRouter
 }
 
-func NewMessagesService(messagesRepo messages.MessagesRepository, cacheClient *redis.Client, ttlMinutes int, realtime RealtimeService) MessagesService {
+// NewMessagesService returns the messages service. Messages starting with a
+// slash command are run by commands instead of being sent, unless commands
+// is nil.
+func NewMessagesService(messagesRepo messages.MessagesRepository, chatsRepo chats.ChatsRepository, moderationRepo moderation.ModerationRepository, cacheClient *redis.Client, ttlMinutes int, realtime RealtimeService, webhooks WebhookPublisher, commands CommandRouter) MessagesService {
 	return &messagesService{
-		messagesRepo: messagesRepo,
-		cache:        cacheClient,
-		idempotency:  redisconn.NewIdempotencyService(cacheClient, ttlMinutes),
-		realtime:     realtime,
+		messagesRepo:   messagesRepo,
+		chatsRepo:      chatsRepo,
+		moderationRepo: moderationRepo,
+		cache:          cacheClient,
+		idempotency:    redisconn.NewIdempotencyService(cacheClient, ttlMinutes),
+		realtime:       realtime,
+		webhooks:       webhooks,
+		commands:       commands,
 	}
 }
 
 func (s *messagesService) SendMessage(ctx context.Context, req models.SendMessageRequest) (models.SendMessageResponse, error) {
 	slog.Info("SendMessage service", "userID", req.UserID, "chatID", req.ChatID)
 
+	return s.send(ctx, req, s.commands != nil)
+}
+
+// send sends req, or runs the command it starts with if routeCommands is
+// set. Command replies are sent without it, so a reply cannot run another
+// command.
+func (s *messagesService) send(ctx context.Context, req models.SendMessageRequest, routeCommands bool) (models.SendMessageResponse, error) {
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return models.SendMessageResponse{}, err
+	}
+
+	if membership.Role == "" {
+		slog.Warn("User is not a member of the chat", "userID", req.UserID, "chatID", req.ChatID)
+		return models.SendMessageResponse{}, ErrNotFound
+	}
+
+	if membership.Kind == models.ChatKindChannel && membership.Role != models.ChatRoleAdmin {
+		slog.Warn("Only admins can post to a channel", "userID", req.UserID, "chatID", req.ChatID)
+		return models.SendMessageResponse{}, ErrPermissionDenied
+	}
+
+	if membership.Kind == models.ChatKindChat {
+		blocked, err := s.moderationRepo.HasBlockWithChatMembers(ctx, req.ChatID, req.UserID)
+		if err != nil {
+			slog.Error("Error checking blocks", "error", err)
+			return models.SendMessageResponse{}, err
+		}
+
+		if blocked {
+			slog.Warn("Chat member is blocked", "userID", req.UserID, "chatID", req.ChatID)
+			return models.SendMessageResponse{}, ErrBlocked
+		}
+	}
+
 	idempotencyKey := req.IdempotencyKey
 	if idempotencyKey == "" {
 		hash := sha256.Sum256([]byte(req.UserID + req.ChatID + req.Content))
@@ -52,7 +114,19 @@ func (s *messagesService) SendMessage(ctx context.Context, req models.SendMessag
 
 	if exists {
 		slog.Info("Message already exists with idempotency key", "idempotencyKey", idempotencyKey)
-		return models.SendMessageResponse{}, fmt.Errorf("message with idempotency key already exists")
+		return models.SendMessageResponse{}, ErrDuplicateMessage
+	}
+
+	if routeCommands {
+		reply, ok, err := s.commands.Route(ctx, req)
+		if err != nil {
+			slog.Error("Error routing command", "error", err)
+			s.releaseIdempotencyKey(ctx, idempotencyKey)
+			return models.SendMessageResponse{}, err
+		}
+		if ok {
+			return s.reply(ctx, req, idempotencyKey, reply)
+		}
 	}
 
 	message := models.Message{
@@ -60,24 +134,36 @@ func (s *messagesService) SendMessage(ctx context.Context, req models.SendMessag
 		ChatID:         req.ChatID,
 		UserID:         req.UserID,
 		Body:           req.Content,
+		Kind:           models.MessageKindUser,
 		Status:         models.MessageStatusSent,
 	}
 
 	messageID, err := s.messagesRepo.Send(ctx, message)
 	if err != nil {
 		slog.Error("Error sending message", "error", err)
+		s.releaseIdempotencyKey(ctx, idempotencyKey)
 		return models.SendMessageResponse{}, err
 	}
 
-	// Broadcast message to real-time subscribers
-	if s.realtime != nil {
+	// Broadcast message to real-time subscribers and webhooks
+	if s.realtime != nil || s.webhooks != nil {
 		msg, err := s.messagesRepo.Get(ctx, messageID)
 		if err != nil {
 			slog.Warn("Failed to get message for broadcasting", "error", err, "messageID", messageID)
 		} else {
-			chatMsg := s.realtime.ConvertToChatMessage(msg)
-			s.realtime.BroadcastMessage(req.ChatID, chatMsg)
-			slog.Info("Message broadcasted to real-time subscribers", "chatID", req.ChatID, "messageID", messageID)
+			if s.realtime != nil {
+				chatMsg := s.realtime.ConvertToChatMessage(msg)
+				s.realtime.BroadcastMessage(req.ChatID, chatMsg)
+				slog.Info("Message broadcasted to real-time subscribers", "chatID", req.ChatID, "messageID", messageID)
+			}
+			if s.webhooks != nil {
+				s.webhooks.Publish(ctx, models.WebhookEvent{
+					Type:    models.WebhookEventMessageCreated,
+					ChatID:  req.ChatID,
+					ActorID: req.UserID,
+					Data:    messageEventData(msg),
+				})
+			}
 		}
 	}
 
@@ -86,6 +172,73 @@ func (s *messagesService) SendMessage(ctx context.Context, req models.SendMessag
 	}, nil
 }
 
+// releaseIdempotencyKey releases the key of a send that failed. If that fails
+// too, retries are rejected as duplicates until the key expires.
+func (s *messagesService) releaseIdempotencyKey(ctx context.Context, key string) {
+	if err := s.idempotency.Release(ctx, key); err != nil {
+		slog.Error("Error releasing idempotency key", "error", err, "idempotencyKey", key)
+	}
+}
+
+// reply delivers the reply to the command in req. Public replies are sent
+// as messages of their sender; if the sender may not post in the chat, the
+// invoker gets the reply alone.
+func (s *messagesService) reply(ctx context.Context, req models.SendMessageRequest, idempotencyKey string, reply models.CommandReply) (models.SendMessageResponse, error) {
+	if reply.Text == "" {
+		return models.SendMessageResponse{Reply: &reply}, nil
+	}
+
+	if reply.Visibility == models.CommandReplyPublic {
+		hash := sha256.Sum256([]byte(idempotencyKey))
+		resp, err := s.send(ctx, models.SendMessageRequest{
+			UserID:         reply.SenderID,
+			ChatID:         req.ChatID,
+			Content:        reply.Text,
+			IdempotencyKey: "command:" + hex.EncodeToString(hash[:]),
+		}, false)
+		switch {
+		case err == nil:
+			resp.Reply = &reply
+			return resp, nil
+		case errors.Is(err, ErrNotFound), errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrBlocked):
+			slog.Warn("Command reply cannot be posted, sending it to the invoker", "senderID", reply.SenderID, "chatID", req.ChatID)
+			reply.Visibility = models.CommandReplyEphemeral
+		default:
+			return models.SendMessageResponse{}, err
+		}
+	}
+
+	if s.realtime != nil {
+		s.realtime.SendToUser(req.ChatID, req.UserID, &ChatMessage{
+			MessageID: ulid.Make().String(),
+			ChatID:    req.ChatID,
+			SenderID:  reply.SenderID,
+			Content:   reply.Text,
+			SentAt:    time.Now(),
+			Type:      MessageTypeEphemeral,
+		})
+	}
+
+	return models.SendMessageResponse{Reply: &reply}, nil
+}
+
+func (s *messagesService) SubscribeToChat(ctx context.Context, chatID, userID string) (<-chan *ChatMessage, error) {
+	slog.Info("SubscribeToChat service", "userID", userID, "chatID", chatID)
+
+	membership, err := s.chatsRepo.GetMembership(ctx, chatID, userID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return nil, err
+	}
+
+	if membership.Role == "" {
+		slog.Warn("User is not a member of the chat", "userID", userID, "chatID", chatID)
+		return nil, ErrNotFound
+	}
+
+	return s.realtime.SubscribeToChat(ctx, chatID, userID)
+}
+
 func (s *messagesService) ListMessages(ctx context.Context, req models.ListMessagesRequest) (models.ListMessagesResponse, error) {
 	slog.Info("ListMessages service", "userID", req.UserID, "chatID", req.ChatID)
 
@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/brenocoelho/messaging-app-go/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewMessagesService(t *testing.T) {
@@ -32,4 +37,111 @@ func TestMessagesService_Interface(t *testing.T) {
 	
 	// This test ensures the interface is properly defined
 	// and can be used for dependency injection
-} 
\ No newline at end of file
+} 
+
+func TestMessagesService_SendMessage_ChannelRequiresAdmin(t *testing.T) {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "news", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPublic},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin, "reader": models.ChatRoleMember})
+	messagesRepo := &fakeMessagesRepository{}
+
+	service := NewMessagesService(messagesRepo, chatsRepo, newFakeModerationRepository(chatsRepo), nil, 10, nil, nil, nil)
+
+	_, err := service.SendMessage(context.Background(), models.SendMessageRequest{
+		UserID:         "reader",
+		ChatID:         "news",
+		Content:        "Can I post here?",
+		IdempotencyKey: "key1",
+	})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	_, err = service.SendMessage(context.Background(), models.SendMessageRequest{
+		UserID:         "stranger",
+		ChatID:         "news",
+		Content:        "Or here?",
+		IdempotencyKey: "key2",
+	})
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, messagesRepo.sent)
+}
+
+func TestMessagesService_SendMessage_BlockedInDirectChat(t *testing.T) {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "direct", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember})
+	messagesRepo := &fakeMessagesRepository{}
+	moderationRepo := newFakeModerationRepository(chatsRepo)
+	require.NoError(t, moderationRepo.Block(context.Background(), "alice", "bob"))
+
+	service := NewMessagesService(messagesRepo, chatsRepo, moderationRepo, nil, 10, nil, nil, nil)
+
+	// Neither side of the block can send.
+	for _, sender := range []string{"bob", "alice"} {
+		_, err := service.SendMessage(context.Background(), models.SendMessageRequest{
+			UserID:         sender,
+			ChatID:         "direct",
+			Content:        "Hi",
+			IdempotencyKey: "key-" + sender,
+		})
+		assert.ErrorIs(t, err, ErrBlocked)
+	}
+	assert.Empty(t, messagesRepo.sent)
+}
+
+func TestMessagesService_SendMessage_FailedSendReleasesKey(t *testing.T) {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "direct", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember})
+	messagesRepo := &fakeMessagesRepository{err: errors.New("connection refused")}
+	service := &messagesService{
+		messagesRepo:   messagesRepo,
+		chatsRepo:      chatsRepo,
+		moderationRepo: newFakeModerationRepository(chatsRepo),
+		idempotency:    newFakeIdempotencyStore(),
+	}
+	req := models.SendMessageRequest{UserID: "alice", ChatID: "direct", Content: "Hi", IdempotencyKey: "key1"}
+
+	_, err := service.SendMessage(context.Background(), req)
+	assert.EqualError(t, err, "connection refused")
+
+	// The failed send did not use up the key, so the retry is sent once.
+	messagesRepo.err = nil
+	resp, err := service.SendMessage(context.Background(), req)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.MessageID)
+
+	_, err = service.SendMessage(context.Background(), req)
+	assert.ErrorIs(t, err, ErrDuplicateMessage)
+	assert.Len(t, messagesRepo.sent, 1)
+}
+
+func TestMessagesService_SubscribeToChat_RequiresMembership(t *testing.T) {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "secret", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPrivate},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin, "reader": models.ChatRoleMember})
+	realtime := NewRealtimeService(nil, nil)
+
+	service := NewMessagesService(&fakeMessagesRepository{}, chatsRepo, newFakeModerationRepository(chatsRepo), nil, 10, realtime, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Knowing the ID of a private channel is not enough to stream it.
+	_, err := service.SubscribeToChat(ctx, "secret", "stranger")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	msgChan, err := service.SubscribeToChat(ctx, "secret", "reader")
+	require.NoError(t, err)
+
+	realtime.BroadcastMessage("secret", &ChatMessage{MessageID: "msg1", ChatID: "secret"})
+	select {
+	case msg := <-msgChan:
+		assert.Equal(t, "msg1", msg.MessageID)
+	case <-time.After(time.Second):
+		t.Fatal("member did not receive the message")
+	}
+}
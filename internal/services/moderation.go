@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/messages"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/moderation"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+)
+
+type ModerationService interface {
+	BlockUser(ctx context.Context, blockerID, blockedID string) error
+	UnblockUser(ctx context.Context, blockerID, blockedID string) error
+	ListBlocked(ctx context.Context, req models.ListBlockedRequest) (models.ListBlockedResponse, error)
+	ReportUser(ctx context.Context, req models.ReportUserRequest) (string, error)
+	ReportMessage(ctx context.Context, req models.ReportMessageRequest) (string, error)
+	ListReports(ctx context.Context, req models.ListReportsRequest) (models.ListReportsResponse, error)
+	ResolveReport(ctx context.Context, reportID string, status models.ReportStatus) (models.Report, error)
+}
+
+type moderationService struct {
+	moderationRepo moderation.ModerationRepository
+	usersRepo      users.UsersRepository
+	chatsRepo      chats.ChatsRepository
+	messagesRepo   messages.MessagesRepository
+}
+
+func NewModerationService(moderationRepo moderation.ModerationRepository, usersRepo users.UsersRepository, chatsRepo chats.ChatsRepository, messagesRepo messages.MessagesRepository) ModerationService {
+	return &moderationService{
+		moderationRepo: moderationRepo,
+		usersRepo:      usersRepo,
+		chatsRepo:      chatsRepo,
+		messagesRepo:   messagesRepo,
+	}
+}
+
+func (s *moderationService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	slog.Info("BlockUser service", "blockerID", blockerID, "blockedID", blockedID)
+
+	user, err := s.usersRepo.GetByID(ctx, blockedID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return err
+	}
+
+	if user.ID == "" {
+		return ErrNotFound
+	}
+
+	return s.moderationRepo.Block(ctx, blockerID, blockedID)
+}
+
+func (s *moderationService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	slog.Info("UnblockUser service", "blockerID", blockerID, "blockedID", blockedID)
+
+	return s.moderationRepo.Unblock(ctx, blockerID, blockedID)
+}
+
+func (s *moderationService) ListBlocked(ctx context.Context, req models.ListBlockedRequest) (models.ListBlockedResponse, error) {
+	slog.Info("ListBlocked service", "userID", req.UserID)
+
+	return s.moderationRepo.ListBlocked(ctx, req)
+}
+
+func (s *moderationService) ReportUser(ctx context.Context, req models.ReportUserRequest) (string, error) {
+	slog.Info("ReportUser service", "reporterID", req.ReporterID, "userID", req.UserID, "reason", req.Reason)
+
+	user, err := s.usersRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return "", err
+	}
+
+	if user.ID == "" {
+		return "", ErrNotFound
+	}
+
+	return s.moderationRepo.CreateReport(ctx, models.Report{
+		ReporterID:     req.ReporterID,
+		ReportedUserID: req.UserID,
+		Reason:         req.Reason,
+		Details:        req.Details,
+	})
+}
+
+// ReportMessage files a report against the author of a message. Only members
+// of the message's chat can report it, so message IDs cannot be probed.
+func (s *moderationService) ReportMessage(ctx context.Context, req models.ReportMessageRequest) (string, error) {
+	slog.Info("ReportMessage service", "reporterID", req.ReporterID, "messageID", req.MessageID, "reason", req.Reason)
+
+	message, err := s.messagesRepo.Get(ctx, req.MessageID)
+	if err != nil {
+		slog.Error("Error getting message", "error", err)
+		return "", err
+	}
+
+	if message.ID == "" {
+		return "", ErrNotFound
+	}
+
+	membership, err := s.chatsRepo.GetMembership(ctx, message.ChatID, req.ReporterID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return "", err
+	}
+
+	if membership.Role == "" {
+		slog.Warn("Reporter is not a member of the chat", "reporterID", req.ReporterID, "chatID", message.ChatID)
+		return "", ErrNotFound
+	}
+
+	return s.moderationRepo.CreateReport(ctx, models.Report{
+		ReporterID:     req.ReporterID,
+		ReportedUserID: message.UserID,
+		ChatID:         message.ChatID,
+		MessageID:      message.ID,
+		Reason:         req.Reason,
+		Details:        req.Details,
+	})
+}
+
+// ListReports lists the report queue. Without a status it lists the open
+// reports.
+func (s *moderationService) ListReports(ctx context.Context, req models.ListReportsRequest) (models.ListReportsResponse, error) {
+	slog.Info("ListReports service", "status", req.Status)
+
+	if req.Status == "" {
+		req.Status = models.ReportStatusOpen
+	}
+
+	return s.moderationRepo.ListReports(ctx, req)
+}
+
+// ResolveReport closes a report as resolved or dismissed.
+func (s *moderationService) ResolveReport(ctx context.Context, reportID string, status models.ReportStatus) (models.Report, error) {
+	slog.Info("ResolveReport service", "reportID", reportID, "status", status)
+
+	report, err := s.moderationRepo.UpdateReportStatus(ctx, reportID, status)
+	if err != nil {
+		slog.Error("Error updating report status", "error", err)
+		return models.Report{}, err
+	}
+
+	if report.ID == "" {
+		return models.Report{}, ErrNotFound
+	}
+
+	return report, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestModerationService() (ModerationService, *fakeModerationRepository) {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "chat1", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember})
+	messagesRepo := &fakeMessagesRepository{
+		sent: []models.Message{{ID: "msg1", ChatID: "chat1", UserID: "bob", Body: "spam"}},
+	}
+	usersRepo := newFakeUsersRepository(models.User{ID: "alice"}, models.User{ID: "bob"})
+	moderationRepo := newFakeModerationRepository(chatsRepo)
+
+	return NewModerationService(moderationRepo, usersRepo, chatsRepo, messagesRepo), moderationRepo
+}
+
+func TestModerationService_BlockUser(t *testing.T) {
+	service, moderationRepo := newTestModerationService()
+
+	require.NoError(t, service.BlockUser(context.Background(), "alice", "bob"))
+	assert.True(t, moderationRepo.blocks[[2]string{"alice", "bob"}])
+
+	err := service.BlockUser(context.Background(), "alice", "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, service.UnblockUser(context.Background(), "alice", "bob"))
+	assert.Empty(t, moderationRepo.blocks)
+}
+
+func TestModerationService_ReportMessage(t *testing.T) {
+	service, moderationRepo := newTestModerationService()
+
+	_, err := service.ReportMessage(context.Background(), models.ReportMessageRequest{
+		ReporterID: "carol",
+		MessageID:  "msg1",
+		Reason:     models.ReportReasonSpam,
+	})
+	assert.ErrorIs(t, err, ErrNotFound, "only chat members can report a message")
+
+	reportID, err := service.ReportMessage(context.Background(), models.ReportMessageRequest{
+		ReporterID: "alice",
+		MessageID:  "msg1",
+		Reason:     models.ReportReasonSpam,
+		Details:    "Keeps sending links",
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, reportID)
+
+	require.Len(t, moderationRepo.reports, 1)
+	report := moderationRepo.reports[0]
+	assert.Equal(t, "bob", report.ReportedUserID)
+	assert.Equal(t, "chat1", report.ChatID)
+	assert.Equal(t, "msg1", report.MessageID)
+}
+
+func TestModerationService_ResolveReport(t *testing.T) {
+	service, _ := newTestModerationService()
+
+	reportID, err := service.ReportUser(context.Background(), models.ReportUserRequest{
+		ReporterID: "alice",
+		UserID:     "bob",
+		Reason:     models.ReportReasonHarassment,
+	})
+	require.NoError(t, err)
+
+	open, err := service.ListReports(context.Background(), models.ListReportsRequest{})
+	require.NoError(t, err)
+	require.Len(t, open.Reports, 1, "reports are listed open by default")
+	assert.Equal(t, reportID, open.Reports[0].ID)
+
+	report, err := service.ResolveReport(context.Background(), reportID, models.ReportStatusDismissed)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportStatusDismissed, report.Status)
+
+	open, err = service.ListReports(context.Background(), models.ListReportsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, open.Reports)
+
+	dismissed, err := service.ListReports(context.Background(), models.ListReportsRequest{Status: models.ReportStatusDismissed})
+	require.NoError(t, err)
+	assert.Len(t, dismissed.Reports, 1)
+
+	_, err = service.ResolveReport(context.Background(), "missing", models.ReportStatusResolved)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRealtimeService_PresenceHidesBlockedUsers(t *testing.T) {
+	moderationRepo := newFakeModerationRepository(newFakeChatsRepository())
+	moderationRepo.blocks[[2]string{"bob", "alice"}] = true
+	realtime := NewRealtimeService(nil, moderationRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aliceChan, err := realtime.SubscribeToChat(ctx, "channel1", "alice")
+	require.NoError(t, err)
+	carolChan, err := realtime.SubscribeToChat(ctx, "channel1", "carol")
+	require.NoError(t, err)
+
+	for _, messageType := range []MessageType{MessageTypeOnline, MessageTypeTyping, MessageTypeOffline} {
+		realtime.BroadcastMessage("channel1", &ChatMessage{ChatID: "channel1", SenderID: "bob", Type: messageType})
+
+		select {
+		case msg := <-carolChan:
+			assert.Equal(t, messageType, msg.Type)
+		default:
+			t.Fatalf("carol did not get the presence of bob")
+		}
+	}
+
+	assert.Empty(t, aliceChan, "presence must not cross a block")
+
+	// Messages themselves are not presence.
+	realtime.BroadcastMessage("channel1", &ChatMessage{ChatID: "channel1", SenderID: "bob", Type: MessageTypeNew})
+	assert.Len(t, aliceChan, 1)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword replaces the password of a signed-in user and signs out
+// every other session; the one making the call stays signed in.
+func (s *usersService) ChangePassword(ctx context.Context, req models.ChangePasswordRequest) error {
+	slog.Info("ChangePassword service", "userID", req.UserID)
+
+	user, err := s.usersRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return err
+	}
+
+	if user.ID == "" {
+		return ErrNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	if err := s.setPassword(ctx, user.ID, req.NewPassword); err != nil {
+		return err
+	}
+
+	return s.revokeSessions(ctx, user.ID, req.SessionID)
+}
+
+// RequestPasswordReset emails a single-use reset link to the account with
+// req.Email. It succeeds whether or not the account exists, and the token and
+// email are handled in the background so the response time does not tell
+// either. Requests are limited per email and per client IP, whether or not
+// the account exists, and fail with a RetryAfterError beyond that.
+func (s *usersService) RequestPasswordReset(ctx context.Context, req models.RequestPasswordResetRequest) error {
+	slog.Info("RequestPasswordReset service", "ip", req.IPAddress)
+
+	if err := s.limitPasswordResets(ctx, req.Email, req.IPAddress); err != nil {
+		return err
+	}
+
+	user, err := s.usersRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		slog.Error("Error getting user by email", "error", err)
+		return err
+	}
+
+	if user.ID == "" || user.IsBot() {
+		return nil
+	}
+
+	go s.sendPasswordReset(context.WithoutCancel(ctx), user)
+	return nil
+}
+
+// passwordResetLimit is one of the keys reset requests are counted under.
+type passwordResetLimit struct {
+	key   string
+	limit int
+}
+
+// limitPasswordResets counts a reset request against its email, so one
+// inbox cannot be flooded, and its client IP, so one client cannot flood
+// many.
+func (s *usersService) limitPasswordResets(ctx context.Context, email, ipAddress string) error {
+	limits := []passwordResetLimit{
+		{key: "password_reset_email:" + strings.ToLower(strings.TrimSpace(email)), limit: s.passwordResetEmailLimit},
+	}
+	if ipAddress != "" {
+		limits = append(limits, passwordResetLimit{key: "password_reset_ip:" + ipAddress, limit: s.passwordResetIPLimit})
+	}
+
+	for _, l := range limits {
+		allowed, retryAfter, err := s.limiter.Allow(ctx, l.key, l.limit, s.passwordResetWindow)
+		if err != nil {
+			return err
+		}
+
+		if !allowed {
+			slog.Warn("Password reset rate limited", "key", l.key, "retryAfter", retryAfter)
+			return &RetryAfterError{RetryAfter: retryAfter}
+		}
+	}
+
+	return nil
+}
+
+func (s *usersService) sendPasswordReset(ctx context.Context, user models.User) {
+	token, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating password reset token", "error", err)
+		return
+	}
+
+	err = s.tokensRepo.CreatePasswordResetToken(ctx, models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.passwordResetTTL),
+	})
+	if err != nil {
+		slog.Error("Error storing password reset token", "error", err)
+		return
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s?token=%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Username, s.passwordResetTTL, s.passwordResetURL, token),
+	})
+	if err != nil {
+		slog.Error("Error sending password reset email", "userID", user.ID, "error", err)
+	}
+}
+
+// ConfirmPasswordReset sets a new password with a token from
+// RequestPasswordReset and signs the user out everywhere. The token is only
+// used up together with the password change, so a failed update leaves it
+// valid.
+func (s *usersService) ConfirmPasswordReset(ctx context.Context, req models.ConfirmPasswordResetRequest) error {
+	slog.Info("ConfirmPasswordReset service")
+
+	passwordHash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	token, err := s.tokensRepo.ConsumePasswordResetToken(ctx, hashToken(req.Token), passwordHash)
+	if err != nil {
+		slog.Error("Error consuming password reset token", "error", err)
+		return err
+	}
+
+	if token.ID == "" {
+		return ErrInvalidResetToken
+	}
+
+	if _, err := s.revocations.IncrementGeneration(ctx, token.UserID); err != nil {
+		return err
+	}
+
+	return s.revokeSessions(ctx, token.UserID, "")
+}
+
+func (s *usersService) setPassword(ctx context.Context, userID, password string) error {
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	if err := s.usersRepo.UpdatePassword(ctx, userID, passwordHash); err != nil {
+		slog.Error("Error updating password", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.Error("Error hashing password", "error", err)
+		return "", err
+	}
+
+	return string(hashedPassword), nil
+}
+
+// revokeSessions revokes every active session of the user except keep.
+func (s *usersService) revokeSessions(ctx context.Context, userID, keep string) error {
+	sessions, err := s.tokensRepo.ListSessions(ctx, userID, time.Now().Add(-s.refreshTokenTTL))
+	if err != nil {
+		slog.Error("Error listing sessions", "error", err)
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID == keep {
+			continue
+		}
+		err := s.RevokeSession(ctx, userID, session.ID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/tokens"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestResetToken asks for a reset email and returns the token from its
+// link.
+func requestResetToken(t *testing.T, service *usersService, email string) string {
+	t.Helper()
+
+	outbox := service.mailer.(*mailer.MemoryMailer)
+	sent := len(outbox.Messages())
+
+	require.NoError(t, service.RequestPasswordReset(context.Background(), models.RequestPasswordResetRequest{Email: email}))
+	require.Eventually(t, func() bool { return len(outbox.Messages()) > sent }, time.Second, 5*time.Millisecond)
+
+	msg := outbox.Messages()[sent]
+	assert.Equal(t, email, msg.To)
+	_, token, found := strings.Cut(msg.Body, "?token=")
+	require.True(t, found)
+	token, _, _ = strings.Cut(token, "\n")
+	return token
+}
+
+func TestUsersService_ChangePassword_WrongCurrentPassword(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+
+	err := service.ChangePassword(context.Background(), models.ChangePasswordRequest{
+		UserID:          "alice",
+		CurrentPassword: "wrong",
+		NewPassword:     "new-password",
+	})
+	assert.ErrorIs(t, err, ErrInvalidPassword)
+}
+
+func TestUsersService_ChangePassword_RevokesOtherSessions(t *testing.T) {
+	service, tokensRepo := newTestSessionsService(t)
+	ctx := context.Background()
+
+	current, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	other, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	currentSession := tokensRepo.refreshTokens[hashToken(current.RefreshToken)].FamilyID
+
+	err = service.ChangePassword(ctx, models.ChangePasswordRequest{
+		UserID:          "alice",
+		SessionID:       currentSession,
+		CurrentPassword: "password123",
+		NewPassword:     "new-password",
+	})
+	require.NoError(t, err)
+
+	sessions, err := service.ListSessions(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, currentSession, sessions[0].ID)
+
+	_, err = service.RefreshSession(ctx, other.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	assert.Error(t, err)
+	_, err = service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "new-password"})
+	assert.NoError(t, err)
+}
+
+func TestUsersService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	service, tokensRepo := newTestSessionsService(t)
+
+	require.NoError(t, service.RequestPasswordReset(context.Background(), models.RequestPasswordResetRequest{Email: "nobody@example.com"}))
+
+	// Give a wrongly started goroutine the chance to send.
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, service.mailer.(*mailer.MemoryMailer).Messages())
+	tokensRepo.mu.Lock()
+	assert.Empty(t, tokensRepo.resetTokens)
+	tokensRepo.mu.Unlock()
+}
+
+func TestUsersService_ConfirmPasswordReset(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	login, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	token := requestResetToken(t, service, "alice@example.com")
+
+	err = service.ConfirmPasswordReset(ctx, models.ConfirmPasswordResetRequest{Token: token, NewPassword: "new-password"})
+	require.NoError(t, err)
+
+	// The token works once.
+	err = service.ConfirmPasswordReset(ctx, models.ConfirmPasswordResetRequest{Token: token, NewPassword: "other-password"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	// Every session is signed out and old access tokens are rejected.
+	_, err = service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	assert.Equal(t, int64(1), service.revocations.(*fakeRevocationStore).generations["alice"])
+
+	_, err = service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "new-password"})
+	assert.NoError(t, err)
+}
+
+func TestUsersService_ConfirmPasswordReset_RejectsExpiredAndReplacedTokens(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	service.passwordResetTTL = -time.Minute
+	expired := requestResetToken(t, service, "alice@example.com")
+	err := service.ConfirmPasswordReset(ctx, models.ConfirmPasswordResetRequest{Token: expired, NewPassword: "new-password"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+
+	service.passwordResetTTL = time.Hour
+	replaced := requestResetToken(t, service, "alice@example.com")
+	latest := requestResetToken(t, service, "alice@example.com")
+
+	err = service.ConfirmPasswordReset(ctx, models.ConfirmPasswordResetRequest{Token: replaced, NewPassword: "new-password"})
+	assert.ErrorIs(t, err, ErrInvalidResetToken)
+	err = service.ConfirmPasswordReset(ctx, models.ConfirmPasswordResetRequest{Token: latest, NewPassword: "new-password"})
+	assert.NoError(t, err)
+}
+
+func TestUsersService_RequestPasswordReset_LimitedPerEmail(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	for i := 0; i < DefaultPasswordResetEmailLimit; i++ {
+		requestResetToken(t, service, "alice@example.com")
+	}
+
+	// Spelling the email differently does not reset the count.
+	err := service.RequestPasswordReset(ctx, models.RequestPasswordResetRequest{Email: " Alice@Example.com"})
+	var retryErr *RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, DefaultPasswordResetWindow, retryErr.RetryAfter)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestUsersService_RequestPasswordReset_LimitedPerIP(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	// Unknown emails count too, so the limit does not tell which exist.
+	for i := 0; i < DefaultPasswordResetIPLimit; i++ {
+		err := service.RequestPasswordReset(ctx, models.RequestPasswordResetRequest{
+			Email:     fmt.Sprintf("user%d@example.com", i),
+			IPAddress: "203.0.113.7",
+		})
+		require.NoError(t, err)
+	}
+
+	err := service.RequestPasswordReset(ctx, models.RequestPasswordResetRequest{Email: "alice@example.com", IPAddress: "203.0.113.7"})
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	err = service.RequestPasswordReset(ctx, models.RequestPasswordResetRequest{Email: "bob@example.com", IPAddress: "198.51.100.2"})
+	assert.NoError(t, err)
+}
+
+func TestUsersService_ConfirmPasswordReset_TokenSurvivesFailedUpdate(t *testing.T) {
+	pool := newTestPool(t)
+	repo := tokens.NewTokensRepository(pool, pool)
+	ctx := context.Background()
+
+	token, tokenHash, err := newOpaqueToken()
+	require.NoError(t, err)
+
+	args := pgx.NamedArgs{
+		"user_id":    ulid.Make().String(),
+		"token_id":   ulid.Make().String(),
+		"token_hash": tokenHash,
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES (@user_id, @user_id, @user_id || '@example.com', 'old')`,
+		`INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at) VALUES (@token_id, @user_id, @token_hash, NOW() + INTERVAL '1 hour')`,
+	}, []string{
+		"DELETE FROM users WHERE id = @user_id",
+	})
+
+	// A hash too long for the column fails the update after the token was
+	// marked used; the token must stay usable.
+	_, err = repo.ConsumePasswordResetToken(ctx, hashToken(token), strings.Repeat("x", 256))
+	require.Error(t, err)
+
+	consumed, err := repo.ConsumePasswordResetToken(ctx, hashToken(token), "new")
+	require.NoError(t, err)
+	assert.Equal(t, args["user_id"], consumed.UserID)
+
+	var passwordHash string
+	require.NoError(t, pool.QueryRow(ctx, "SELECT password_hash FROM users WHERE id = @user_id", args).Scan(&passwordHash))
+	assert.Equal(t, "new", passwordHash)
+}
@@ -14,7 +14,13 @@ type RealtimeService interface {
 	SubscribeToChat(ctx context.Context, chatID, userID string) (<-chan *ChatMessage, error)
 	UnsubscribeFromChat(chatID, userID string)
 	BroadcastMessage(chatID string, message *ChatMessage)
+	SendToUser(chatID, userID string, message *ChatMessage)
 	ConvertToChatMessage(msg models.Message) *ChatMessage
+	SubscribeToUserUpdates(ctx context.Context, userID string) (<-chan *UserUpdate, error)
+	UnsubscribeFromUserUpdates(userID string)
+	BroadcastUserUpdate(userIDs []string, update *UserUpdate)
+	TrackSession(ctx context.Context, sessionID string) (context.Context, func())
+	CloseSession(sessionID string)
 }
 
 type realtimeService struct {
@@ -23,7 +29,21 @@ type realtimeService struct {
 	// Chat subscriptions: chatID -> userID -> message channel
 	chatSubscriptions map[string]map[string]chan *ChatMessage
 
-	redis *redisconn.IdempotencyService
+	// User update subscriptions: userID -> update channel
+	userSubscriptions map[string]chan *UserUpdate
+
+	// Open streams per login session: sessionID -> stream ID -> cancel
+	sessionStreams map[string]map[uint64]context.CancelCauseFunc
+	nextStreamID   uint64
+
+	redis  *redisconn.IdempotencyService
+	blocks BlockFilter
+}
+
+// BlockFilter reports which of ids have a block with userID in either
+// direction. It keeps presence from crossing a block.
+type BlockFilter interface {
+	FilterBlocked(ctx context.Context, userID string, ids []string) (map[string]bool, error)
 }
 
 type ChatMessage struct {
@@ -46,8 +66,26 @@ const (
 	MessageTypeTyping
 	MessageTypeOnline
 	MessageTypeOffline
+	MessageTypeChatUpdated
+	// MessageTypeEphemeral messages are sent to one subscriber and not
+	// stored, such as replies to slash commands.
+	MessageTypeEphemeral
+)
+
+type UserUpdateType string
+
+const (
+	UserUpdateTypeProfile UserUpdateType = "profile_update"
 )
 
+type UserUpdate struct {
+	UserID    string
+	Type      UserUpdateType
+	Content   string
+	Timestamp time.Time
+	User      *models.User
+}
+
 type UserPresence struct {
 	UserID   string
 	Username string
@@ -63,10 +101,15 @@ type TypingIndicator struct {
 	Timestamp time.Time
 }
 
-func NewRealtimeService(redis *redisconn.IdempotencyService) RealtimeService {
+// NewRealtimeService returns the realtime service. Without blocks, presence
+// is sent to every subscriber.
+func NewRealtimeService(redis *redisconn.IdempotencyService, blocks BlockFilter) RealtimeService {
 	return &realtimeService{
 		chatSubscriptions: make(map[string]map[string]chan *ChatMessage),
+		userSubscriptions: make(map[string]chan *UserUpdate),
+		sessionStreams:    make(map[string]map[uint64]context.CancelCauseFunc),
 		redis:             redis,
+		blocks:            blocks,
 	}
 }
 
@@ -112,26 +155,221 @@ func (s *realtimeService) UnsubscribeFromChat(chatID, userID string) {
 }
 
 func (s *realtimeService) BroadcastMessage(chatID string, message *ChatMessage) {
+	if isPresence(message.Type) {
+		s.broadcastPresence(context.Background(), chatID, message)
+		return
+	}
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	if chatSubs, exists := s.chatSubscriptions[chatID]; exists {
 		slog.Info("Broadcasting message to chat", "chatID", chatID, "subscribers", len(chatSubs))
 
+		// Channels can have thousands of subscribers, so the loop only does
+		// non-blocking sends and slow consumers are dropped in one batch.
+		var slowUsers []string
 		for userID, msgChan := range chatSubs {
 			select {
 			case msgChan <- message:
-				slog.Debug("Message sent to user", "userID", userID, "chatID", chatID)
 			default:
-				slog.Warn("User's message channel is full, skipping", "userID", userID, "chatID", chatID)
-				go s.UnsubscribeFromChat(chatID, userID)
+				slowUsers = append(slowUsers, userID)
 			}
 		}
+
+		if len(slowUsers) > 0 {
+			slog.Warn("Message channels are full, dropping subscribers", "chatID", chatID, "users", len(slowUsers))
+			go func() {
+				for _, userID := range slowUsers {
+					s.UnsubscribeFromChat(chatID, userID)
+				}
+			}()
+		}
 	} else {
 		slog.Debug("No subscribers for chat", "chatID", chatID)
 	}
 }
 
+func isPresence(messageType MessageType) bool {
+	return messageType == MessageTypeTyping || messageType == MessageTypeOnline || messageType == MessageTypeOffline
+}
+
+// broadcastPresence sends the presence of message.SenderID to the other
+// subscribers of chatID, except users on either side of a block with them.
+// Presence is dropped if blocks cannot be checked.
+func (s *realtimeService) broadcastPresence(ctx context.Context, chatID string, message *ChatMessage) {
+	s.mu.RLock()
+	subscriberIDs := make([]string, 0, len(s.chatSubscriptions[chatID]))
+	for userID := range s.chatSubscriptions[chatID] {
+		if userID != message.SenderID {
+			subscriberIDs = append(subscriberIDs, userID)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(subscriberIDs) == 0 {
+		return
+	}
+
+	var blocked map[string]bool
+	if s.blocks != nil {
+		var err error
+		blocked, err = s.blocks.FilterBlocked(ctx, message.SenderID, subscriberIDs)
+		if err != nil {
+			slog.Error("Error filtering blocked subscribers", "error", err, "chatID", chatID, "userID", message.SenderID)
+			return
+		}
+	}
+
+	for _, userID := range subscriberIDs {
+		if !blocked[userID] {
+			s.SendToUser(chatID, userID, message)
+		}
+	}
+}
+
+// SendToUser sends message to the subscription of userID to chatID only. A
+// user without a subscription does not get it.
+func (s *realtimeService) SendToUser(chatID, userID string, message *ChatMessage) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	msgChan, exists := s.chatSubscriptions[chatID][userID]
+	if !exists {
+		slog.Debug("User is not subscribed to chat", "chatID", chatID, "userID", userID)
+		return
+	}
+
+	select {
+	case msgChan <- message:
+	default:
+		slog.Warn("Message channel is full, dropping subscriber", "chatID", chatID, "userID", userID)
+		go s.UnsubscribeFromChat(chatID, userID)
+	}
+}
+
+func (s *realtimeService) SubscribeToUserUpdates(ctx context.Context, userID string) (<-chan *UserUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updateChan := make(chan *UserUpdate, 100)
+
+	// A newer stream for the same user replaces the old one.
+	if existing, exists := s.userSubscriptions[userID]; exists {
+		close(existing)
+	}
+	s.userSubscriptions[userID] = updateChan
+
+	slog.Info("User subscribed to user updates", "userID", userID)
+
+	go func() {
+		<-ctx.Done()
+		s.unsubscribeUserUpdates(userID, updateChan)
+	}()
+
+	return updateChan, nil
+}
+
+func (s *realtimeService) UnsubscribeFromUserUpdates(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if updateChan, exists := s.userSubscriptions[userID]; exists {
+		close(updateChan)
+		delete(s.userSubscriptions, userID)
+
+		slog.Info("User unsubscribed from user updates", "userID", userID)
+	}
+}
+
+// unsubscribeUserUpdates removes the subscription only if it is still the
+// given channel, so an ended stream does not close its replacement.
+func (s *realtimeService) unsubscribeUserUpdates(userID string, updateChan chan *UserUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, exists := s.userSubscriptions[userID]; exists && current == updateChan {
+		close(updateChan)
+		delete(s.userSubscriptions, userID)
+
+		slog.Info("User unsubscribed from user updates", "userID", userID)
+	}
+}
+
+func (s *realtimeService) BroadcastUserUpdate(userIDs []string, update *UserUpdate) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var slowUsers []string
+	for _, userID := range userIDs {
+		updateChan, exists := s.userSubscriptions[userID]
+		if !exists {
+			continue
+		}
+		select {
+		case updateChan <- update:
+		default:
+			slowUsers = append(slowUsers, userID)
+		}
+	}
+
+	if len(slowUsers) > 0 {
+		slog.Warn("User update channels are full, dropping subscribers", "users", len(slowUsers))
+		go func() {
+			for _, userID := range slowUsers {
+				s.UnsubscribeFromUserUpdates(userID)
+			}
+		}()
+	}
+}
+
+// TrackSession returns a context derived from ctx that is cancelled with
+// ErrSessionRevoked when sessionID is closed. Callers must call the returned
+// release func once their stream ends.
+func (s *realtimeService) TrackSession(ctx context.Context, sessionID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if sessionID == "" {
+		return ctx, func() { cancel(nil) }
+	}
+
+	s.mu.Lock()
+	s.nextStreamID++
+	streamID := s.nextStreamID
+	if s.sessionStreams[sessionID] == nil {
+		s.sessionStreams[sessionID] = make(map[uint64]context.CancelCauseFunc)
+	}
+	s.sessionStreams[sessionID][streamID] = cancel
+	s.mu.Unlock()
+
+	release := func() {
+		s.mu.Lock()
+		if streams, exists := s.sessionStreams[sessionID]; exists {
+			delete(streams, streamID)
+			if len(streams) == 0 {
+				delete(s.sessionStreams, sessionID)
+			}
+		}
+		s.mu.Unlock()
+		cancel(nil)
+	}
+
+	return ctx, release
+}
+
+// CloseSession ends every stream opened with sessionID.
+func (s *realtimeService) CloseSession(sessionID string) {
+	s.mu.Lock()
+	streams := s.sessionStreams[sessionID]
+	delete(s.sessionStreams, sessionID)
+	s.mu.Unlock()
+
+	for _, cancel := range streams {
+		cancel(ErrSessionRevoked)
+	}
+
+	slog.Info("Closed session streams", "sessionID", sessionID, "streams", len(streams))
+}
+
 func (s *realtimeService) ConvertToChatMessage(msg models.Message) *ChatMessage {
 	username := ""
 	if msg.User != nil {
@@ -11,7 +11,7 @@ import (
 )
 
 func TestNewRealtimeService(t *testing.T) {
-	service := NewRealtimeService(nil)
+	service := NewRealtimeService(nil, nil)
 	assert.NotNil(t, service)
 
 	// Test that the service implements the interface
@@ -19,7 +19,7 @@ func TestNewRealtimeService(t *testing.T) {
 }
 
 func TestRealtimeService_SubscribeToChat(t *testing.T) {
-	service := NewRealtimeService(nil)
+	service := NewRealtimeService(nil, nil)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
@@ -34,7 +34,7 @@ func TestRealtimeService_SubscribeToChat(t *testing.T) {
 }
 
 func TestRealtimeService_UnsubscribeFromChat(t *testing.T) {
-	service := NewRealtimeService(nil)
+	service := NewRealtimeService(nil, nil)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
@@ -55,7 +55,7 @@ func TestRealtimeService_UnsubscribeFromChat(t *testing.T) {
 }
 
 func TestRealtimeService_BroadcastMessage(t *testing.T) {
-	service := NewRealtimeService(nil)
+	service := NewRealtimeService(nil, nil)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
@@ -101,8 +101,42 @@ func TestRealtimeService_BroadcastMessage(t *testing.T) {
 	time.Sleep(10 * time.Millisecond) // Give time for cleanup
 }
 
+func TestRealtimeService_SendToUser(t *testing.T) {
+	service := NewRealtimeService(nil, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	aliceChan, err := service.SubscribeToChat(ctx, "chat_ephemeral_test", "alice")
+	require.NoError(t, err)
+	bobChan, err := service.SubscribeToChat(ctx, "chat_ephemeral_test", "bob")
+	require.NoError(t, err)
+
+	service.SendToUser("chat_ephemeral_test", "alice", &ChatMessage{
+		ChatID:  "chat_ephemeral_test",
+		Content: "Only for Alice",
+		Type:    MessageTypeEphemeral,
+	})
+
+	select {
+	case receivedMsg := <-aliceChan:
+		assert.Equal(t, "Only for Alice", receivedMsg.Content)
+		assert.Equal(t, MessageTypeEphemeral, receivedMsg.Type)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Alice did not receive the message")
+	}
+
+	select {
+	case receivedMsg := <-bobChan:
+		t.Errorf("Bob received %q", receivedMsg.Content)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	time.Sleep(10 * time.Millisecond) // Give time for cleanup
+}
+
 func TestRealtimeService_ConvertToChatMessage(t *testing.T) {
-	service := NewRealtimeService(nil)
+	service := NewRealtimeService(nil, nil)
 
 	// Create a test message
 	msg := models.Message{
@@ -126,7 +160,7 @@ func TestRealtimeService_ConvertToChatMessage(t *testing.T) {
 }
 
 func TestRealtimeService_ContextCancellation(t *testing.T) {
-	service := NewRealtimeService(nil)
+	service := NewRealtimeService(nil, nil)
 
 	// Create context with cancellation
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -152,3 +186,28 @@ func TestRealtimeService_ContextCancellation(t *testing.T) {
 	newCancel()
 	time.Sleep(10 * time.Millisecond) // Give time for cleanup
 }
+
+func TestRealtimeService_CloseSession(t *testing.T) {
+	service := NewRealtimeService(nil, nil)
+
+	revokedCtx, releaseRevoked := service.TrackSession(context.Background(), "session1")
+	defer releaseRevoked()
+	otherCtx, releaseOther := service.TrackSession(context.Background(), "session2")
+	defer releaseOther()
+
+	msgChan, err := service.SubscribeToChat(revokedCtx, "chat_close_session", "user_close_session")
+	require.NoError(t, err)
+
+	service.CloseSession("session1")
+
+	assert.ErrorIs(t, context.Cause(revokedCtx), ErrSessionRevoked)
+	assert.NoError(t, otherCtx.Err())
+
+	// The subscription made with the session's context is closed as well.
+	select {
+	case _, ok := <-msgChan:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed")
+	}
+}
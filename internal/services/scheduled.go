@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/scheduled"
+)
+
+type ScheduledMessagesService interface {
+	ScheduleMessage(ctx context.Context, req models.ScheduleMessageRequest) (models.ScheduledMessage, error)
+	ListScheduled(ctx context.Context, req models.ListScheduledRequest) (models.ListScheduledResponse, error)
+	CancelScheduled(ctx context.Context, req models.CancelScheduledRequest) error
+	// DeliverDue sends one batch of due messages and returns how many it
+	// attempted.
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// CatchUpPolicy decides what happens to messages that were due while no
+// scheduler ran, for example during a deploy or an outage.
+type CatchUpPolicy string
+
+const (
+	// CatchUpSend sends missed messages late.
+	CatchUpSend CatchUpPolicy = "send"
+	// CatchUpSkip drops messages missed by more than the catch-up window.
+	CatchUpSkip CatchUpPolicy = "skip"
+)
+
+// SchedulerConfig tunes scheduled message delivery. Zero values use the
+// defaults.
+type SchedulerConfig struct {
+	// CatchUp is applied to messages found more than CatchUpWindow after
+	// their send time. Later messages are always sent.
+	CatchUp       CatchUpPolicy
+	CatchUpWindow time.Duration
+	// MaxAttempts is how often a message is tried when sending fails for a
+	// reason that may pass, such as the database being unavailable. Each
+	// retry waits RetryDelay longer than the one before.
+	MaxAttempts int
+	RetryDelay  time.Duration
+	// BatchSize messages are claimed at a time and sent concurrently.
+	BatchSize int
+	// Lease is how long a claimed message is reserved for its scheduler.
+	Lease time.Duration
+
+	Clock func() time.Time
+}
+
+const (
+	DefaultCatchUpWindow        = 5 * time.Minute
+	DefaultScheduledMaxAttempts = 5
+	DefaultScheduledRetryDelay  = time.Minute
+	DefaultScheduledBatchSize   = 20
+	DefaultScheduledLease       = 2 * time.Minute
+)
+
+type scheduledMessagesService struct {
+	scheduledRepo scheduled.ScheduledRepository
+	chatsRepo     chats.ChatsRepository
+	messages      MessagesService
+	cfg           SchedulerConfig
+}
+
+// NewScheduledMessagesService returns the scheduled messages service. Due
+// messages are sent with messages, like any other message of their author.
+func NewScheduledMessagesService(scheduledRepo scheduled.ScheduledRepository, chatsRepo chats.ChatsRepository, messages MessagesService, cfg SchedulerConfig) ScheduledMessagesService {
+	if cfg.CatchUp == "" {
+		cfg.CatchUp = CatchUpSend
+	}
+	if cfg.CatchUpWindow <= 0 {
+		cfg.CatchUpWindow = DefaultCatchUpWindow
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultScheduledMaxAttempts
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = DefaultScheduledRetryDelay
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultScheduledBatchSize
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultScheduledLease
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	return &scheduledMessagesService{
+		scheduledRepo: scheduledRepo,
+		chatsRepo:     chatsRepo,
+		messages:      messages,
+		cfg:           cfg,
+	}
+}
+
+func (s *scheduledMessagesService) ScheduleMessage(ctx context.Context, req models.ScheduleMessageRequest) (models.ScheduledMessage, error) {
+	slog.Info("ScheduleMessage service", "userID", req.UserID, "chatID", req.ChatID, "sendAt", req.SendAt)
+
+	now := s.cfg.Clock()
+	if !req.SendAt.After(now) || req.SendAt.Sub(now) > models.MaxScheduleAhead {
+		return models.ScheduledMessage{}, ErrInvalidScheduleTime
+	}
+
+	// The checks of SendMessage are repeated when the message is sent, but
+	// failing now tells the user while they can still do something about it.
+	membership, err := s.chatsRepo.GetMembership(ctx, req.ChatID, req.UserID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return models.ScheduledMessage{}, err
+	}
+
+	if membership.Role == "" {
+		return models.ScheduledMessage{}, ErrNotFound
+	}
+
+	if membership.Kind == models.ChatKindChannel && membership.Role != models.ChatRoleAdmin {
+		return models.ScheduledMessage{}, ErrPermissionDenied
+	}
+
+	count, err := s.scheduledRepo.CountPending(ctx, req.UserID)
+	if err != nil {
+		return models.ScheduledMessage{}, err
+	}
+
+	if count >= models.MaxScheduledPerUser {
+		return models.ScheduledMessage{}, ErrLimitReached
+	}
+
+	return s.scheduledRepo.Create(ctx, models.ScheduledMessage{
+		UserID:  req.UserID,
+		ChatID:  req.ChatID,
+		Content: req.Content,
+		SendAt:  req.SendAt.UTC(),
+	})
+}
+
+func (s *scheduledMessagesService) ListScheduled(ctx context.Context, req models.ListScheduledRequest) (models.ListScheduledResponse, error) {
+	slog.Info("ListScheduled service", "userID", req.UserID, "chatID", req.ChatID, "status", req.Status)
+
+	if req.Status == "" {
+		req.Status = models.ScheduledMessagePending
+	}
+
+	if req.Limit > models.MaxListScheduledMessagesLimit {
+		req.Limit = models.MaxListScheduledMessagesLimit
+	}
+
+	return s.scheduledRepo.List(ctx, req)
+}
+
+func (s *scheduledMessagesService) CancelScheduled(ctx context.Context, req models.CancelScheduledRequest) error {
+	slog.Info("CancelScheduled service", "userID", req.UserID, "scheduledID", req.ScheduledID)
+
+	canceled, err := s.scheduledRepo.Cancel(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if !canceled {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *scheduledMessagesService) DeliverDue(ctx context.Context) (int, error) {
+	due, err := s.scheduledRepo.ClaimDue(ctx, s.cfg.BatchSize, s.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, msg := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, msg)
+		}()
+	}
+	wg.Wait()
+
+	return len(due), nil
+}
+
+// deliver sends msg, whose attempt was counted when it was claimed, and
+// records the outcome: sent, skipped by the catch-up policy, retried later,
+// or failed.
+func (s *scheduledMessagesService) deliver(ctx context.Context, msg models.ScheduledMessage) {
+	now := s.cfg.Clock()
+	msg.LastError = ""
+
+	// Only the first attempt can be missed; retries are late because of the
+	// scheduler itself.
+	if msg.Attempts == 1 && s.cfg.CatchUp == CatchUpSkip && now.Sub(msg.SendAt) > s.cfg.CatchUpWindow {
+		slog.Warn("Skipping missed scheduled message", "scheduledID", msg.ID, "sendAt", msg.SendAt)
+		msg.Status = models.ScheduledMessageSkipped
+		s.record(ctx, msg)
+		return
+	}
+
+	// The idempotency key makes a message sent twice, by a scheduler whose
+	// lease ran out, arrive once.
+	resp, err := s.messages.SendMessage(ctx, models.SendMessageRequest{
+		UserID:         msg.UserID,
+		ChatID:         msg.ChatID,
+		Content:        msg.Content,
+		IdempotencyKey: "scheduled:" + msg.ID,
+	})
+
+	switch {
+	case err == nil, errors.Is(err, ErrDuplicateMessage):
+		msg.Status = models.ScheduledMessageSent
+		msg.SentAt = &now
+		if resp.MessageID != "" {
+			msg.MessageID = &resp.MessageID
+		}
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrPermissionDenied), errors.Is(err, ErrBlocked):
+		slog.Warn("Scheduled message can no longer be sent", "scheduledID", msg.ID, "error", err)
+		msg.Status = models.ScheduledMessageFailed
+		msg.LastError = err.Error()
+	case int(msg.Attempts) >= s.cfg.MaxAttempts:
+		slog.Error("Scheduled message failed for good", "scheduledID", msg.ID, "error", err)
+		msg.Status = models.ScheduledMessageFailed
+		msg.LastError = truncate(err.Error(), maxWebhookErrorLength)
+	default:
+		slog.Info("Scheduled message failed, retrying", "scheduledID", msg.ID, "attempts", msg.Attempts, "error", err)
+		msg.Status = models.ScheduledMessagePending
+		msg.NextAttemptAt = now.Add(s.cfg.RetryDelay * time.Duration(msg.Attempts))
+		msg.LastError = truncate(err.Error(), maxWebhookErrorLength)
+	}
+
+	s.record(ctx, msg)
+}
+
+func (s *scheduledMessagesService) record(ctx context.Context, msg models.ScheduledMessage) {
+	if err := s.scheduledRepo.RecordAttempt(ctx, msg); err != nil {
+		slog.Error("Error recording scheduled message attempt", "error", err, "scheduledID", msg.ID)
+	}
+}
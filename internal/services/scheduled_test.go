@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testScheduled struct {
+	service  *scheduledMessagesService
+	repo     *fakeScheduledRepository
+	messages *fakeMessagesService
+	now      *time.Time
+}
+
+func newTestScheduledService(cfg SchedulerConfig) testScheduled {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "chat1", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember})
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "channel1", Kind: models.ChatKindChannel},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember})
+
+	repo := newFakeScheduledRepository(clock)
+	messages := &fakeMessagesService{}
+	cfg.MaxAttempts = 3
+	cfg.Clock = clock
+	service := NewScheduledMessagesService(repo, chatsRepo, messages, cfg).(*scheduledMessagesService)
+
+	return testScheduled{service: service, repo: repo, messages: messages, now: &now}
+}
+
+func (ts testScheduled) schedule(t *testing.T, userID, chatID string, in time.Duration) models.ScheduledMessage {
+	t.Helper()
+
+	scheduled, err := ts.service.ScheduleMessage(context.Background(), models.ScheduleMessageRequest{
+		UserID: userID, ChatID: chatID, Content: "good morning", SendAt: ts.now.Add(in),
+	})
+	require.NoError(t, err)
+	return scheduled
+}
+
+func TestScheduledMessagesService_ScheduleMessage(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+	ctx := context.Background()
+
+	scheduled := ts.schedule(t, "bob", "chat1", time.Hour)
+	assert.Equal(t, models.ScheduledMessagePending, scheduled.Status)
+	assert.Equal(t, ts.now.Add(time.Hour), scheduled.SendAt)
+
+	tests := []struct {
+		name   string
+		userID string
+		chatID string
+		sendAt time.Time
+		want   error
+	}{
+		{"in the past", "bob", "chat1", ts.now.Add(-time.Minute), ErrInvalidScheduleTime},
+		{"too far ahead", "bob", "chat1", ts.now.Add(models.MaxScheduleAhead + time.Hour), ErrInvalidScheduleTime},
+		{"not a member", "carol", "chat1", ts.now.Add(time.Hour), ErrNotFound},
+		{"channel member", "bob", "channel1", ts.now.Add(time.Hour), ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ts.service.ScheduleMessage(ctx, models.ScheduleMessageRequest{
+				UserID: tt.userID, ChatID: tt.chatID, Content: "hi", SendAt: tt.sendAt,
+			})
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	// Channel admins may schedule posts.
+	ts.schedule(t, "alice", "channel1", time.Hour)
+}
+
+func TestScheduledMessagesService_ScheduleMessageLimit(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+
+	for range models.MaxScheduledPerUser {
+		ts.schedule(t, "bob", "chat1", time.Hour)
+	}
+
+	_, err := ts.service.ScheduleMessage(context.Background(), models.ScheduleMessageRequest{
+		UserID: "bob", ChatID: "chat1", Content: "hi", SendAt: ts.now.Add(time.Hour),
+	})
+	assert.ErrorIs(t, err, ErrLimitReached)
+}
+
+func TestScheduledMessagesService_ListAndCancel(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+	ctx := context.Background()
+
+	first := ts.schedule(t, "alice", "chat1", time.Hour)
+	second := ts.schedule(t, "alice", "channel1", time.Hour)
+	ts.schedule(t, "bob", "chat1", time.Hour)
+
+	resp, err := ts.service.ListScheduled(ctx, models.ListScheduledRequest{UserID: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), resp.Total)
+
+	// Someone else's message cannot be canceled.
+	err = ts.service.CancelScheduled(ctx, models.CancelScheduledRequest{UserID: "bob", ScheduledID: first.ID})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Neither can one outside the chats of a restricted API key.
+	err = ts.service.CancelScheduled(ctx, models.CancelScheduledRequest{UserID: "alice", ScheduledID: second.ID, ChatIDs: []string{"chat1"}})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, ts.service.CancelScheduled(ctx, models.CancelScheduledRequest{UserID: "alice", ScheduledID: first.ID}))
+	err = ts.service.CancelScheduled(ctx, models.CancelScheduledRequest{UserID: "alice", ScheduledID: first.ID})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	resp, err = ts.service.ListScheduled(ctx, models.ListScheduledRequest{UserID: "alice"})
+	require.NoError(t, err)
+	require.Len(t, resp.Messages, 1)
+	assert.Equal(t, second.ID, resp.Messages[0].ID)
+
+	resp, err = ts.service.ListScheduled(ctx, models.ListScheduledRequest{UserID: "alice", Status: models.ScheduledMessageCanceled})
+	require.NoError(t, err)
+	require.Len(t, resp.Messages, 1)
+	assert.Equal(t, first.ID, resp.Messages[0].ID)
+
+	// Canceled messages are never sent.
+	*ts.now = ts.now.Add(time.Hour)
+	n, err := ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	for _, sent := range ts.messages.sent {
+		assert.NotEqual(t, "scheduled:"+first.ID, sent.IdempotencyKey)
+	}
+	assert.Equal(t, models.ScheduledMessageCanceled, ts.repo.message(first.ID).Status)
+}
+
+func TestScheduledMessagesService_DeliverDue(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+	ctx := context.Background()
+
+	scheduled := ts.schedule(t, "bob", "chat1", time.Hour)
+
+	// Nothing is due yet.
+	n, err := ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	*ts.now = ts.now.Add(time.Hour)
+	n, err = ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.Len(t, ts.messages.sent, 1)
+	assert.Equal(t, models.SendMessageRequest{
+		UserID: "bob", ChatID: "chat1", Content: "good morning", IdempotencyKey: "scheduled:" + scheduled.ID,
+	}, ts.messages.sent[0])
+
+	sent := ts.repo.message(scheduled.ID)
+	assert.Equal(t, models.ScheduledMessageSent, sent.Status)
+	require.NotNil(t, sent.MessageID)
+	assert.Equal(t, "msg1", *sent.MessageID)
+	require.NotNil(t, sent.SentAt)
+	assert.Equal(t, *ts.now, *sent.SentAt)
+
+	// A sent message cannot be canceled anymore.
+	err = ts.service.CancelScheduled(ctx, models.CancelScheduledRequest{UserID: "bob", ScheduledID: scheduled.ID})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	n, err = ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestScheduledMessagesService_DeliverDueTwice(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+	ctx := context.Background()
+
+	scheduled := ts.schedule(t, "bob", "chat1", time.Hour)
+	*ts.now = ts.now.Add(time.Hour)
+
+	// A scheduler whose lease ran out before it recorded the send leaves
+	// the message pending; the next one finds it already sent.
+	msg := ts.repo.message(scheduled.ID)
+	msg.Attempts = 1
+	ts.service.deliver(ctx, msg)
+	ts.repo.messages[0].Status = models.ScheduledMessagePending
+
+	_, err := ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	assert.Len(t, ts.messages.sent, 1)
+	assert.Equal(t, models.ScheduledMessageSent, ts.repo.message(scheduled.ID).Status)
+}
+
+func TestScheduledMessagesService_DeliverDueCatchUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   CatchUpPolicy
+		late     time.Duration
+		want     models.ScheduledMessageStatus
+		wantSent int
+	}{
+		{"send policy sends late", CatchUpSend, 3 * time.Hour, models.ScheduledMessageSent, 1},
+		{"skip policy skips late", CatchUpSkip, 3 * time.Hour, models.ScheduledMessageSkipped, 0},
+		{"skip policy sends within the window", CatchUpSkip, 10 * time.Minute, models.ScheduledMessageSent, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestScheduledService(SchedulerConfig{CatchUp: tt.policy, CatchUpWindow: 15 * time.Minute})
+
+			scheduled := ts.schedule(t, "bob", "chat1", time.Hour)
+			*ts.now = ts.now.Add(time.Hour + tt.late)
+
+			_, err := ts.service.DeliverDue(context.Background())
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, ts.repo.message(scheduled.ID).Status)
+			assert.Len(t, ts.messages.sent, tt.wantSent)
+		})
+	}
+}
+
+func TestScheduledMessagesService_DeliverDueRetries(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{CatchUp: CatchUpSkip, RetryDelay: time.Minute})
+	ctx := context.Background()
+
+	scheduled := ts.schedule(t, "bob", "chat1", time.Hour)
+	*ts.now = ts.now.Add(time.Hour)
+	ts.messages.err = errors.New("connection refused")
+
+	_, err := ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	msg := ts.repo.message(scheduled.ID)
+	assert.Equal(t, models.ScheduledMessagePending, msg.Status)
+	assert.Equal(t, int32(1), msg.Attempts)
+	assert.Equal(t, "connection refused", msg.LastError)
+	assert.Equal(t, ts.now.Add(time.Minute), msg.NextAttemptAt)
+
+	// Retries are not skipped by the catch-up policy, however late.
+	*ts.now = ts.now.Add(time.Hour)
+	ts.messages.err = nil
+	_, err = ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	msg = ts.repo.message(scheduled.ID)
+	assert.Equal(t, models.ScheduledMessageSent, msg.Status)
+	assert.Equal(t, int32(2), msg.Attempts)
+	assert.Empty(t, msg.LastError)
+	assert.Len(t, ts.messages.sent, 1)
+}
+
+func TestScheduledMessagesService_DeliverDueFails(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+	ctx := context.Background()
+
+	// The sender left the chat before the message was due.
+	left := ts.schedule(t, "bob", "chat1", time.Hour)
+	*ts.now = ts.now.Add(time.Hour)
+	ts.messages.err = ErrNotFound
+
+	_, err := ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	msg := ts.repo.message(left.ID)
+	assert.Equal(t, models.ScheduledMessageFailed, msg.Status)
+	assert.Equal(t, int32(1), msg.Attempts)
+
+	// Other errors fail the message after the last attempt.
+	broken := ts.schedule(t, "bob", "chat1", time.Hour)
+	ts.messages.err = errors.New("connection refused")
+	for range 3 {
+		*ts.now = ts.now.Add(time.Hour)
+		_, err := ts.service.DeliverDue(ctx)
+		require.NoError(t, err)
+	}
+
+	msg = ts.repo.message(broken.ID)
+	assert.Equal(t, models.ScheduledMessageFailed, msg.Status)
+	assert.Equal(t, int32(3), msg.Attempts)
+	assert.Equal(t, "connection refused", msg.LastError)
+}
+
+func TestScheduledMessagesService_DeliverDueRetriesFailedSend(t *testing.T) {
+	ts := newTestScheduledService(SchedulerConfig{})
+	ctx := context.Background()
+
+	// Send through the real messages service, whose repository is down for
+	// the first attempt.
+	messagesRepo := &fakeMessagesRepository{err: errors.New("connection refused")}
+	ts.service.messages = &messagesService{
+		messagesRepo:   messagesRepo,
+		chatsRepo:      ts.service.chatsRepo,
+		moderationRepo: newFakeModerationRepository(ts.service.chatsRepo.(*fakeChatsRepository)),
+		idempotency:    newFakeIdempotencyStore(),
+	}
+
+	scheduled := ts.schedule(t, "bob", "chat1", time.Hour)
+	*ts.now = ts.now.Add(time.Hour)
+	_, err := ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	msg := ts.repo.message(scheduled.ID)
+	assert.Equal(t, models.ScheduledMessagePending, msg.Status)
+	assert.Equal(t, "connection refused", msg.LastError)
+
+	// The retry is sent, not mistaken for a duplicate of the failed attempt.
+	messagesRepo.err = nil
+	*ts.now = ts.now.Add(time.Hour)
+	_, err = ts.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	msg = ts.repo.message(scheduled.ID)
+	assert.Equal(t, models.ScheduledMessageSent, msg.Status)
+	require.Len(t, messagesRepo.sent, 1)
+	require.NotNil(t, msg.MessageID)
+	assert.Equal(t, messagesRepo.sent[0].ID, *msg.MessageID)
+}
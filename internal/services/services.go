@@ -1,33 +1,194 @@
 package services
 
 import (
+	"time"
+
 	"github.com/brenocoelho/messaging-app-go/internal/repositories"
+	"github.com/brenocoelho/messaging-app-go/pkg/encryption"
 	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
+	"github.com/brenocoelho/messaging-app-go/pkg/webhook"
 	"github.com/redis/go-redis/v9"
 )
 
 type Services struct {
-	Users    UsersService
-	Messages MessagesService
-	Chats    ChatsService
-	JWT      jwt.Service
-	Realtime RealtimeService
+	Users       UsersService
+	Messages    MessagesService
+	Chats       ChatsService
+	Moderation  ModerationService
+	Bots        BotsService
+	Webhooks    WebhooksService
+	Incoming    IncomingWebhooksService
+	Commands    CommandsService
+	Scheduled   ScheduledMessagesService
+	JWT         jwt.Service
+	Revocations redisconn.RevocationStore
+	Realtime    RealtimeService
 }
 
-func NewServices(repos *repositories.Repositories, cacheClient *redis.Client, ttlMinutes int) *Services {
-	jwtService := jwt.NewService()
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
 
-	realtimeService := NewRealtimeService(nil)
+	// JWT signing keys and registered claims, see jwt.Config.
+	KeyDir             string
+	SigningKeyID       string
+	KeyRotationOverlap time.Duration
+	Issuer             string
+	Audience           string
+	Leeway             time.Duration
 
-	usersService := NewUsersService(repos.Users, jwtService)
-	messagesService := NewMessagesService(repos.Messages, cacheClient, ttlMinutes, realtimeService)
-	chatsService := NewChatsService(repos.Chats, repos.Users)
+	// PasswordResetTTL is how long a reset link works. PasswordResetURL is
+	// the page the link opens; the token is appended as ?token=. Reset
+	// emails can be requested PasswordResetEmailLimit times per email and
+	// PasswordResetIPLimit times per client IP every PasswordResetWindow.
+	PasswordResetTTL        time.Duration
+	PasswordResetURL        string
+	PasswordResetEmailLimit int
+	PasswordResetIPLimit    int
+	PasswordResetWindow     time.Duration
 
-	return &Services{
-		Users:    usersService,
-		Messages: messagesService,
-		Chats:    chatsService,
-		JWT:      jwtService,
-		Realtime: realtimeService,
+	// EmailVerificationTTL and EmailVerificationURL work the same way for
+	// verification links. VerificationResendLimit emails can be requested
+	// per VerificationResendWindow.
+	EmailVerificationTTL     time.Duration
+	EmailVerificationURL     string
+	VerificationResendLimit  int
+	VerificationResendWindow time.Duration
+
+	// EmailVerification lists what unverified accounts may not do.
+	EmailVerification EmailVerificationPolicy
+
+	// TOTPEncryptionKey encrypts TOTP secrets at rest; without it two-factor
+	// enrollment is unavailable. TOTPIssuer names the account in
+	// authenticator apps. LoginChallengeTTL is how long a login may wait for
+	// its second factor.
+	TOTPEncryptionKey []byte
+	TOTPIssuer        string
+	LoginChallengeTTL time.Duration
+
+	// OIDC configures login with an external OpenID Connect issuer; it is
+	// disabled without an issuer.
+	OIDC oidc.Config
+
+	// LoginEmailThrottle and LoginIPThrottle slow down failed logins per
+	// email and per client IP. The IP policy should be more lenient, since
+	// many users can share an address.
+	LoginEmailThrottle redisconn.ThrottlePolicy
+	LoginIPThrottle    redisconn.ThrottlePolicy
+	// SecondFactorThrottle slows down wrong two-factor codes per user,
+	// across login challenges.
+	SecondFactorThrottle redisconn.ThrottlePolicy
+
+	// Clock is used for TOTP codes and login challenges. Defaults to
+	// time.Now.
+	Clock func() time.Time
+}
+
+const (
+	DefaultRefreshTokenTTL          = 30 * 24 * time.Hour
+	DefaultPasswordResetTTL         = time.Hour
+	DefaultPasswordResetEmailLimit  = 3
+	DefaultPasswordResetIPLimit     = 20
+	DefaultPasswordResetWindow      = time.Hour
+	DefaultEmailVerificationTTL     = 24 * time.Hour
+	DefaultVerificationResendLimit  = 3
+	DefaultVerificationResendWindow = time.Hour
+	DefaultTOTPIssuer               = "Messaging App"
+	DefaultLoginChallengeTTL        = 5 * time.Minute
+)
+
+var (
+	DefaultLoginEmailThrottle = redisconn.ThrottlePolicy{
+		Window:          15 * time.Minute,
+		BackoffAfter:    3,
+		BackoffBase:     time.Second,
+		BackoffMax:      30 * time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+	}
+	DefaultLoginIPThrottle = redisconn.ThrottlePolicy{
+		Window:          15 * time.Minute,
+		BackoffAfter:    20,
+		BackoffBase:     time.Second,
+		BackoffMax:      30 * time.Second,
+		LockoutAfter:    100,
+		LockoutDuration: 15 * time.Minute,
+	}
+	DefaultSecondFactorThrottle = redisconn.ThrottlePolicy{
+		Window:          time.Hour,
+		BackoffAfter:    3,
+		BackoffBase:     time.Second,
+		BackoffMax:      30 * time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: time.Hour,
 	}
+)
+
+func NewServices(repos *repositories.Repositories, cacheClient *redis.Client, ttlMinutes int, authCfg AuthConfig, webhookCfg WebhookConfig, schedulerCfg SchedulerConfig, mailer mailer.Mailer) (*Services, error) {
+	jwtService, err := jwt.NewServiceWithConfig(jwt.Config{
+		AccessTokenTTL:  authCfg.AccessTokenTTL,
+		KeyDir:          authCfg.KeyDir,
+		SigningKeyID:    authCfg.SigningKeyID,
+		RotationOverlap: authCfg.KeyRotationOverlap,
+		Issuer:          authCfg.Issuer,
+		Audience:        authCfg.Audience,
+		Leeway:          authCfg.Leeway,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var totpCipher encryption.Cipher
+	if len(authCfg.TOTPEncryptionKey) > 0 {
+		totpCipher, err = encryption.NewCipher(authCfg.TOTPEncryptionKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var identities oidc.Provider
+	if authCfg.OIDC.Issuer != "" {
+		identities, err = oidc.NewProvider(authCfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	revocationStore := redisconn.NewRevocationStore(cacheClient)
+	rateLimiter := redisconn.NewRateLimiter(cacheClient)
+	loginThrottle := redisconn.NewLoginThrottle(cacheClient)
+	realtimeService := NewRealtimeService(nil, repos.Moderation)
+	webhooksService := NewWebhooksService(repos.Webhooks, repos.Users, repos.Chats, webhookCfg)
+
+	var commandSender webhook.Sender
+	if webhookCfg.CommandTimeout > 0 {
+		commandSender = webhook.NewSender(webhook.NewClient(webhookCfg.CommandTimeout), webhookCfg.Clock)
+	}
+	commandsService := NewCommandsService(repos.Commands, repos.Users, repos.Chats, commandSender)
+
+	usersService := NewUsersService(repos.Users, repos.Moderation, repos.Tokens, repos.TwoFactor, repos.Audit, jwtService, revocationStore, rateLimiter, loginThrottle, realtimeService, mailer, totpCipher, identities, authCfg)
+	messagesService := NewMessagesService(repos.Messages, repos.Chats, repos.Moderation, cacheClient, ttlMinutes, realtimeService, webhooksService, commandsService)
+	chatsService := NewChatsService(repos.Chats, repos.Users, repos.Messages, repos.Moderation, realtimeService, authCfg.EmailVerification, webhooksService)
+	moderationService := NewModerationService(repos.Moderation, repos.Users, repos.Chats, repos.Messages)
+	botsService := NewBotsService(repos.Users, repos.APIKeys, repos.Chats, webhooksService)
+	incomingService := NewIncomingWebhooksService(repos.Webhooks, repos.Chats, messagesService, webhookCfg.IncomingBaseURL)
+	scheduledService := NewScheduledMessagesService(repos.Scheduled, repos.Chats, messagesService, schedulerCfg)
+
+	return &Services{
+		Users:       usersService,
+		Messages:    messagesService,
+		Chats:       chatsService,
+		Moderation:  moderationService,
+		Bots:        botsService,
+		Webhooks:    webhooksService,
+		Incoming:    incomingService,
+		Commands:    commandsService,
+		Scheduled:   scheduledService,
+		JWT:         jwtService,
+		Revocations: revocationStore,
+		Realtime:    realtimeService,
+	}, nil
 }
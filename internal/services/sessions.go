@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/tokens"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+)
+
+// RefreshSession trades a refresh token for a new access token and a new
+// refresh token. The presented token is used up; presenting it again revokes
+// every token issued from the same login.
+func (s *usersService) RefreshSession(ctx context.Context, refreshToken string, device models.DeviceInfo) (models.RefreshSessionResponse, error) {
+	slog.Info("RefreshSession service")
+
+	nextToken, nextHash, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating refresh token", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	used, err := s.tokensRepo.RotateRefreshToken(ctx, hashToken(refreshToken), models.RefreshToken{
+		TokenHash: nextHash,
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, tokens.ErrRefreshTokenReused):
+			slog.Warn("Refresh token reuse detected", "userID", used.UserID, "familyID", used.FamilyID)
+			return models.RefreshSessionResponse{}, ErrInvalidRefreshToken
+		case errors.Is(err, tokens.ErrRefreshTokenExpired), errors.Is(err, tokens.ErrRefreshTokenRevoked):
+			slog.Info("Refresh token rejected", "error", err, "userID", used.UserID)
+			return models.RefreshSessionResponse{}, ErrInvalidRefreshToken
+		}
+		slog.Error("Error rotating refresh token", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	if used.ID == "" {
+		return models.RefreshSessionResponse{}, ErrInvalidRefreshToken
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, used.UserID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	if user.ID == "" {
+		return models.RefreshSessionResponse{}, ErrInvalidRefreshToken
+	}
+
+	// The refresh token family is the session.
+	if err := s.tokensRepo.TouchSession(ctx, used.FamilyID, device); err != nil {
+		slog.Error("Error touching session", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	token, expiresAt, err := s.generateAccessToken(ctx, user, used.FamilyID)
+	if err != nil {
+		return models.RefreshSessionResponse{}, err
+	}
+
+	return models.RefreshSessionResponse{
+		Token:          token,
+		TokenExpiresAt: expiresAt,
+		RefreshToken:   nextToken,
+		UserID:         user.ID,
+	}, nil
+}
+
+// issueSession records a session for a fresh login and starts its refresh
+// token family.
+func (s *usersService) issueSession(ctx context.Context, user models.User, device models.DeviceInfo) (models.RefreshSessionResponse, error) {
+	session, err := s.tokensRepo.CreateSession(ctx, models.Session{
+		UserID:     user.ID,
+		DeviceName: device.DeviceName,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+	})
+	if err != nil {
+		slog.Error("Error creating session", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	token, expiresAt, err := s.generateAccessToken(ctx, user, session.ID)
+	if err != nil {
+		return models.RefreshSessionResponse{}, err
+	}
+
+	refreshToken, refreshHash, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating refresh token", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	_, err = s.tokensRepo.CreateRefreshToken(ctx, models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  session.ID,
+		TokenHash: refreshHash,
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		slog.Error("Error storing refresh token", "error", err)
+		return models.RefreshSessionResponse{}, err
+	}
+
+	return models.RefreshSessionResponse{
+		Token:          token,
+		TokenExpiresAt: expiresAt,
+		RefreshToken:   refreshToken,
+		UserID:         user.ID,
+	}, nil
+}
+
+// Logout revokes the access token used for the call and the session it
+// belongs to. With AllSessions it bumps the user's token generation, which
+// invalidates every access token issued so far, and revokes all of the
+// user's sessions.
+func (s *usersService) Logout(ctx context.Context, req models.LogoutRequest) error {
+	slog.Info("Logout service", "userID", req.UserID, "sessionID", req.SessionID, "allSessions", req.AllSessions)
+
+	if err := s.revocations.RevokeToken(ctx, req.TokenID, time.Until(req.TokenExpiresAt)); err != nil {
+		return err
+	}
+
+	if req.AllSessions {
+		if _, err := s.revocations.IncrementGeneration(ctx, req.UserID); err != nil {
+			return err
+		}
+		return s.tokensRepo.RevokeAllForUser(ctx, req.UserID)
+	}
+
+	if req.SessionID != "" {
+		err := s.RevokeSession(ctx, req.UserID, req.SessionID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+	}
+
+	if req.RefreshToken == "" {
+		return nil
+	}
+
+	return s.tokensRepo.RevokeFamilyByTokenHash(ctx, req.UserID, hashToken(req.RefreshToken))
+}
+
+// ListSessions returns the user's sessions that can still be refreshed.
+func (s *usersService) ListSessions(ctx context.Context, userID string) ([]models.Session, error) {
+	slog.Info("ListSessions service", "userID", userID)
+
+	return s.tokensRepo.ListSessions(ctx, userID, time.Now().Add(-s.refreshTokenTTL))
+}
+
+// RevokeSession signs one of the user's devices out: its refresh tokens stop
+// working, its access tokens are rejected and its open streams are closed.
+func (s *usersService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	slog.Info("RevokeSession service", "userID", userID, "sessionID", sessionID)
+
+	revoked, err := s.tokensRepo.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		slog.Error("Error revoking session", "error", err)
+		return err
+	}
+
+	if !revoked {
+		return ErrNotFound
+	}
+
+	if err := s.revocations.RevokeSession(ctx, sessionID, s.jwtService.AccessTokenTTL()); err != nil {
+		return err
+	}
+
+	s.realtime.CloseSession(sessionID)
+	return nil
+}
+
+func (s *usersService) generateAccessToken(ctx context.Context, user models.User, sessionID string) (string, time.Time, error) {
+	generation, err := s.revocations.GetGeneration(ctx, user.ID)
+	if err != nil {
+		slog.Error("Error getting token generation", "error", err)
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(s.jwtService.AccessTokenTTL())
+
+	token, err := s.jwtService.GenerateToken(jwt.User{
+		ID:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		Generation: generation,
+		SessionID:  sessionID,
+		Admin:      user.IsAdmin,
+	})
+	if err != nil {
+		slog.Error("Error generating JWT token", "error", err)
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// newOpaqueToken returns a random token for the client and the hash that is
+// stored in its place.
+func newOpaqueToken() (string, string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(randomBytes)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTestSessionsService(t *testing.T) (*usersService, *fakeTokensRepository) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	usersRepo := newFakeUsersRepository(models.User{
+		ID:           "alice",
+		Username:     "alice",
+		Email:        "alice@example.com",
+		PasswordHash: string(hash),
+	})
+	tokensRepo := newFakeTokensRepository()
+	tokensRepo.users = usersRepo
+	jwtService := newTestJWTService(jwt.Config{AccessTokenTTL: 5 * time.Minute})
+
+	service := NewUsersService(usersRepo, newFakeModerationRepository(newFakeChatsRepository()), tokensRepo, newFakeTwoFactorRepository(), newFakeAuditRepository(), jwtService, newFakeRevocationStore(), newFakeRateLimiter(), newFakeLoginThrottle(), NewRealtimeService(nil, nil), mailer.NewMemoryMailer(), nil, nil, AuthConfig{RefreshTokenTTL: time.Hour}).(*usersService)
+	return service, tokensRepo
+}
+
+func TestUsersService_Login_ReturnsTokenPair(t *testing.T) {
+	service, tokensRepo := newTestSessionsService(t)
+
+	resp, err := service.Login(context.Background(), models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), resp.TokenExpiresAt, 2*time.Second)
+
+	// Only the hash of the refresh token is stored.
+	require.Len(t, tokensRepo.refreshTokens, 1)
+	stored, ok := tokensRepo.refreshTokens[hashToken(resp.RefreshToken)]
+	require.True(t, ok)
+	assert.Equal(t, "alice", stored.UserID)
+	assert.NotContains(t, tokensRepo.refreshTokens, resp.RefreshToken)
+}
+
+func TestUsersService_RefreshSession_Rotates(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	login, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	refreshed, err := service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", refreshed.UserID)
+	assert.NotEmpty(t, refreshed.Token)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	claims, err := service.jwtService.ValidateToken(refreshed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.UserID)
+
+	_, err = service.RefreshSession(ctx, refreshed.RefreshToken, models.DeviceInfo{})
+	require.NoError(t, err)
+}
+
+func TestUsersService_RefreshSession_ReuseRevokesFamily(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	login, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	other, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	refreshed, err := service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{})
+	require.NoError(t, err)
+
+	// Replaying the used token is treated as theft: it fails and the token
+	// issued from it stops working too.
+	_, err = service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = service.RefreshSession(ctx, refreshed.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	// Other logins are separate families and keep working.
+	_, err = service.RefreshSession(ctx, other.RefreshToken, models.DeviceInfo{})
+	assert.NoError(t, err)
+}
+
+func TestUsersService_RefreshSession_Invalid(t *testing.T) {
+	service, tokensRepo := newTestSessionsService(t)
+	ctx := context.Background()
+
+	_, err := service.RefreshSession(ctx, "unknown", models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	login, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	tokensRepo.refreshTokens[hashToken(login.RefreshToken)].ExpiresAt = time.Now().Add(-time.Minute)
+
+	_, err = service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestUsersService_Logout_RevokesTokenAndRefreshFamily(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	store := service.revocations.(*fakeRevocationStore)
+	ctx := context.Background()
+
+	login, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	other, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	claims, err := service.jwtService.ValidateToken(login.Token)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.Jti)
+
+	err = service.Logout(ctx, models.LogoutRequest{
+		UserID:         "alice",
+		TokenID:        claims.Jti,
+		TokenExpiresAt: time.Unix(claims.Exp, 0),
+		RefreshToken:   login.RefreshToken,
+	})
+	require.NoError(t, err)
+
+	// The access token is kept only for what is left of its lifetime.
+	ttl, ok := store.revoked[claims.Jti]
+	require.True(t, ok)
+	assert.LessOrEqual(t, ttl, 5*time.Minute)
+
+	_, err = service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = service.RefreshSession(ctx, other.RefreshToken, models.DeviceInfo{})
+	assert.NoError(t, err)
+}
+
+func TestUsersService_Logout_AllSessions(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	store := service.revocations.(*fakeRevocationStore)
+	ctx := context.Background()
+
+	login, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	other, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	claims, err := service.jwtService.ValidateToken(login.Token)
+	require.NoError(t, err)
+	otherClaims, err := service.jwtService.ValidateToken(other.Token)
+	require.NoError(t, err)
+
+	err = service.Logout(ctx, models.LogoutRequest{
+		UserID:         "alice",
+		TokenID:        claims.Jti,
+		TokenExpiresAt: time.Unix(claims.Exp, 0),
+		AllSessions:    true,
+	})
+	require.NoError(t, err)
+
+	// Access tokens of other devices fall behind the new generation.
+	revoked, err := store.IsRevoked(ctx, otherClaims.Jti, otherClaims.Sid, "alice", otherClaims.Gen)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	_, err = service.RefreshSession(ctx, other.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	// A fresh login carries the new generation.
+	relogin, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	reloginClaims, err := service.jwtService.ValidateToken(relogin.Token)
+	require.NoError(t, err)
+	revoked, err = store.IsRevoked(ctx, reloginClaims.Jti, reloginClaims.Sid, "alice", reloginClaims.Gen)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestUsersService_Login_RecordsSession(t *testing.T) {
+	service, tokensRepo := newTestSessionsService(t)
+	ctx := context.Background()
+
+	login, err := service.Login(ctx, models.LoginRequest{
+		Email:    "alice@example.com",
+		Password: "password123",
+		Device:   models.DeviceInfo{DeviceName: "Alice's phone", UserAgent: "grpc-go/1.75", IPAddress: "203.0.113.7"},
+	})
+	require.NoError(t, err)
+
+	claims, err := service.jwtService.ValidateToken(login.Token)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.Sid)
+
+	// The session ID is the refresh token family.
+	assert.Equal(t, claims.Sid, tokensRepo.refreshTokens[hashToken(login.RefreshToken)].FamilyID)
+
+	refreshed, err := service.RefreshSession(ctx, login.RefreshToken, models.DeviceInfo{UserAgent: "grpc-go/1.76", IPAddress: "198.51.100.2"})
+	require.NoError(t, err)
+	refreshedClaims, err := service.jwtService.ValidateToken(refreshed.Token)
+	require.NoError(t, err)
+	assert.Equal(t, claims.Sid, refreshedClaims.Sid)
+
+	sessions, err := service.ListSessions(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "Alice's phone", sessions[0].DeviceName)
+	assert.Equal(t, "grpc-go/1.76", sessions[0].UserAgent)
+	assert.Equal(t, "198.51.100.2", sessions[0].IPAddress)
+}
+
+func TestUsersService_RevokeSession(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	store := service.revocations.(*fakeRevocationStore)
+	ctx := context.Background()
+
+	phone, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	laptop, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	phoneClaims, err := service.jwtService.ValidateToken(phone.Token)
+	require.NoError(t, err)
+
+	streamCtx, release := service.realtime.TrackSession(ctx, phoneClaims.Sid)
+	defer release()
+
+	assert.ErrorIs(t, service.RevokeSession(ctx, "bob", phoneClaims.Sid), ErrNotFound)
+
+	require.NoError(t, service.RevokeSession(ctx, "alice", phoneClaims.Sid))
+
+	revoked, err := store.IsRevoked(ctx, phoneClaims.Jti, phoneClaims.Sid, "alice", phoneClaims.Gen)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.ErrorIs(t, context.Cause(streamCtx), ErrSessionRevoked)
+
+	_, err = service.RefreshSession(ctx, phone.RefreshToken, models.DeviceInfo{})
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = service.RefreshSession(ctx, laptop.RefreshToken, models.DeviceInfo{})
+	assert.NoError(t, err)
+
+	sessions, err := service.ListSessions(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	assert.ErrorIs(t, service.RevokeSession(ctx, "alice", phoneClaims.Sid), ErrNotFound)
+}
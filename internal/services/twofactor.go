@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"log/slog"
+	"strings"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/totp"
+)
+
+// totpSkew is how many 30 second steps a code may be early or late.
+const totpSkew = 1
+
+// EnrollTOTP starts two-factor enrollment with a new secret and recovery
+// codes. Nothing changes for logins until ConfirmTOTP; enrolling again before
+// that replaces the secret and the codes.
+func (s *usersService) EnrollTOTP(ctx context.Context, userID string) (models.EnrollTOTPResponse, error) {
+	slog.Info("EnrollTOTP service", "userID", userID)
+
+	if s.totpCipher == nil {
+		return models.EnrollTOTPResponse{}, ErrTwoFactorUnavailable
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return models.EnrollTOTPResponse{}, err
+	}
+
+	if user.ID == "" {
+		return models.EnrollTOTPResponse{}, ErrNotFound
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		slog.Error("Error generating TOTP secret", "error", err)
+		return models.EnrollTOTPResponse{}, err
+	}
+
+	ciphertext, err := s.totpCipher.Encrypt(secret)
+	if err != nil {
+		slog.Error("Error encrypting TOTP secret", "error", err)
+		return models.EnrollTOTPResponse{}, err
+	}
+
+	recoveryCodes := make([]string, models.RecoveryCodeCount)
+	recoveryCodeHashes := make([]string, models.RecoveryCodeCount)
+	for i := range recoveryCodes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			slog.Error("Error generating recovery code", "error", err)
+			return models.EnrollTOTPResponse{}, err
+		}
+		recoveryCodes[i] = code
+		recoveryCodeHashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	saved, err := s.twoFactorRepo.SaveTOTPEnrollment(ctx, models.TOTP{
+		UserID:           user.ID,
+		SecretCiphertext: ciphertext,
+	}, recoveryCodeHashes)
+	if err != nil {
+		return models.EnrollTOTPResponse{}, err
+	}
+
+	if !saved {
+		return models.EnrollTOTPResponse{}, ErrTOTPAlreadyEnabled
+	}
+
+	return models.EnrollTOTPResponse{
+		Secret:        totp.EncodeSecret(secret),
+		URI:           totp.URI(s.totpIssuer, user.Email, secret),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// ConfirmTOTP turns two-factor authentication on once the user proves their
+// authenticator app produces valid codes.
+func (s *usersService) ConfirmTOTP(ctx context.Context, userID, code string) error {
+	slog.Info("ConfirmTOTP service", "userID", userID)
+
+	state, err := s.twoFactorRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		slog.Error("Error getting TOTP", "error", err)
+		return err
+	}
+
+	if state.UserID == "" {
+		return ErrTOTPNotEnrolled
+	}
+
+	if state.ConfirmedAt != nil {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	step, ok, err := s.validateTOTP(state, code)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrInvalidTOTPCode
+	}
+
+	confirmed, err := s.twoFactorRepo.ConfirmTOTP(ctx, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	slog.Info("Two-factor authentication enabled", "userID", userID)
+	return nil
+}
+
+// startLoginChallenge is the first half of a login with two-factor
+// authentication: the password was right, and the session is issued by
+// VerifyLoginChallenge.
+func (s *usersService) startLoginChallenge(ctx context.Context, user models.User, device models.DeviceInfo) (models.LoginResponse, error) {
+	token, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating login challenge", "error", err)
+		return models.LoginResponse{}, err
+	}
+
+	challenge, err := s.twoFactorRepo.CreateLoginChallenge(ctx, models.LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		Device:    device,
+		ExpiresAt: s.clock().Add(s.loginChallengeTTL),
+	})
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{
+		UserID:             user.ID,
+		ChallengeToken:     token,
+		ChallengeExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+// VerifyLoginChallenge completes a login started by Login with a TOTP code
+// or a recovery code. A challenge allows a few attempts and completes one
+// login; wrong codes also count against the user across challenges.
+func (s *usersService) VerifyLoginChallenge(ctx context.Context, req models.VerifyLoginChallengeRequest) (models.LoginResponse, error) {
+	slog.Info("VerifyLoginChallenge service")
+
+	challenge, err := s.twoFactorRepo.GetLoginChallenge(ctx, hashToken(req.ChallengeToken))
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	if challenge.ID == "" || !s.clock().Before(challenge.ExpiresAt) {
+		return models.LoginResponse{}, ErrInvalidLoginChallenge
+	}
+
+	throttle := []loginThrottleScope{s.secondFactorThrottleScope(challenge.UserID)}
+	if err := s.checkThrottle(ctx, throttle); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	// The attempt is counted before the code is checked, so concurrent
+	// requests cannot try more codes than the challenge allows.
+	counted, err := s.twoFactorRepo.RecordChallengeAttempt(ctx, challenge.ID, models.MaxLoginChallengeTries)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	if !counted {
+		return models.LoginResponse{}, ErrInvalidLoginChallenge
+	}
+
+	ok, err := s.checkSecondFactor(ctx, challenge.UserID, req.Code)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	if !ok {
+		slog.Warn("Wrong two-factor code", "userID", challenge.UserID)
+		s.recordThrottleFailure(ctx, throttle, "", challenge.Device.IPAddress, challenge.UserID)
+		return models.LoginResponse{}, ErrInvalidTOTPCode
+	}
+
+	consumed, err := s.twoFactorRepo.ConsumeLoginChallenge(ctx, challenge.ID)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	if !consumed {
+		return models.LoginResponse{}, ErrInvalidLoginChallenge
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return models.LoginResponse{}, err
+	}
+
+	if user.ID == "" {
+		return models.LoginResponse{}, ErrInvalidLoginChallenge
+	}
+
+	s.resetLoginThrottle(ctx, user)
+
+	session, err := s.issueSession(ctx, user, challenge.Device)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return models.LoginResponse{
+		Token:          session.Token,
+		TokenExpiresAt: session.TokenExpiresAt,
+		RefreshToken:   session.RefreshToken,
+		UserID:         user.ID,
+	}, nil
+}
+
+// checkSecondFactor accepts a current TOTP code that was not used before, or
+// an unused recovery code.
+func (s *usersService) checkSecondFactor(ctx context.Context, userID, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) != totp.Digits {
+		return s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+	}
+
+	state, err := s.twoFactorRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		slog.Error("Error getting TOTP", "error", err)
+		return false, err
+	}
+
+	if state.ConfirmedAt == nil {
+		return false, nil
+	}
+
+	step, ok, err := s.validateTOTP(state, code)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return s.twoFactorRepo.UseTOTPStep(ctx, userID, step)
+}
+
+func (s *usersService) validateTOTP(state models.TOTP, code string) (int64, bool, error) {
+	if s.totpCipher == nil {
+		return 0, false, ErrTwoFactorUnavailable
+	}
+
+	secret, err := s.totpCipher.Decrypt(state.SecretCiphertext)
+	if err != nil {
+		slog.Error("Error decrypting TOTP secret", "userID", state.UserID, "error", err)
+		return 0, false, err
+	}
+
+	step, ok := totp.Validate(secret, code, s.clock(), totpSkew)
+	return step, ok, nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCode returns 80 random bits as xxxx-xxxx-xxxx-xxxx.
+func newRecoveryCode() (string, error) {
+	randomBytes := make([]byte, 10)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(randomBytes))
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base32"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/twofactor"
+	"github.com/brenocoelho/messaging-app-go/pkg/encryption"
+	"github.com/brenocoelho/messaging-app-go/pkg/totp"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTwoFactorService returns a service whose clock only moves when the
+// test advances it.
+func newTestTwoFactorService(t *testing.T) (*usersService, *fakeTwoFactorRepository, func(time.Duration)) {
+	service, _ := newTestSessionsService(t)
+
+	cipher, err := encryption.NewCipher(bytes.Repeat([]byte{7}, encryption.KeySize))
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	service.totpCipher = cipher
+	service.clock = func() time.Time { return now }
+
+	return service, service.twoFactorRepo.(*fakeTwoFactorRepository), func(d time.Duration) { now = now.Add(d) }
+}
+
+// enableTOTP enrolls and confirms alice and returns her secret and recovery
+// codes.
+func enableTOTP(t *testing.T, service *usersService) ([]byte, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	enrollment, err := service.EnrollTOTP(ctx, "alice")
+	require.NoError(t, err)
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+
+	require.NoError(t, service.ConfirmTOTP(ctx, "alice", currentCode(service, secret)))
+	return secret, enrollment.RecoveryCodes
+}
+
+func currentCode(service *usersService, secret []byte) string {
+	return totp.Code(secret, totp.Step(service.clock()))
+}
+
+// loginChallenge logs alice in and returns the challenge token.
+func loginChallenge(t *testing.T, service *usersService) string {
+	t.Helper()
+
+	resp, err := service.Login(context.Background(), models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.ChallengeToken)
+	assert.Empty(t, resp.Token)
+	assert.Empty(t, resp.RefreshToken)
+	return resp.ChallengeToken
+}
+
+func TestUsersService_EnrollTOTP(t *testing.T) {
+	service, twoFactorRepo, _ := newTestTwoFactorService(t)
+	ctx := context.Background()
+
+	enrollment, err := service.EnrollTOTP(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/Messaging%20App:alice@example.com?"))
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	assert.Len(t, enrollment.RecoveryCodes, models.RecoveryCodeCount)
+
+	// The secret is stored encrypted.
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(twoFactorRepo.totp["alice"].SecretCiphertext, secret))
+
+	// Until it is confirmed, logins do not ask for a code.
+	resp, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "password123"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+	assert.Empty(t, resp.ChallengeToken)
+
+	assert.ErrorIs(t, service.ConfirmTOTP(ctx, "alice", "000000"), ErrInvalidTOTPCode)
+	require.NoError(t, service.ConfirmTOTP(ctx, "alice", currentCode(service, secret)))
+
+	_, err = service.EnrollTOTP(ctx, "alice")
+	assert.ErrorIs(t, err, ErrTOTPAlreadyEnabled)
+}
+
+func TestUsersService_EnrollTOTP_WithoutEncryptionKey(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+
+	_, err := service.EnrollTOTP(context.Background(), "alice")
+	assert.ErrorIs(t, err, ErrTwoFactorUnavailable)
+}
+
+func TestUsersService_VerifyLoginChallenge(t *testing.T) {
+	service, _, advance := newTestTwoFactorService(t)
+	ctx := context.Background()
+	secret, _ := enableTOTP(t, service)
+
+	// The code used for confirmation cannot be used again.
+	challenge := loginChallenge(t, service)
+	_, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: currentCode(service, secret)})
+	assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+
+	advance(totp.Period)
+	resp, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: currentCode(service, secret)})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", resp.UserID)
+	assert.NotEmpty(t, resp.Token)
+	assert.NotEmpty(t, resp.RefreshToken)
+
+	// A challenge completes one login.
+	_, err = service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: currentCode(service, secret)})
+	assert.ErrorIs(t, err, ErrInvalidLoginChallenge)
+}
+
+func TestUsersService_VerifyLoginChallenge_RecoveryCode(t *testing.T) {
+	service, _, _ := newTestTwoFactorService(t)
+	ctx := context.Background()
+	_, recoveryCodes := enableTOTP(t, service)
+
+	resp, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{
+		ChallengeToken: loginChallenge(t, service),
+		Code:           strings.ToUpper(recoveryCodes[0]),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Token)
+
+	// Recovery codes work once.
+	_, err = service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{
+		ChallengeToken: loginChallenge(t, service),
+		Code:           recoveryCodes[0],
+	})
+	assert.ErrorIs(t, err, ErrInvalidTOTPCode)
+}
+
+func TestUsersService_VerifyLoginChallenge_Expired(t *testing.T) {
+	service, _, advance := newTestTwoFactorService(t)
+	secret, _ := enableTOTP(t, service)
+
+	challenge := loginChallenge(t, service)
+	advance(DefaultLoginChallengeTTL)
+
+	_, err := service.VerifyLoginChallenge(context.Background(), models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: currentCode(service, secret)})
+	assert.ErrorIs(t, err, ErrInvalidLoginChallenge)
+}
+
+func TestUsersService_VerifyLoginChallenge_TooManyAttempts(t *testing.T) {
+	service, _, advance := newTestTwoFactorService(t)
+	ctx := context.Background()
+	secret, _ := enableTOTP(t, service)
+	advance(totp.Period)
+
+	throttle := service.loginThrottle.(*fakeLoginThrottle)
+
+	challenge := loginChallenge(t, service)
+	for range models.MaxLoginChallengeTries {
+		_, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: "000000"})
+		require.ErrorIs(t, err, ErrInvalidTOTPCode)
+		throttle.now = throttle.now.Add(time.Minute)
+	}
+
+	_, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: currentCode(service, secret)})
+	assert.ErrorIs(t, err, ErrInvalidLoginChallenge)
+}
+
+func TestUsersService_VerifyLoginChallenge_ThrottlesAcrossChallenges(t *testing.T) {
+	service, _, advance := newTestTwoFactorService(t)
+	ctx := context.Background()
+	secret, _ := enableTOTP(t, service)
+	advance(totp.Period)
+	throttle := service.loginThrottle.(*fakeLoginThrottle)
+	auditRepo := service.auditRepo.(*fakeAuditRepository)
+
+	// Logging in again for a new challenge does not give more guesses.
+	for range DefaultSecondFactorThrottle.LockoutAfter {
+		_, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: loginChallenge(t, service), Code: "000000"})
+		require.ErrorIs(t, err, ErrInvalidTOTPCode)
+		throttle.now = throttle.now.Add(time.Minute)
+	}
+
+	require.Len(t, auditRepo.events, 1)
+	assert.Equal(t, "second_factor", auditRepo.events[0].Details["scope"])
+	assert.Equal(t, "alice", auditRepo.events[0].UserID)
+
+	_, err := service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: loginChallenge(t, service), Code: currentCode(service, secret)})
+	var retryErr *RetryAfterError
+	require.ErrorAs(t, err, &retryErr)
+
+	throttle.now = throttle.now.Add(DefaultSecondFactorThrottle.LockoutDuration)
+	_, err = service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: loginChallenge(t, service), Code: currentCode(service, secret)})
+	require.NoError(t, err)
+}
+
+func TestUsersService_VerifyLoginChallenge_ResetsPasswordThrottle(t *testing.T) {
+	service, _, advance := newTestTwoFactorService(t)
+	ctx := context.Background()
+	secret, _ := enableTOTP(t, service)
+	advance(totp.Period)
+	throttle := service.loginThrottle.(*fakeLoginThrottle)
+
+	_, err := service.Login(ctx, models.LoginRequest{Email: "alice@example.com", Password: "wrong"})
+	require.Error(t, err)
+
+	// The right password alone does not complete the login.
+	challenge := loginChallenge(t, service)
+	assert.Len(t, throttle.failures["email:alice@example.com"], 1)
+
+	_, err = service.VerifyLoginChallenge(ctx, models.VerifyLoginChallengeRequest{ChallengeToken: challenge, Code: currentCode(service, secret)})
+	require.NoError(t, err)
+	assert.Empty(t, throttle.failures["email:alice@example.com"])
+}
+
+func TestTwoFactorRepository_RecordChallengeAttempt_Concurrent(t *testing.T) {
+	pool := newTestPool(t)
+	repo := twofactor.NewTwoFactorRepository(pool, pool)
+	ctx := context.Background()
+
+	_, tokenHash, err := newOpaqueToken()
+	require.NoError(t, err)
+
+	args := pgx.NamedArgs{
+		"user_id":      ulid.Make().String(),
+		"challenge_id": ulid.Make().String(),
+		"token_hash":   tokenHash,
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES (@user_id, @user_id, @user_id || '@example.com', 'x')`,
+		`INSERT INTO login_challenges (id, user_id, token_hash, expires_at) VALUES (@challenge_id, @user_id, @token_hash, NOW() + INTERVAL '5 minutes')`,
+	}, []string{
+		"DELETE FROM users WHERE id = @user_id",
+	})
+
+	// Concurrent attempts get no more than the limit between them.
+	var counted atomic.Int32
+	var wg sync.WaitGroup
+	for range 4 * models.MaxLoginChallengeTries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.RecordChallengeAttempt(ctx, args["challenge_id"].(string), models.MaxLoginChallengeTries)
+			assert.NoError(t, err)
+			if ok {
+				counted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(models.MaxLoginChallengeTries), counted.Load())
+}
+
+func TestTwoFactorRepository_ExpiredChallenge(t *testing.T) {
+	pool := newTestPool(t)
+	repo := twofactor.NewTwoFactorRepository(pool, pool)
+	ctx := context.Background()
+
+	_, tokenHash, err := newOpaqueToken()
+	require.NoError(t, err)
+
+	args := pgx.NamedArgs{
+		"user_id":      ulid.Make().String(),
+		"challenge_id": ulid.Make().String(),
+		"token_hash":   tokenHash,
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES (@user_id, @user_id, @user_id || '@example.com', 'x')`,
+		`INSERT INTO login_challenges (id, user_id, token_hash, expires_at) VALUES (@challenge_id, @user_id, @token_hash, NOW() - INTERVAL '1 second')`,
+	}, []string{
+		"DELETE FROM users WHERE id = @user_id",
+	})
+
+	// The database clock decides, whatever the service's clock says.
+	challenge, err := repo.GetLoginChallenge(ctx, tokenHash)
+	require.NoError(t, err)
+	assert.Empty(t, challenge.ID)
+
+	counted, err := repo.RecordChallengeAttempt(ctx, args["challenge_id"].(string), models.MaxLoginChallengeTries)
+	require.NoError(t, err)
+	assert.False(t, counted)
+
+	consumed, err := repo.ConsumeLoginChallenge(ctx, args["challenge_id"].(string))
+	require.NoError(t, err)
+	assert.False(t, consumed)
+}
@@ -2,30 +2,159 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/audit"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/moderation"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/tokens"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/twofactor"
 	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/pkg/encryption"
 	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+	"github.com/brenocoelho/messaging-app-go/pkg/redisconn"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UsersService interface {
 	CreateUser(ctx context.Context, req models.CreateUserRequest) (models.CreateUserResponse, error)
 	Login(ctx context.Context, req models.LoginRequest) (models.LoginResponse, error)
+	RefreshSession(ctx context.Context, refreshToken string, device models.DeviceInfo) (models.RefreshSessionResponse, error)
+	Logout(ctx context.Context, req models.LogoutRequest) error
+	ListSessions(ctx context.Context, userID string) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	ChangePassword(ctx context.Context, req models.ChangePasswordRequest) error
+	RequestPasswordReset(ctx context.Context, req models.RequestPasswordResetRequest) error
+	ConfirmPasswordReset(ctx context.Context, req models.ConfirmPasswordResetRequest) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(ctx context.Context, userID string) error
+	EnrollTOTP(ctx context.Context, userID string) (models.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, userID, code string) error
+	VerifyLoginChallenge(ctx context.Context, req models.VerifyLoginChallengeRequest) (models.LoginResponse, error)
+	LoginWithIDToken(ctx context.Context, req models.LoginWithIDTokenRequest) (models.LoginResponse, error)
+	ExchangeOIDCCode(ctx context.Context, req models.ExchangeOIDCCodeRequest) (models.LoginResponse, error)
 	GetByID(ctx context.Context, userID string) (models.User, error)
+	GetUser(ctx context.Context, viewerID, userID string) (models.User, error)
+	BatchGetUsers(ctx context.Context, viewerID string, userIDs []string) ([]models.User, error)
+	UpdateProfile(ctx context.Context, req models.UpdateProfileRequest) (models.User, error)
+	SearchUsers(ctx context.Context, req models.SearchUsersRequest) (models.SearchUsersResponse, error)
 }
 
 type usersService struct {
-	usersRepo  users.UsersRepository
-	jwtService jwt.Service
+	usersRepo      users.UsersRepository
+	moderationRepo moderation.ModerationRepository
+	tokensRepo     tokens.TokensRepository
+	twoFactorRepo  twofactor.TwoFactorRepository
+	auditRepo      audit.AuditRepository
+	jwtService     jwt.Service
+	revocations    redisconn.RevocationStore
+	limiter        redisconn.RateLimiter
+	loginThrottle  redisconn.LoginThrottle
+	realtime       RealtimeService
+	mailer         mailer.Mailer
+	totpCipher     encryption.Cipher
+	identities     oidc.Provider
+	clock          func() time.Time
+
+	refreshTokenTTL          time.Duration
+	passwordResetTTL         time.Duration
+	passwordResetURL         string
+	emailVerificationTTL     time.Duration
+	emailVerificationURL     string
+	verificationResendLimit  int
+	verificationResendWindow time.Duration
+	passwordResetEmailLimit  int
+	passwordResetIPLimit     int
+	passwordResetWindow      time.Duration
+	totpIssuer               string
+	loginChallengeTTL        time.Duration
+	loginEmailThrottle       redisconn.ThrottlePolicy
+	loginIPThrottle          redisconn.ThrottlePolicy
+	secondFactorThrottle     redisconn.ThrottlePolicy
 }
 
-func NewUsersService(usersRepo users.UsersRepository, jwtService jwt.Service) UsersService {
+func NewUsersService(usersRepo users.UsersRepository, moderationRepo moderation.ModerationRepository, tokensRepo tokens.TokensRepository, twoFactorRepo twofactor.TwoFactorRepository, auditRepo audit.AuditRepository, jwtService jwt.Service, revocations redisconn.RevocationStore, limiter redisconn.RateLimiter, loginThrottle redisconn.LoginThrottle, realtime RealtimeService, mailer mailer.Mailer, totpCipher encryption.Cipher, identities oidc.Provider, cfg AuthConfig) UsersService {
+	if cfg.RefreshTokenTTL <= 0 {
+		cfg.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	if cfg.PasswordResetTTL <= 0 {
+		cfg.PasswordResetTTL = DefaultPasswordResetTTL
+	}
+	if cfg.PasswordResetEmailLimit <= 0 {
+		cfg.PasswordResetEmailLimit = DefaultPasswordResetEmailLimit
+	}
+	if cfg.PasswordResetIPLimit <= 0 {
+		cfg.PasswordResetIPLimit = DefaultPasswordResetIPLimit
+	}
+	if cfg.PasswordResetWindow <= 0 {
+		cfg.PasswordResetWindow = DefaultPasswordResetWindow
+	}
+	if cfg.EmailVerificationTTL <= 0 {
+		cfg.EmailVerificationTTL = DefaultEmailVerificationTTL
+	}
+	if cfg.VerificationResendLimit <= 0 {
+		cfg.VerificationResendLimit = DefaultVerificationResendLimit
+	}
+	if cfg.VerificationResendWindow <= 0 {
+		cfg.VerificationResendWindow = DefaultVerificationResendWindow
+	}
+	if cfg.TOTPIssuer == "" {
+		cfg.TOTPIssuer = DefaultTOTPIssuer
+	}
+	if cfg.LoginChallengeTTL <= 0 {
+		cfg.LoginChallengeTTL = DefaultLoginChallengeTTL
+	}
+	if cfg.LoginEmailThrottle == (redisconn.ThrottlePolicy{}) {
+		cfg.LoginEmailThrottle = DefaultLoginEmailThrottle
+	}
+	if cfg.LoginIPThrottle == (redisconn.ThrottlePolicy{}) {
+		cfg.LoginIPThrottle = DefaultLoginIPThrottle
+	}
+	if cfg.SecondFactorThrottle == (redisconn.ThrottlePolicy{}) {
+		cfg.SecondFactorThrottle = DefaultSecondFactorThrottle
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
 	return &usersService{
-		usersRepo:  usersRepo,
-		jwtService: jwtService,
+		usersRepo:      usersRepo,
+		moderationRepo: moderationRepo,
+		tokensRepo:     tokensRepo,
+		twoFactorRepo:  twoFactorRepo,
+		auditRepo:      auditRepo,
+		jwtService:     jwtService,
+		revocations:    revocations,
+		limiter:        limiter,
+		loginThrottle:  loginThrottle,
+		realtime:       realtime,
+		mailer:         mailer,
+		totpCipher:     totpCipher,
+		identities:     identities,
+		clock:          cfg.Clock,
+
+		refreshTokenTTL:          cfg.RefreshTokenTTL,
+		passwordResetTTL:         cfg.PasswordResetTTL,
+		passwordResetURL:         cfg.PasswordResetURL,
+		passwordResetEmailLimit:  cfg.PasswordResetEmailLimit,
+		passwordResetIPLimit:     cfg.PasswordResetIPLimit,
+		passwordResetWindow:      cfg.PasswordResetWindow,
+		emailVerificationTTL:     cfg.EmailVerificationTTL,
+		emailVerificationURL:     cfg.EmailVerificationURL,
+		verificationResendLimit:  cfg.VerificationResendLimit,
+		verificationResendWindow: cfg.VerificationResendWindow,
+		totpIssuer:               cfg.TOTPIssuer,
+		loginChallengeTTL:        cfg.LoginChallengeTTL,
+		loginEmailThrottle:       cfg.LoginEmailThrottle,
+		loginIPThrottle:          cfg.LoginIPThrottle,
+		secondFactorThrottle:     cfg.SecondFactorThrottle,
 	}
 }
 
@@ -40,7 +169,18 @@ func (s *usersService) CreateUser(ctx context.Context, req models.CreateUserRequ
 
 	if existingUser.ID != "" {
 		slog.Warn("User already exists", "email", req.Email)
-		return models.CreateUserResponse{}, errors.New("user already exists")
+		return models.CreateUserResponse{}, ErrEmailTaken
+	}
+
+	existingUser, err = s.usersRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		slog.Error("Error checking if username is taken", "error", err)
+		return models.CreateUserResponse{}, err
+	}
+
+	if existingUser.ID != "" {
+		slog.Warn("Username already taken", "username", req.Username)
+		return models.CreateUserResponse{}, ErrUsernameTaken
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
@@ -53,6 +193,7 @@ func (s *usersService) CreateUser(ctx context.Context, req models.CreateUserRequ
 		Username:     req.Username,
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
+		DisplayName:  req.Username,
 	}
 
 	userID, err := s.usersRepo.Create(ctx, user)
@@ -63,25 +204,28 @@ func (s *usersService) CreateUser(ctx context.Context, req models.CreateUserRequ
 
 	user.ID = userID
 
-	token, err := s.jwtService.GenerateToken(jwt.User{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-	})
+	go s.sendVerificationEmail(context.WithoutCancel(ctx), user)
+
+	session, err := s.issueSession(ctx, user, req.Device)
 	if err != nil {
-		slog.Error("Error generating JWT token", "error", err)
 		return models.CreateUserResponse{}, err
 	}
 
 	return models.CreateUserResponse{
-		Token:  token,
-		UserID: user.ID,
+		Token:          session.Token,
+		TokenExpiresAt: session.TokenExpiresAt,
+		RefreshToken:   session.RefreshToken,
+		UserID:         user.ID,
 	}, nil
 }
 
 func (s *usersService) Login(ctx context.Context, req models.LoginRequest) (models.LoginResponse, error) {
 	slog.Info("Login service", "email", req.Email)
 
+	if err := s.checkLoginThrottle(ctx, req.Email, req.Device.IPAddress); err != nil {
+		return models.LoginResponse{}, err
+	}
+
 	user, err := s.usersRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		slog.Error("Error getting user by email", "error", err)
@@ -90,27 +234,60 @@ func (s *usersService) Login(ctx context.Context, req models.LoginRequest) (mode
 
 	if user.ID == "" {
 		slog.Warn("User not found", "email", req.Email)
+		s.recordLoginFailure(ctx, req.Email, req.Device.IPAddress, "")
+		return models.LoginResponse{}, errors.New("invalid credentials")
+	}
+
+	// Bots have no password and authenticate with API keys only.
+	if user.IsBot() {
+		slog.Warn("Password login for a bot", "userID", user.ID)
+		s.recordLoginFailure(ctx, req.Email, req.Device.IPAddress, user.ID)
 		return models.LoginResponse{}, errors.New("invalid credentials")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		slog.Warn("Invalid password", "email", req.Email)
+		s.recordLoginFailure(ctx, req.Email, req.Device.IPAddress, user.ID)
 		return models.LoginResponse{}, errors.New("invalid credentials")
 	}
 
-	token, err := s.jwtService.GenerateToken(jwt.User{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-	})
+	resp, err := s.completeLogin(ctx, user, req.Device)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	// With two-factor authentication, the login is not complete until the
+	// challenge is; until then the password failures still count.
+	if resp.ChallengeToken == "" {
+		s.resetLoginThrottle(ctx, user)
+	}
+
+	return resp, nil
+}
+
+// completeLogin issues a session for an authenticated user, or a login
+// challenge if they use two-factor authentication.
+func (s *usersService) completeLogin(ctx context.Context, user models.User, device models.DeviceInfo) (models.LoginResponse, error) {
+	totpState, err := s.twoFactorRepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		slog.Error("Error getting TOTP", "error", err)
+		return models.LoginResponse{}, err
+	}
+
+	if totpState.ConfirmedAt != nil {
+		return s.startLoginChallenge(ctx, user, device)
+	}
+
+	session, err := s.issueSession(ctx, user, device)
 	if err != nil {
-		slog.Error("Error generating JWT token", "error", err)
 		return models.LoginResponse{}, err
 	}
 
 	return models.LoginResponse{
-		Token:  token,
-		UserID: user.ID,
+		Token:          session.Token,
+		TokenExpiresAt: session.TokenExpiresAt,
+		RefreshToken:   session.RefreshToken,
+		UserID:         user.ID,
 	}, nil
 }
 
@@ -119,3 +296,268 @@ func (s *usersService) GetByID(ctx context.Context, userID string) (models.User,
 
 	return s.usersRepo.GetByID(ctx, userID)
 }
+
+// GetUser returns userID as seen by viewerID. The email is only included for
+// the viewer themselves and for users who share a regular chat with them;
+// a shared channel is not enough.
+func (s *usersService) GetUser(ctx context.Context, viewerID, userID string) (models.User, error) {
+	slog.Info("GetUser service", "viewerID", viewerID, "userID", userID)
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return models.User{}, err
+	}
+
+	if user.ID == "" {
+		return models.User{}, nil
+	}
+
+	result, err := s.redactEmails(ctx, viewerID, []models.User{user})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return result[0], nil
+}
+
+// BatchGetUsers returns the users that exist, in request order, with the same
+// email visibility as GetUser.
+func (s *usersService) BatchGetUsers(ctx context.Context, viewerID string, userIDs []string) ([]models.User, error) {
+	slog.Info("BatchGetUsers service", "viewerID", viewerID, "count", len(userIDs))
+
+	seen := make(map[string]bool, len(userIDs))
+	ids := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := s.usersRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		slog.Error("Error getting users", "error", err)
+		return nil, err
+	}
+
+	byID := make(map[string]models.User, len(found))
+	for _, user := range found {
+		byID[user.ID] = user
+	}
+
+	result := make([]models.User, 0, len(found))
+	for _, id := range ids {
+		if user, ok := byID[id]; ok {
+			result = append(result, user)
+		}
+	}
+
+	return s.redactEmails(ctx, viewerID, result)
+}
+
+func (s *usersService) UpdateProfile(ctx context.Context, req models.UpdateProfileRequest) (models.User, error) {
+	slog.Info("UpdateProfile service", "userID", req.UserID)
+
+	user, err := s.usersRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return models.User{}, err
+	}
+
+	if user.ID == "" {
+		return models.User{}, ErrNotFound
+	}
+
+	var changed []string
+	if req.Username != nil && *req.Username != user.Username {
+		if !strings.EqualFold(*req.Username, user.Username) {
+			existingUser, err := s.usersRepo.GetByUsername(ctx, *req.Username)
+			if err != nil {
+				slog.Error("Error checking if username is taken", "error", err)
+				return models.User{}, err
+			}
+
+			if existingUser.ID != "" {
+				slog.Warn("Username already taken", "username", *req.Username)
+				return models.User{}, ErrUsernameTaken
+			}
+		}
+		user.Username = *req.Username
+		changed = append(changed, "username")
+	}
+	if req.DisplayName != nil && *req.DisplayName != user.DisplayName {
+		user.DisplayName = *req.DisplayName
+		changed = append(changed, "display_name")
+	}
+	if req.Bio != nil && *req.Bio != user.Bio {
+		user.Bio = *req.Bio
+		changed = append(changed, "bio")
+	}
+	if req.AvatarAttachmentID != nil && *req.AvatarAttachmentID != user.AvatarAttachmentID {
+		user.AvatarAttachmentID = *req.AvatarAttachmentID
+		changed = append(changed, "avatar_attachment_id")
+	}
+	// Discoverability is private, so flipping it alone is not broadcast.
+	discoverableChanged := req.Discoverable != nil && *req.Discoverable != user.Discoverable
+	if discoverableChanged {
+		user.Discoverable = *req.Discoverable
+	}
+
+	if len(changed) == 0 && !discoverableChanged {
+		return user, nil
+	}
+
+	if err := s.usersRepo.Update(ctx, user); err != nil {
+		slog.Error("Error updating user", "error", err)
+		return models.User{}, err
+	}
+
+	user.UpdatedAt = time.Now()
+	if len(changed) > 0 {
+		s.broadcastProfileUpdate(ctx, user, changed)
+	}
+
+	return user, nil
+}
+
+func (s *usersService) SearchUsers(ctx context.Context, req models.SearchUsersRequest) (models.SearchUsersResponse, error) {
+	slog.Info("SearchUsers service", "userID", req.UserID, "query", req.Query)
+
+	req.Query = strings.ToLower(strings.TrimSpace(req.Query))
+	if req.Limit < 1 {
+		req.Limit = 20
+	}
+	if req.Limit > models.MaxSearchUsersLimit {
+		req.Limit = models.MaxSearchUsersLimit
+	}
+
+	var after *models.UserSearchCursor
+	if req.Cursor != "" {
+		cursor, err := decodeUserSearchCursor(req.Cursor)
+		if err != nil {
+			slog.Warn("Invalid search cursor", "error", err)
+			return models.SearchUsersResponse{}, ErrInvalidCursor
+		}
+		after = &cursor
+	}
+
+	// Fetch one extra row to know whether there is a next page.
+	limit := req.Limit
+	req.Limit++
+	results, err := s.usersRepo.Search(ctx, req, after)
+	if err != nil {
+		slog.Error("Error searching users", "error", err)
+		return models.SearchUsersResponse{}, err
+	}
+
+	var resp models.SearchUsersResponse
+	if len(results) > int(limit) {
+		results = results[:limit]
+		last := results[len(results)-1]
+		resp.NextCursor = encodeUserSearchCursor(models.UserSearchCursor{
+			Rank:  last.Rank,
+			Score: last.Score,
+			ID:    last.ID,
+		})
+	}
+
+	found := make([]models.User, len(results))
+	for i, result := range results {
+		found[i] = result.User
+	}
+
+	resp.Users, err = s.redactEmails(ctx, req.UserID, found)
+	if err != nil {
+		return models.SearchUsersResponse{}, err
+	}
+
+	return resp, nil
+}
+
+func encodeUserSearchCursor(cursor models.UserSearchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeUserSearchCursor(value string) (models.UserSearchCursor, error) {
+	var cursor models.UserSearchCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, err
+	}
+
+	if cursor.ID == "" {
+		return cursor, errors.New("cursor is missing the id")
+	}
+
+	return cursor, nil
+}
+
+// broadcastProfileUpdate notifies everyone sharing a chat with the user,
+// except users on either side of a block. Failures are logged only, the
+// profile change itself already succeeded.
+func (s *usersService) broadcastProfileUpdate(ctx context.Context, user models.User, changed []string) {
+	coMemberIDs, err := s.usersRepo.GetCoMemberIDs(ctx, user.ID)
+	if err != nil {
+		slog.Error("Error getting co-members for profile update", "error", err, "userID", user.ID)
+		return
+	}
+
+	if len(coMemberIDs) == 0 {
+		return
+	}
+
+	blocked, err := s.moderationRepo.FilterBlocked(ctx, user.ID, coMemberIDs)
+	if err != nil {
+		slog.Error("Error filtering blocked co-members", "error", err, "userID", user.ID)
+		return
+	}
+
+	recipients := make([]string, 0, len(coMemberIDs))
+	for _, id := range coMemberIDs {
+		if !blocked[id] {
+			recipients = append(recipients, id)
+		}
+	}
+
+	s.realtime.BroadcastUserUpdate(recipients, &UserUpdate{
+		UserID:    user.ID,
+		Type:      UserUpdateTypeProfile,
+		Content:   strings.Join(changed, ","),
+		Timestamp: user.UpdatedAt,
+		User:      &user,
+	})
+}
+
+func (s *usersService) redactEmails(ctx context.Context, viewerID string, list []models.User) ([]models.User, error) {
+	var others []string
+	for _, user := range list {
+		if user.ID != viewerID {
+			others = append(others, user.ID)
+		}
+	}
+
+	if len(others) == 0 {
+		return list, nil
+	}
+
+	coMembers, err := s.usersRepo.FilterCoMembers(ctx, viewerID, others)
+	if err != nil {
+		slog.Error("Error filtering co-members", "error", err)
+		return nil, err
+	}
+
+	for i := range list {
+		if list[i].ID != viewerID && !coMembers[list[i].ID] {
+			list[i].Email = ""
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUsersService() (*usersService, *fakeUsersRepository, RealtimeService) {
+	usersRepo := newFakeUsersRepository(
+		models.User{ID: "alice", Username: "alice", Email: "alice@example.com"},
+		models.User{ID: "bob", Username: "bob", Email: "bob@example.com"},
+		models.User{ID: "carol", Username: "carol", Email: "carol@example.com"},
+	)
+	usersRepo.addCoMembers("alice", "bob")
+	realtime := NewRealtimeService(nil, nil)
+
+	service := NewUsersService(usersRepo, newFakeModerationRepository(newFakeChatsRepository()), newFakeTokensRepository(), newFakeTwoFactorRepository(), newFakeAuditRepository(), newTestJWTService(jwt.Config{}), newFakeRevocationStore(), newFakeRateLimiter(), newFakeLoginThrottle(), realtime, mailer.NewMemoryMailer(), nil, nil, AuthConfig{RefreshTokenTTL: time.Hour}).(*usersService)
+	return service, usersRepo, realtime
+}
+
+func TestUsersService_CreateUser_Uniqueness(t *testing.T) {
+	service, _, _ := newTestUsersService()
+
+	_, err := service.CreateUser(context.Background(), models.CreateUserRequest{
+		Username: "dave",
+		Email:    "alice@example.com",
+		Password: "password123",
+	})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	_, err = service.CreateUser(context.Background(), models.CreateUserRequest{
+		Username: "Alice",
+		Email:    "dave@example.com",
+		Password: "password123",
+	})
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+}
+
+func TestUsersService_UpdateProfile_UsernameTaken(t *testing.T) {
+	service, usersRepo, _ := newTestUsersService()
+	username := "BOB"
+
+	_, err := service.UpdateProfile(context.Background(), models.UpdateProfileRequest{
+		UserID:   "alice",
+		Username: &username,
+	})
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+	assert.Empty(t, usersRepo.updates)
+
+	// Changing only the case of your own username is allowed.
+	username = "Alice"
+	user, err := service.UpdateProfile(context.Background(), models.UpdateProfileRequest{
+		UserID:   "alice",
+		Username: &username,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Username)
+}
+
+func TestUsersService_UpdateProfile_BroadcastsToCoMembers(t *testing.T) {
+	service, usersRepo, realtime := newTestUsersService()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	bobUpdates, err := realtime.SubscribeToUserUpdates(ctx, "bob")
+	require.NoError(t, err)
+	carolUpdates, err := realtime.SubscribeToUserUpdates(ctx, "carol")
+	require.NoError(t, err)
+
+	displayName := "Alice A."
+	bio := "Hello"
+	user, err := service.UpdateProfile(ctx, models.UpdateProfileRequest{
+		UserID:      "alice",
+		DisplayName: &displayName,
+		Bio:         &bio,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Alice A.", user.DisplayName)
+	require.Len(t, usersRepo.updates, 1)
+
+	select {
+	case update := <-bobUpdates:
+		assert.Equal(t, "alice", update.UserID)
+		assert.Equal(t, UserUpdateTypeProfile, update.Type)
+		assert.Equal(t, "display_name,bio", update.Content)
+		require.NotNil(t, update.User)
+		assert.Equal(t, "Hello", update.User.Bio)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("co-member did not receive the profile update")
+	}
+
+	select {
+	case update := <-carolUpdates:
+		t.Fatalf("unexpected update for a user outside alice's chats: %+v", update)
+	default:
+	}
+
+	// Setting the same values again is a no-op.
+	_, err = service.UpdateProfile(ctx, models.UpdateProfileRequest{
+		UserID:      "alice",
+		DisplayName: &displayName,
+	})
+	require.NoError(t, err)
+	assert.Len(t, usersRepo.updates, 1)
+}
+
+func TestUsersService_BatchGetUsers_RedactsEmails(t *testing.T) {
+	service, _, _ := newTestUsersService()
+
+	users, err := service.BatchGetUsers(context.Background(), "alice", []string{"carol", "alice", "missing", "bob", "carol"})
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+
+	assert.Equal(t, "carol", users[0].ID)
+	assert.Empty(t, users[0].Email)
+	assert.Equal(t, "alice@example.com", users[1].Email)
+	assert.Equal(t, "bob@example.com", users[2].Email)
+
+	user, err := service.GetUser(context.Background(), "carol", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user.ID)
+	assert.Empty(t, user.Email)
+}
+
+// Sharing a channel, which anyone can join, must not reveal an email. This
+// is decided in SQL, so it needs the database of newTestPool.
+func TestUsersService_BatchGetUsers_ChannelIsNotASharedChat(t *testing.T) {
+	pool := newTestPool(t)
+	service := &usersService{usersRepo: users.NewUsersRepository(pool, pool)}
+
+	args := pgx.NamedArgs{
+		"viewer_id":  ulid.Make().String(),
+		"channel_id": ulid.Make().String(),
+		"subscriber": ulid.Make().String(),
+		"chat_id":    ulid.Make().String(),
+		"peer_id":    ulid.Make().String(),
+		"uc1":        ulid.Make().String(),
+		"uc2":        ulid.Make().String(),
+		"uc3":        ulid.Make().String(),
+		"uc4":        ulid.Make().String(),
+	}
+	execTestSQL(t, pool, args, []string{
+		`INSERT INTO users (id, username, email, password_hash) VALUES
+			(@viewer_id, @viewer_id, @viewer_id || '@example.com', 'x'),
+			(@subscriber, @subscriber, @subscriber || '@example.com', 'x'),
+			(@peer_id, @peer_id, @peer_id || '@example.com', 'x')`,
+		`INSERT INTO chats (id, name, kind, visibility) VALUES
+			(@channel_id, 'news', 'CHANNEL', 'PUBLIC'),
+			(@chat_id, 'chat', 'CHAT', 'PRIVATE')`,
+		`INSERT INTO users_chats (id, user_id, chat_id) VALUES
+			(@uc1, @viewer_id, @channel_id),
+			(@uc2, @subscriber, @channel_id),
+			(@uc3, @viewer_id, @chat_id),
+			(@uc4, @peer_id, @chat_id)`,
+	}, []string{
+		"DELETE FROM chats WHERE id IN (@channel_id, @chat_id)",
+		"DELETE FROM users WHERE id IN (@viewer_id, @subscriber, @peer_id)",
+	})
+
+	viewerID := args["viewer_id"].(string)
+	subscriberID := args["subscriber"].(string)
+	peerID := args["peer_id"].(string)
+
+	result, err := service.BatchGetUsers(context.Background(), viewerID, []string{subscriberID, peerID})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Empty(t, result[0].Email, "sharing a channel must not reveal the email")
+	assert.Equal(t, strings.ToLower(peerID)+"@example.com", strings.ToLower(result[1].Email))
+}
+
+func TestUsersService_SearchUsers_CursorPagination(t *testing.T) {
+	service, usersRepo, _ := newTestUsersService()
+	usersRepo.searchResults = []models.UserSearchResult{
+		{User: usersRepo.users["bob"], Rank: 0, Score: 0.8},
+		{User: usersRepo.users["carol"], Rank: 1, Score: 0.4},
+	}
+
+	resp, err := service.SearchUsers(context.Background(), models.SearchUsersRequest{
+		UserID: "alice",
+		Query:  "  BO ",
+		Limit:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, "bob", resp.Users[0].ID)
+	assert.Equal(t, "bob@example.com", resp.Users[0].Email)
+	assert.NotEmpty(t, resp.NextCursor)
+	assert.Equal(t, "bo", usersRepo.searches[0].Query)
+	assert.Equal(t, int32(2), usersRepo.searches[0].Limit)
+
+	resp, err = service.SearchUsers(context.Background(), models.SearchUsersRequest{
+		UserID: "alice",
+		Query:  "bo",
+		Cursor: resp.NextCursor,
+		Limit:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	assert.Equal(t, "carol", resp.Users[0].ID)
+	assert.Empty(t, resp.Users[0].Email, "email of a user outside the caller's chats must not leak")
+	assert.Empty(t, resp.NextCursor)
+
+	_, err = service.SearchUsers(context.Background(), models.SearchUsersRequest{
+		UserID: "alice",
+		Query:  "bo",
+		Cursor: "not-a-cursor",
+	})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestUsersService_UpdateProfile_SkipsBlockedCoMembers(t *testing.T) {
+	service, usersRepo, realtime := newTestUsersService()
+	usersRepo.addCoMembers("alice", "carol")
+	require.NoError(t, service.moderationRepo.Block(context.Background(), "carol", "alice"))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	bobUpdates, err := realtime.SubscribeToUserUpdates(ctx, "bob")
+	require.NoError(t, err)
+	carolUpdates, err := realtime.SubscribeToUserUpdates(ctx, "carol")
+	require.NoError(t, err)
+
+	bio := "New bio"
+	_, err = service.UpdateProfile(ctx, models.UpdateProfileRequest{UserID: "alice", Bio: &bio})
+	require.NoError(t, err)
+
+	select {
+	case <-bobUpdates:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("co-member did not receive the profile update")
+	}
+
+	select {
+	case update := <-carolUpdates:
+		t.Fatalf("blocked co-member received an update: %+v", update)
+	default:
+	}
+}
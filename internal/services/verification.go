@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+)
+
+// EmailVerificationPolicy lists what accounts whose email is not verified yet
+// may not do. The zero value restricts nothing.
+type EmailVerificationPolicy struct {
+	// CreateChats covers both CreateChat and CreateChannel.
+	CreateChats  bool
+	JoinChannels bool
+}
+
+// ParseEmailVerificationPolicy reads a comma separated list of restricted
+// actions: "create_chats" and "join_channels".
+func ParseEmailVerificationPolicy(value string) (EmailVerificationPolicy, error) {
+	var policy EmailVerificationPolicy
+	for _, action := range strings.Split(value, ",") {
+		switch strings.TrimSpace(action) {
+		case "":
+		case "create_chats":
+			policy.CreateChats = true
+		case "join_channels":
+			policy.JoinChannels = true
+		default:
+			return EmailVerificationPolicy{}, fmt.Errorf("unknown email verification action %q", action)
+		}
+	}
+	return policy, nil
+}
+
+// VerifyEmail marks the email of the token's owner as verified.
+func (s *usersService) VerifyEmail(ctx context.Context, token string) error {
+	slog.Info("VerifyEmail service")
+
+	verification, err := s.tokensRepo.ConsumeEmailVerificationToken(ctx, hashToken(token))
+	if err != nil {
+		slog.Error("Error consuming email verification token", "error", err)
+		return err
+	}
+
+	if verification.ID == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	slog.Info("Email verified", "userID", verification.UserID)
+	return nil
+}
+
+// ResendVerificationEmail sends a new verification link, which replaces the
+// earlier ones. Each user can ask for a limited number per window.
+func (s *usersService) ResendVerificationEmail(ctx context.Context, userID string) error {
+	slog.Info("ResendVerificationEmail service", "userID", userID)
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return err
+	}
+
+	if user.ID == "" {
+		return ErrNotFound
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	allowed, retryAfter, err := s.limiter.Allow(ctx, "verification_email:"+user.ID, s.verificationResendLimit, s.verificationResendWindow)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return &RetryAfterError{RetryAfter: retryAfter}
+	}
+
+	go s.sendVerificationEmail(context.WithoutCancel(ctx), user)
+	return nil
+}
+
+func (s *usersService) sendVerificationEmail(ctx context.Context, user models.User) {
+	token, tokenHash, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating email verification token", "error", err)
+		return
+	}
+
+	err = s.tokensRepo.CreateEmailVerificationToken(ctx, models.EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.emailVerificationTTL),
+	})
+	if err != nil {
+		slog.Error("Error storing email verification token", "error", err)
+		return
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email address. It expires in %s.\n\n%s?token=%s\n\nIf you did not create an account, you can ignore this email.\n",
+			user.Username, s.emailVerificationTTL, s.emailVerificationURL, token),
+	})
+	if err != nil {
+		slog.Error("Error sending verification email", "userID", user.ID, "error", err)
+	}
+}
+
+// requireVerifiedEmail fails with ErrEmailNotVerified when restricted is set
+// and the user has not verified their email.
+func (s *chatsService) requireVerifiedEmail(ctx context.Context, userID string, restricted bool) error {
+	if !restricted {
+		return nil
+	}
+
+	user, err := s.usersRepo.GetByID(ctx, userID)
+	if err != nil {
+		slog.Error("Error getting user", "error", err)
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		slog.Warn("Action requires a verified email", "userID", userID)
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
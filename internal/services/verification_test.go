@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForToken waits for the email number index and returns the token from
+// its link.
+func waitForToken(t *testing.T, outbox *mailer.MemoryMailer, index int) string {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return len(outbox.Messages()) > index }, time.Second, 5*time.Millisecond)
+
+	_, token, found := strings.Cut(outbox.Messages()[index].Body, "?token=")
+	require.True(t, found)
+	token, _, _ = strings.Cut(token, "\n")
+	return token
+}
+
+func TestUsersService_CreateUser_SendsVerificationEmail(t *testing.T) {
+	service, tokensRepo := newTestSessionsService(t)
+	outbox := service.mailer.(*mailer.MemoryMailer)
+	ctx := context.Background()
+
+	resp, err := service.CreateUser(ctx, models.CreateUserRequest{Username: "dave", Email: "dave@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	token := waitForToken(t, outbox, 0)
+	assert.Equal(t, "dave@example.com", outbox.Messages()[0].To)
+
+	require.NoError(t, service.VerifyEmail(ctx, token))
+	assert.Equal(t, []string{resp.UserID}, tokensRepo.verifiedUsers)
+
+	// The token works once.
+	assert.ErrorIs(t, service.VerifyEmail(ctx, token), ErrInvalidVerificationToken)
+	assert.ErrorIs(t, service.VerifyEmail(ctx, "unknown"), ErrInvalidVerificationToken)
+}
+
+func TestUsersService_ResendVerificationEmail(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	outbox := service.mailer.(*mailer.MemoryMailer)
+	ctx := context.Background()
+
+	require.NoError(t, service.ResendVerificationEmail(ctx, "alice"))
+	first := waitForToken(t, outbox, 0)
+
+	require.NoError(t, service.ResendVerificationEmail(ctx, "alice"))
+	latest := waitForToken(t, outbox, 1)
+
+	// A new email replaces the earlier link.
+	assert.ErrorIs(t, service.VerifyEmail(ctx, first), ErrInvalidVerificationToken)
+	assert.NoError(t, service.VerifyEmail(ctx, latest))
+}
+
+func TestUsersService_ResendVerificationEmail_RateLimited(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	ctx := context.Background()
+
+	for range service.verificationResendLimit {
+		require.NoError(t, service.ResendVerificationEmail(ctx, "alice"))
+	}
+
+	err := service.ResendVerificationEmail(ctx, "alice")
+	require.ErrorIs(t, err, ErrRateLimited)
+
+	var retryErr *RetryAfterError
+	require.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, DefaultVerificationResendWindow, retryErr.RetryAfter)
+}
+
+func TestUsersService_ResendVerificationEmail_AlreadyVerified(t *testing.T) {
+	service, _ := newTestSessionsService(t)
+	verifiedAt := time.Now()
+	usersRepo := service.usersRepo.(*fakeUsersRepository)
+	alice := usersRepo.users["alice"]
+	alice.EmailVerifiedAt = &verifiedAt
+	usersRepo.users["alice"] = alice
+
+	err := service.ResendVerificationEmail(context.Background(), "alice")
+	assert.ErrorIs(t, err, ErrEmailAlreadyVerified)
+	assert.Empty(t, service.mailer.(*mailer.MemoryMailer).Messages())
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/chats"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/users"
+	"github.com/brenocoelho/messaging-app-go/internal/repositories/webhooks"
+	"github.com/brenocoelho/messaging-app-go/pkg/backoff"
+	"github.com/brenocoelho/messaging-app-go/pkg/webhook"
+	"github.com/oklog/ulid/v2"
+)
+
+// WebhookSecretPrefix starts every webhook secret.
+const WebhookSecretPrefix = "whsec_"
+
+// maxWebhookErrorLength limits the error stored with a failed attempt.
+const maxWebhookErrorLength = 500
+
+// WebhookPublisher queues chat events for the webhooks subscribed to them.
+type WebhookPublisher interface {
+	// Publish queues event. Failures are logged only, since the event has
+	// already happened.
+	Publish(ctx context.Context, event models.WebhookEvent)
+}
+
+type WebhooksService interface {
+	WebhookPublisher
+
+	CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (models.CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context, req models.ListWebhooksRequest) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID, webhookID string) error
+	ListWebhookDeliveries(ctx context.Context, req models.ListWebhookDeliveriesRequest) (models.ListWebhookDeliveriesResponse, error)
+	// DeliverDue attempts one batch of due deliveries and returns how many
+	// it attempted.
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// WebhookConfig tunes webhook delivery. Zero values use the defaults.
+type WebhookConfig struct {
+	// MaxAttempts is how often a delivery is tried before it is moved to
+	// the dead-letter state.
+	MaxAttempts int
+	// BackoffBase is the delay after the first failed attempt; it doubles
+	// with every further failure, up to BackoffMax.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// BatchSize deliveries are claimed at a time and sent concurrently.
+	BatchSize int
+	// Lease is how long a claimed delivery is reserved for its worker. It
+	// must be longer than a request may take.
+	Lease time.Duration
+	// IncomingBaseURL is the public URL of the HTTP listener; incoming
+	// webhook URLs start with it.
+	IncomingBaseURL string
+	// CommandTimeout is how long a bot may take to answer a slash command.
+	CommandTimeout time.Duration
+
+	Sender webhook.Sender
+	Clock  func() time.Time
+}
+
+const (
+	DefaultWebhookMaxAttempts = 8
+	DefaultWebhookBackoffBase = 30 * time.Second
+	DefaultWebhookBackoffMax  = time.Hour
+	DefaultWebhookBatchSize   = 20
+	DefaultWebhookLease       = 2 * time.Minute
+)
+
+type webhooksService struct {
+	webhooksRepo webhooks.WebhooksRepository
+	usersRepo    users.UsersRepository
+	chatsRepo    chats.ChatsRepository
+	cfg          WebhookConfig
+}
+
+func NewWebhooksService(webhooksRepo webhooks.WebhooksRepository, usersRepo users.UsersRepository, chatsRepo chats.ChatsRepository, cfg WebhookConfig) WebhooksService {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if cfg.BackoffBase <= 0 {
+		cfg.BackoffBase = DefaultWebhookBackoffBase
+	}
+	if cfg.BackoffMax <= 0 {
+		cfg.BackoffMax = DefaultWebhookBackoffMax
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultWebhookBatchSize
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = DefaultWebhookLease
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.Sender == nil {
+		cfg.Sender = webhook.NewSender(nil, cfg.Clock)
+	}
+
+	return &webhooksService{
+		webhooksRepo: webhooksRepo,
+		usersRepo:    usersRepo,
+		chatsRepo:    chatsRepo,
+		cfg:          cfg,
+	}
+}
+
+func (s *webhooksService) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (models.CreateWebhookResponse, error) {
+	slog.Info("CreateWebhook service", "userID", req.UserID, "chatID", req.ChatID, "botID", req.BotID, "events", req.Events)
+
+	if err := s.authorize(ctx, req.UserID, req.ChatID, req.BotID); err != nil {
+		return models.CreateWebhookResponse{}, err
+	}
+
+	if !validWebhookURL(req.URL) {
+		return models.CreateWebhookResponse{}, ErrInvalidWebhookURL
+	}
+
+	if len(req.Events) == 0 {
+		return models.CreateWebhookResponse{}, ErrInvalidEvent
+	}
+	for _, event := range req.Events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return models.CreateWebhookResponse{}, ErrInvalidEvent
+		}
+	}
+
+	existing, err := s.webhooksRepo.List(ctx, req.ChatID, req.BotID)
+	if err != nil {
+		slog.Error("Error listing webhooks", "error", err)
+		return models.CreateWebhookResponse{}, err
+	}
+
+	if len(existing) >= models.MaxWebhooksPerTarget {
+		slog.Warn("Too many webhooks", "chatID", req.ChatID, "botID", req.BotID, "count", len(existing))
+		return models.CreateWebhookResponse{}, ErrLimitReached
+	}
+
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		slog.Error("Error generating webhook secret", "error", err)
+		return models.CreateWebhookResponse{}, err
+	}
+	secret := WebhookSecretPrefix + token
+
+	created, err := s.webhooksRepo.Create(ctx, models.Webhook{
+		OwnerID: req.UserID,
+		ChatID:  req.ChatID,
+		BotID:   req.BotID,
+		URL:     req.URL,
+		Secret:  secret,
+		Events:  slices.Compact(slices.Sorted(slices.Values(req.Events))),
+	})
+	if err != nil {
+		slog.Error("Error creating webhook", "error", err)
+		return models.CreateWebhookResponse{}, err
+	}
+
+	return models.CreateWebhookResponse{
+		Webhook: created,
+		Secret:  secret,
+	}, nil
+}
+
+func (s *webhooksService) ListWebhooks(ctx context.Context, req models.ListWebhooksRequest) ([]models.Webhook, error) {
+	slog.Info("ListWebhooks service", "userID", req.UserID, "chatID", req.ChatID, "botID", req.BotID)
+
+	if err := s.authorize(ctx, req.UserID, req.ChatID, req.BotID); err != nil {
+		return nil, err
+	}
+
+	return s.webhooksRepo.List(ctx, req.ChatID, req.BotID)
+}
+
+func (s *webhooksService) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	slog.Info("DeleteWebhook service", "userID", userID, "webhookID", webhookID)
+
+	if _, err := s.managedWebhook(ctx, userID, webhookID); err != nil {
+		return err
+	}
+
+	deleted, err := s.webhooksRepo.Delete(ctx, webhookID)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *webhooksService) ListWebhookDeliveries(ctx context.Context, req models.ListWebhookDeliveriesRequest) (models.ListWebhookDeliveriesResponse, error) {
+	slog.Info("ListWebhookDeliveries service", "userID", req.UserID, "webhookID", req.WebhookID, "status", req.Status)
+
+	if _, err := s.managedWebhook(ctx, req.UserID, req.WebhookID); err != nil {
+		return models.ListWebhookDeliveriesResponse{}, err
+	}
+
+	if req.Limit > models.MaxListWebhookDeliveriesLimit {
+		req.Limit = models.MaxListWebhookDeliveriesLimit
+	}
+
+	return s.webhooksRepo.ListDeliveries(ctx, req)
+}
+
+func (s *webhooksService) Publish(ctx context.Context, event models.WebhookEvent) {
+	if event.ID == "" {
+		event.ID = ulid.Make().String()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = s.cfg.Clock().UTC()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Error encoding webhook event", "error", err, "event", event.Type)
+		return
+	}
+
+	if _, err := s.webhooksRepo.Enqueue(ctx, event, payload); err != nil {
+		slog.Error("Error enqueueing webhook event", "error", err, "event", event.Type, "chatID", event.ChatID)
+	}
+}
+
+func (s *webhooksService) DeliverDue(ctx context.Context) (int, error) {
+	jobs, err := s.webhooksRepo.ClaimDue(ctx, s.cfg.BatchSize, s.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.deliver(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	return len(jobs), nil
+}
+
+// deliver attempts job once and records the outcome: delivered, retried
+// after a backoff, or dead-lettered after the last attempt.
+func (s *webhooksService) deliver(ctx context.Context, job models.WebhookJob) {
+	delivery := job.Delivery
+
+	code, err := s.cfg.Sender.Send(ctx, webhook.Request{
+		URL:        job.URL,
+		Secret:     job.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Body:       delivery.Payload,
+	})
+
+	now := s.cfg.Clock()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode = int32(code)
+	delivery.LastError = ""
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case int(delivery.Attempts) >= s.cfg.MaxAttempts:
+		slog.Warn("Webhook delivery failed for good", "deliveryID", delivery.ID, "webhookID", delivery.WebhookID, "error", err)
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.LastError = truncate(err.Error(), maxWebhookErrorLength)
+	default:
+		slog.Info("Webhook delivery failed, retrying", "deliveryID", delivery.ID, "attempts", delivery.Attempts, "error", err)
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(s.backoff(int(delivery.Attempts)))
+		delivery.LastError = truncate(err.Error(), maxWebhookErrorLength)
+	}
+
+	if err := s.webhooksRepo.RecordAttempt(ctx, delivery); err != nil {
+		slog.Error("Error recording webhook attempt", "error", err, "deliveryID", delivery.ID)
+	}
+}
+
+// backoff returns the delay after the given number of failed attempts.
+func (s *webhooksService) backoff(attempts int) time.Duration {
+	return backoff.Exponential(s.cfg.BackoffBase, s.cfg.BackoffMax, attempts-1)
+}
+
+// authorize checks that userID may manage the webhooks of chatID or botID:
+// chat webhooks belong to the chat's admins, bot webhooks to the bot's
+// owner. Exactly one of chatID and botID must be set.
+func (s *webhooksService) authorize(ctx context.Context, userID, chatID, botID string) error {
+	if (chatID == "") == (botID == "") {
+		return ErrNotFound
+	}
+
+	if botID != "" {
+		_, err := ownedBot(ctx, s.usersRepo, userID, botID)
+		return err
+	}
+
+	membership, err := s.chatsRepo.GetMembership(ctx, chatID, userID)
+	if err != nil {
+		slog.Error("Error getting chat membership", "error", err)
+		return err
+	}
+
+	if membership.Role == "" {
+		return ErrNotFound
+	}
+
+	if membership.Role != models.ChatRoleAdmin {
+		slog.Warn("Only chat admins manage webhooks", "userID", userID, "chatID", chatID)
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// managedWebhook returns webhookID if userID may manage it, and ErrNotFound
+// otherwise.
+func (s *webhooksService) managedWebhook(ctx context.Context, userID, webhookID string) (models.Webhook, error) {
+	found, err := s.webhooksRepo.Get(ctx, webhookID)
+	if err != nil {
+		slog.Error("Error getting webhook", "error", err)
+		return models.Webhook{}, err
+	}
+
+	if found.ID == "" {
+		return models.Webhook{}, ErrNotFound
+	}
+
+	if err := s.authorize(ctx, userID, found.ChatID, found.BotID); err != nil {
+		return models.Webhook{}, err
+	}
+
+	return found, nil
+}
+
+// truncate cuts s to at most n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+// validWebhookURL reports whether raw is an absolute http or https URL that
+// does not name a non-public address outright. Hostnames are checked again
+// when the sender connects, since they can resolve to anything.
+func validWebhookURL(raw string) bool {
+	target, err := url.Parse(raw)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return webhook.IsPublicAddr(addr)
+	}
+
+	return true
+}
+
+// messageEventData is the data of message events.
+func messageEventData(msg models.Message) map[string]any {
+	senderUsername := ""
+	if msg.User != nil {
+		senderUsername = msg.User.Username
+	}
+
+	return map[string]any{
+		"message": map[string]any{
+			"id":              msg.ID,
+			"chat_id":         msg.ChatID,
+			"sender_id":       msg.UserID,
+			"sender_username": senderUsername,
+			"content":         msg.Body,
+			"kind":            msg.Kind,
+			"created_at":      msg.CreatedAt,
+		},
+	}
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/internal/models"
+	"github.com/brenocoelho/messaging-app-go/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver is a local endpoint that records the requests it gets and
+// answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiverURL is where webhooks of the receiver point. Its own URL is
+// on loopback, which webhooks may not call, so requests reach it through
+// sender instead.
+const webhookReceiverURL = "http://hooks.example.com/receive"
+
+func newWebhookReceiver(t *testing.T) *webhookReceiver {
+	receiver := &webhookReceiver{status: http.StatusOK}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, receivedWebhook{header: r.Header, body: body})
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+// sender returns a Sender that connects to the receiver whatever the URL.
+func (r *webhookReceiver) sender(clock func() time.Time) webhook.Sender {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, r.Listener.Addr().String())
+		},
+	}
+	return webhook.NewSender(&http.Client{Transport: transport}, clock)
+}
+
+func (r *webhookReceiver) respondWith(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.requests)
+}
+
+type testWebhooks struct {
+	service   *webhooksService
+	repo      *fakeWebhooksRepository
+	chatsRepo *fakeChatsRepository
+	now       *time.Time
+}
+
+func newTestWebhooksService() testWebhooks {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "chat1", Kind: models.ChatKindChat},
+	}, map[string]models.ChatRole{"alice": models.ChatRoleAdmin, "bob": models.ChatRoleMember, "bot1": models.ChatRoleMember})
+
+	usersRepo := newFakeUsersRepository(
+		models.User{ID: "alice", Username: "alice"},
+		models.User{ID: "bob", Username: "bob"},
+		models.User{ID: "bot1", Username: "bot1", BotOwnerID: "bob"},
+	)
+
+	repo := newFakeWebhooksRepository(chatsRepo, clock)
+	service := NewWebhooksService(repo, usersRepo, chatsRepo, WebhookConfig{
+		MaxAttempts: 3,
+		BackoffBase: time.Minute,
+		Clock:       clock,
+	}).(*webhooksService)
+
+	return testWebhooks{service: service, repo: repo, chatsRepo: chatsRepo, now: &now}
+}
+
+func TestWebhooksService_CreateWebhook(t *testing.T) {
+	tw := newTestWebhooksService()
+	ctx := context.Background()
+
+	resp, err := tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "alice",
+		ChatID: "chat1",
+		URL:    "https://example.com/hook",
+		Events: []string{models.WebhookEventMessageCreated, models.WebhookEventMemberJoined, models.WebhookEventMessageCreated},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Secret, WebhookSecretPrefix))
+	assert.Equal(t, resp.Secret, tw.repo.webhooks[resp.Webhook.ID].Secret)
+	assert.Equal(t, []string{models.WebhookEventMemberJoined, models.WebhookEventMessageCreated}, resp.Webhook.Events)
+
+	// Bot webhooks belong to the bot's owner.
+	_, err = tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "bob",
+		BotID:  "bot1",
+		URL:    "http://hooks.example.com:9000/hook",
+		Events: []string{models.WebhookEventMessageCreated},
+	})
+	require.NoError(t, err)
+
+	webhooks, err := tw.service.ListWebhooks(ctx, models.ListWebhooksRequest{UserID: "bob", BotID: "bot1"})
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+}
+
+func TestWebhooksService_CreateWebhookValidates(t *testing.T) {
+	tw := newTestWebhooksService()
+	ctx := context.Background()
+
+	valid := models.CreateWebhookRequest{
+		UserID: "alice",
+		ChatID: "chat1",
+		URL:    "https://example.com/hook",
+		Events: []string{models.WebhookEventMessageCreated},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(req *models.CreateWebhookRequest)
+		want   error
+	}{
+		{"member", func(req *models.CreateWebhookRequest) { req.UserID = "bob" }, ErrPermissionDenied},
+		{"outsider", func(req *models.CreateWebhookRequest) { req.UserID = "carol" }, ErrNotFound},
+		{"bot of another user", func(req *models.CreateWebhookRequest) { req.ChatID, req.BotID = "", "bot1" }, ErrNotFound},
+		{"chat and bot", func(req *models.CreateWebhookRequest) { req.BotID = "bot1" }, ErrNotFound},
+		{"not a URL", func(req *models.CreateWebhookRequest) { req.URL = "example.com/hook" }, ErrInvalidWebhookURL},
+		{"other scheme", func(req *models.CreateWebhookRequest) { req.URL = "ftp://example.com/hook" }, ErrInvalidWebhookURL},
+		{"localhost", func(req *models.CreateWebhookRequest) { req.URL = "http://localhost:9000/hook" }, ErrInvalidWebhookURL},
+		{"loopback", func(req *models.CreateWebhookRequest) { req.URL = "http://127.0.0.1/hook" }, ErrInvalidWebhookURL},
+		{"private", func(req *models.CreateWebhookRequest) { req.URL = "http://10.0.0.5/hook" }, ErrInvalidWebhookURL},
+		{"metadata", func(req *models.CreateWebhookRequest) { req.URL = "http://169.254.169.254/latest/meta-data" }, ErrInvalidWebhookURL},
+		{"IPv6 loopback", func(req *models.CreateWebhookRequest) { req.URL = "http://[::1]/hook" }, ErrInvalidWebhookURL},
+		{"no events", func(req *models.CreateWebhookRequest) { req.Events = nil }, ErrInvalidEvent},
+		{"unknown event", func(req *models.CreateWebhookRequest) { req.Events = []string{"message.deleted"} }, ErrInvalidEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			_, err := tw.service.CreateWebhook(ctx, req)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	for range models.MaxWebhooksPerTarget {
+		_, err := tw.service.CreateWebhook(ctx, valid)
+		require.NoError(t, err)
+	}
+	_, err := tw.service.CreateWebhook(ctx, valid)
+	assert.ErrorIs(t, err, ErrLimitReached)
+}
+
+func TestWebhooksService_PublishQueuesSubscribedWebhooks(t *testing.T) {
+	tw := newTestWebhooksService()
+	ctx := context.Background()
+
+	chatHook, err := tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "alice", ChatID: "chat1", URL: "https://example.com/chat", Events: []string{models.WebhookEventMessageCreated},
+	})
+	require.NoError(t, err)
+	botHook, err := tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "bob", BotID: "bot1", URL: "https://example.com/bot", Events: []string{models.WebhookEventMessageCreated},
+	})
+	require.NoError(t, err)
+	_, err = tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "alice", ChatID: "chat1", URL: "https://example.com/members", Events: []string{models.WebhookEventMemberJoined},
+	})
+	require.NoError(t, err)
+
+	tw.service.Publish(ctx, models.WebhookEvent{
+		Type:    models.WebhookEventMessageCreated,
+		ChatID:  "chat1",
+		ActorID: "alice",
+		Data:    map[string]any{"message": map[string]any{"id": "msg1"}},
+	})
+
+	require.Len(t, tw.repo.deliveries, 2)
+	assert.ElementsMatch(t, []string{chatHook.Webhook.ID, botHook.Webhook.ID},
+		[]string{tw.repo.deliveries[0].WebhookID, tw.repo.deliveries[1].WebhookID})
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(tw.repo.deliveries[0].Payload, &payload))
+	assert.NotEmpty(t, payload["id"])
+	assert.Equal(t, models.WebhookEventMessageCreated, payload["event"])
+	assert.Equal(t, "chat1", payload["chat_id"])
+	assert.Equal(t, "alice", payload["actor_id"])
+	assert.Equal(t, "2026-10-18T12:00:00Z", payload["created_at"])
+	assert.Equal(t, map[string]any{"message": map[string]any{"id": "msg1"}}, payload["data"])
+
+	// A bot's own messages are not posted to its webhooks.
+	tw.service.Publish(ctx, models.WebhookEvent{Type: models.WebhookEventMessageCreated, ChatID: "chat1", ActorID: "bot1"})
+	require.Len(t, tw.repo.deliveries, 3)
+	assert.Equal(t, chatHook.Webhook.ID, tw.repo.deliveries[2].WebhookID)
+}
+
+func TestWebhooksService_DeliverDue(t *testing.T) {
+	tw := newTestWebhooksService()
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	tw.service.cfg.Sender = receiver.sender(tw.service.cfg.Clock)
+
+	created, err := tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "alice", ChatID: "chat1", URL: webhookReceiverURL, Events: []string{models.WebhookEventMemberJoined},
+	})
+	require.NoError(t, err)
+
+	tw.service.Publish(ctx, models.WebhookEvent{Type: models.WebhookEventMemberJoined, ChatID: "chat1", ActorID: "bob"})
+	deliveryID := tw.repo.deliveries[0].ID
+
+	n, err := tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	requests := receiver.received()
+	require.Len(t, requests, 1)
+	assert.Equal(t, models.WebhookEventMemberJoined, requests[0].header.Get(webhook.EventHeader))
+	assert.Equal(t, deliveryID, requests[0].header.Get(webhook.DeliveryHeader))
+	assert.NoError(t, webhook.Verify(created.Secret, requests[0].header.Get(webhook.SignatureHeader), requests[0].body, 0, *tw.now))
+	assert.JSONEq(t, string(tw.repo.deliveries[0].Payload), string(requests[0].body))
+
+	delivery := tw.repo.delivery(deliveryID)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, int32(1), delivery.Attempts)
+	assert.Equal(t, int32(http.StatusOK), delivery.LastStatusCode)
+	require.NotNil(t, delivery.DeliveredAt)
+
+	// Nothing is due anymore.
+	n, err = tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestWebhooksService_DeliverDueRetriesWithBackoff(t *testing.T) {
+	tw := newTestWebhooksService()
+	ctx := context.Background()
+	receiver := newWebhookReceiver(t)
+	tw.service.cfg.Sender = receiver.sender(tw.service.cfg.Clock)
+	receiver.respondWith(http.StatusServiceUnavailable)
+
+	_, err := tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "alice", ChatID: "chat1", URL: webhookReceiverURL, Events: []string{models.WebhookEventMemberJoined},
+	})
+	require.NoError(t, err)
+	tw.service.Publish(ctx, models.WebhookEvent{Type: models.WebhookEventMemberJoined, ChatID: "chat1", ActorID: "bob"})
+	deliveryID := tw.repo.deliveries[0].ID
+
+	_, err = tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	delivery := tw.repo.delivery(deliveryID)
+	assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, int32(1), delivery.Attempts)
+	assert.Equal(t, int32(http.StatusServiceUnavailable), delivery.LastStatusCode)
+	assert.Contains(t, delivery.LastError, "503")
+	assert.Equal(t, tw.now.Add(time.Minute), delivery.NextAttemptAt)
+
+	// Not due before the backoff has passed.
+	n, err := tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	*tw.now = tw.now.Add(time.Minute)
+	_, err = tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	delivery = tw.repo.delivery(deliveryID)
+	assert.Equal(t, int32(2), delivery.Attempts)
+	assert.Equal(t, tw.now.Add(2*time.Minute), delivery.NextAttemptAt)
+
+	// The last attempt moves the delivery to the dead-letter state.
+	*tw.now = tw.now.Add(2 * time.Minute)
+	_, err = tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	delivery = tw.repo.delivery(deliveryID)
+	assert.Equal(t, models.WebhookDeliveryDead, delivery.Status)
+	assert.Equal(t, int32(3), delivery.Attempts)
+	assert.Nil(t, delivery.DeliveredAt)
+	assert.Len(t, receiver.received(), 3)
+
+	*tw.now = tw.now.Add(time.Hour)
+	n, err = tw.service.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestWebhooksService_ListWebhookDeliveries(t *testing.T) {
+	tw := newTestWebhooksService()
+	ctx := context.Background()
+
+	created, err := tw.service.CreateWebhook(ctx, models.CreateWebhookRequest{
+		UserID: "alice", ChatID: "chat1", URL: "https://example.com/hook", Events: []string{models.WebhookEventChatUpdated},
+	})
+	require.NoError(t, err)
+	tw.service.Publish(ctx, models.WebhookEvent{Type: models.WebhookEventChatUpdated, ChatID: "chat1", ActorID: "alice"})
+
+	resp, err := tw.service.ListWebhookDeliveries(ctx, models.ListWebhookDeliveriesRequest{UserID: "alice", WebhookID: created.Webhook.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), resp.Total)
+
+	_, err = tw.service.ListWebhookDeliveries(ctx, models.ListWebhookDeliveriesRequest{UserID: "bob", WebhookID: created.Webhook.ID})
+	assert.ErrorIs(t, err, ErrPermissionDenied)
+
+	require.NoError(t, tw.service.DeleteWebhook(ctx, "alice", created.Webhook.ID))
+	_, err = tw.service.ListWebhookDeliveries(ctx, models.ListWebhookDeliveriesRequest{UserID: "alice", WebhookID: created.Webhook.ID})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestChatsService_PublishesMemberEvents(t *testing.T) {
+	chatsRepo := newFakeChatsRepository()
+	chatsRepo.addChat(models.ChatWithLastMessage{
+		Chat: models.Chat{ID: "news", Kind: models.ChatKindChannel, Visibility: models.ChatVisibilityPublic},
+	}, map[string]models.ChatRole{"admin": models.ChatRoleAdmin})
+	publisher := &fakeWebhookPublisher{}
+
+	service := NewChatsService(chatsRepo, newFakeUsersRepository(), &fakeMessagesRepository{}, newFakeModerationRepository(chatsRepo), nil, EmailVerificationPolicy{}, publisher)
+	ctx := context.Background()
+
+	require.NoError(t, service.JoinChannel(ctx, models.JoinChannelRequest{ChatID: "news", UserID: "reader"}))
+	// Joining again is a no-op and no event.
+	require.NoError(t, service.JoinChannel(ctx, models.JoinChannelRequest{ChatID: "news", UserID: "reader"}))
+	require.NoError(t, service.LeaveChannel(ctx, models.LeaveChannelRequest{ChatID: "news", UserID: "reader"}))
+
+	require.Len(t, publisher.events, 2)
+	assert.Equal(t, models.WebhookEventMemberJoined, publisher.events[0].Type)
+	assert.Equal(t, models.WebhookEventMemberLeft, publisher.events[1].Type)
+	for _, event := range publisher.events {
+		assert.Equal(t, "news", event.ChatID)
+		assert.Equal(t, map[string]any{"user_id": "reader"}, event.Data)
+	}
+}
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE notification_level_enum AS ENUM ('ALL', 'MENTIONS', 'NONE');
+
+ALTER TABLE users_chats
+    ADD COLUMN muted_until TIMESTAMPTZ,
+    ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN pin_order INTEGER,
+    ADD COLUMN notification_level notification_level_enum NOT NULL DEFAULT 'ALL';
+
+CREATE INDEX idx_users_chats_user_archived ON users_chats (user_id, archived);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_users_chats_user_archived;
+
+ALTER TABLE users_chats
+    DROP COLUMN IF EXISTS notification_level,
+    DROP COLUMN IF EXISTS pin_order,
+    DROP COLUMN IF EXISTS archived,
+    DROP COLUMN IF EXISTS muted_until;
+
+DROP TYPE IF EXISTS notification_level_enum;
+
+-- +goose StatementEnd
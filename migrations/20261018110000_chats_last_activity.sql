@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chats
+    ADD COLUMN last_message_id CHAR(26),
+    ADD COLUMN last_activity_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD CONSTRAINT fk_chats_last_message_id
+        FOREIGN KEY (last_message_id) REFERENCES messages(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_chats_last_activity_at ON chats (last_activity_at DESC, id DESC);
+
+-- The inbox reads every membership of one user and sorts them by pin and by
+-- the activity of the chat. Covering the columns it selects lets it read the
+-- memberships from the index alone; activity stays on chats, so a message
+-- does not have to update every member.
+CREATE INDEX idx_users_chats_inbox
+    ON users_chats (user_id) INCLUDE (chat_id, pin_order, archived, muted_until, notification_level);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_users_chats_inbox;
+DROP INDEX IF EXISTS idx_chats_last_activity_at;
+
+ALTER TABLE chats
+    DROP CONSTRAINT IF EXISTS fk_chats_last_message_id,
+    DROP COLUMN IF EXISTS last_activity_at,
+    DROP COLUMN IF EXISTS last_message_id;
+
+-- +goose StatementEnd
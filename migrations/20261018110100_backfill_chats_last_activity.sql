@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE chats DISABLE TRIGGER set_chats_updated_at;
+
+UPDATE chats c
+SET last_activity_at = c.created_at
+WHERE c.last_message_id IS NULL;
+
+UPDATE chats c
+SET last_message_id = m.id,
+    last_activity_at = m.created_at
+FROM (
+    SELECT DISTINCT ON (chat_id) id, chat_id, created_at
+    FROM messages
+    ORDER BY chat_id, created_at DESC, id DESC
+) m
+WHERE m.chat_id = c.id;
+
+ALTER TABLE chats ENABLE TRIGGER set_chats_updated_at;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- Nothing to undo: the columns are dropped by the previous migration.
+SELECT 1;
+
+-- +goose StatementEnd
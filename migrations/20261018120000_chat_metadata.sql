@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE chat_role_enum AS ENUM ('ADMIN', 'MEMBER');
+CREATE TYPE message_kind_enum AS ENUM ('USER', 'SYSTEM');
+
+ALTER TABLE chats
+    ADD COLUMN description VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_attachment_id VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE users_chats
+    ADD COLUMN role chat_role_enum NOT NULL DEFAULT 'MEMBER';
+
+-- The creator of an existing chat is not recorded, so the earliest member
+-- becomes its admin.
+UPDATE users_chats uc
+SET role = 'ADMIN'
+FROM (
+    SELECT DISTINCT ON (chat_id) id
+    FROM users_chats
+    ORDER BY chat_id, created_at, id
+) first_member
+WHERE uc.id = first_member.id;
+
+ALTER TABLE messages
+    ADD COLUMN kind message_kind_enum NOT NULL DEFAULT 'USER';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE messages DROP COLUMN IF EXISTS kind;
+ALTER TABLE users_chats DROP COLUMN IF EXISTS role;
+ALTER TABLE chats
+    DROP COLUMN IF EXISTS avatar_attachment_id,
+    DROP COLUMN IF EXISTS description;
+
+DROP TYPE IF EXISTS message_kind_enum;
+DROP TYPE IF EXISTS chat_role_enum;
+
+-- +goose StatementEnd
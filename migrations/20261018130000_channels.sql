@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE chat_kind_enum AS ENUM ('CHAT', 'CHANNEL');
+CREATE TYPE chat_visibility_enum AS ENUM ('PUBLIC', 'PRIVATE');
+
+ALTER TABLE chats
+    ADD COLUMN kind chat_kind_enum NOT NULL DEFAULT 'CHAT',
+    ADD COLUMN visibility chat_visibility_enum NOT NULL DEFAULT 'PRIVATE',
+    ADD COLUMN member_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE chats DISABLE TRIGGER set_chats_updated_at;
+
+UPDATE chats c
+SET member_count = counts.member_count
+FROM (
+    SELECT chat_id, COUNT(*) AS member_count
+    FROM users_chats
+    GROUP BY chat_id
+) counts
+WHERE counts.chat_id = c.id;
+
+ALTER TABLE chats ENABLE TRIGGER set_chats_updated_at;
+
+-- member_count is a cache of the users_chats rows of a chat, so that large
+-- channels don't need a COUNT on every read.
+CREATE OR REPLACE FUNCTION update_chat_member_count()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    UPDATE chats SET member_count = member_count + 1 WHERE id = NEW.chat_id;
+  ELSIF TG_OP = 'DELETE' THEN
+    UPDATE chats SET member_count = member_count - 1 WHERE id = OLD.chat_id;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER update_users_chats_member_count
+AFTER INSERT OR DELETE ON users_chats
+FOR EACH ROW
+EXECUTE FUNCTION update_chat_member_count();
+
+CREATE INDEX idx_chats_public_channels ON chats (member_count DESC, id)
+WHERE kind = 'CHANNEL' AND visibility = 'PUBLIC';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_chats_public_channels;
+DROP TRIGGER IF EXISTS update_users_chats_member_count ON users_chats;
+DROP FUNCTION IF EXISTS update_chat_member_count;
+
+ALTER TABLE chats
+    DROP COLUMN IF EXISTS member_count,
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS kind;
+
+DROP TYPE IF EXISTS chat_visibility_enum;
+DROP TYPE IF EXISTS chat_kind_enum;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN avatar_attachment_id VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Usernames were never unique; keep the oldest account's name and suffix
+-- the others before enforcing it.
+UPDATE users u
+SET username = LEFT(u.username, 93) || '_' || RIGHT(u.id, 6)
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(username) ORDER BY created_at, id) AS position
+    FROM users
+) duplicates
+WHERE duplicates.id = u.id AND duplicates.position > 1;
+
+CREATE UNIQUE INDEX users_username_key ON users (LOWER(username));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS users_username_key;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS avatar_attachment_id,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS display_name;
+
+-- +goose StatementEnd
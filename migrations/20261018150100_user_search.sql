@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE users
+    ADD COLUMN discoverable BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Trigram GIN indexes serve both the prefix LIKE and the % similarity match.
+CREATE INDEX idx_users_username_trgm ON users USING GIN (LOWER(username) gin_trgm_ops);
+CREATE INDEX idx_users_display_name_trgm ON users USING GIN (LOWER(display_name) gin_trgm_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_users_display_name_trgm;
+DROP INDEX IF EXISTS idx_users_username_trgm;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS discoverable;
+
+-- +goose StatementEnd
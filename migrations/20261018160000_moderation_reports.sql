@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE report_reason_enum AS ENUM ('SPAM', 'HARASSMENT', 'INAPPROPRIATE', 'OTHER');
+CREATE TYPE report_status_enum AS ENUM ('OPEN', 'RESOLVED', 'DISMISSED');
+
+CREATE TABLE moderation_reports (
+    id CHAR(26) PRIMARY KEY,
+    reporter_id CHAR(26) NOT NULL,
+    reported_user_id CHAR(26) NOT NULL,
+    chat_id CHAR(26),
+    message_id CHAR(26),
+    reason report_reason_enum NOT NULL,
+    details VARCHAR(1000) NOT NULL DEFAULT '',
+    status report_status_enum NOT NULL DEFAULT 'OPEN',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (reported_user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (chat_id) REFERENCES chats(id) ON DELETE SET NULL,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE SET NULL
+);
+
+-- Moderators work the queue oldest first.
+CREATE INDEX idx_moderation_reports_open ON moderation_reports (created_at) WHERE status = 'OPEN';
+CREATE INDEX idx_moderation_reports_reported_user_id ON moderation_reports (reported_user_id);
+
+CREATE TRIGGER set_moderation_reports_updated_at
+BEFORE UPDATE ON moderation_reports
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TRIGGER IF EXISTS set_moderation_reports_updated_at ON moderation_reports;
+DROP TABLE IF EXISTS moderation_reports;
+DROP TYPE IF EXISTS report_status_enum;
+DROP TYPE IF EXISTS report_reason_enum;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Opaque refresh tokens, stored as SHA-256 hashes. Every refresh uses up the
+-- presented token and issues a new one in the same family; presenting a
+-- used token again revokes the whole family.
+CREATE TABLE refresh_tokens (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    family_id CHAR(26) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS refresh_tokens;
+
+-- +goose StatementEnd
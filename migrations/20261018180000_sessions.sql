@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per login. The session ID doubles as the family_id of the refresh
+-- tokens issued for it, so revoking a session revokes its refresh tokens.
+CREATE TABLE sessions (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    device_name VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user_id_active ON sessions (user_id, last_used_at DESC) WHERE revoked_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS sessions;
+
+-- +goose StatementEnd
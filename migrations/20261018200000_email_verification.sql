@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Accounts created before verification existed stay unverified; their owners
+-- can ask for a new verification email.
+ALTER TABLE users
+    ADD COLUMN email_verified_at TIMESTAMPTZ;
+
+-- Single-use email verification tokens, stored as SHA-256 hashes.
+CREATE TABLE email_verification_tokens (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_email_verification_tokens_user_id ON email_verification_tokens (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS email_verification_tokens;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified_at;
+
+-- +goose StatementEnd
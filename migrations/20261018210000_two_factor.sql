@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- TOTP secrets are encrypted by the application. A row without confirmed_at
+-- is an enrollment that has not been confirmed with a code yet.
+-- last_used_step is the time step of the last accepted code, so a code
+-- cannot be used twice.
+CREATE TABLE user_totp (
+    user_id CHAR(26) PRIMARY KEY,
+    secret_ciphertext BYTEA NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- One-time recovery codes, stored as SHA-256 hashes.
+CREATE TABLE totp_recovery_codes (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (user_id, code_hash)
+);
+
+-- Logins that passed the password check and wait for a second factor.
+CREATE TABLE login_challenges (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    device_name VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_login_challenges_user_id ON login_challenges (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS totp_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+
+-- +goose StatementEnd
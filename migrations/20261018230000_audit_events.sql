@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Security relevant events, such as login lockouts. Events outlive the users
+-- they mention, so user_id has no foreign key.
+CREATE TABLE audit_events (
+    id CHAR(26) PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    user_id CHAR(26),
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_action_created_at ON audit_events (action, created_at);
+CREATE INDEX idx_audit_events_user_id ON audit_events (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS audit_events;
+
+-- +goose StatementEnd
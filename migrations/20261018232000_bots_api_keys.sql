@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Bots are users owned by a human. They have no password and authenticate
+-- with API keys only; their email is a placeholder nobody receives.
+ALTER TABLE users
+    ADD COLUMN bot_owner_id CHAR(26) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_users_bot_owner_id ON users (bot_owner_id) WHERE bot_owner_id IS NOT NULL;
+
+-- Long-lived API keys of bots, stored as SHA-256 hashes. A key may only do
+-- what its scopes allow, and only in chat_ids when that is not empty.
+CREATE TABLE api_keys (
+    id CHAR(26) PRIMARY KEY,
+    bot_id CHAR(26) NOT NULL,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    chat_ids TEXT[] NOT NULL DEFAULT '{}',
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (bot_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_api_keys_bot_id ON api_keys (bot_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS api_keys;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS bot_owner_id;
+
+-- +goose StatementEnd
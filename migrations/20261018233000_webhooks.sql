@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Outgoing webhooks, for one chat or for every chat a bot is in. The secret
+-- signs the requests, so it is kept in clear.
+CREATE TABLE webhooks (
+    id CHAR(26) PRIMARY KEY,
+    owner_id CHAR(26) NOT NULL,
+    chat_id CHAR(26),
+    bot_id CHAR(26),
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    events TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (chat_id) REFERENCES chats(id) ON DELETE CASCADE,
+    FOREIGN KEY (bot_id) REFERENCES users(id) ON DELETE CASCADE,
+
+    CHECK ((chat_id IS NULL) <> (bot_id IS NULL))
+);
+
+CREATE INDEX idx_webhooks_chat_id ON webhooks (chat_id) WHERE chat_id IS NOT NULL;
+CREATE INDEX idx_webhooks_bot_id ON webhooks (bot_id) WHERE bot_id IS NOT NULL;
+
+CREATE TYPE webhook_delivery_status_enum AS ENUM ('PENDING', 'SUCCEEDED', 'DEAD');
+
+-- The delivery queue. Workers claim due PENDING rows with
+-- FOR UPDATE SKIP LOCKED; rows that keep failing end up DEAD.
+CREATE TABLE webhook_deliveries (
+    id CHAR(26) PRIMARY KEY,
+    webhook_id CHAR(26) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status webhook_delivery_status_enum NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMPTZ,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TYPE IF EXISTS webhook_delivery_status_enum;
+DROP TABLE IF EXISTS webhooks;
+
+-- +goose StatementEnd
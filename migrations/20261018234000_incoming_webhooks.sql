@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Incoming webhooks post into a chat as user_id, a bot created for the
+-- webhook and owned by the admin who made it. Only the hash of the token in
+-- the webhook URL is stored.
+CREATE TABLE incoming_webhooks (
+    id CHAR(26) PRIMARY KEY,
+    chat_id CHAR(26) NOT NULL,
+    user_id CHAR(26) NOT NULL,
+    created_by CHAR(26) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (chat_id) REFERENCES chats(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incoming_webhooks_chat_id ON incoming_webhooks (chat_id);
+CREATE INDEX idx_incoming_webhooks_user_id ON incoming_webhooks (user_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS incoming_webhooks;
+
+-- +goose StatementEnd
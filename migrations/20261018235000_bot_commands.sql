@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Slash commands of bots. Invoking one posts the invocation to url, signed
+-- with secret, and the response is the reply. A command is available in the
+-- chats its bot is a member of.
+CREATE TABLE bot_commands (
+    id CHAR(26) PRIMARY KEY,
+    bot_id CHAR(26) NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    description VARCHAR(200) NOT NULL DEFAULT '',
+    usage VARCHAR(200) NOT NULL DEFAULT '',
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (bot_id) REFERENCES users(id) ON DELETE CASCADE,
+    UNIQUE (bot_id, name)
+);
+
+CREATE INDEX idx_bot_commands_name ON bot_commands (name);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS bot_commands;
+
+-- +goose StatementEnd
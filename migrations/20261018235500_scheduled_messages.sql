@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE scheduled_message_status_enum AS ENUM ('PENDING', 'SENT', 'CANCELED', 'SKIPPED', 'FAILED');
+
+-- Messages to be sent at send_at. Schedulers claim due PENDING rows with
+-- FOR UPDATE SKIP LOCKED and hold them until claimed_until, so a row is sent
+-- by one scheduler; a row whose scheduler stopped midway is claimed again
+-- after that. Failed attempts are retried at next_attempt_at.
+CREATE TABLE scheduled_messages (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    chat_id CHAR(26) NOT NULL,
+    content TEXT NOT NULL,
+    send_at TIMESTAMPTZ NOT NULL,
+    status scheduled_message_status_enum NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    claimed_until TIMESTAMPTZ,
+    message_id CHAR(26),
+    last_error TEXT NOT NULL DEFAULT '',
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (chat_id) REFERENCES chats(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scheduled_messages_due ON scheduled_messages (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX idx_scheduled_messages_user_id ON scheduled_messages (user_id, send_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS scheduled_messages;
+DROP TYPE IF EXISTS scheduled_message_status_enum;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Failed deliveries used to keep the start of the response body, which let a
+-- webhook aimed at another service read its responses. Only the status is
+-- kept now.
+UPDATE webhook_deliveries
+SET last_error = split_part(last_error, ': ', 1)
+WHERE last_error LIKE 'webhook returned %: %';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- The removed bodies cannot be restored.
+SELECT 1;
+
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+package backoff
+
+import "time"
+
+// Exponential returns base doubled n times, capped at limit. A zero limit
+// means no cap.
+func Exponential(base, limit time.Duration, n int) time.Duration {
+	// Doubling stops well before the duration overflows.
+	delay := base << min(max(n, 0), 30)
+	if limit > 0 && (delay > limit || delay < base) {
+		return limit
+	}
+	return delay
+}
@@ -0,0 +1,30 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponential(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit time.Duration
+		n     int
+		want  time.Duration
+	}{
+		{name: "first", limit: time.Minute, n: 0, want: time.Second},
+		{name: "doubles", limit: time.Minute, n: 3, want: 8 * time.Second},
+		{name: "capped", limit: time.Minute, n: 6, want: time.Minute},
+		{name: "no overflow", limit: time.Minute, n: 1000, want: time.Minute},
+		{name: "no cap", n: 10, want: 1024 * time.Second},
+		{name: "negative", limit: time.Minute, n: -1, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Exponential(time.Second, tt.limit, tt.n))
+		})
+	}
+}
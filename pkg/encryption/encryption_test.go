@@ -0,0 +1,54 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{1}, KeySize))
+	require.NoError(t, err)
+
+	first, err := c.Encrypt([]byte("secret"))
+	require.NoError(t, err)
+	second, err := c.Encrypt([]byte("secret"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "every encryption uses a new nonce")
+	assert.NotContains(t, string(first), "secret")
+
+	plaintext, err := c.Decrypt(first)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), plaintext)
+}
+
+func TestCipher_RejectsTamperingAndWrongKey(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{1}, KeySize))
+	require.NoError(t, err)
+	other, err := NewCipher(bytes.Repeat([]byte{2}, KeySize))
+	require.NoError(t, err)
+
+	ciphertext, err := c.Encrypt([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	ciphertext[len(ciphertext)-1] ^= 1
+	_, err = c.Decrypt(ciphertext)
+	assert.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = c.Decrypt([]byte("short"))
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestNewCipher_KeySize(t *testing.T) {
+	_, err := NewCipher([]byte("too short"))
+	assert.Error(t, err)
+
+	key, err := ParseKey("AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=")
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+}
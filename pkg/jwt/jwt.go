@@ -2,10 +2,9 @@ package jwt
 
 import (
 	"crypto/rand"
-	"encoding/base64"
 	"fmt"
 	"log/slog"
-	"os"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,22 +13,79 @@ import (
 type Service interface {
 	GenerateToken(user User) (string, error)
 	ValidateToken(tokenString string) (*Claims, error)
-	RefreshToken(tokenString string) (string, error)
-	GetSecretKey() []byte
+	AccessTokenTTL() time.Duration
+	JWKS() JWKS
+	ReloadKeys() error
+}
+
+// DefaultAccessTokenTTL keeps access tokens short-lived; clients renew them
+// with a refresh token.
+const DefaultAccessTokenTTL = 15 * time.Minute
+
+const (
+	DefaultIssuer   = "messaging-app"
+	DefaultAudience = "messaging-app"
+	// DefaultLeeway absorbs clock skew between the issuer and verifiers.
+	DefaultLeeway = 30 * time.Second
+)
+
+type Config struct {
+	AccessTokenTTL time.Duration
+	// KeyDir holds the signing and verification keys, one <kid>.pem file each.
+	KeyDir string
+	// SigningKeyID selects the signing key. By default the private key with
+	// the greatest ID signs.
+	SigningKeyID string
+	// RotationOverlap is how long a key removed from KeyDir keeps verifying
+	// tokens. Defaults to AccessTokenTTL, so tokens it signed can expire.
+	// The overlap only lives in memory, so a service started after the
+	// removal does not know the key; retired keys belong on disk, as public
+	// keys, until their tokens expired.
+	RotationOverlap time.Duration
+	// Keys are used in addition to the keys in KeyDir.
+	Keys []Key
+
+	// Issuer and Audience are set on issued tokens and required on
+	// validated ones.
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew allowed when checking exp, nbf and iat.
+	Leeway time.Duration
+	// Clock returns the current time; tests replace it.
+	Clock func() time.Time
 }
 
 type User struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	// Generation is the user's current token generation. Tokens issued with
+	// an older generation are rejected after a log-out-everywhere.
+	Generation int64 `json:"generation"`
+	// SessionID ties the token to the login session it was issued for.
+	SessionID string `json:"session_id"`
+	// Admin lets the token call admin-only methods.
+	Admin bool `json:"admin"`
 }
 
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Exp      int64  `json:"exp"`
-	Iat      int64  `json:"iat"`
+	UserID   string           `json:"user_id"`
+	Username string           `json:"username"`
+	Email    string           `json:"email"`
+	Iss      string           `json:"iss"`
+	Aud      jwt.ClaimStrings `json:"aud"`
+	Exp      int64            `json:"exp"`
+	Nbf      int64            `json:"nbf"`
+	Iat      int64            `json:"iat"`
+	Jti      string           `json:"jti"`
+	Gen      int64            `json:"gen"`
+	Sid      string           `json:"sid"`
+	Adm      bool             `json:"adm,omitempty"`
+	// Scopes restrict what the token may do. Login sessions have none and
+	// are not restricted.
+	Scopes []string `json:"scopes,omitempty"`
+	// Chats restricts the token to these chats, if set.
+	Chats []string `json:"chats,omitempty"`
 }
 
 func (c Claims) GetExpirationTime() (*jwt.NumericDate, error) {
@@ -41,7 +97,11 @@ func (c Claims) GetExpirationTime() (*jwt.NumericDate, error) {
 }
 
 func (c Claims) GetNotBefore() (*jwt.NumericDate, error) {
-	return nil, nil
+	if c.Nbf == 0 {
+		return nil, nil
+	}
+	t := time.Unix(c.Nbf, 0)
+	return jwt.NewNumericDate(t), nil
 }
 
 func (c Claims) GetIssuedAt() (*jwt.NumericDate, error) {
@@ -53,7 +113,7 @@ func (c Claims) GetIssuedAt() (*jwt.NumericDate, error) {
 }
 
 func (c Claims) GetIssuer() (string, error) {
-	return "", nil
+	return c.Iss, nil
 }
 
 func (c Claims) GetSubject() (string, error) {
@@ -61,52 +121,120 @@ func (c Claims) GetSubject() (string, error) {
 }
 
 func (c Claims) GetAudience() (jwt.ClaimStrings, error) {
-	return nil, nil
+	return c.Aud, nil
 }
 
 type jwtService struct {
-	secretKey []byte
+	cfg            Config
+	accessTokenTTL time.Duration
+	parser         *jwt.Parser
+
+	mu   sync.RWMutex
+	keys *keySet
 }
 
-func NewService() Service {
-	secretKey := getSecretKey()
-	return &jwtService{
-		secretKey: secretKey,
+// NewServiceWithConfig loads the configured keys. It fails when there is no
+// private key to sign with.
+func NewServiceWithConfig(cfg Config) (Service, error) {
+	if cfg.AccessTokenTTL <= 0 {
+		cfg.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if cfg.RotationOverlap <= 0 {
+		cfg.RotationOverlap = cfg.AccessTokenTTL
+	}
+	if cfg.Issuer == "" {
+		cfg.Issuer = DefaultIssuer
+	}
+	if cfg.Audience == "" {
+		cfg.Audience = DefaultAudience
+	}
+	if cfg.Leeway <= 0 {
+		cfg.Leeway = DefaultLeeway
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	s := &jwtService{
+		cfg:            cfg,
+		accessTokenTTL: cfg.AccessTokenTTL,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{AlgorithmEdDSA, AlgorithmRS256}),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithLeeway(cfg.Leeway),
+			jwt.WithTimeFunc(cfg.Clock),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
 	}
+
+	if err := s.ReloadKeys(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
-func getSecretKey() []byte {
-	secretKey := os.Getenv("JWT_SECRET_KEY")
-	if secretKey != "" {
-		return []byte(secretKey)
+// ReloadKeys reads the key directory again. On error the current keys stay
+// in use.
+func (s *jwtService) ReloadKeys() error {
+	keys := append([]Key{}, s.cfg.Keys...)
+	if s.cfg.KeyDir != "" {
+		dirKeys, err := LoadKeyDir(s.cfg.KeyDir)
+		if err != nil {
+			slog.Error("Failed to load JWT keys", "dir", s.cfg.KeyDir, "error", err)
+			return err
+		}
+		keys = append(keys, dirKeys...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	set, err := newKeySet(keys, s.cfg.SigningKeyID, s.keys, s.cfg.RotationOverlap, s.cfg.Clock())
+	if err != nil {
+		slog.Error("Failed to load JWT keys", "dir", s.cfg.KeyDir, "error", err)
+		return err
 	}
 
-	randomBytes := make([]byte, 32)
-	if _, err := rand.Read(randomBytes); err != nil {
-		slog.Error("Failed to generate random secret key", "error", err)
-		return []byte("default_jwt_secret_key_change_in_production")
+	if s.keys == nil || s.keys.signer.ID != set.signer.ID {
+		slog.Info("JWT signing key selected", "kid", set.signer.ID, "alg", set.signer.Algorithm, "keys", len(set.keys))
 	}
+	s.keys = set
 
-	encodedKey := base64.StdEncoding.EncodeToString(randomBytes)
-	slog.Warn("Generated new JWT secret key. Set JWT_SECRET_KEY environment variable for production use", "key", encodedKey[:10]+"...")
+	return nil
+}
 
-	return []byte(encodedKey)
+func (s *jwtService) currentKeys() *keySet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys
 }
 
 func (s *jwtService) GenerateToken(user User) (string, error) {
-	now := time.Now()
-	expirationTime := now.Add(24 * time.Hour)
+	now := s.cfg.Clock()
+	expirationTime := now.Add(s.accessTokenTTL)
 
 	claims := Claims{
 		UserID:   user.ID,
 		Username: user.Username,
 		Email:    user.Email,
+		Iss:      s.cfg.Issuer,
+		Aud:      jwt.ClaimStrings{s.cfg.Audience},
 		Exp:      expirationTime.Unix(),
+		Nbf:      now.Unix(),
 		Iat:      now.Unix(),
+		Jti:      rand.Text(),
+		Gen:      user.Generation,
+		Sid:      user.SessionID,
+		Adm:      user.Admin,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(s.secretKey)
+	signer := s.currentKeys().signer
+	token := jwt.NewWithClaims(signer.signingMethod(), claims)
+	token.Header["kid"] = signer.ID
+	tokenString, err := token.SignedString(signer.Private)
 	if err != nil {
 		slog.Error("Failed to sign JWT token", "error", err, "userID", user.ID)
 		return "", fmt.Errorf("failed to generate token: %w", err)
@@ -117,11 +245,17 @@ func (s *jwtService) GenerateToken(user User) (string, error) {
 }
 
 func (s *jwtService) ValidateToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+	keys := s.currentKeys()
+	token, err := s.parser.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, exists := keys.keys[kid]
+		if !exists {
+			return nil, fmt.Errorf("unknown key ID %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return s.secretKey, nil
+		return key.Public, nil
 	})
 
 	if err != nil {
@@ -138,54 +272,14 @@ func (s *jwtService) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	if time.Unix(claims.Exp, 0).Before(time.Now()) {
-		return nil, fmt.Errorf("token expired")
+	if claims.Jti == "" {
+		return nil, fmt.Errorf("invalid token: missing jti")
 	}
 
 	slog.Info("Validated JWT token", "userID", claims.UserID, "username", claims.Username)
 	return claims, nil
 }
 
-func (s *jwtService) RefreshToken(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return s.secretKey, nil
-	})
-
-	if err != nil {
-		return "", fmt.Errorf("invalid token: %w", err)
-	}
-
-	claims, ok := token.Claims.(*Claims)
-	if !ok {
-		return "", fmt.Errorf("invalid token claims")
-	}
-
-	expirationTime := time.Unix(claims.Exp, 0)
-	if time.Until(expirationTime) > time.Hour {
-		return "", fmt.Errorf("token not yet eligible for refresh")
-	}
-
-	now := time.Now()
-	newExpirationTime := now.Add(24 * time.Hour)
-
-	newClaims := Claims{
-		UserID:   claims.UserID,
-		Username: claims.Username,
-		Email:    claims.Email,
-		Exp:      newExpirationTime.Unix(),
-		Iat:      now.Unix(),
-	}
-
-	newToken := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims)
-	newTokenString, err := newToken.SignedString(s.secretKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate refresh token: %w", err)
-	}
-
-	slog.Info("Refreshed JWT token", "userID", claims.UserID, "newExpiresAt", newExpirationTime)
-	return newTokenString, nil
-}
-
-func (s *jwtService) GetSecretKey() []byte {
-	return s.secretKey
+func (s *jwtService) AccessTokenTTL() time.Duration {
+	return s.accessTokenTTL
 }
@@ -4,21 +4,34 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func newTestService(t *testing.T, cfg Config) Service {
+	t.Helper()
+
+	key, err := GenerateKey("test-key", AlgorithmEdDSA)
+	require.NoError(t, err)
+	cfg.Keys = append(cfg.Keys, key)
+
+	service, err := NewServiceWithConfig(cfg)
+	require.NoError(t, err)
+	return service
+}
+
 func TestNewService(t *testing.T) {
-	service := NewService()
+	service := newTestService(t, Config{})
 	assert.NotNil(t, service)
-	
-	// Test that we can get the secret key
-	secretKey := service.GetSecretKey()
-	assert.NotEmpty(t, secretKey)
+
+	// Without a private key there is nothing to sign with.
+	_, err := NewServiceWithConfig(Config{})
+	assert.ErrorIs(t, err, ErrNoSigningKey)
 }
 
 func TestGenerateToken(t *testing.T) {
-	service := NewService()
+	service := newTestService(t, Config{})
 	user := User{
 		ID:       "user123",
 		Username: "testuser",
@@ -32,7 +45,7 @@ func TestGenerateToken(t *testing.T) {
 }
 
 func TestValidateToken(t *testing.T) {
-	service := NewService()
+	service := newTestService(t, Config{})
 	user := User{
 		ID:       "user123",
 		Username: "testuser",
@@ -52,15 +65,114 @@ func TestValidateToken(t *testing.T) {
 	assert.True(t, claims.Exp > time.Now().Unix())
 }
 
+// fakeClock is a settable time source for Config.Clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newClockedServices(t *testing.T, issuerCfg, verifierCfg Config) (Service, Service, *fakeClock) {
+	t.Helper()
+
+	key, err := GenerateKey("test-key", AlgorithmEdDSA)
+	require.NoError(t, err)
+
+	clock := &fakeClock{now: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+	issuerCfg.Keys = []Key{key}
+	issuerCfg.Clock = clock.Now
+	verifierCfg.Keys = []Key{key}
+	verifierCfg.Clock = clock.Now
+
+	issuer, err := NewServiceWithConfig(issuerCfg)
+	require.NoError(t, err)
+	verifier, err := NewServiceWithConfig(verifierCfg)
+	require.NoError(t, err)
+	return issuer, verifier, clock
+}
+
 func TestValidateToken_Expired(t *testing.T) {
-	// This test requires a different approach since we can't directly inject expired claims
-	// through the interface. In a real scenario, you'd test this with an actual expired token
-	// or by mocking the time.Now() function.
-	t.Skip("Skipping expired token test - requires different testing approach")
+	cfg := Config{AccessTokenTTL: 5 * time.Minute, Leeway: 10 * time.Second}
+	service, _, clock := newClockedServices(t, cfg, cfg)
+
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	// Still accepted within the leeway after expiry.
+	clock.now = clock.now.Add(5*time.Minute + 5*time.Second)
+	_, err = service.ValidateToken(token)
+	require.NoError(t, err)
+
+	clock.now = clock.now.Add(10 * time.Second)
+	_, err = service.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestValidateToken_NotYetValid(t *testing.T) {
+	cfg := Config{Leeway: 10 * time.Second}
+	service, _, clock := newClockedServices(t, cfg, cfg)
+
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	// A verifier whose clock is slightly behind accepts the token.
+	clock.now = clock.now.Add(-5 * time.Second)
+	_, err = service.ValidateToken(token)
+	require.NoError(t, err)
+
+	clock.now = clock.now.Add(-time.Minute)
+	_, err = service.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenNotValidYet)
+}
+
+func TestValidateToken_WrongAudience(t *testing.T) {
+	issuer, verifier, _ := newClockedServices(t, Config{Audience: "billing"}, Config{})
+
+	token, err := issuer.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	_, err = verifier.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	_, err = issuer.ValidateToken(token)
+	assert.NoError(t, err)
+}
+
+func TestValidateToken_WrongIssuer(t *testing.T) {
+	issuer, verifier, _ := newClockedServices(t, Config{Issuer: "other-service"}, Config{})
+
+	token, err := issuer.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	_, err = verifier.ValidateToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+}
+
+func TestGenerateToken_RegisteredClaims(t *testing.T) {
+	service, _, clock := newClockedServices(t, Config{Issuer: "chat", Audience: "chat-api"}, Config{})
+
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+	other, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	otherClaims, err := service.ValidateToken(other)
+	require.NoError(t, err)
+
+	assert.Equal(t, "chat", claims.Iss)
+	assert.Equal(t, jwt.ClaimStrings{"chat-api"}, claims.Aud)
+	assert.Equal(t, clock.now.Unix(), claims.Nbf)
+	assert.Equal(t, clock.now.Unix(), claims.Iat)
+	assert.NotEmpty(t, claims.Jti)
+	assert.NotEqual(t, claims.Jti, otherClaims.Jti)
 }
 
 func TestValidateToken_Invalid(t *testing.T) {
-	service := NewService()
+	service := newTestService(t, Config{})
 	
 	// Test invalid token
 	_, err := service.ValidateToken("invalid.token.here")
@@ -75,12 +187,6 @@ func TestValidateToken_Invalid(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestRefreshToken(t *testing.T) {
-	// This test requires a token that's close to expiration
-	// The refresh logic only allows refresh when token expires within 1 hour
-	t.Skip("Skipping refresh token test - requires token near expiration")
-}
-
 func TestClaims_GetAudience(t *testing.T) {
 	claims := &Claims{
 		UserID:   "user123",
@@ -91,7 +197,12 @@ func TestClaims_GetAudience(t *testing.T) {
 
 	audience, err := claims.GetAudience()
 	require.NoError(t, err)
-	assert.Nil(t, audience) // Returns nil as per implementation
+	assert.Nil(t, audience)
+
+	claims.Aud = jwt.ClaimStrings{"messaging-app"}
+	audience, err = claims.GetAudience()
+	require.NoError(t, err)
+	assert.Equal(t, jwt.ClaimStrings{"messaging-app"}, audience)
 }
 
 func TestClaims_GetExpirationTime(t *testing.T) {
@@ -135,7 +246,12 @@ func TestClaims_GetIssuer(t *testing.T) {
 
 	issuer, err := claims.GetIssuer()
 	require.NoError(t, err)
-	assert.Equal(t, "", issuer) // Returns empty string as per implementation
+	assert.Equal(t, "", issuer)
+
+	claims.Iss = "messaging-app"
+	issuer, err = claims.GetIssuer()
+	require.NoError(t, err)
+	assert.Equal(t, "messaging-app", issuer)
 }
 
 func TestClaims_GetNotBefore(t *testing.T) {
@@ -148,7 +264,13 @@ func TestClaims_GetNotBefore(t *testing.T) {
 
 	notBefore, err := claims.GetNotBefore()
 	require.NoError(t, err)
-	assert.Nil(t, notBefore) // Returns nil as per implementation
+	assert.Nil(t, notBefore)
+
+	nbf := time.Now()
+	claims.Nbf = nbf.Unix()
+	notBefore, err = claims.GetNotBefore()
+	require.NoError(t, err)
+	assert.Equal(t, nbf.Unix(), notBefore.Unix())
 }
 
 func TestClaims_GetSubject(t *testing.T) {
@@ -164,5 +286,16 @@ func TestClaims_GetSubject(t *testing.T) {
 	assert.Equal(t, "user123", subject)
 }
 
-// Note: Testing expired tokens requires a different approach
-// since we can't directly inject expired claims through the interface 
\ No newline at end of file
+func TestNewServiceWithConfig_AccessTokenTTL(t *testing.T) {
+	service := newTestService(t, Config{AccessTokenTTL: 5 * time.Minute})
+	assert.Equal(t, 5*time.Minute, service.AccessTokenTTL())
+
+	token, err := service.GenerateToken(User{ID: "user123", Username: "testuser"})
+	require.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(5*time.Minute).Unix(), claims.Exp, 2)
+
+	assert.Equal(t, DefaultAccessTokenTTL, newTestService(t, Config{}).AccessTokenTTL())
+}
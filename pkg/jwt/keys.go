@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+
+	minRSAKeyBits = 2048
+)
+
+var ErrNoSigningKey = errors.New("no JWT signing key configured")
+
+// Key is one signing or verification key. Keys without a private part only
+// verify tokens; they are used to publish a key before it starts signing and
+// to keep accepting tokens of a retired key.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+func (k Key) signingMethod() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// GenerateKey creates a new private key for alg, mostly for tests and local
+// development.
+func GenerateKey(id, alg string) (Key, error) {
+	switch alg {
+	case AlgorithmEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return Key{}, err
+		}
+		return Key{ID: id, Algorithm: alg, Private: private, Public: public}, nil
+	case AlgorithmRS256:
+		private, err := rsa.GenerateKey(rand.Reader, minRSAKeyBits)
+		if err != nil {
+			return Key{}, err
+		}
+		return Key{ID: id, Algorithm: alg, Private: private, Public: &private.PublicKey}, nil
+	}
+	return Key{}, fmt.Errorf("unsupported algorithm %q", alg)
+}
+
+// LoadKeyDir reads every <kid>.pem file in dir. A file holds either a PKCS#8
+// private key or a PKIX public key, Ed25519 or RSA.
+func LoadKeyDir(dir string) ([]Key, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key %s: %w", path, err)
+		}
+
+		key, err := parseKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func parseKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unsupported PEM block %q, expected PRIVATE KEY or PUBLIC KEY", block.Type)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch k := parsed.(type) {
+	case ed25519.PrivateKey:
+		return Key{ID: id, Algorithm: AlgorithmEdDSA, Private: k, Public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return Key{ID: id, Algorithm: AlgorithmEdDSA, Public: k}, nil
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return Key{}, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+		return Key{ID: id, Algorithm: AlgorithmRS256, Private: k, Public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return Key{}, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+		}
+		return Key{ID: id, Algorithm: AlgorithmRS256, Public: k}, nil
+	}
+	return Key{}, fmt.Errorf("unsupported key type %T", parsed)
+}
+
+// keySet is the keys a service signs and verifies with at one point in time.
+type keySet struct {
+	signer Key
+	keys   map[string]Key
+	// retired holds keys that left the configuration and stay valid for
+	// verification until the given time.
+	retired map[string]time.Time
+}
+
+// newKeySet picks the signing key from keys: signingKeyID if set, otherwise
+// the private key with the greatest ID, so date-named keys rotate in order.
+// Keys that were in previous but are gone now keep verifying for overlap.
+func newKeySet(keys []Key, signingKeyID string, previous *keySet, overlap time.Duration, now time.Time) (*keySet, error) {
+	set := &keySet{
+		keys:    make(map[string]Key, len(keys)),
+		retired: make(map[string]time.Time),
+	}
+
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("key without an ID")
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key ID %q", key.ID)
+		}
+		set.keys[key.ID] = key
+		ids = append(ids, key.ID)
+	}
+	sort.Strings(ids)
+
+	if signingKeyID != "" {
+		key, exists := set.keys[signingKeyID]
+		if !exists || key.Private == nil {
+			return nil, fmt.Errorf("%w: signing key %q has no private key", ErrNoSigningKey, signingKeyID)
+		}
+		set.signer = key
+	} else {
+		for i := len(ids) - 1; i >= 0; i-- {
+			if key := set.keys[ids[i]]; key.Private != nil {
+				set.signer = key
+				break
+			}
+		}
+		if set.signer.ID == "" {
+			return nil, ErrNoSigningKey
+		}
+	}
+
+	if previous != nil {
+		for id, key := range previous.keys {
+			if _, exists := set.keys[id]; !exists {
+				set.keys[id] = key
+				set.retired[id] = now.Add(overlap)
+			}
+		}
+		for id, until := range previous.retired {
+			if _, exists := set.retired[id]; exists {
+				set.retired[id] = until
+			}
+		}
+		for id, until := range set.retired {
+			if !now.Before(until) {
+				delete(set.keys, id)
+				delete(set.retired, id)
+			}
+		}
+	}
+
+	return set, nil
+}
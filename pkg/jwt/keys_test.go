@@ -0,0 +1,240 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, dir string, key Key, private bool) {
+	t.Helper()
+
+	var block *pem.Block
+	if private {
+		der, err := x509.MarshalPKCS8PrivateKey(key.Private)
+		require.NoError(t, err)
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKIXPublicKey(key.Public)
+		require.NoError(t, err)
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, key.ID+".pem"), pem.EncodeToMemory(block), 0o600))
+}
+
+func TestLoadKeyDir(t *testing.T) {
+	dir := t.TempDir()
+
+	ed, err := GenerateKey("2026-01-01", AlgorithmEdDSA)
+	require.NoError(t, err)
+	rsaKey, err := GenerateKey("2026-02-01", AlgorithmRS256)
+	require.NoError(t, err)
+	next, err := GenerateKey("2026-03-01", AlgorithmEdDSA)
+	require.NoError(t, err)
+
+	writeKey(t, dir, ed, true)
+	writeKey(t, dir, rsaKey, true)
+	writeKey(t, dir, next, false)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0o600))
+
+	keys, err := LoadKeyDir(dir)
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	service, err := NewServiceWithConfig(Config{KeyDir: dir})
+	require.NoError(t, err)
+
+	// The newest private key signs; the public-only key is published but
+	// does not sign yet.
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-01", tokenKeyID(t, token))
+
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user123", claims.UserID)
+
+	jwks := service.JWKS()
+	require.Len(t, jwks.Keys, 3)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "2026-03-01", jwks.Keys[2].KeyID)
+}
+
+func TestNewServiceWithConfig_FailsWithoutPrivateKey(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewServiceWithConfig(Config{KeyDir: dir})
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	key, err := GenerateKey("verify-only", AlgorithmEdDSA)
+	require.NoError(t, err)
+	writeKey(t, dir, key, false)
+
+	_, err = NewServiceWithConfig(Config{KeyDir: dir})
+	assert.ErrorIs(t, err, ErrNoSigningKey)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0o600))
+	_, err = NewServiceWithConfig(Config{KeyDir: dir})
+	assert.Error(t, err)
+}
+
+func TestReloadKeys_RotationOverlap(t *testing.T) {
+	dir := t.TempDir()
+
+	old, err := GenerateKey("2026-01-01", AlgorithmEdDSA)
+	require.NoError(t, err)
+	writeKey(t, dir, old, true)
+
+	service, err := NewServiceWithConfig(Config{KeyDir: dir, RotationOverlap: time.Hour})
+	require.NoError(t, err)
+
+	oldToken, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	// Rotate: the new key takes over signing and the old one is removed.
+	next, err := GenerateKey("2026-02-01", AlgorithmEdDSA)
+	require.NoError(t, err)
+	writeKey(t, dir, next, true)
+	require.NoError(t, os.Remove(filepath.Join(dir, old.ID+".pem")))
+	require.NoError(t, service.ReloadKeys())
+
+	newToken, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-01", tokenKeyID(t, newToken))
+
+	// Tokens signed by the removed key stay valid during the overlap.
+	_, err = service.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	assert.Len(t, service.JWKS().Keys, 2)
+
+	// A broken reload keeps the current keys.
+	require.NoError(t, os.Remove(filepath.Join(dir, next.ID+".pem")))
+	assert.ErrorIs(t, service.ReloadKeys(), ErrNoSigningKey)
+	_, err = service.ValidateToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestNewKeySet_RetiredKeysExpire(t *testing.T) {
+	old, err := GenerateKey("old", AlgorithmEdDSA)
+	require.NoError(t, err)
+	next, err := GenerateKey("next", AlgorithmEdDSA)
+	require.NoError(t, err)
+
+	now := time.Now()
+	first, err := newKeySet([]Key{old}, "", nil, time.Hour, now)
+	require.NoError(t, err)
+
+	second, err := newKeySet([]Key{next}, "next", first, time.Hour, now)
+	require.NoError(t, err)
+	assert.Contains(t, second.keys, "old")
+
+	// Reloading again does not extend the overlap.
+	third, err := newKeySet([]Key{next}, "next", second, time.Hour, now.Add(59*time.Minute))
+	require.NoError(t, err)
+	assert.Contains(t, third.keys, "old")
+
+	fourth, err := newKeySet([]Key{next}, "next", third, time.Hour, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.NotContains(t, fourth.keys, "old")
+}
+
+func TestValidateToken_UnknownKeyID(t *testing.T) {
+	service := newTestService(t, Config{})
+	other := newTestService(t, Config{})
+
+	token, err := other.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	// Same kid, different key: the signature does not verify.
+	_, err = service.ValidateToken(token)
+	assert.Error(t, err)
+
+	otherKey, err := GenerateKey("other-key", AlgorithmEdDSA)
+	require.NoError(t, err)
+	another, err := NewServiceWithConfig(Config{Keys: []Key{otherKey}})
+	require.NoError(t, err)
+	token, err = another.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	_, err = service.ValidateToken(token)
+	assert.ErrorContains(t, err, "unknown key ID")
+}
+
+func TestJWKSHandler(t *testing.T) {
+	service := newTestService(t, Config{})
+
+	recorder := httptest.NewRecorder()
+	JWKSHandler(service).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, JWKSPath, nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var jwks JWKS
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "test-key", jwks.Keys[0].KeyID)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+
+	recorder = httptest.NewRecorder()
+	JWKSHandler(service).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, JWKSPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	for _, alg := range []string{AlgorithmEdDSA, AlgorithmRS256} {
+		key, err := GenerateKey("key-"+alg, alg)
+		require.NoError(t, err)
+
+		jwks := NewJWKS(key)
+		require.Len(t, jwks.Keys, 1)
+
+		public, err := jwks.Keys[0].PublicKey()
+		require.NoError(t, err)
+		assert.True(t, public.(interface{ Equal(crypto.PublicKey) bool }).Equal(key.Public), alg)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	public, err := JWK{
+		KeyType: "EC",
+		Curve:   "P-256",
+		X:       base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+		Y:       base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()),
+	}.PublicKey()
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(public))
+
+	_, err = JWK{KeyType: "EC", Curve: "P-256", X: "AQ", Y: "AQ"}.PublicKey()
+	assert.Error(t, err, "point not on the curve")
+
+	_, err = JWK{KeyType: "oct", KeyID: "secret"}.PublicKey()
+	assert.Error(t, err)
+}
+
+func tokenKeyID(t *testing.T, token string) string {
+	t.Helper()
+
+	parsed, _, err := gojwt.NewParser().ParseUnverified(token, &Claims{})
+	require.NoError(t, err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
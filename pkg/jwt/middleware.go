@@ -22,30 +22,51 @@ const (
 	jwtClaimsKey contextKey = "jwt_claims"
 )
 
+// RevocationChecker reports whether a token was revoked before it expired,
+// by its ID, by its session, or because the user's token generation moved
+// past it.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID, sessionID, userID string, generation int64) (bool, error)
+}
+
+// APIKeyHeader carries API keys, as an alternative to a bearer token.
+const APIKeyHeader = "x-api-key"
+
+// APIKeyAuthenticator resolves an API key to the claims it acts with.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*Claims, error)
+}
+
 type Interceptor struct {
-	jwtService Service
+	jwtService  Service
+	revocations RevocationChecker
+	apiKeys     APIKeyAuthenticator
+	policies    Policies
 }
 
-func NewInterceptor(jwtService Service) *Interceptor {
+// NewInterceptor builds the auth interceptor, which authorizes every call by
+// the policy of its method and denies methods without one. revocations may
+// be nil, in which case tokens are only checked for signature and expiry.
+// apiKeys may be nil, in which case API keys are not accepted.
+func NewInterceptor(jwtService Service, revocations RevocationChecker, apiKeys APIKeyAuthenticator, policies Policies) *Interceptor {
 	return &Interceptor{
-		jwtService: jwtService,
+		jwtService:  jwtService,
+		revocations: revocations,
+		apiKeys:     apiKeys,
+		policies:    policies,
 	}
 }
 
 func (i *Interceptor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	if shouldSkipAuth(info.FullMethod) {
+	claims, err := i.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if claims == nil {
 		return handler(ctx, req)
 	}
 
-	claims, err := i.extractAndValidateToken(ctx)
-	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
-	}
-
-	ctx = context.WithValue(ctx, userIDKey, claims.UserID)
-	ctx = context.WithValue(ctx, usernameKey, claims.Username)
-	ctx = context.WithValue(ctx, emailKey, claims.Email)
-	ctx = context.WithValue(ctx, jwtClaimsKey, claims)
+	ctx = ContextWithClaims(ctx, claims)
 
 	slog.Info("JWT authentication successful", "userID", claims.UserID, "username", claims.Username, "method", info.FullMethod)
 
@@ -53,23 +74,17 @@ func (i *Interceptor) UnaryInterceptor(ctx context.Context, req interface{}, inf
 }
 
 func (i *Interceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	if shouldSkipAuth(info.FullMethod) {
-		return handler(srv, ss)
-	}
-
-	claims, err := i.extractAndValidateToken(ss.Context())
+	claims, err := i.authorize(ss.Context(), info.FullMethod)
 	if err != nil {
-		return status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+		return err
+	}
+	if claims == nil {
+		return handler(srv, ss)
 	}
-
-	ctx := context.WithValue(ss.Context(), userIDKey, claims.UserID)
-	ctx = context.WithValue(ctx, usernameKey, claims.Username)
-	ctx = context.WithValue(ctx, emailKey, claims.Email)
-	ctx = context.WithValue(ctx, jwtClaimsKey, claims)
 
 	wrappedStream := &wrappedServerStream{
 		ServerStream: ss,
-		ctx:          ctx,
+		ctx:          ContextWithClaims(ss.Context(), claims),
 	}
 
 	slog.Info("JWT authentication successful for stream", "userID", claims.UserID, "username", claims.Username, "method", info.FullMethod)
@@ -77,12 +92,49 @@ func (i *Interceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, i
 	return handler(srv, wrappedStream)
 }
 
+// authorize applies the policy of method to the call. It returns the
+// caller's claims, or nil for public methods.
+func (i *Interceptor) authorize(ctx context.Context, method string) (*Claims, error) {
+	policy, ok := i.policies[method]
+	if !ok {
+		slog.Error("Method has no auth policy", "method", method)
+		return nil, status.Error(codes.PermissionDenied, "method has no auth policy")
+	}
+
+	if policy.Access == Public {
+		return nil, nil
+	}
+
+	claims, err := i.extractAndValidateToken(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %v", err)
+	}
+
+	if err := policy.authorize(claims); err != nil {
+		slog.Warn("Call not authorized", "userID", claims.UserID, "method", method, "error", err)
+		return nil, status.Errorf(codes.PermissionDenied, "not authorized: %v", err)
+	}
+
+	return claims, nil
+}
+
 func (i *Interceptor) extractAndValidateToken(ctx context.Context) (*Claims, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("no metadata found")
 	}
 
+	if apiKeys := md.Get(APIKeyHeader); len(apiKeys) > 0 {
+		if i.apiKeys == nil {
+			return nil, fmt.Errorf("API keys are not accepted")
+		}
+		claims, err := i.apiKeys.AuthenticateAPIKey(ctx, apiKeys[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid API key: %w", err)
+		}
+		return claims, nil
+	}
+
 	authHeaders := md.Get("authorization")
 	if len(authHeaders) == 0 {
 		return nil, fmt.Errorf("no authorization header found")
@@ -103,22 +155,17 @@ func (i *Interceptor) extractAndValidateToken(ctx context.Context) (*Claims, err
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
-	return claims, nil
-}
-
-func shouldSkipAuth(method string) bool {
-	skipMethods := []string{
-		"/messaging.UsersService/CreateUser",
-		"/messaging.UsersService/Login",
-	}
-
-	for _, skipMethod := range skipMethods {
-		if method == skipMethod {
-			return true
+	if i.revocations != nil {
+		revoked, err := i.revocations.IsRevoked(ctx, claims.Jti, claims.Sid, claims.UserID, claims.Gen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, fmt.Errorf("token revoked")
 		}
 	}
 
-	return false
+	return claims, nil
 }
 
 type wrappedServerStream struct {
@@ -130,6 +177,15 @@ func (w *wrappedServerStream) Context() context.Context {
 	return w.ctx
 }
 
+// ContextWithClaims returns ctx carrying the authenticated user of claims, as
+// the interceptor passes it to handlers.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	ctx = context.WithValue(ctx, userIDKey, claims.UserID)
+	ctx = context.WithValue(ctx, usernameKey, claims.Username)
+	ctx = context.WithValue(ctx, emailKey, claims.Email)
+	return context.WithValue(ctx, jwtClaimsKey, claims)
+}
+
 // GetUserFromContext extracts user information from context
 func GetUserFromContext(ctx context.Context) (string, string, string, error) {
 	userID, ok := ctx.Value(userIDKey).(string)
@@ -0,0 +1,211 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRevocationChecker struct {
+	revoked    map[string]bool // token or session ID
+	generation map[string]int64
+}
+
+func (f *fakeRevocationChecker) IsRevoked(ctx context.Context, tokenID, sessionID, userID string, generation int64) (bool, error) {
+	return f.revoked[tokenID] || f.revoked[sessionID] || generation < f.generation[userID], nil
+}
+
+func authContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestInterceptor_RejectsRevokedToken(t *testing.T) {
+	service := newTestService(t, Config{})
+	checker := &fakeRevocationChecker{revoked: map[string]bool{}, generation: map[string]int64{}}
+	interceptor := NewInterceptor(service, checker, nil, nil)
+
+	token, err := service.GenerateToken(User{ID: "user123", Username: "testuser"})
+	require.NoError(t, err)
+
+	claims, err := interceptor.extractAndValidateToken(authContext(token))
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.Jti)
+
+	checker.revoked[claims.Jti] = true
+	_, err = interceptor.extractAndValidateToken(authContext(token))
+	assert.ErrorContains(t, err, "token revoked")
+}
+
+func TestInterceptor_RejectsRevokedSession(t *testing.T) {
+	service := newTestService(t, Config{})
+	checker := &fakeRevocationChecker{revoked: map[string]bool{"session1": true}, generation: map[string]int64{}}
+	interceptor := NewInterceptor(service, checker, nil, nil)
+
+	token, err := service.GenerateToken(User{ID: "user123", SessionID: "session1"})
+	require.NoError(t, err)
+	_, err = interceptor.extractAndValidateToken(authContext(token))
+	assert.ErrorContains(t, err, "token revoked")
+
+	other, err := service.GenerateToken(User{ID: "user123", SessionID: "session2"})
+	require.NoError(t, err)
+	claims, err := interceptor.extractAndValidateToken(authContext(other))
+	require.NoError(t, err)
+	assert.Equal(t, "session2", claims.Sid)
+}
+
+func TestInterceptor_RejectsOldGeneration(t *testing.T) {
+	service := newTestService(t, Config{})
+	checker := &fakeRevocationChecker{revoked: map[string]bool{}, generation: map[string]int64{"user123": 2}}
+	interceptor := NewInterceptor(service, checker, nil, nil)
+
+	old, err := service.GenerateToken(User{ID: "user123", Generation: 1})
+	require.NoError(t, err)
+	_, err = interceptor.extractAndValidateToken(authContext(old))
+	assert.ErrorContains(t, err, "token revoked")
+
+	current, err := service.GenerateToken(User{ID: "user123", Generation: 2})
+	require.NoError(t, err)
+	_, err = interceptor.extractAndValidateToken(authContext(current))
+	assert.NoError(t, err)
+}
+
+func TestInterceptor_NilRevocationChecker(t *testing.T) {
+	service := newTestService(t, Config{})
+	interceptor := NewInterceptor(service, nil, nil, nil)
+
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	_, err = interceptor.extractAndValidateToken(authContext(token))
+	assert.NoError(t, err)
+}
+
+var testPolicies = Policies{
+	"/test.Service/Public":        {Access: Public},
+	"/test.Service/Authenticated": {Access: Authenticated},
+	"/test.Service/Admin":         {Access: AdminOnly},
+	"/test.Service/Send":          {Access: Authenticated, Scopes: []string{"messages:send"}},
+}
+
+func callUnary(interceptor *Interceptor, ctx context.Context, method string) (string, error) {
+	resp, err := interceptor.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		userID, _, _, _ := GetUserFromContext(ctx)
+		return userID, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.(string), nil
+}
+
+func TestInterceptor_AppliesMethodPolicies(t *testing.T) {
+	service := newTestService(t, Config{})
+	interceptor := NewInterceptor(service, nil, nil, testPolicies)
+
+	userToken, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+	adminToken, err := service.GenerateToken(User{ID: "admin1", Admin: true})
+	require.NoError(t, err)
+
+	userID, err := callUnary(interceptor, context.Background(), "/test.Service/Public")
+	require.NoError(t, err)
+	assert.Empty(t, userID)
+
+	_, err = callUnary(interceptor, context.Background(), "/test.Service/Authenticated")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	userID, err = callUnary(interceptor, authContext(userToken), "/test.Service/Authenticated")
+	require.NoError(t, err)
+	assert.Equal(t, "user123", userID)
+
+	_, err = callUnary(interceptor, authContext(userToken), "/test.Service/Admin")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	userID, err = callUnary(interceptor, authContext(adminToken), "/test.Service/Admin")
+	require.NoError(t, err)
+	assert.Equal(t, "admin1", userID)
+}
+
+func TestInterceptor_DeniesMethodWithoutPolicy(t *testing.T) {
+	service := newTestService(t, Config{})
+	interceptor := NewInterceptor(service, nil, nil, testPolicies)
+
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	_, err = callUnary(interceptor, authContext(token), "/test.Service/Unknown")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestInterceptor_StreamAppliesMethodPolicies(t *testing.T) {
+	service := newTestService(t, Config{})
+	interceptor := NewInterceptor(service, nil, nil, testPolicies)
+
+	token, err := service.GenerateToken(User{ID: "user123"})
+	require.NoError(t, err)
+
+	var userID string
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		userID, _, _, _ = GetUserFromContext(ss.Context())
+		return nil
+	}
+
+	err = interceptor.StreamInterceptor(nil, &fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Authenticated"}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	err = interceptor.StreamInterceptor(nil, &fakeServerStream{ctx: authContext(token)}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Authenticated"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "user123", userID)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+type fakeAPIKeyAuthenticator map[string]*Claims
+
+func (f fakeAPIKeyAuthenticator) AuthenticateAPIKey(ctx context.Context, key string) (*Claims, error) {
+	if claims, ok := f[key]; ok {
+		return claims, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+func apiKeyContext(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyHeader, key))
+}
+
+func TestInterceptor_AcceptsScopedAPIKeys(t *testing.T) {
+	service := newTestService(t, Config{})
+	apiKeys := fakeAPIKeyAuthenticator{"mak_valid": {UserID: "bot1", Scopes: []string{"messages:send"}}}
+	interceptor := NewInterceptor(service, nil, apiKeys, testPolicies)
+
+	userID, err := callUnary(interceptor, apiKeyContext("mak_valid"), "/test.Service/Send")
+	require.NoError(t, err)
+	assert.Equal(t, "bot1", userID)
+
+	// Keys only reach methods that require one of their scopes.
+	_, err = callUnary(interceptor, apiKeyContext("mak_valid"), "/test.Service/Authenticated")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = callUnary(interceptor, apiKeyContext("mak_unknown"), "/test.Service/Send")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestInterceptor_RejectsAPIKeysWithoutAuthenticator(t *testing.T) {
+	service := newTestService(t, Config{})
+	interceptor := NewInterceptor(service, nil, nil, testPolicies)
+
+	_, err := callUnary(interceptor, apiKeyContext("mak_valid"), "/test.Service/Send")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
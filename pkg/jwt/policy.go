@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+// Access says who may call a method.
+type Access int
+
+const (
+	// AccessUnspecified is the zero value; a policy must not use it.
+	AccessUnspecified Access = iota
+	// Public methods need no token, such as Login.
+	Public
+	// Authenticated methods need a valid token.
+	Authenticated
+	// AdminOnly methods need a valid token of an administrator.
+	AdminOnly
+)
+
+func (a Access) String() string {
+	switch a {
+	case Public:
+		return "public"
+	case Authenticated:
+		return "authenticated"
+	case AdminOnly:
+		return "admin"
+	default:
+		return "unspecified"
+	}
+}
+
+// Policy declares how a method is authorized. A token restricted to scopes
+// must hold all of Scopes, and cannot call methods without Scopes at all.
+// Public methods have no Scopes.
+type Policy struct {
+	Access Access
+	Scopes []string
+}
+
+// Policies maps full method names, such as
+// "/messaging.UsersService/Login", to their policy.
+type Policies map[string]Policy
+
+// Validate checks that every method the server registered has a valid
+// policy, and that every policy names a registered method. It is meant to
+// run at startup, so a new RPC cannot be served without a policy.
+func (p Policies) Validate(services map[string]grpc.ServiceInfo) error {
+	var problems []string
+
+	registered := make(map[string]bool)
+	for service, info := range services {
+		for _, method := range info.Methods {
+			fullMethod := "/" + service + "/" + method.Name
+			registered[fullMethod] = true
+
+			policy, ok := p[fullMethod]
+			switch {
+			case !ok:
+				problems = append(problems, fullMethod+" has no auth policy")
+			case policy.Access < Public || policy.Access > AdminOnly:
+				problems = append(problems, fullMethod+" has no access level")
+			case policy.Access == Public && len(policy.Scopes) > 0:
+				problems = append(problems, fullMethod+" is public but requires scopes")
+			}
+		}
+	}
+
+	for method := range p {
+		if !registered[method] {
+			problems = append(problems, method+" has a policy but is not registered")
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid auth policies: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// authorize checks claims, already validated, against the policy of a
+// method that is not public.
+func (p Policy) authorize(claims *Claims) error {
+	if p.Access == AdminOnly && !claims.Adm {
+		return fmt.Errorf("admin access required")
+	}
+	if len(p.Scopes) == 0 && len(claims.Scopes) > 0 {
+		return fmt.Errorf("method not available to scoped tokens")
+	}
+	for _, scope := range p.Scopes {
+		if !claims.HasScope(scope) {
+			return fmt.Errorf("scope %s required", scope)
+		}
+	}
+	return nil
+}
+
+// HasScope reports whether the token may act within scope. Tokens without
+// scopes are not restricted.
+func (c *Claims) HasScope(scope string) bool {
+	return len(c.Scopes) == 0 || slices.Contains(c.Scopes, scope)
+}
+
+// AllowsChat reports whether the token may act in chatID. Tokens without
+// chats are not restricted.
+func (c *Claims) AllowsChat(chatID string) bool {
+	return len(c.Chats) == 0 || slices.Contains(c.Chats, chatID)
+}
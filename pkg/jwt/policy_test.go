@@ -0,0 +1,61 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func testServices() map[string]grpc.ServiceInfo {
+	return map[string]grpc.ServiceInfo{
+		"test.Service": {Methods: []grpc.MethodInfo{
+			{Name: "Public"}, {Name: "Authenticated"}, {Name: "Admin"}, {Name: "Send"},
+		}},
+	}
+}
+
+func TestPolicies_Validate(t *testing.T) {
+	assert.NoError(t, testPolicies.Validate(testServices()))
+}
+
+func TestPolicies_ValidateRejectsMissingPolicy(t *testing.T) {
+	services := testServices()
+	services["test.Other"] = grpc.ServiceInfo{Methods: []grpc.MethodInfo{{Name: "New"}}}
+
+	err := testPolicies.Validate(services)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/test.Other/New has no auth policy")
+}
+
+func TestPolicies_ValidateRejectsInvalidPolicies(t *testing.T) {
+	policies := Policies{
+		"/test.Service/Public":        {Access: Public, Scopes: []string{"chats:read"}},
+		"/test.Service/Authenticated": {},
+		"/test.Service/Admin":         {Access: AdminOnly},
+		"/test.Service/Send":          {Access: Authenticated},
+		"/test.Service/Removed":       {Access: Authenticated},
+	}
+
+	err := policies.Validate(testServices())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/test.Service/Public is public but requires scopes")
+	assert.Contains(t, err.Error(), "/test.Service/Authenticated has no access level")
+	assert.Contains(t, err.Error(), "/test.Service/Removed has a policy but is not registered")
+}
+
+func TestPolicy_AuthorizeScopes(t *testing.T) {
+	policy := testPolicies["/test.Service/Send"]
+
+	assert.NoError(t, policy.authorize(&Claims{UserID: "user123"}))
+	assert.NoError(t, policy.authorize(&Claims{UserID: "bot1", Scopes: []string{"chats:read", "messages:send"}}))
+	assert.ErrorContains(t, policy.authorize(&Claims{UserID: "bot1", Scopes: []string{"chats:read"}}), "scope messages:send required")
+}
+
+func TestPolicy_AuthorizeDeniesScopedTokensWithoutScopes(t *testing.T) {
+	policy := testPolicies["/test.Service/Authenticated"]
+
+	assert.NoError(t, policy.authorize(&Claims{UserID: "user123"}))
+	assert.ErrorContains(t, policy.authorize(&Claims{UserID: "bot1", Scopes: []string{"messages:send"}}), "not available to scoped tokens")
+}
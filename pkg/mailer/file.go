@@ -0,0 +1,79 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+
+	mu    sync.Mutex
+	count int
+}
+
+// NewFileMailer writes every message to dir as an .eml file instead of
+// sending it, for local development.
+func NewFileMailer(dir, from string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &fileMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+	data, err := buildMessage(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.count++
+	name := fmt.Sprintf("%s-%04d.eml", now.UTC().Format("20060102T150405"), m.count)
+	m.mu.Unlock()
+
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		slog.Error("Error writing email", "path", path, "error", err)
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+
+	slog.Info("Email written to file", "to", msg.To, "subject", msg.Subject, "path", path)
+	return nil
+}
+
+// MemoryMailer keeps sent messages in memory, for tests.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns a copy of the messages sent so far.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
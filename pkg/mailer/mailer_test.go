@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessage(t *testing.T) {
+	data, err := buildMessage("noreply@example.com", Message{
+		To:      "alice@example.com",
+		Subject: "Reset your password",
+		Body:    "line one\nline two",
+	}, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	msg := string(data)
+	assert.Contains(t, msg, "From: noreply@example.com\r\n")
+	assert.Contains(t, msg, "To: alice@example.com\r\n")
+	assert.Contains(t, msg, "Subject: Reset your password\r\n")
+	assert.Contains(t, msg, "\r\n\r\nline one\r\nline two")
+}
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("noreply@example.com", Message{
+		To:      "alice@example.com\r\nBcc: mallory@example.com",
+		Subject: "hi",
+	}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m, err := NewFileMailer(dir, "noreply@example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, m.Send(context.Background(), Message{To: "alice@example.com", Subject: "one", Body: "1"}))
+	require.NoError(t, m.Send(context.Background(), Message{To: "bob@example.com", Subject: "two", Body: "2"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: alice@example.com")
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+	require.NoError(t, m.Send(context.Background(), Message{To: "alice@example.com", Subject: "hi"}))
+
+	messages := m.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "alice@example.com", messages[0].To)
+}
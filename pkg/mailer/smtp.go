@@ -0,0 +1,66 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type smtpMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer sends mail through an SMTP server, authenticating with PLAIN
+// auth when a username is set. net/smtp only allows PLAIN auth over TLS or to
+// localhost.
+func NewSMTPMailer(cfg SMTPConfig) Mailer {
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	return &smtpMailer{
+		cfg: cfg,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	slog.Info("Sending email", "to", msg.To, "subject", msg.Subject)
+
+	data, err := buildMessage(m.cfg.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	// smtp.SendMail has no context support, so cancellation only stops the
+	// caller from waiting.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(m.cfg.Host, m.cfg.Port), auth, m.cfg.From, []string{msg.To}, data)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			slog.Error("Error sending email", "to", msg.To, "error", err)
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
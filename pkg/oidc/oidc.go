@@ -0,0 +1,351 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+// DiscoveryPath is where an issuer publishes its configuration, relative to
+// the issuer URL.
+const DiscoveryPath = "/.well-known/openid-configuration"
+
+const (
+	defaultHTTPTimeout = 10 * time.Second
+	// minKeyRefresh limits how often an unknown key ID refetches the JWKS, so
+	// forged tokens cannot make us hammer the issuer.
+	minKeyRefresh   = time.Minute
+	maxDocumentSize = 1 << 20
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	ErrExchangeFailed = errors.New("authorization code exchange failed")
+)
+
+type Config struct {
+	// Issuer is the issuer URL exactly as it appears in ID tokens.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is used for code exchanges that do not name their own.
+	RedirectURL string
+
+	HTTPClient *http.Client
+	Leeway     time.Duration
+	Clock      func() time.Time
+}
+
+// Identity is the verified subject of an ID token.
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// Provider verifies ID tokens of one OpenID Connect issuer and redeems its
+// authorization codes.
+type Provider interface {
+	Issuer() string
+	VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (Identity, error)
+	Exchange(ctx context.Context, code, codeVerifier, redirectURL string) (Identity, error)
+}
+
+type discovery struct {
+	Issuer        string `json:"issuer"`
+	TokenEndpoint string `json:"token_endpoint"`
+	JWKSURI       string `json:"jwks_uri"`
+}
+
+type provider struct {
+	cfg    Config
+	client *http.Client
+	parser *gojwt.Parser
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewProvider returns a provider for cfg.Issuer. The discovery document and
+// keys are fetched on first use, so the issuer does not have to be reachable
+// at startup.
+func NewProvider(cfg Config) (Provider, error) {
+	if cfg.Issuer == "" || cfg.ClientID == "" {
+		return nil, errors.New("OIDC issuer and client ID are required")
+	}
+	if _, err := url.ParseRequestURI(cfg.Issuer); err != nil {
+		return nil, fmt.Errorf("invalid OIDC issuer: %w", err)
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	return &provider{
+		cfg:    cfg,
+		client: cfg.HTTPClient,
+		parser: gojwt.NewParser(
+			gojwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+			gojwt.WithIssuer(cfg.Issuer),
+			gojwt.WithAudience(cfg.ClientID),
+			gojwt.WithLeeway(cfg.Leeway),
+			gojwt.WithTimeFunc(cfg.Clock),
+			gojwt.WithExpirationRequired(),
+			gojwt.WithIssuedAt(),
+		),
+	}, nil
+}
+
+func (p *provider) Issuer() string {
+	return p.cfg.Issuer
+}
+
+type idTokenClaims struct {
+	gojwt.RegisteredClaims
+	Nonce             string   `json:"nonce,omitempty"`
+	AuthorizedParty   string   `json:"azp,omitempty"`
+	Email             string   `json:"email,omitempty"`
+	EmailVerified     flexBool `json:"email_verified,omitempty"`
+	Name              string   `json:"name,omitempty"`
+	PreferredUsername string   `json:"preferred_username,omitempty"`
+}
+
+// flexBool accepts "true" as well as true; some issuers send claims as
+// strings.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(v == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// VerifyIDToken checks the token's signature against the issuer's JWKS, its
+// issuer, audience and lifetime, and nonce when one is given.
+func (p *provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := p.parser.ParseWithClaims(rawIDToken, claims, func(token *gojwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return Identity{}, fmt.Errorf("%w: token was issued to %q", ErrInvalidIDToken, claims.AuthorizedParty)
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return Identity{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return Identity{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Email:             strings.ToLower(strings.TrimSpace(claims.Email)),
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+// Exchange redeems an authorization code at the token endpoint and verifies
+// the returned ID token. codeVerifier is the PKCE verifier, if the flow used
+// one.
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier, redirectURL string) (Identity, error) {
+	disc, err := p.getDiscovery(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+	if disc.TokenEndpoint == "" {
+		return Identity{}, fmt.Errorf("%w: issuer has no token endpoint", ErrExchangeFailed)
+	}
+	if redirectURL == "" {
+		redirectURL = p.cfg.RedirectURL
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURL},
+	}
+	if codeVerifier != "" {
+		form.Set("code_verifier", codeVerifier)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, disc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		slog.Error("Error calling OIDC token endpoint", "issuer", p.cfg.Issuer, "error", err)
+		return Identity{}, fmt.Errorf("%w: %w", ErrExchangeFailed, err)
+	}
+	defer resp.Body.Close()
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(&token); err != nil {
+		return Identity{}, fmt.Errorf("%w: status %d", ErrExchangeFailed, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		slog.Warn("OIDC token endpoint rejected the code", "issuer", p.cfg.Issuer, "status", resp.StatusCode, "error", token.Error)
+		return Identity{}, fmt.Errorf("%w: %s", ErrExchangeFailed, token.Error)
+	}
+	if token.IDToken == "" {
+		return Identity{}, fmt.Errorf("%w: response has no id_token", ErrExchangeFailed)
+	}
+
+	return p.VerifyIDToken(ctx, token.IDToken, "")
+}
+
+// key returns the verification key for kid. An unknown kid refetches the
+// JWKS, at most once per minKeyRefresh, so key rotations are picked up.
+func (p *provider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	if p.keys != nil && p.cfg.Clock().Sub(p.fetchedAt) < minKeyRefresh {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	if err := p.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// lookupKey finds kid; tokens without a kid match a JWKS with a single key.
+func (p *provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *provider) fetchKeys(ctx context.Context) error {
+	disc, err := p.discoveryLocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	var jwks jwt.JWKS
+	if err := p.getJSON(ctx, disc.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			slog.Warn("Skipping unusable JWK", "issuer", p.cfg.Issuer, "kid", jwk.KeyID, "error", err)
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	p.keys = keys
+	p.fetchedAt = p.cfg.Clock()
+	slog.Info("Fetched OIDC keys", "issuer", p.cfg.Issuer, "count", len(keys))
+	return nil
+}
+
+func (p *provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.discoveryLocked(ctx)
+}
+
+func (p *provider) discoveryLocked(ctx context.Context) (*discovery, error) {
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var disc discovery
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.cfg.Issuer, "/")+DiscoveryPath, &disc); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+	// The issuer must match exactly, or tokens could be accepted for an
+	// issuer that merely hosts the document.
+	if disc.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovery document is for issuer %q, expected %q", disc.Issuer, p.cfg.Issuer)
+	}
+	if disc.JWKSURI == "" {
+		return nil, errors.New("discovery document has no jwks_uri")
+	}
+
+	p.discovery = &disc
+	return p.discovery, nil
+}
+
+func (p *provider) getJSON(ctx context.Context, target string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", target, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(v)
+}
@@ -0,0 +1,179 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProvider(t *testing.T, idp *oidctest.Provider, clock func() time.Time) oidc.Provider {
+	t.Helper()
+
+	cfg := idp.Config()
+	cfg.Clock = clock
+	provider, err := oidc.NewProvider(cfg)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	provider := newProvider(t, idp, nil)
+
+	token := idp.IDToken(oidctest.Claims{
+		Subject:           "sub-1",
+		Email:             "Alice@Example.com",
+		EmailVerified:     true,
+		Name:              "Alice",
+		PreferredUsername: "alice",
+		Nonce:             "n-1",
+	})
+
+	identity, err := provider.VerifyIDToken(context.Background(), token, "n-1")
+	require.NoError(t, err)
+	assert.Equal(t, oidc.Identity{
+		Issuer:            idp.Issuer(),
+		Subject:           "sub-1",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		Name:              "Alice",
+		PreferredUsername: "alice",
+	}, identity)
+}
+
+func TestVerifyIDToken_Rejects(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	provider := newProvider(t, idp, nil)
+
+	foreignKey, err := jwt.GenerateKey("key-1", jwt.AlgorithmRS256)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		nonce string
+	}{
+		{
+			name:  "wrong audience",
+			token: idp.IDToken(oidctest.Claims{Subject: "sub-1", Audience: []string{"other-client"}}),
+		},
+		{
+			name:  "expired",
+			token: idp.IDToken(oidctest.Claims{Subject: "sub-1", IssuedAt: time.Now().Add(-2 * time.Hour)}),
+		},
+		{
+			name:  "bad signature",
+			token: idp.SignWith(foreignKey, oidctest.Claims{Subject: "sub-1"}),
+		},
+		{
+			name:  "nonce mismatch",
+			token: idp.IDToken(oidctest.Claims{Subject: "sub-1", Nonce: "n-1"}),
+			nonce: "n-2",
+		},
+		{
+			name:  "missing subject",
+			token: idp.IDToken(oidctest.Claims{}),
+		},
+		{
+			name:  "malformed",
+			token: "not-a-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.VerifyIDToken(context.Background(), tt.token, tt.nonce)
+			assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestVerifyIDToken_AcceptsSecondAudienceWithAuthorizedParty(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	provider := newProvider(t, idp, nil)
+
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-1", Audience: []string{oidctest.ClientID, "other-client"}})
+
+	_, err := provider.VerifyIDToken(context.Background(), token, "")
+	assert.NoError(t, err)
+}
+
+func TestVerifyIDToken_WrongIssuer(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+
+	cfg := idp.Config()
+	cfg.Issuer = idp.Issuer() + "/other"
+	provider, err := oidc.NewProvider(cfg)
+	require.NoError(t, err)
+
+	_, err = provider.VerifyIDToken(context.Background(), idp.IDToken(oidctest.Claims{Subject: "sub-1"}), "")
+	assert.Error(t, err)
+}
+
+func TestVerifyIDToken_KeyRotation(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+
+	now := time.Now()
+	provider := newProvider(t, idp, func() time.Time { return now })
+
+	_, err := provider.VerifyIDToken(context.Background(), idp.IDToken(oidctest.Claims{Subject: "sub-1"}), "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, idp.JWKSFetches())
+
+	idp.RotateKey("key-2", jwt.AlgorithmEdDSA)
+	token := idp.IDToken(oidctest.Claims{Subject: "sub-1"})
+
+	// Unknown keys refetch the JWKS at most once a minute.
+	_, err = provider.VerifyIDToken(context.Background(), token, "")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	assert.Equal(t, 1, idp.JWKSFetches())
+
+	now = now.Add(2 * time.Minute)
+	_, err = provider.VerifyIDToken(context.Background(), token, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, idp.JWKSFetches())
+}
+
+func TestExchange(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	provider := newProvider(t, idp, nil)
+
+	idp.Code("code-1", oidctest.Claims{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+
+	identity, err := provider.Exchange(context.Background(), "code-1", "verifier", "")
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", identity.Subject)
+	assert.True(t, identity.EmailVerified)
+
+	// Codes are single use.
+	_, err = provider.Exchange(context.Background(), "code-1", "verifier", "")
+	assert.ErrorIs(t, err, oidc.ErrExchangeFailed)
+}
+
+func TestExchange_WrongClientSecret(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+
+	cfg := idp.Config()
+	cfg.ClientSecret = "wrong"
+	provider, err := oidc.NewProvider(cfg)
+	require.NoError(t, err)
+
+	idp.Code("code-1", oidctest.Claims{Subject: "sub-1"})
+
+	_, err = provider.Exchange(context.Background(), "code-1", "", "")
+	assert.ErrorIs(t, err, oidc.ErrExchangeFailed)
+}
+
+func TestNewProvider_RequiresIssuerAndClientID(t *testing.T) {
+	_, err := oidc.NewProvider(oidc.Config{Issuer: "https://issuer.example.com"})
+	assert.Error(t, err)
+
+	_, err = oidc.NewProvider(oidc.Config{ClientID: "client"})
+	assert.Error(t, err)
+}
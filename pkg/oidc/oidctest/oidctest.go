@@ -0,0 +1,229 @@
+package oidctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/brenocoelho/messaging-app-go/pkg/oidc"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+const ClientID = "test-client"
+const ClientSecret = "test-secret"
+
+// Claims are the claims of an ID token issued by the fake provider.
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Nonce             string
+	// Audience defaults to ClientID.
+	Audience []string
+	// IssuedAt defaults to now, ExpiresAt to an hour later.
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Provider is a local identity provider serving discovery, JWKS and token
+// endpoints over httptest.
+type Provider struct {
+	*httptest.Server
+	t testing.TB
+
+	mu       sync.Mutex
+	key      jwt.Key
+	codes    map[string]Claims
+	jwksHits int
+}
+
+// NewProvider starts a provider signing with a fresh RS256 key. It is closed
+// when the test finishes.
+func NewProvider(t testing.TB) *Provider {
+	t.Helper()
+
+	p := &Provider{
+		t:     t,
+		codes: map[string]Claims{},
+	}
+	p.RotateKey("key-1", jwt.AlgorithmRS256)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+oidc.DiscoveryPath, p.serveDiscovery)
+	mux.HandleFunc("GET /jwks", p.serveJWKS)
+	mux.HandleFunc("POST /token", p.serveToken)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// Issuer is the provider's issuer URL.
+func (p *Provider) Issuer() string {
+	return p.URL
+}
+
+// Config returns a client configuration for the provider.
+func (p *Provider) Config() oidc.Config {
+	return oidc.Config{
+		Issuer:       p.Issuer(),
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		RedirectURL:  "http://localhost/callback",
+		HTTPClient:   p.Client(),
+	}
+}
+
+// RotateKey replaces the signing key; the old key is no longer published.
+func (p *Provider) RotateKey(id, alg string) {
+	p.t.Helper()
+
+	key, err := jwt.GenerateKey(id, alg)
+	if err != nil {
+		p.t.Fatalf("failed to generate key: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.key = key
+}
+
+// JWKSFetches reports how often the JWKS was requested.
+func (p *Provider) JWKSFetches() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jwksHits
+}
+
+// IDToken issues a signed ID token for claims.
+func (p *Provider) IDToken(claims Claims) string {
+	p.t.Helper()
+
+	p.mu.Lock()
+	key := p.key
+	p.mu.Unlock()
+
+	return p.sign(key, claims)
+}
+
+// SignWith issues an ID token signed by key, which the provider does not
+// publish.
+func (p *Provider) SignWith(key jwt.Key, claims Claims) string {
+	p.t.Helper()
+	return p.sign(key, claims)
+}
+
+// Code registers an authorization code that the token endpoint exchanges
+// for an ID token with claims.
+func (p *Provider) Code(code string, claims Claims) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.codes[code] = claims
+}
+
+func (p *Provider) sign(key jwt.Key, claims Claims) string {
+	now := time.Now()
+	if claims.IssuedAt.IsZero() {
+		claims.IssuedAt = now
+	}
+	if claims.ExpiresAt.IsZero() {
+		claims.ExpiresAt = claims.IssuedAt.Add(time.Hour)
+	}
+	if claims.Audience == nil {
+		claims.Audience = []string{ClientID}
+	}
+
+	mapClaims := gojwt.MapClaims{
+		"iss":            p.Issuer(),
+		"sub":            claims.Subject,
+		"aud":            claims.Audience,
+		"iat":            claims.IssuedAt.Unix(),
+		"exp":            claims.ExpiresAt.Unix(),
+		"email":          claims.Email,
+		"email_verified": claims.EmailVerified,
+	}
+	if len(claims.Audience) > 1 {
+		mapClaims["azp"] = ClientID
+	}
+	if claims.Name != "" {
+		mapClaims["name"] = claims.Name
+	}
+	if claims.PreferredUsername != "" {
+		mapClaims["preferred_username"] = claims.PreferredUsername
+	}
+	if claims.Nonce != "" {
+		mapClaims["nonce"] = claims.Nonce
+	}
+
+	method := gojwt.SigningMethod(gojwt.SigningMethodRS256)
+	if key.Algorithm == jwt.AlgorithmEdDSA {
+		method = gojwt.SigningMethodEdDSA
+	}
+	token := gojwt.NewWithClaims(method, mapClaims)
+	token.Header["kid"] = key.ID
+
+	signed, err := token.SignedString(key.Private)
+	if err != nil {
+		p.t.Fatalf("failed to sign ID token: %v", err)
+	}
+	return signed
+}
+
+func (p *Provider) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256", "EdDSA"},
+	})
+}
+
+func (p *Provider) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.jwksHits++
+	key := p.key
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, jwt.NewJWKS(key))
+}
+
+func (p *Provider) serveToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.FormValue("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	p.mu.Lock()
+	claims, ok := p.codes[r.FormValue("code")]
+	delete(p.codes, r.FormValue("code"))
+	p.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access-" + claims.Subject,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     p.IDToken(claims),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
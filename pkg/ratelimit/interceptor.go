@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Trailers set on calls rejected by the limiter.
+const (
+	LimitTrailer      = "ratelimit-limit"
+	RemainingTrailer  = "ratelimit-remaining"
+	RetryAfterTrailer = "retry-after"
+)
+
+// Interceptor limits calls per caller and full method name. It must run
+// after the JWT interceptor, so authenticated calls are limited per user;
+// other calls are limited per client IP.
+type Interceptor struct {
+	store  Store
+	config Config
+}
+
+func NewInterceptor(store Store, config Config) *Interceptor {
+	return &Interceptor{
+		store:  store,
+		config: config,
+	}
+}
+
+func (i *Interceptor) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	limit, result, ok := i.take(ctx, info.FullMethod)
+	if !ok {
+		_ = grpc.SetTrailer(ctx, trailer(limit, result))
+		return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamInterceptor limits opening streams; messages on an open stream are
+// not counted.
+func (i *Interceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	limit, result, ok := i.take(ss.Context(), info.FullMethod)
+	if !ok {
+		ss.SetTrailer(trailer(limit, result))
+		return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+	}
+
+	return handler(srv, ss)
+}
+
+// take reports whether the call may go ahead. When the store fails the call
+// is let through, so an outage of Redis does not take the API down with it.
+func (i *Interceptor) take(ctx context.Context, method string) (Limit, Result, bool) {
+	limit := i.config.limit(method)
+	if limit.Unlimited() {
+		return limit, Result{}, true
+	}
+
+	caller := callerKey(ctx)
+	result, err := i.store.Take(ctx, method+":"+caller, limit)
+	if err != nil {
+		slog.Error("Error checking rate limit, allowing call", "method", method, "caller", caller, "error", err)
+		return limit, Result{}, true
+	}
+
+	if !result.Allowed {
+		slog.Warn("Rate limit exceeded", "method", method, "caller", caller, "retryAfter", result.RetryAfter)
+	}
+	return limit, result, result.Allowed
+}
+
+func callerKey(ctx context.Context) string {
+	if userID, _, _, err := jwt.GetUserFromContext(ctx); err == nil {
+		return "user:" + userID
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return "ip:" + host
+		}
+		return "ip:" + p.Addr.String()
+	}
+	return "anonymous"
+}
+
+func trailer(limit Limit, result Result) metadata.MD {
+	seconds := int64(math.Ceil(result.RetryAfter.Seconds()))
+	return metadata.Pairs(
+		LimitTrailer, strconv.Itoa(limit.Burst),
+		RemainingTrailer, strconv.Itoa(result.Remaining),
+		RetryAfterTrailer, strconv.FormatInt(seconds, 10),
+	)
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	sendMessage = "/messaging.MessagesService/SendMessage"
+	subscribe   = "/messaging.MessagesService/SubscribeToChat"
+)
+
+// fakeTransportStream records the trailer a unary interceptor sets.
+type fakeTransportStream struct {
+	method  string
+	trailer metadata.MD
+}
+
+func (s *fakeTransportStream) Method() string                  { return s.method }
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error  { return nil }
+func (s *fakeTransportStream) SendHeader(md metadata.MD) error { return nil }
+func (s *fakeTransportStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	trailer metadata.MD
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+func (s *fakeServerStream) SetTrailer(md metadata.MD) {
+	s.trailer = metadata.Join(s.trailer, md)
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errors.New("redis down")
+}
+
+func userContext(userID string) context.Context {
+	return jwt.ContextWithClaims(context.Background(), &jwt.Claims{UserID: userID, Username: userID})
+}
+
+func newTestInterceptor() *Interceptor {
+	now := time.Now()
+	return NewInterceptor(NewMemoryStore(func() time.Time { return now }), Config{
+		Default: Limit{Rate: 100, Burst: 100},
+		Methods: map[string]Limit{
+			sendMessage: {Rate: 1, Burst: 2},
+			subscribe:   {Rate: 0.1, Burst: 1},
+		},
+	})
+}
+
+func callUnary(interceptor *Interceptor, ctx context.Context, method string) (*fakeTransportStream, error) {
+	stream := &fakeTransportStream{method: method}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+	_, err := interceptor.UnaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	return stream, err
+}
+
+func TestUnaryInterceptor_LimitsPerUserAndMethod(t *testing.T) {
+	interceptor := newTestInterceptor()
+	alice := userContext("alice")
+
+	for i := 0; i < 2; i++ {
+		_, err := callUnary(interceptor, alice, sendMessage)
+		require.NoError(t, err)
+	}
+
+	stream, err := callUnary(interceptor, alice, sendMessage)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"2"}, stream.trailer.Get(LimitTrailer))
+	assert.Equal(t, []string{"0"}, stream.trailer.Get(RemainingTrailer))
+	assert.Equal(t, []string{"1"}, stream.trailer.Get(RetryAfterTrailer))
+
+	// Another user, and another method for the same user, are not affected.
+	_, err = callUnary(interceptor, userContext("bob"), sendMessage)
+	assert.NoError(t, err)
+	_, err = callUnary(interceptor, alice, "/messaging.ChatsService/ListChats")
+	assert.NoError(t, err)
+}
+
+func TestUnaryInterceptor_LimitsAnonymousCallsPerIP(t *testing.T) {
+	interceptor := newTestInterceptor()
+	fromIP := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000}})
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := callUnary(interceptor, fromIP("203.0.113.7"), sendMessage)
+		require.NoError(t, err)
+	}
+	_, err := callUnary(interceptor, fromIP("203.0.113.7"), sendMessage)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = callUnary(interceptor, fromIP("203.0.113.8"), sendMessage)
+	assert.NoError(t, err)
+}
+
+func TestUnaryInterceptor_UnlimitedMethod(t *testing.T) {
+	interceptor := NewInterceptor(failingStore{}, Config{})
+
+	_, err := callUnary(interceptor, userContext("alice"), sendMessage)
+	assert.NoError(t, err)
+}
+
+func TestUnaryInterceptor_AllowsWhenStoreFails(t *testing.T) {
+	interceptor := NewInterceptor(failingStore{}, Config{Default: Limit{Rate: 1, Burst: 1}})
+
+	_, err := callUnary(interceptor, userContext("alice"), sendMessage)
+	assert.NoError(t, err)
+}
+
+func TestStreamInterceptor_LimitsOpeningStreams(t *testing.T) {
+	interceptor := newTestInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: subscribe, IsServerStream: true}
+	opened := 0
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		opened++
+		return nil
+	}
+
+	stream := &fakeServerStream{ctx: userContext("alice")}
+	require.NoError(t, interceptor.StreamInterceptor(nil, stream, info, handler))
+
+	stream = &fakeServerStream{ctx: userContext("alice")}
+	err := interceptor.StreamInterceptor(nil, stream, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, []string{"10"}, stream.trailer.Get(RetryAfterTrailer))
+	assert.Equal(t, 1, opened)
+}
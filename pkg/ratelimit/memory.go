@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops buckets that have
+// refilled, so idle users do not keep memory.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+type memoryStore struct {
+	clock func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryStore keeps buckets in process memory, for single instances and
+// tests. clock defaults to time.Now.
+func NewMemoryStore(clock func() time.Time) Store {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &memoryStore{
+		clock:   clock,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := s.clock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed.Seconds()*limit.Rate)
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		return Result{Remaining: 0, RetryAfter: retryAfter(b.tokens, limit)}, nil
+	}
+
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		refill := time.Duration((float64(b.limit.Burst) - b.tokens) / b.limit.Rate * float64(time.Second))
+		if now.Sub(b.updated) >= refill {
+			delete(s.buckets, key)
+		}
+	}
+}
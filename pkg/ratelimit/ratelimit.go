@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket: it holds up to Burst tokens and refills Rate
+// tokens per second. Every call takes a token. A zero Rate means no limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Result is the state of a bucket after a call took, or tried to take, a
+// token.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long until the next token, when the call was not
+	// allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets by key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Config holds the limit for each full method name, such as
+// "/messaging.MessagesService/SendMessage". Methods without an entry use
+// Default.
+type Config struct {
+	Default Limit
+	Methods map[string]Limit
+}
+
+func (c Config) limit(method string) Limit {
+	if limit, ok := c.Methods[method]; ok {
+		return limit
+	}
+	return c.Default
+}
+
+// ParseMethodLimits reads limits in the form
+// "/pkg.Service/Method=rate:burst,...". The leading slash is optional and
+// the burst defaults to the rate rounded up.
+func ParseMethodLimits(value string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		method, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q, expected method=rate:burst", entry)
+		}
+
+		limit, err := ParseLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s: %w", method, err)
+		}
+
+		method = strings.TrimSpace(method)
+		if !strings.HasPrefix(method, "/") {
+			method = "/" + method
+		}
+		if strings.Count(method, "/") != 2 {
+			return nil, fmt.Errorf("invalid method %q, expected /package.Service/Method", method)
+		}
+		limits[method] = limit
+	}
+	return limits, nil
+}
+
+// ParseLimit reads "rate:burst" or just "rate", in tokens per second.
+func ParseLimit(value string) (Limit, error) {
+	rateValue, burstValue, hasBurst := strings.Cut(strings.TrimSpace(value), ":")
+
+	rate, err := strconv.ParseFloat(rateValue, 64)
+	if err != nil || rate < 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return Limit{}, fmt.Errorf("invalid rate %q", rateValue)
+	}
+
+	burst := int(math.Ceil(rate))
+	if hasBurst {
+		burst, err = strconv.Atoi(burstValue)
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("invalid burst %q", burstValue)
+		}
+	}
+	if rate > 0 && burst < 1 {
+		burst = 1
+	}
+
+	return Limit{Rate: rate, Burst: burst}, nil
+}
+
+// retryAfter is how long a bucket with tokens left takes to refill to one.
+func retryAfter(tokens float64, limit Limit) time.Duration {
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - tokens) / limit.Rate * float64(time.Second)))
+}
@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMethodLimits(t *testing.T) {
+	limits, err := ParseMethodLimits("/messaging.MessagesService/SendMessage=5:20, messaging.MessagesService/SubscribeToChat=0.5:3,messaging.ChatsService/ListChats=10")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]Limit{
+		"/messaging.MessagesService/SendMessage":     {Rate: 5, Burst: 20},
+		"/messaging.MessagesService/SubscribeToChat": {Rate: 0.5, Burst: 3},
+		"/messaging.ChatsService/ListChats":          {Rate: 10, Burst: 10},
+	}, limits)
+}
+
+func TestParseMethodLimits_Invalid(t *testing.T) {
+	for _, value := range []string{
+		"/messaging.MessagesService/SendMessage",
+		"/messaging.MessagesService/SendMessage=fast",
+		"/messaging.MessagesService/SendMessage=-1",
+		"/messaging.MessagesService/SendMessage=5:0",
+		"SendMessage=5:20",
+	} {
+		_, err := ParseMethodLimits(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseLimit_Unlimited(t *testing.T) {
+	limit, err := ParseLimit("0")
+	require.NoError(t, err)
+	assert.True(t, limit.Unlimited())
+}
+
+func TestMemoryStore_TakesBurstThenRefills(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore(func() time.Time { return now })
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	for want := 2; want >= 0; want-- {
+		result, err := store.Take(ctx, "key", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, want, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = store.Take(ctx, "key", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Other keys have their own bucket.
+	result, err = store.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestMemoryStore_RefillIsCappedAtBurst(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore(func() time.Time { return now })
+	limit := Limit{Rate: 1, Burst: 2}
+
+	_, err := store.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	result, err := store.Take(context.Background(), "key", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+func TestMemoryStore_SweepsFullBuckets(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore(func() time.Time { return now }).(*memoryStore)
+	limit := Limit{Rate: 1, Burst: 5}
+
+	_, err := store.Take(context.Background(), "idle", limit)
+	require.NoError(t, err)
+
+	now = now.Add(2 * sweepInterval)
+	_, err = store.Take(context.Background(), "active", limit)
+	require.NoError(t, err)
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "active")
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from a bucket atomically. It reads Redis's
+// clock, so instances with skewed clocks share buckets correctly. Buckets
+// expire once they would be full again.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) / 1000 * rate)
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore keeps buckets in Redis, so every instance shares them.
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{
+		client: client,
+	}
+}
+
+func (s *redisStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := takeScript.Run(ctx, s.client, []string{bucketKey(key)}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		slog.Error("Error taking rate limit token in Redis", "key", key, "error", err)
+		return Result{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid rate limit tokens %v: %w", values[1], err)
+	}
+
+	if allowed != 1 {
+		return Result{Remaining: 0, RetryAfter: retryAfter(tokens, limit)}, nil
+	}
+	return Result{Allowed: true, Remaining: int(tokens)}, nil
+}
+
+func bucketKey(key string) string {
+	return fmt.Sprintf("rate_limit_bucket:%s", key)
+}
@@ -48,6 +48,19 @@ func (s *IdempotencyService) CheckAndSetIdempotency(ctx context.Context, key str
 	return false, nil
 }
 
+// Release deletes key, so a request whose key was set but which then failed
+// can be retried.
+func (s *IdempotencyService) Release(ctx context.Context, key string) error {
+	redisKey := fmt.Sprintf("idempotency:%s", key)
+
+	if err := s.client.Del(ctx, redisKey).Err(); err != nil {
+		slog.Error("Error releasing idempotency key in Redis", "key", key, "error", err)
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
 func (s *IdempotencyService) GetTTL() time.Duration {
 	return s.ttl
 }
@@ -0,0 +1,151 @@
+package redisconn
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/brenocoelho/messaging-app-go/pkg/backoff"
+	"github.com/redis/go-redis/v9"
+)
+
+// ThrottlePolicy says how failed attempts on a key are slowed down. Failures
+// are counted in a sliding Window. From the BackoffAfter-th failure on, the
+// next attempt has to wait BackoffBase, doubling with each further failure up
+// to BackoffMax. LockoutAfter failures lock the key for LockoutDuration. Zero
+// values disable backoff and lockout.
+type ThrottlePolicy struct {
+	Window          time.Duration
+	BackoffAfter    int
+	BackoffBase     time.Duration
+	BackoffMax      time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+}
+
+// Backoff returns how long to wait after the last of failures.
+func (p ThrottlePolicy) Backoff(failures int) time.Duration {
+	if p.BackoffAfter <= 0 || p.BackoffBase <= 0 || failures < p.BackoffAfter {
+		return 0
+	}
+
+	return backoff.Exponential(p.BackoffBase, p.BackoffMax, failures-p.BackoffAfter)
+}
+
+// FailureResult describes a key after a failed attempt. Locked is only set by
+// the failure that started a lockout, so each lockout is reported once.
+type FailureResult struct {
+	Failures int
+	Locked   bool
+}
+
+// LoginThrottle slows down repeated failed attempts, such as password
+// guesses, per key.
+type LoginThrottle interface {
+	// Check reports how long the caller has to wait before the next attempt
+	// on key, or zero if it may try now.
+	Check(ctx context.Context, key string, policy ThrottlePolicy) (time.Duration, error)
+	RecordFailure(ctx context.Context, key string, policy ThrottlePolicy) (FailureResult, error)
+	// Reset forgets the failures of key; a lockout stays in place.
+	Reset(ctx context.Context, key string) error
+}
+
+type loginThrottle struct {
+	client *redis.Client
+}
+
+func NewLoginThrottle(client *redis.Client) LoginThrottle {
+	return &loginThrottle{
+		client: client,
+	}
+}
+
+func (t *loginThrottle) Check(ctx context.Context, key string, policy ThrottlePolicy) (time.Duration, error) {
+	now := time.Now()
+
+	var lockTTL *redis.DurationCmd
+	var count *redis.IntCmd
+	var last *redis.ZSliceCmd
+	_, err := t.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		lockTTL = pipe.PTTL(ctx, loginLockoutKey(key))
+		count = pipe.ZCount(ctx, loginFailuresKey(key), strconv.FormatInt(now.Add(-policy.Window).UnixMilli(), 10), "+inf")
+		last = pipe.ZRevRangeWithScores(ctx, loginFailuresKey(key), 0, 0)
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error checking login throttle in Redis", "key", key, "error", err)
+		return 0, fmt.Errorf("failed to check login throttle: %w", err)
+	}
+
+	if lockTTL.Val() > 0 {
+		return lockTTL.Val(), nil
+	}
+
+	delay := policy.Backoff(int(count.Val()))
+	if delay <= 0 || len(last.Val()) == 0 {
+		return 0, nil
+	}
+
+	lastFailure := time.UnixMilli(int64(last.Val()[0].Score))
+	if wait := lastFailure.Add(delay).Sub(now); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+func (t *loginThrottle) RecordFailure(ctx context.Context, key string, policy ThrottlePolicy) (FailureResult, error) {
+	now := time.Now()
+	failuresKey := loginFailuresKey(key)
+
+	var count *redis.IntCmd
+	_, err := t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, failuresKey, redis.Z{
+			Score: float64(now.UnixMilli()),
+			// Members must be unique for concurrent failures to count apart.
+			Member: strconv.FormatInt(now.UnixNano(), 10) + ":" + strconv.FormatUint(rand.Uint64(), 36),
+		})
+		pipe.ZRemRangeByScore(ctx, failuresKey, "-inf", "("+strconv.FormatInt(now.Add(-policy.Window).UnixMilli(), 10))
+		count = pipe.ZCard(ctx, failuresKey)
+		pipe.PExpire(ctx, failuresKey, policy.Window)
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error recording login failure in Redis", "key", key, "error", err)
+		return FailureResult{}, fmt.Errorf("failed to record login failure: %w", err)
+	}
+
+	result := FailureResult{Failures: int(count.Val())}
+	if policy.LockoutAfter <= 0 || result.Failures < policy.LockoutAfter {
+		return result, nil
+	}
+
+	result.Locked, err = t.client.SetNX(ctx, loginLockoutKey(key), "1", policy.LockoutDuration).Result()
+	if err != nil {
+		slog.Error("Error locking out key in Redis", "key", key, "error", err)
+		return FailureResult{}, fmt.Errorf("failed to lock out: %w", err)
+	}
+
+	if result.Locked {
+		slog.Warn("Login locked out", "key", key, "failures", result.Failures, "duration", policy.LockoutDuration)
+	}
+	return result, nil
+}
+
+func (t *loginThrottle) Reset(ctx context.Context, key string) error {
+	if err := t.client.Del(ctx, loginFailuresKey(key)).Err(); err != nil {
+		slog.Error("Error resetting login throttle in Redis", "key", key, "error", err)
+		return fmt.Errorf("failed to reset login throttle: %w", err)
+	}
+	return nil
+}
+
+func loginFailuresKey(key string) string {
+	return fmt.Sprintf("login_failures:%s", key)
+}
+
+func loginLockoutKey(key string) string {
+	return fmt.Sprintf("login_lockout:%s", key)
+}
@@ -0,0 +1,44 @@
+package redisconn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottlePolicy_Backoff(t *testing.T) {
+	policy := ThrottlePolicy{
+		BackoffAfter: 3,
+		BackoffBase:  time.Second,
+		BackoffMax:   10 * time.Second,
+	}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{6, 8 * time.Second},
+		{7, 10 * time.Second},
+		{100, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, policy.Backoff(tt.failures), "failures=%d", tt.failures)
+	}
+}
+
+func TestThrottlePolicy_BackoffDisabled(t *testing.T) {
+	assert.Zero(t, ThrottlePolicy{}.Backoff(50))
+	assert.Zero(t, ThrottlePolicy{BackoffAfter: 1}.Backoff(50))
+}
+
+func TestThrottlePolicy_BackoffUncapped(t *testing.T) {
+	policy := ThrottlePolicy{BackoffAfter: 1, BackoffBase: time.Second}
+	assert.Equal(t, 16*time.Second, policy.Backoff(5))
+}
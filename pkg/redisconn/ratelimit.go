@@ -0,0 +1,62 @@
+package redisconn
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RateLimiter counts events per key in fixed windows.
+type RateLimiter interface {
+	// Allow records one event for key. It reports whether the event is within
+	// limit events per window and, when it is not, how long until the window
+	// resets.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+}
+
+type rateLimiter struct {
+	client *redis.Client
+}
+
+func NewRateLimiter(client *redis.Client) RateLimiter {
+	return &rateLimiter{
+		client: client,
+	}
+}
+
+func (l *rateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	redisKey := rateLimitKey(key)
+
+	var count *redis.IntCmd
+	var ttl *redis.DurationCmd
+	_, err := l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, redisKey)
+		// Only the first event of a window sets the expiry.
+		pipe.ExpireNX(ctx, redisKey, window)
+		ttl = pipe.PTTL(ctx, redisKey)
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error checking rate limit in Redis", "key", key, "error", err)
+		return false, 0, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	if count.Val() <= int64(limit) {
+		return true, 0, nil
+	}
+
+	retryAfter := ttl.Val()
+	if retryAfter <= 0 {
+		retryAfter = window
+	}
+
+	slog.Info("Rate limit exceeded", "key", key, "limit", limit, "retryAfter", retryAfter)
+	return false, retryAfter, nil
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
@@ -0,0 +1,137 @@
+package redisconn
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RevocationStore keeps access tokens and sessions that were revoked before
+// their tokens expired, and a per-user token generation. Bumping the
+// generation revokes every token issued before it.
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error
+	RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, tokenID, sessionID, userID string, generation int64) (bool, error)
+	GetGeneration(ctx context.Context, userID string) (int64, error)
+	IncrementGeneration(ctx context.Context, userID string) (int64, error)
+}
+
+type revocationStore struct {
+	client *redis.Client
+}
+
+func NewRevocationStore(client *redis.Client) RevocationStore {
+	return &revocationStore{
+		client: client,
+	}
+}
+
+// RevokeToken stores tokenID for ttl, which should be the token's remaining
+// lifetime; after that the token is rejected as expired anyway.
+func (s *revocationStore) RevokeToken(ctx context.Context, tokenID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	err := s.client.Set(ctx, revokedTokenKey(tokenID), "1", ttl).Err()
+	if err != nil {
+		slog.Error("Error revoking token in Redis", "tokenID", tokenID, "error", err)
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	slog.Info("Token revoked", "tokenID", tokenID, "ttl", ttl)
+	return nil
+}
+
+// RevokeSession rejects every access token of sessionID for ttl, which should
+// be the access token lifetime; later tokens cannot be issued for a revoked
+// session.
+func (s *revocationStore) RevokeSession(ctx context.Context, sessionID string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+
+	err := s.client.Set(ctx, revokedSessionKey(sessionID), "1", ttl).Err()
+	if err != nil {
+		slog.Error("Error revoking session in Redis", "sessionID", sessionID, "error", err)
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	slog.Info("Session revoked", "sessionID", sessionID, "ttl", ttl)
+	return nil
+}
+
+// IsRevoked checks the token, its session and the user's generation in one
+// round trip.
+func (s *revocationStore) IsRevoked(ctx context.Context, tokenID, sessionID, userID string, generation int64) (bool, error) {
+	values, err := s.client.MGet(ctx, revokedTokenKey(tokenID), revokedSessionKey(sessionID), tokenGenerationKey(userID)).Result()
+	if err != nil {
+		slog.Error("Error checking token revocation in Redis", "tokenID", tokenID, "error", err)
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+
+	if values[0] != nil || (sessionID != "" && values[1] != nil) {
+		return true, nil
+	}
+
+	current, err := parseGeneration(values[2])
+	if err != nil {
+		return false, err
+	}
+
+	return generation < current, nil
+}
+
+func (s *revocationStore) GetGeneration(ctx context.Context, userID string) (int64, error) {
+	value, err := s.client.Get(ctx, tokenGenerationKey(userID)).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		slog.Error("Error getting token generation from Redis", "userID", userID, "error", err)
+		return 0, fmt.Errorf("failed to get token generation: %w", err)
+	}
+
+	return parseGeneration(value)
+}
+
+func (s *revocationStore) IncrementGeneration(ctx context.Context, userID string) (int64, error) {
+	generation, err := s.client.Incr(ctx, tokenGenerationKey(userID)).Result()
+	if err != nil {
+		slog.Error("Error incrementing token generation in Redis", "userID", userID, "error", err)
+		return 0, fmt.Errorf("failed to increment token generation: %w", err)
+	}
+
+	slog.Info("Token generation incremented", "userID", userID, "generation", generation)
+	return generation, nil
+}
+
+func parseGeneration(value any) (int64, error) {
+	if value == nil {
+		return 0, nil
+	}
+
+	generation, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token generation %q: %w", value, err)
+	}
+
+	return generation, nil
+}
+
+func revokedTokenKey(tokenID string) string {
+	return fmt.Sprintf("revoked_token:%s", tokenID)
+}
+
+func revokedSessionKey(sessionID string) string {
+	return fmt.Sprintf("revoked_session:%s", sessionID)
+}
+
+func tokenGenerationKey(userID string) string {
+	return fmt.Sprintf("token_generation:%s", userID)
+}
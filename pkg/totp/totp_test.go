@@ -0,0 +1,66 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The SHA-1 test vectors of RFC 6238, appendix B, cut to 6 digits.
+func TestCode_RFC6238(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, Code(secret, Step(time.Unix(tt.unix, 0))), "time %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	matched, ok := Validate(secret, "050471", now, 1)
+	require.True(t, ok)
+	assert.Equal(t, step, matched)
+
+	// One step of drift either way is accepted.
+	matched, ok = Validate(secret, Code(secret, step-1), now, 1)
+	require.True(t, ok)
+	assert.Equal(t, step-1, matched)
+	_, ok = Validate(secret, Code(secret, step+1), now, 1)
+	assert.True(t, ok)
+
+	_, ok = Validate(secret, Code(secret, step-2), now, 1)
+	assert.False(t, ok)
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	uri, err := url.Parse(URI("Messaging App", "alice@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Messaging App:alice@example.com", uri.Path)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri.Query().Get("secret"))
+	assert.Equal(t, "Messaging App", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress is returned for webhook URLs that resolve to a
+// loopback, private, link-local or otherwise non-public address.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// reservedPrefixes are the non-public ranges netip has no method for.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// IsPublicAddr reports whether addr may be called by webhooks. Loopback,
+// private, link-local (including the cloud metadata address
+// 169.254.169.254), multicast and reserved addresses may not, so webhooks
+// cannot reach the server's own network.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewClient returns the HTTP client for calls to user supplied URLs. It only
+// connects to public addresses, checked after DNS resolution so a hostname
+// cannot point it elsewhere, ignores proxy settings, and does not follow
+// redirects.
+func NewClient(timeout time.Duration) *http.Client {
+	return newClient(timeout, IsPublicAddr)
+}
+
+func newClient(timeout time.Duration, allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowed(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// A redirect is returned as the response, which is not 2xx and so
+		// fails the call.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,199 @@
+// Package webhook signs and sends webhook requests. A request carries its
+// body's HMAC-SHA256 in the X-Webhook-Signature header, as
+// "t=<unix seconds>,v1=<hex>", where the MAC covers "<t>.<body>" so that a
+// captured request cannot be replayed later.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+
+	// DefaultTolerance is how old a signature Verify accepts by default.
+	DefaultTolerance = 5 * time.Minute
+
+	defaultTimeout = 10 * time.Second
+	// maxDrainBody limits how much of an unused response is read so the
+	// connection can be reused.
+	maxDrainBody = 512
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrResponseTooLarge = errors.New("webhook response too large")
+)
+
+// Sign returns the signature header value for body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + mac(secret, t, body)
+}
+
+// Verify checks a signature header made by Sign. Signatures older or newer
+// than tolerance are rejected; a zero tolerance uses DefaultTolerance.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var t string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	age := now.Sub(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	expected := mac(secret, t, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func mac(secret, timestamp string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Request is one webhook call.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// Sender posts webhook requests.
+type Sender interface {
+	// Send posts req and returns the response status code. Responses other
+	// than 2xx are errors; the status code is 0 if no response arrived.
+	Send(ctx context.Context, req Request) (int, error)
+	// Call posts req like Send and returns the body of the response, which
+	// may be at most limit bytes.
+	Call(ctx context.Context, req Request, limit int64) ([]byte, error)
+}
+
+type sender struct {
+	client *http.Client
+	clock  func() time.Time
+}
+
+// NewSender returns a Sender using client, or NewClient with a 10 second
+// timeout if client is nil. clock defaults to time.Now.
+func NewSender(client *http.Client, clock func() time.Time) Sender {
+	if client == nil {
+		client = NewClient(defaultTimeout)
+	}
+	if clock == nil {
+		clock = time.Now
+	}
+	return &sender{
+		client: client,
+		clock:  clock,
+	}
+}
+
+func (s *sender) Send(ctx context.Context, req Request) (int, error) {
+	resp, err := s.post(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	defer drain(resp)
+
+	if err := checkStatus(resp); err != nil {
+		return resp.StatusCode, err
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (s *sender) Call(ctx context.Context, req Request, limit int64) ([]byte, error) {
+	resp, err := s.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > limit {
+		return nil, ErrResponseTooLarge
+	}
+
+	return body, nil
+}
+
+func (s *sender) post(ctx context.Context, req Request) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "messaging-app-webhooks")
+	httpReq.Header.Set(EventHeader, req.Event)
+	httpReq.Header.Set(DeliveryHeader, req.DeliveryID)
+	httpReq.Header.Set(SignatureHeader, Sign(req.Secret, s.clock(), req.Body))
+
+	return s.client.Do(httpReq)
+}
+
+// checkStatus turns responses other than 2xx into errors. The body is left
+// out: errors are shown to the webhook's owner, and a URL pointing at some
+// other service must not read its responses back through them.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// drain reads what is left of the body, up to a limit, and closes it so the
+// connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBody))
+	resp.Body.Close()
+}
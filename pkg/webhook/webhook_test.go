@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"message.created"}`)
+	header := Sign("secret", now, body)
+
+	assert.NoError(t, Verify("secret", header, body, 0, now.Add(time.Minute)))
+	assert.ErrorIs(t, Verify("other", header, body, 0, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, []byte(`{}`), 0, now), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, body, 0, now.Add(DefaultTolerance+time.Second)), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", "v1=abc", body, 0, now), ErrInvalidSignature)
+}
+
+func TestSender_Send(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	var received *http.Request
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewSender(server.Client(), func() time.Time { return now })
+	code, err := sender.Send(context.Background(), Request{
+		URL:        server.URL,
+		Secret:     "secret",
+		Event:      "message.created",
+		DeliveryID: "delivery1",
+		Body:       []byte(`{"id":"1"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "message.created", received.Header.Get(EventHeader))
+	assert.Equal(t, "delivery1", received.Header.Get(DeliveryHeader))
+	assert.Equal(t, `{"id":"1"}`, string(receivedBody))
+	assert.NoError(t, Verify("secret", received.Header.Get(SignatureHeader), receivedBody, 0, now))
+}
+
+func TestSender_SendFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := NewSender(server.Client(), nil)
+	code, err := sender.Send(context.Background(), Request{URL: server.URL, Secret: "secret", Body: []byte(`{}`)})
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.ErrorContains(t, err, "503")
+	// The response body is not passed on to the webhook's owner.
+	assert.NotContains(t, err.Error(), "try later")
+
+	server.Close()
+	code, err = sender.Send(context.Background(), Request{URL: server.URL, Secret: "secret", Body: []byte(`{}`)})
+	assert.Zero(t, code)
+	assert.Error(t, err)
+}
+
+func TestSender_Call(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify("secret", r.Header.Get(SignatureHeader), body, 0, now); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"text":"pong"}`))
+	}))
+	defer server.Close()
+
+	sender := NewSender(server.Client(), func() time.Time { return now })
+	body, err := sender.Call(context.Background(), Request{URL: server.URL, Secret: "secret", Event: "command", Body: []byte(`{}`)}, 64)
+	require.NoError(t, err)
+	assert.Equal(t, `{"text":"pong"}`, string(body))
+
+	_, err = sender.Call(context.Background(), Request{URL: server.URL, Secret: "secret", Body: []byte(`{}`)}, 4)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+
+	_, err = sender.Call(context.Background(), Request{URL: server.URL, Secret: "wrong", Body: []byte(`{}`)}, 64)
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{addr: "93.184.215.14", public: true},
+		{addr: "2606:2800:21f:cb07:6820:80da:af6b:8b2c", public: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "100.64.0.1"},
+		{addr: "0.0.0.0"},
+		{addr: "fd00::1"},
+		{addr: "fe80::1"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "224.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, IsPublicAddr(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestNewClient_RejectsPrivateAddresses(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	sender := NewSender(NewClient(time.Second), nil)
+	code, err := sender.Send(context.Background(), Request{URL: server.URL, Secret: "secret", Body: []byte(`{}`)})
+	assert.Zero(t, code)
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, called)
+}
+
+func TestNewClient_DoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	// The test server is on loopback, so only the redirect rule is checked.
+	client := newClient(time.Second, func(netip.Addr) bool { return true })
+	code, err := NewSender(client, nil).Send(context.Background(), Request{URL: server.URL, Secret: "secret", Body: []byte(`{}`)})
+	assert.Equal(t, http.StatusTemporaryRedirect, code)
+	assert.Error(t, err)
+	assert.False(t, redirected)
+}
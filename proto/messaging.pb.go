@@ -22,12 +22,84 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type MessageType int32
+
+const (
+	MessageType_MESSAGE_TYPE_UNSPECIFIED  MessageType = 0
+	MessageType_MESSAGE_TYPE_NEW          MessageType = 1
+	MessageType_MESSAGE_TYPE_READ         MessageType = 2
+	MessageType_MESSAGE_TYPE_TYPING       MessageType = 3
+	MessageType_MESSAGE_TYPE_ONLINE       MessageType = 4
+	MessageType_MESSAGE_TYPE_OFFLINE      MessageType = 5
+	MessageType_MESSAGE_TYPE_CHAT_UPDATED MessageType = 6
+	MessageType_MESSAGE_TYPE_EPHEMERAL    MessageType = 7 // Only for this subscriber, and not stored.
+)
+
+// Enum value maps for MessageType.
+var (
+	MessageType_name = map[int32]string{
+		0: "MESSAGE_TYPE_UNSPECIFIED",
+		1: "MESSAGE_TYPE_NEW",
+		2: "MESSAGE_TYPE_READ",
+		3: "MESSAGE_TYPE_TYPING",
+		4: "MESSAGE_TYPE_ONLINE",
+		5: "MESSAGE_TYPE_OFFLINE",
+		6: "MESSAGE_TYPE_CHAT_UPDATED",
+		7: "MESSAGE_TYPE_EPHEMERAL",
+	}
+	MessageType_value = map[string]int32{
+		"MESSAGE_TYPE_UNSPECIFIED":  0,
+		"MESSAGE_TYPE_NEW":          1,
+		"MESSAGE_TYPE_READ":         2,
+		"MESSAGE_TYPE_TYPING":       3,
+		"MESSAGE_TYPE_ONLINE":       4,
+		"MESSAGE_TYPE_OFFLINE":      5,
+		"MESSAGE_TYPE_CHAT_UPDATED": 6,
+		"MESSAGE_TYPE_EPHEMERAL":    7,
+	}
+)
+
+func (x MessageType) Enum() *MessageType {
+	p := new(MessageType)
+	*p = x
+	return p
+}
+
+func (x MessageType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_messaging_proto_enumTypes[0].Descriptor()
+}
+
+func (MessageType) Type() protoreflect.EnumType {
+	return &file_proto_messaging_proto_enumTypes[0]
+}
+
+func (x MessageType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MessageType.Descriptor instead.
+func (MessageType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{0}
+}
+
 type User struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username           string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Email              string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DisplayName        string                 `protobuf:"bytes,5,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Bio                string                 `protobuf:"bytes,6,opt,name=bio,proto3" json:"bio,omitempty"`
+	AvatarAttachmentId string                 `protobuf:"bytes,7,opt,name=avatar_attachment_id,json=avatarAttachmentId,proto3" json:"avatar_attachment_id,omitempty"`
+	Discoverable       bool                   `protobuf:"varint,8,opt,name=discoverable,proto3" json:"discoverable,omitempty"`
+	EmailVerified      bool                   `protobuf:"varint,9,opt,name=email_verified,json=emailVerified,proto3" json:"email_verified,omitempty"`
+	// Bots post on behalf of their owner and cannot log in with a password.
+	Bot           bool   `protobuf:"varint,10,opt,name=bot,proto3" json:"bot,omitempty"`
+	BotOwnerId    string `protobuf:"bytes,11,opt,name=bot_owner_id,json=botOwnerId,proto3" json:"bot_owner_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -90,6 +162,55 @@ func (x *User) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *User) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *User) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *User) GetAvatarAttachmentId() string {
+	if x != nil {
+		return x.AvatarAttachmentId
+	}
+	return ""
+}
+
+func (x *User) GetDiscoverable() bool {
+	if x != nil {
+		return x.Discoverable
+	}
+	return false
+}
+
+func (x *User) GetEmailVerified() bool {
+	if x != nil {
+		return x.EmailVerified
+	}
+	return false
+}
+
+func (x *User) GetBot() bool {
+	if x != nil {
+		return x.Bot
+	}
+	return false
+}
+
+func (x *User) GetBotOwnerId() string {
+	if x != nil {
+		return x.BotOwnerId
+	}
+	return ""
+}
+
 type Message struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -98,6 +219,7 @@ type Message struct {
 	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
 	SentAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
 	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Kind          string                 `protobuf:"bytes,7,opt,name=kind,proto3" json:"kind,omitempty"` // "USER", "SYSTEM"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -174,15 +296,30 @@ func (x *Message) GetStatus() string {
 	return ""
 }
 
+func (x *Message) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
 type Chat struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Members       []*User                `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
-	LastMessage   *Message               `protobuf:"bytes,6,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Members            []*User                `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
+	LastMessage        *Message               `protobuf:"bytes,6,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
+	Settings           *ChatSettings          `protobuf:"bytes,7,opt,name=settings,proto3" json:"settings,omitempty"`
+	LastActivityAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_activity_at,json=lastActivityAt,proto3" json:"last_activity_at,omitempty"`
+	UnreadCount        int32                  `protobuf:"varint,9,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	ParticipantCount   int32                  `protobuf:"varint,10,opt,name=participant_count,json=participantCount,proto3" json:"participant_count,omitempty"`
+	Description        string                 `protobuf:"bytes,11,opt,name=description,proto3" json:"description,omitempty"`
+	AvatarAttachmentId string                 `protobuf:"bytes,12,opt,name=avatar_attachment_id,json=avatarAttachmentId,proto3" json:"avatar_attachment_id,omitempty"`
+	Kind               string                 `protobuf:"bytes,13,opt,name=kind,proto3" json:"kind,omitempty"`             // "CHAT", "CHANNEL"
+	Visibility         string                 `protobuf:"bytes,14,opt,name=visibility,proto3" json:"visibility,omitempty"` // "PUBLIC", "PRIVATE"
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Chat) Reset() {
@@ -250,6 +387,139 @@ func (x *Chat) GetLastMessage() *Message {
 	return nil
 }
 
+func (x *Chat) GetSettings() *ChatSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *Chat) GetLastActivityAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActivityAt
+	}
+	return nil
+}
+
+func (x *Chat) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *Chat) GetParticipantCount() int32 {
+	if x != nil {
+		return x.ParticipantCount
+	}
+	return 0
+}
+
+func (x *Chat) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Chat) GetAvatarAttachmentId() string {
+	if x != nil {
+		return x.AvatarAttachmentId
+	}
+	return ""
+}
+
+func (x *Chat) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Chat) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+// Per-member chat preferences; they only apply to the calling user.
+type ChatSettings struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MutedUntil        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"`
+	Archived          bool                   `protobuf:"varint,2,opt,name=archived,proto3" json:"archived,omitempty"`
+	Pinned            bool                   `protobuf:"varint,3,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	PinOrder          int32                  `protobuf:"varint,4,opt,name=pin_order,json=pinOrder,proto3" json:"pin_order,omitempty"`
+	NotificationLevel string                 `protobuf:"bytes,5,opt,name=notification_level,json=notificationLevel,proto3" json:"notification_level,omitempty"` // "ALL", "MENTIONS", "NONE"
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChatSettings) Reset() {
+	*x = ChatSettings{}
+	mi := &file_proto_messaging_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatSettings) ProtoMessage() {}
+
+func (x *ChatSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatSettings.ProtoReflect.Descriptor instead.
+func (*ChatSettings) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChatSettings) GetMutedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return nil
+}
+
+func (x *ChatSettings) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *ChatSettings) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *ChatSettings) GetPinOrder() int32 {
+	if x != nil {
+		return x.PinOrder
+	}
+	return 0
+}
+
+func (x *ChatSettings) GetNotificationLevel() string {
+	if x != nil {
+		return x.NotificationLevel
+	}
+	return ""
+}
+
 type SendMessageRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ChatId         string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
@@ -261,7 +531,7 @@ type SendMessageRequest struct {
 
 func (x *SendMessageRequest) Reset() {
 	*x = SendMessageRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[3]
+	mi := &file_proto_messaging_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -273,7 +543,7 @@ func (x *SendMessageRequest) String() string {
 func (*SendMessageRequest) ProtoMessage() {}
 
 func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[3]
+	mi := &file_proto_messaging_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +556,7 @@ func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
 func (*SendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{3}
+	return file_proto_messaging_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SendMessageRequest) GetChatId() string {
@@ -310,16 +580,18 @@ func (x *SendMessageRequest) GetIdempotencyKey() string {
 	return ""
 }
 
+// For a slash command, message is the public reply if there is one.
 type SendMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	CommandReply  *CommandReply          `protobuf:"bytes,2,opt,name=command_reply,json=commandReply,proto3" json:"command_reply,omitempty"` // Set if the content was a slash command.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendMessageResponse) Reset() {
 	*x = SendMessageResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[4]
+	mi := &file_proto_messaging_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -331,7 +603,7 @@ func (x *SendMessageResponse) String() string {
 func (*SendMessageResponse) ProtoMessage() {}
 
 func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[4]
+	mi := &file_proto_messaging_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -344,7 +616,7 @@ func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
 func (*SendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{4}
+	return file_proto_messaging_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SendMessageResponse) GetMessage() *Message {
@@ -354,6 +626,73 @@ func (x *SendMessageResponse) GetMessage() *Message {
 	return nil
 }
 
+func (x *SendMessageResponse) GetCommandReply() *CommandReply {
+	if x != nil {
+		return x.CommandReply
+	}
+	return nil
+}
+
+type CommandReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Visibility    string                 `protobuf:"bytes,2,opt,name=visibility,proto3" json:"visibility,omitempty"` // "ephemeral" or "public"
+	SenderId      string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandReply) Reset() {
+	*x = CommandReply{}
+	mi := &file_proto_messaging_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandReply) ProtoMessage() {}
+
+func (x *CommandReply) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandReply.ProtoReflect.Descriptor instead.
+func (*CommandReply) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CommandReply) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CommandReply) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *CommandReply) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
 type ListMessagesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
@@ -365,7 +704,7 @@ type ListMessagesRequest struct {
 
 func (x *ListMessagesRequest) Reset() {
 	*x = ListMessagesRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[5]
+	mi := &file_proto_messaging_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -377,7 +716,7 @@ func (x *ListMessagesRequest) String() string {
 func (*ListMessagesRequest) ProtoMessage() {}
 
 func (x *ListMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[5]
+	mi := &file_proto_messaging_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,7 +729,7 @@ func (x *ListMessagesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListMessagesRequest.ProtoReflect.Descriptor instead.
 func (*ListMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{5}
+	return file_proto_messaging_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ListMessagesRequest) GetChatId() string {
@@ -424,7 +763,7 @@ type ListMessagesResponse struct {
 
 func (x *ListMessagesResponse) Reset() {
 	*x = ListMessagesResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[6]
+	mi := &file_proto_messaging_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -436,7 +775,7 @@ func (x *ListMessagesResponse) String() string {
 func (*ListMessagesResponse) ProtoMessage() {}
 
 func (x *ListMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[6]
+	mi := &file_proto_messaging_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -449,7 +788,7 @@ func (x *ListMessagesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListMessagesResponse.ProtoReflect.Descriptor instead.
 func (*ListMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{6}
+	return file_proto_messaging_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListMessagesResponse) GetMessages() []*Message {
@@ -476,7 +815,7 @@ type UpdateMessageStatusRequest struct {
 
 func (x *UpdateMessageStatusRequest) Reset() {
 	*x = UpdateMessageStatusRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[7]
+	mi := &file_proto_messaging_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -488,7 +827,7 @@ func (x *UpdateMessageStatusRequest) String() string {
 func (*UpdateMessageStatusRequest) ProtoMessage() {}
 
 func (x *UpdateMessageStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[7]
+	mi := &file_proto_messaging_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -501,7 +840,7 @@ func (x *UpdateMessageStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateMessageStatusRequest.ProtoReflect.Descriptor instead.
 func (*UpdateMessageStatusRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{7}
+	return file_proto_messaging_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UpdateMessageStatusRequest) GetMessageId() string {
@@ -528,7 +867,7 @@ type UpdateMessageStatusResponse struct {
 
 func (x *UpdateMessageStatusResponse) Reset() {
 	*x = UpdateMessageStatusResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[8]
+	mi := &file_proto_messaging_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -540,7 +879,7 @@ func (x *UpdateMessageStatusResponse) String() string {
 func (*UpdateMessageStatusResponse) ProtoMessage() {}
 
 func (x *UpdateMessageStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[8]
+	mi := &file_proto_messaging_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -553,7 +892,7 @@ func (x *UpdateMessageStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateMessageStatusResponse.ProtoReflect.Descriptor instead.
 func (*UpdateMessageStatusResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{8}
+	return file_proto_messaging_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateMessageStatusResponse) GetMessageId() string {
@@ -570,29 +909,37 @@ func (x *UpdateMessageStatusResponse) GetStatus() string {
 	return ""
 }
 
-// Real-time messaging messages
-type SubscribeToChatRequest struct {
+type ScheduledMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	SendAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // PENDING, SENT, CANCELED, SKIPPED or FAILED
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	MessageId     string                 `protobuf:"bytes,7,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // The message it was sent as.
+	LastError     string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SubscribeToChatRequest) Reset() {
-	*x = SubscribeToChatRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[9]
+func (x *ScheduledMessage) Reset() {
+	*x = ScheduledMessage{}
+	mi := &file_proto_messaging_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubscribeToChatRequest) String() string {
+func (x *ScheduledMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribeToChatRequest) ProtoMessage() {}
+func (*ScheduledMessage) ProtoMessage() {}
 
-func (x *SubscribeToChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[9]
+func (x *ScheduledMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -603,46 +950,5862 @@ func (x *SubscribeToChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribeToChatRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeToChatRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ScheduledMessage.ProtoReflect.Descriptor instead.
+func (*ScheduledMessage) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ScheduledMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetSendAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SendAt
+	}
+	return nil
+}
+
+func (x *ScheduledMessage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *ScheduledMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *ScheduledMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ScheduleMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	SendAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageRequest) Reset() {
+	*x = ScheduleMessageRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageRequest) ProtoMessage() {}
+
+func (x *ScheduleMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ScheduleMessageRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ScheduleMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ScheduleMessageRequest) GetSendAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SendAt
+	}
+	return nil
+}
+
+type ScheduleMessageResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledMessage *ScheduledMessage      `protobuf:"bytes,1,opt,name=scheduled_message,json=scheduledMessage,proto3" json:"scheduled_message,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageResponse) Reset() {
+	*x = ScheduleMessageResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageResponse) ProtoMessage() {}
+
+func (x *ScheduleMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ScheduleMessageResponse) GetScheduledMessage() *ScheduledMessage {
+	if x != nil {
+		return x.ScheduledMessage
+	}
+	return nil
+}
+
+type ListScheduledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // Optional
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`               // Optional, defaults to PENDING
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledRequest) Reset() {
+	*x = ListScheduledRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledRequest) ProtoMessage() {}
+
+func (x *ListScheduledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListScheduledRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ListScheduledRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListScheduledRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListScheduledRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListScheduledResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledMessages []*ScheduledMessage    `protobuf:"bytes,1,rep,name=scheduled_messages,json=scheduledMessages,proto3" json:"scheduled_messages,omitempty"`
+	Total             int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListScheduledResponse) Reset() {
+	*x = ListScheduledResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledResponse) ProtoMessage() {}
+
+func (x *ListScheduledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListScheduledResponse) GetScheduledMessages() []*ScheduledMessage {
+	if x != nil {
+		return x.ScheduledMessages
+	}
+	return nil
+}
+
+func (x *ListScheduledResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CancelScheduledRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledMessageId string                 `protobuf:"bytes,1,opt,name=scheduled_message_id,json=scheduledMessageId,proto3" json:"scheduled_message_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CancelScheduledRequest) Reset() {
+	*x = CancelScheduledRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledRequest) ProtoMessage() {}
+
+func (x *CancelScheduledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CancelScheduledRequest) GetScheduledMessageId() string {
+	if x != nil {
+		return x.ScheduledMessageId
+	}
+	return ""
+}
+
+type CancelScheduledResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ScheduledMessageId string                 `protobuf:"bytes,1,opt,name=scheduled_message_id,json=scheduledMessageId,proto3" json:"scheduled_message_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CancelScheduledResponse) Reset() {
+	*x = CancelScheduledResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledResponse) ProtoMessage() {}
+
+func (x *CancelScheduledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelScheduledResponse) GetScheduledMessageId() string {
+	if x != nil {
+		return x.ScheduledMessageId
+	}
+	return ""
+}
+
+// Real-time messaging messages
+type SubscribeToChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeToChatRequest) Reset() {
+	*x = SubscribeToChatRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeToChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToChatRequest) ProtoMessage() {}
+
+func (x *SubscribeToChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToChatRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToChatRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SubscribeToChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type ChatMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Type          MessageType            `protobuf:"varint,8,opt,name=type,proto3,enum=messaging.MessageType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_proto_messaging_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChatMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetType() MessageType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageType_MESSAGE_TYPE_UNSPECIFIED
+}
+
+type CreateChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatRequest) Reset() {
+	*x = CreateChatRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatRequest) ProtoMessage() {}
+
+func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateChatRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateChatRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+type CreateChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatResponse) Reset() {
+	*x = CreateChatResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatResponse) ProtoMessage() {}
+
+func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatResponse.ProtoReflect.Descriptor instead.
+func (*CreateChatResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CreateChatResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type GetChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatRequest) Reset() {
+	*x = GetChatRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatRequest) ProtoMessage() {}
+
+func (x *GetChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatRequest.ProtoReflect.Descriptor instead.
+func (*GetChatRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type GetChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatResponse) Reset() {
+	*x = GetChatResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatResponse) ProtoMessage() {}
+
+func (x *GetChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatResponse.ProtoReflect.Descriptor instead.
+func (*GetChatResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetChatResponse) GetChat() *Chat {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+type ListChatsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Page            int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit           int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	ArchivedOnly    bool                   `protobuf:"varint,3,opt,name=archived_only,json=archivedOnly,proto3" json:"archived_only,omitempty"`
+	ExcludeArchived bool                   `protobuf:"varint,4,opt,name=exclude_archived,json=excludeArchived,proto3" json:"exclude_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListChatsRequest) Reset() {
+	*x = ListChatsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChatsRequest) ProtoMessage() {}
+
+func (x *ListChatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChatsRequest.ProtoReflect.Descriptor instead.
+func (*ListChatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListChatsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListChatsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListChatsRequest) GetArchivedOnly() bool {
+	if x != nil {
+		return x.ArchivedOnly
+	}
+	return false
+}
+
+func (x *ListChatsRequest) GetExcludeArchived() bool {
+	if x != nil {
+		return x.ExcludeArchived
+	}
+	return false
+}
+
+type ListChatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chats         []*Chat                `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChatsResponse) Reset() {
+	*x = ListChatsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChatsResponse) ProtoMessage() {}
+
+func (x *ListChatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChatsResponse.ProtoReflect.Descriptor instead.
+func (*ListChatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListChatsResponse) GetChats() []*Chat {
+	if x != nil {
+		return x.Chats
+	}
+	return nil
+}
+
+func (x *ListChatsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// Only the fields that are set are changed.
+type UpdateChatSettingsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ChatId            string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MutedUntil        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"`
+	Unmute            bool                   `protobuf:"varint,3,opt,name=unmute,proto3" json:"unmute,omitempty"`
+	Archived          *bool                  `protobuf:"varint,4,opt,name=archived,proto3,oneof" json:"archived,omitempty"`
+	Pinned            *bool                  `protobuf:"varint,5,opt,name=pinned,proto3,oneof" json:"pinned,omitempty"`
+	PinOrder          *int32                 `protobuf:"varint,6,opt,name=pin_order,json=pinOrder,proto3,oneof" json:"pin_order,omitempty"`
+	NotificationLevel *string                `protobuf:"bytes,7,opt,name=notification_level,json=notificationLevel,proto3,oneof" json:"notification_level,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *UpdateChatSettingsRequest) Reset() {
+	*x = UpdateChatSettingsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateChatSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChatSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateChatSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChatSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateChatSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UpdateChatSettingsRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UpdateChatSettingsRequest) GetMutedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return nil
+}
+
+func (x *UpdateChatSettingsRequest) GetUnmute() bool {
+	if x != nil {
+		return x.Unmute
+	}
+	return false
+}
+
+func (x *UpdateChatSettingsRequest) GetArchived() bool {
+	if x != nil && x.Archived != nil {
+		return *x.Archived
+	}
+	return false
+}
+
+func (x *UpdateChatSettingsRequest) GetPinned() bool {
+	if x != nil && x.Pinned != nil {
+		return *x.Pinned
+	}
+	return false
+}
+
+func (x *UpdateChatSettingsRequest) GetPinOrder() int32 {
+	if x != nil && x.PinOrder != nil {
+		return *x.PinOrder
+	}
+	return 0
+}
+
+func (x *UpdateChatSettingsRequest) GetNotificationLevel() string {
+	if x != nil && x.NotificationLevel != nil {
+		return *x.NotificationLevel
+	}
+	return ""
+}
+
+type UpdateChatSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Settings      *ChatSettings          `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateChatSettingsResponse) Reset() {
+	*x = UpdateChatSettingsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateChatSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChatSettingsResponse) ProtoMessage() {}
+
+func (x *UpdateChatSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChatSettingsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateChatSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UpdateChatSettingsResponse) GetSettings() *ChatSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+// Only the fields that are set are changed. Requires the chat admin role.
+type UpdateChatRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ChatId             string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name               *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Description        *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	AvatarAttachmentId *string                `protobuf:"bytes,4,opt,name=avatar_attachment_id,json=avatarAttachmentId,proto3,oneof" json:"avatar_attachment_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateChatRequest) Reset() {
+	*x = UpdateChatRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChatRequest) ProtoMessage() {}
+
+func (x *UpdateChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChatRequest.ProtoReflect.Descriptor instead.
+func (*UpdateChatRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *UpdateChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UpdateChatRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateChatRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *UpdateChatRequest) GetAvatarAttachmentId() string {
+	if x != nil && x.AvatarAttachmentId != nil {
+		return *x.AvatarAttachmentId
+	}
+	return ""
+}
+
+type UpdateChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateChatResponse) Reset() {
+	*x = UpdateChatResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChatResponse) ProtoMessage() {}
+
+func (x *UpdateChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChatResponse.ProtoReflect.Descriptor instead.
+func (*UpdateChatResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UpdateChatResponse) GetChat() *Chat {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+type CreateChannelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Visibility    string                 `protobuf:"bytes,3,opt,name=visibility,proto3" json:"visibility,omitempty"` // "PUBLIC", "PRIVATE"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChannelRequest) Reset() {
+	*x = CreateChannelRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChannelRequest) ProtoMessage() {}
+
+func (x *CreateChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChannelRequest.ProtoReflect.Descriptor instead.
+func (*CreateChannelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CreateChannelRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateChannelRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateChannelRequest) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+type CreateChannelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChannelResponse) Reset() {
+	*x = CreateChannelResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChannelResponse) ProtoMessage() {}
+
+func (x *CreateChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChannelResponse.ProtoReflect.Descriptor instead.
+func (*CreateChannelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CreateChannelResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type JoinChannelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinChannelRequest) Reset() {
+	*x = JoinChannelRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChannelRequest) ProtoMessage() {}
+
+func (x *JoinChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChannelRequest.ProtoReflect.Descriptor instead.
+func (*JoinChannelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *JoinChannelRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type JoinChannelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinChannelResponse) Reset() {
+	*x = JoinChannelResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChannelResponse) ProtoMessage() {}
+
+func (x *JoinChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChannelResponse.ProtoReflect.Descriptor instead.
+func (*JoinChannelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *JoinChannelResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type LeaveChannelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveChannelRequest) Reset() {
+	*x = LeaveChannelRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveChannelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveChannelRequest) ProtoMessage() {}
+
+func (x *LeaveChannelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveChannelRequest.ProtoReflect.Descriptor instead.
+func (*LeaveChannelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LeaveChannelRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type LeaveChannelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveChannelResponse) Reset() {
+	*x = LeaveChannelResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveChannelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveChannelResponse) ProtoMessage() {}
+
+func (x *LeaveChannelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveChannelResponse.ProtoReflect.Descriptor instead.
+func (*LeaveChannelResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *LeaveChannelResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type AddChannelMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddChannelMemberRequest) Reset() {
+	*x = AddChannelMemberRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddChannelMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddChannelMemberRequest) ProtoMessage() {}
+
+func (x *AddChannelMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddChannelMemberRequest.ProtoReflect.Descriptor instead.
+func (*AddChannelMemberRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AddChannelMemberRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *AddChannelMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type AddChannelMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddChannelMemberResponse) Reset() {
+	*x = AddChannelMemberResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddChannelMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddChannelMemberResponse) ProtoMessage() {}
+
+func (x *AddChannelMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddChannelMemberResponse.ProtoReflect.Descriptor instead.
+func (*AddChannelMemberResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AddChannelMemberResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *AddChannelMemberResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type DiscoverChannelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverChannelsRequest) Reset() {
+	*x = DiscoverChannelsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverChannelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverChannelsRequest) ProtoMessage() {}
+
+func (x *DiscoverChannelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverChannelsRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverChannelsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DiscoverChannelsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *DiscoverChannelsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *DiscoverChannelsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type DiscoverChannelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channels      []*Chat                `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverChannelsResponse) Reset() {
+	*x = DiscoverChannelsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverChannelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverChannelsResponse) ProtoMessage() {}
+
+func (x *DiscoverChannelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverChannelsResponse.ProtoReflect.Descriptor instead.
+func (*DiscoverChannelsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DiscoverChannelsResponse) GetChannels() []*Chat {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+func (x *DiscoverChannelsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CreateUserRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Username string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Email    string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Password string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	// Shown in ListSessions, e.g. "Alice's iPhone".
+	DeviceName    string `protobuf:"bytes,4,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *CreateUserRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+type CreateUserResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	User           *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Token          string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken   string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TokenExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=token_expires_at,json=tokenExpiresAt,proto3" json:"token_expires_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateUserResponse) Reset() {
+	*x = CreateUserResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserResponse) ProtoMessage() {}
+
+func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
+func (*CreateUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *CreateUserResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateUserResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *CreateUserResponse) GetTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TokenExpiresAt
+	}
+	return nil
+}
+
+type LoginRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Email    string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Password string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// Shown in ListSessions, e.g. "Alice's iPhone".
+	DeviceName    string `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequest) Reset() {
+	*x = LoginRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequest) ProtoMessage() {}
+
+func (x *LoginRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
+func (*LoginRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *LoginRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *LoginRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+// For accounts with two-factor authentication only challenge_token and
+// challenge_expires_at are set; VerifyLoginChallenge completes the login.
+type LoginResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	User               *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Token              string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken       string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TokenExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=token_expires_at,json=tokenExpiresAt,proto3" json:"token_expires_at,omitempty"`
+	ChallengeToken     string                 `protobuf:"bytes,5,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	ChallengeExpiresAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=challenge_expires_at,json=challengeExpiresAt,proto3" json:"challenge_expires_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *LoginResponse) Reset() {
+	*x = LoginResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginResponse) ProtoMessage() {}
+
+func (x *LoginResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LoginResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *LoginResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TokenExpiresAt
+	}
+	return nil
+}
+
+func (x *LoginResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *LoginResponse) GetChallengeExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChallengeExpiresAt
+	}
+	return nil
+}
+
+type RefreshSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshSessionRequest) Reset() {
+	*x = RefreshSessionRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshSessionRequest) ProtoMessage() {}
+
+func (x *RefreshSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshSessionRequest.ProtoReflect.Descriptor instead.
+func (*RefreshSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RefreshSessionRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type RefreshSessionResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Token          string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken   string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TokenExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=token_expires_at,json=tokenExpiresAt,proto3" json:"token_expires_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RefreshSessionResponse) Reset() {
+	*x = RefreshSessionResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshSessionResponse) ProtoMessage() {}
+
+func (x *RefreshSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshSessionResponse.ProtoReflect.Descriptor instead.
+func (*RefreshSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RefreshSessionResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RefreshSessionResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RefreshSessionResponse) GetTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TokenExpiresAt
+	}
+	return nil
+}
+
+type LogoutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Refresh token of the session being ended, so it cannot be renewed.
+	RefreshToken string `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	// Revoke every access and refresh token of the caller, on all devices.
+	AllSessions   bool `protobuf:"varint,2,opt,name=all_sessions,json=allSessions,proto3" json:"all_sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequest) Reset() {
+	*x = LogoutRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequest) ProtoMessage() {}
+
+func (x *LogoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequest.ProtoReflect.Descriptor instead.
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *LogoutRequest) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *LogoutRequest) GetAllSessions() bool {
+	if x != nil {
+		return x.AllSessions
+	}
+	return false
+}
+
+type LogoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutResponse) Reset() {
+	*x = LogoutResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutResponse) ProtoMessage() {}
+
+func (x *LogoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutResponse.ProtoReflect.Descriptor instead.
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{47}
+}
+
+type Session struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DeviceName string                 `protobuf:"bytes,2,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	UserAgent  string                 `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	IpAddress  string                 `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	// True for the session of the access token used for the call.
+	Current       bool `protobuf:"varint,7,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
+	mi := &file_proto_messaging_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Session) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Session) ProtoMessage() {}
+
+func (x *Session) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *Session) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Session) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *Session) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *Session) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *Session) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Session) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *Session) GetCurrent() bool {
+	if x != nil {
+		return x.Current
+	}
+	return false
+}
+
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{49}
+}
+
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*Session {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type RevokeSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionRequest) Reset() {
+	*x = RevokeSessionRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionRequest) ProtoMessage() {}
+
+func (x *RevokeSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionRequest.ProtoReflect.Descriptor instead.
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RevokeSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type RevokeSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeSessionResponse) Reset() {
+	*x = RevokeSessionResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeSessionResponse) ProtoMessage() {}
+
+func (x *RevokeSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeSessionResponse.ProtoReflect.Descriptor instead.
+func (*RevokeSessionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RevokeSessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type ChangePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CurrentPassword string                 `protobuf:"bytes,1,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordRequest) ProtoMessage() {}
+
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
+	if x != nil {
+		return x.CurrentPassword
+	}
+	return ""
+}
+
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ChangePasswordResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordResponse) Reset() {
+	*x = ChangePasswordResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordResponse) ProtoMessage() {}
+
+func (x *ChangePasswordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordResponse.ProtoReflect.Descriptor instead.
+func (*ChangePasswordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{54}
+}
+
+type RequestPasswordResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetRequest) ProtoMessage() {}
+
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RequestPasswordResetRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// Returned whether or not an account uses the email.
+type RequestPasswordResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestPasswordResetResponse) Reset() {
+	*x = RequestPasswordResetResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestPasswordResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestPasswordResetResponse) ProtoMessage() {}
+
+func (x *RequestPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{56}
+}
+
+type ConfirmPasswordResetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token from the reset email.
+	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword   string `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetRequest) Reset() {
+	*x = ConfirmPasswordResetRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetRequest) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ConfirmPasswordResetRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *ConfirmPasswordResetRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type ConfirmPasswordResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPasswordResetResponse) Reset() {
+	*x = ConfirmPasswordResetResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPasswordResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPasswordResetResponse) ProtoMessage() {}
+
+func (x *ConfirmPasswordResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPasswordResetResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{58}
+}
+
+type VerifyEmailRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Token from the verification email.
+	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailRequest) Reset() {
+	*x = VerifyEmailRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailRequest) ProtoMessage() {}
+
+func (x *VerifyEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *VerifyEmailRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEmailResponse) Reset() {
+	*x = VerifyEmailResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEmailResponse) ProtoMessage() {}
+
+func (x *VerifyEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEmailResponse.ProtoReflect.Descriptor instead.
+func (*VerifyEmailResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{60}
+}
+
+type ResendVerificationEmailRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendVerificationEmailRequest) Reset() {
+	*x = ResendVerificationEmailRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendVerificationEmailRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendVerificationEmailRequest) ProtoMessage() {}
+
+func (x *ResendVerificationEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendVerificationEmailRequest.ProtoReflect.Descriptor instead.
+func (*ResendVerificationEmailRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{61}
+}
+
+type ResendVerificationEmailResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResendVerificationEmailResponse) Reset() {
+	*x = ResendVerificationEmailResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResendVerificationEmailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResendVerificationEmailResponse) ProtoMessage() {}
+
+func (x *ResendVerificationEmailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResendVerificationEmailResponse.ProtoReflect.Descriptor instead.
+func (*ResendVerificationEmailResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{62}
+}
+
+type EnrollTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPRequest) Reset() {
+	*x = EnrollTOTPRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPRequest) ProtoMessage() {}
+
+func (x *EnrollTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPRequest.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{63}
+}
+
+type EnrollTOTPResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Base32 secret for typing into an authenticator app.
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// otpauth:// URI for a QR code.
+	OtpauthUri string `protobuf:"bytes,2,opt,name=otpauth_uri,json=otpauthUri,proto3" json:"otpauth_uri,omitempty"`
+	// One-time codes for logging in without the authenticator app. They are
+	// only shown once.
+	RecoveryCodes []string `protobuf:"bytes,3,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnrollTOTPResponse) Reset() {
+	*x = EnrollTOTPResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnrollTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnrollTOTPResponse) ProtoMessage() {}
+
+func (x *EnrollTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnrollTOTPResponse.ProtoReflect.Descriptor instead.
+func (*EnrollTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *EnrollTOTPResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *EnrollTOTPResponse) GetOtpauthUri() string {
+	if x != nil {
+		return x.OtpauthUri
+	}
+	return ""
+}
+
+func (x *EnrollTOTPResponse) GetRecoveryCodes() []string {
+	if x != nil {
+		return x.RecoveryCodes
+	}
+	return nil
+}
+
+type ConfirmTOTPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPRequest) Reset() {
+	*x = ConfirmTOTPRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPRequest) ProtoMessage() {}
+
+func (x *ConfirmTOTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ConfirmTOTPRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ConfirmTOTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmTOTPResponse) Reset() {
+	*x = ConfirmTOTPResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmTOTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmTOTPResponse) ProtoMessage() {}
+
+func (x *ConfirmTOTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmTOTPResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmTOTPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{66}
+}
+
+type VerifyLoginChallengeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeToken string                 `protobuf:"bytes,1,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	// A code from the authenticator app or a recovery code.
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyLoginChallengeRequest) Reset() {
+	*x = VerifyLoginChallengeRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyLoginChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyLoginChallengeRequest) ProtoMessage() {}
+
+func (x *VerifyLoginChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyLoginChallengeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyLoginChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *VerifyLoginChallengeRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *VerifyLoginChallengeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifyLoginChallengeResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	User           *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Token          string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	RefreshToken   string                 `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	TokenExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=token_expires_at,json=tokenExpiresAt,proto3" json:"token_expires_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *VerifyLoginChallengeResponse) Reset() {
+	*x = VerifyLoginChallengeResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyLoginChallengeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyLoginChallengeResponse) ProtoMessage() {}
+
+func (x *VerifyLoginChallengeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyLoginChallengeResponse.ProtoReflect.Descriptor instead.
+func (*VerifyLoginChallengeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *VerifyLoginChallengeResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *VerifyLoginChallengeResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *VerifyLoginChallengeResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *VerifyLoginChallengeResponse) GetTokenExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TokenExpiresAt
+	}
+	return nil
+}
+
+// Login with an ID token of the configured OpenID Connect issuer.
+type LoginWithIDTokenRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	IdToken string                 `protobuf:"bytes,1,opt,name=id_token,json=idToken,proto3" json:"id_token,omitempty"`
+	// The nonce of the authorization request, if it had one.
+	Nonce         string `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	DeviceName    string `protobuf:"bytes,3,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginWithIDTokenRequest) Reset() {
+	*x = LoginWithIDTokenRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginWithIDTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginWithIDTokenRequest) ProtoMessage() {}
+
+func (x *LoginWithIDTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginWithIDTokenRequest.ProtoReflect.Descriptor instead.
+func (*LoginWithIDTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *LoginWithIDTokenRequest) GetIdToken() string {
+	if x != nil {
+		return x.IdToken
+	}
+	return ""
+}
+
+func (x *LoginWithIDTokenRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *LoginWithIDTokenRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+// Login with an authorization code of the configured OpenID Connect issuer.
+type ExchangeOIDCCodeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// The PKCE code verifier, if the authorization request used one.
+	CodeVerifier string `protobuf:"bytes,2,opt,name=code_verifier,json=codeVerifier,proto3" json:"code_verifier,omitempty"`
+	// Defaults to the configured redirect URL.
+	RedirectUri   string `protobuf:"bytes,3,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	DeviceName    string `protobuf:"bytes,4,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeOIDCCodeRequest) Reset() {
+	*x = ExchangeOIDCCodeRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeOIDCCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeOIDCCodeRequest) ProtoMessage() {}
+
+func (x *ExchangeOIDCCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeOIDCCodeRequest.ProtoReflect.Descriptor instead.
+func (*ExchangeOIDCCodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ExchangeOIDCCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ExchangeOIDCCodeRequest) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+func (x *ExchangeOIDCCodeRequest) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *ExchangeOIDCCodeRequest) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type GetMeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeRequest) Reset() {
+	*x = GetMeRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeRequest) ProtoMessage() {}
+
+func (x *GetMeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeRequest.ProtoReflect.Descriptor instead.
+func (*GetMeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{73}
+}
+
+type GetMeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMeResponse) Reset() {
+	*x = GetMeResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMeResponse) ProtoMessage() {}
+
+func (x *GetMeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMeResponse.ProtoReflect.Descriptor instead.
+func (*GetMeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *GetMeResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type UpdateProfileRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Username           *string                `protobuf:"bytes,1,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	DisplayName        *string                `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3,oneof" json:"display_name,omitempty"`
+	Bio                *string                `protobuf:"bytes,3,opt,name=bio,proto3,oneof" json:"bio,omitempty"`
+	AvatarAttachmentId *string                `protobuf:"bytes,4,opt,name=avatar_attachment_id,json=avatarAttachmentId,proto3,oneof" json:"avatar_attachment_id,omitempty"`
+	Discoverable       *bool                  `protobuf:"varint,5,opt,name=discoverable,proto3,oneof" json:"discoverable,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateProfileRequest) Reset() {
+	*x = UpdateProfileRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRequest) ProtoMessage() {}
+
+func (x *UpdateProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *UpdateProfileRequest) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetDisplayName() string {
+	if x != nil && x.DisplayName != nil {
+		return *x.DisplayName
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetBio() string {
+	if x != nil && x.Bio != nil {
+		return *x.Bio
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetAvatarAttachmentId() string {
+	if x != nil && x.AvatarAttachmentId != nil {
+		return *x.AvatarAttachmentId
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequest) GetDiscoverable() bool {
+	if x != nil && x.Discoverable != nil {
+		return *x.Discoverable
+	}
+	return false
+}
+
+type UpdateProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileResponse) Reset() {
+	*x = UpdateProfileResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileResponse) ProtoMessage() {}
+
+func (x *UpdateProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProfileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *UpdateProfileResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type BatchGetUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetUsersRequest) Reset() {
+	*x = BatchGetUsersRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetUsersRequest) ProtoMessage() {}
+
+func (x *BatchGetUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetUsersRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *BatchGetUsersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type BatchGetUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetUsersResponse) Reset() {
+	*x = BatchGetUsersResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetUsersResponse) ProtoMessage() {}
+
+func (x *BatchGetUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetUsersResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *BatchGetUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type SearchUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersResponse) ProtoMessage() {}
+
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *SearchUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SearchUsersResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SubscribeToUserUpdatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeToUserUpdatesRequest) Reset() {
+	*x = SubscribeToUserUpdatesRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeToUserUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToUserUpdatesRequest) ProtoMessage() {}
+
+func (x *SubscribeToUserUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToUserUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToUserUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{81}
+}
+
+type UserUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UpdateType    string                 `protobuf:"bytes,2,opt,name=update_type,json=updateType,proto3" json:"update_type,omitempty"` // "profile_update", "status_change", "new_chat"
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	User          *User                  `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserUpdate) Reset() {
+	*x = UserUpdate{}
+	mi := &file_proto_messaging_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUpdate) ProtoMessage() {}
+
+func (x *UserUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUpdate.ProtoReflect.Descriptor instead.
+func (*UserUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *UserUpdate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserUpdate) GetUpdateType() string {
+	if x != nil {
+		return x.UpdateType
+	}
+	return ""
+}
+
+func (x *UserUpdate) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *UserUpdate) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *UserUpdate) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type BlockUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockUserRequest) Reset() {
+	*x = BlockUserRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUserRequest) ProtoMessage() {}
+
+func (x *BlockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUserRequest.ProtoReflect.Descriptor instead.
+func (*BlockUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *BlockUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type BlockUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockUserResponse) Reset() {
+	*x = BlockUserResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockUserResponse) ProtoMessage() {}
+
+func (x *BlockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockUserResponse.ProtoReflect.Descriptor instead.
+func (*BlockUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *BlockUserResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UnblockUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnblockUserRequest) Reset() {
+	*x = UnblockUserRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnblockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnblockUserRequest) ProtoMessage() {}
+
+func (x *UnblockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnblockUserRequest.ProtoReflect.Descriptor instead.
+func (*UnblockUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *UnblockUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UnblockUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnblockUserResponse) Reset() {
+	*x = UnblockUserResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnblockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnblockUserResponse) ProtoMessage() {}
+
+func (x *UnblockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnblockUserResponse.ProtoReflect.Descriptor instead.
+func (*UnblockUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *UnblockUserResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type BlockedUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	BlockedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=blocked_at,json=blockedAt,proto3" json:"blocked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockedUser) Reset() {
+	*x = BlockedUser{}
+	mi := &file_proto_messaging_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockedUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockedUser) ProtoMessage() {}
+
+func (x *BlockedUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockedUser.ProtoReflect.Descriptor instead.
+func (*BlockedUser) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *BlockedUser) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *BlockedUser) GetBlockedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BlockedAt
+	}
+	return nil
+}
+
+type ListBlockedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedRequest) Reset() {
+	*x = ListBlockedRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedRequest) ProtoMessage() {}
+
+func (x *ListBlockedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedRequest.ProtoReflect.Descriptor instead.
+func (*ListBlockedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *ListBlockedRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListBlockedRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListBlockedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*BlockedUser         `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedResponse) Reset() {
+	*x = ListBlockedResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedResponse) ProtoMessage() {}
+
+func (x *ListBlockedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlockedResponse.ProtoReflect.Descriptor instead.
+func (*ListBlockedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ListBlockedResponse) GetUsers() []*BlockedUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListBlockedResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ReportUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // SPAM, HARASSMENT, INAPPROPRIATE or OTHER
+	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportUserRequest) Reset() {
+	*x = ReportUserRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportUserRequest) ProtoMessage() {}
+
+func (x *ReportUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportUserRequest.ProtoReflect.Descriptor instead.
+func (*ReportUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ReportUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReportUserRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReportUserRequest) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+type ReportUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportUserResponse) Reset() {
+	*x = ReportUserResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportUserResponse) ProtoMessage() {}
+
+func (x *ReportUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportUserResponse.ProtoReflect.Descriptor instead.
+func (*ReportUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ReportUserResponse) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+type ReportMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // SPAM, HARASSMENT, INAPPROPRIATE or OTHER
+	Details       string                 `protobuf:"bytes,3,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportMessageRequest) Reset() {
+	*x = ReportMessageRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportMessageRequest) ProtoMessage() {}
+
+func (x *ReportMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportMessageRequest.ProtoReflect.Descriptor instead.
+func (*ReportMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ReportMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ReportMessageRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReportMessageRequest) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+type ReportMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportMessageResponse) Reset() {
+	*x = ReportMessageResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportMessageResponse) ProtoMessage() {}
+
+func (x *ReportMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportMessageResponse.ProtoReflect.Descriptor instead.
+func (*ReportMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *ReportMessageResponse) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+type Report struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReporterId     string                 `protobuf:"bytes,2,opt,name=reporter_id,json=reporterId,proto3" json:"reporter_id,omitempty"`
+	ReportedUserId string                 `protobuf:"bytes,3,opt,name=reported_user_id,json=reportedUserId,proto3" json:"reported_user_id,omitempty"`
+	ChatId         string                 `protobuf:"bytes,4,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId      string                 `protobuf:"bytes,5,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Reason         string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Details        string                 `protobuf:"bytes,7,opt,name=details,proto3" json:"details,omitempty"`
+	Status         string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"` // OPEN, RESOLVED or DISMISSED
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_proto_messaging_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *Report) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Report) GetReporterId() string {
+	if x != nil {
+		return x.ReporterId
+	}
+	return ""
+}
+
+func (x *Report) GetReportedUserId() string {
+	if x != nil {
+		return x.ReportedUserId
+	}
+	return ""
+}
+
+func (x *Report) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *Report) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *Report) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Report) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *Report) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Report) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Report) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ListReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // defaults to OPEN
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsRequest) Reset() {
+	*x = ListReportsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsRequest) ProtoMessage() {}
+
+func (x *ListReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListReportsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *ListReportsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListReportsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListReportsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Reports are listed oldest first.
+type ListReportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*Report              `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsResponse) Reset() {
+	*x = ListReportsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsResponse) ProtoMessage() {}
+
+func (x *ListReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListReportsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *ListReportsResponse) GetReports() []*Report {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+func (x *ListReportsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ResolveReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // RESOLVED or DISMISSED
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReportRequest) Reset() {
+	*x = ResolveReportRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReportRequest) ProtoMessage() {}
+
+func (x *ResolveReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReportRequest.ProtoReflect.Descriptor instead.
+func (*ResolveReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *ResolveReportRequest) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+func (x *ResolveReportRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ResolveReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *Report                `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReportResponse) Reset() {
+	*x = ResolveReportResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReportResponse) ProtoMessage() {}
+
+func (x *ResolveReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReportResponse.ProtoReflect.Descriptor instead.
+func (*ResolveReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *ResolveReportResponse) GetReport() *Report {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type CreateBotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBotRequest) Reset() {
+	*x = CreateBotRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBotRequest) ProtoMessage() {}
+
+func (x *CreateBotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBotRequest.ProtoReflect.Descriptor instead.
+func (*CreateBotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *CreateBotRequest) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *CreateBotRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+type CreateBotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bot           *User                  `protobuf:"bytes,1,opt,name=bot,proto3" json:"bot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateBotResponse) Reset() {
+	*x = CreateBotResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateBotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateBotResponse) ProtoMessage() {}
+
+func (x *CreateBotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateBotResponse.ProtoReflect.Descriptor instead.
+func (*CreateBotResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *CreateBotResponse) GetBot() *User {
+	if x != nil {
+		return x.Bot
+	}
+	return nil
+}
+
+type ListBotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBotsRequest) Reset() {
+	*x = ListBotsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBotsRequest) ProtoMessage() {}
+
+func (x *ListBotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBotsRequest.ProtoReflect.Descriptor instead.
+func (*ListBotsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{101}
+}
+
+type ListBotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bots          []*User                `protobuf:"bytes,1,rep,name=bots,proto3" json:"bots,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBotsResponse) Reset() {
+	*x = ListBotsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBotsResponse) ProtoMessage() {}
+
+func (x *ListBotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBotsResponse.ProtoReflect.Descriptor instead.
+func (*ListBotsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *ListBotsResponse) GetBots() []*User {
+	if x != nil {
+		return x.Bots
+	}
+	return nil
+}
+
+type AddBotToChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BotId         string                 `protobuf:"bytes,1,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBotToChatRequest) Reset() {
+	*x = AddBotToChatRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBotToChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBotToChatRequest) ProtoMessage() {}
+
+func (x *AddBotToChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBotToChatRequest.ProtoReflect.Descriptor instead.
+func (*AddBotToChatRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *AddBotToChatRequest) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *AddBotToChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type AddBotToChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddBotToChatResponse) Reset() {
+	*x = AddBotToChatResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddBotToChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddBotToChatResponse) ProtoMessage() {}
+
+func (x *AddBotToChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddBotToChatResponse.ProtoReflect.Descriptor instead.
+func (*AddBotToChatResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *AddBotToChatResponse) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BotId         string                 `protobuf:"bytes,2,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	KeyPrefix     string                 `protobuf:"bytes,4,opt,name=key_prefix,json=keyPrefix,proto3" json:"key_prefix,omitempty"` // The first characters of the key, to tell keys apart.
+	Scopes        []string               `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	ChatIds       []string               `protobuf:"bytes,6,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"` // Empty for keys not restricted to chats.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_proto_messaging_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *APIKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *APIKey) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *APIKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *APIKey) GetKeyPrefix() string {
+	if x != nil {
+		return x.KeyPrefix
+	}
+	return ""
+}
+
+func (x *APIKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *APIKey) GetChatIds() []string {
+	if x != nil {
+		return x.ChatIds
+	}
+	return nil
+}
+
+func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+type CreateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BotId         string                 `protobuf:"bytes,1,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes        []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"` // "messages:send", "chats:read"
+	ChatIds       []string               `protobuf:"bytes,4,rep,name=chat_ids,json=chatIds,proto3" json:"chat_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *CreateAPIKeyRequest) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetChatIds() []string {
+	if x != nil {
+		return x.ChatIds
+	}
+	return nil
+}
+
+// The key is only returned here; the server stores its hash.
+type CreateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ApiKey        *APIKey                `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *CreateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type ListAPIKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BotId         string                 `protobuf:"bytes,1,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysRequest) Reset() {
+	*x = ListAPIKeysRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysRequest) ProtoMessage() {}
+
+func (x *ListAPIKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *ListAPIKeysRequest) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+type ListAPIKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*APIKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAPIKeysResponse) Reset() {
+	*x = ListAPIKeysResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAPIKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAPIKeysResponse) ProtoMessage() {}
+
+func (x *ListAPIKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAPIKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BotId         string                 `protobuf:"bytes,1,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	ApiKeyId      string                 `protobuf:"bytes,2,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *RevokeAPIKeyRequest) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *RevokeAPIKeyRequest) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+type RevokeAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyId      string                 `protobuf:"bytes,1,opt,name=api_key_id,json=apiKeyId,proto3" json:"api_key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyResponse) Reset() {
+	*x = RevokeAPIKeyResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *RevokeAPIKeyResponse) GetApiKeyId() string {
+	if x != nil {
+		return x.ApiKeyId
+	}
+	return ""
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"` // Set for chat webhooks.
+	BotId         string                 `protobuf:"bytes,3,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`    // Set for bot webhooks.
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_proto_messaging_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *Webhook) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// Exactly one of chat_id and bot_id is set.
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	BotId         string                 `protobuf:"bytes,2,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"` // "message.created", "member.joined", "member.left", "chat.updated"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *CreateWebhookRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// The secret verifies the X-Webhook-Signature header and is only returned
+// here.
+type CreateWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       *Webhook               `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookResponse) Reset() {
+	*x = CreateWebhookResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookResponse) ProtoMessage() {}
+
+func (x *CreateWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *CreateWebhookResponse) GetWebhook() *Webhook {
+	if x != nil {
+		return x.Webhook
+	}
+	return nil
+}
+
+func (x *CreateWebhookResponse) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	BotId         string                 `protobuf:"bytes,2,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *ListWebhooksRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ListWebhooksRequest) GetBotId() string {
+	if x != nil {
+		return x.BotId
+	}
+	return ""
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+type DeleteWebhookResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookResponse) Reset() {
+	*x = DeleteWebhookResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookResponse) ProtoMessage() {}
+
+func (x *DeleteWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookResponse.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *DeleteWebhookResponse) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId      string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Event          string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	Payload        string                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"` // The JSON body.
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`   // PENDING, SUCCEEDED or DEAD
+	Attempts       int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_attempt_at,json=lastAttemptAt,proto3" json:"last_attempt_at,omitempty"`
+	LastStatusCode int32                  `protobuf:"varint,9,opt,name=last_status_code,json=lastStatusCode,proto3" json:"last_status_code,omitempty"`
+	LastError      string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	DeliveredAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_proto_messaging_proto_msgTypes[119]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[119]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{119}
+}
+
+func (x *WebhookDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
 }
 
-func (x *SubscribeToChatRequest) GetChatId() string {
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ChatId
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetLastAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetLastStatusCode() int32 {
+	if x != nil {
+		return x.LastStatusCode
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
 	}
 	return ""
 }
 
-type ChatMessage struct {
+func (x *WebhookDelivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Username      string                 `protobuf:"bytes,4,opt,name=username,proto3" json:"username,omitempty"`
-	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
-	SentAt        *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
-	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // Optional: PENDING, SUCCEEDED or DEAD
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatMessage) Reset() {
-	*x = ChatMessage{}
-	mi := &file_proto_messaging_proto_msgTypes[10]
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[120]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatMessage) String() string {
+func (x *ListWebhookDeliveriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatMessage) ProtoMessage() {}
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
 
-func (x *ChatMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[10]
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[120]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -653,83 +6816,119 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
-func (*ChatMessage) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{120}
 }
 
-func (x *ChatMessage) GetMessageId() string {
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
 	if x != nil {
-		return x.MessageId
+		return x.WebhookId
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetChatId() string {
+func (x *ListWebhookDeliveriesRequest) GetStatus() string {
 	if x != nil {
-		return x.ChatId
+		return x.Status
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetUserId() string {
+func (x *ListWebhookDeliveriesRequest) GetPage() int32 {
 	if x != nil {
-		return x.UserId
+		return x.Page
 	}
-	return ""
+	return 0
 }
 
-func (x *ChatMessage) GetUsername() string {
+func (x *ListWebhookDeliveriesRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Username
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-func (x *ChatMessage) GetContent() string {
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[121]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[121]
 	if x != nil {
-		return x.Content
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ChatMessage) GetSentAt() *timestamppb.Timestamp {
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{121}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
 	if x != nil {
-		return x.SentAt
+		return x.Deliveries
 	}
 	return nil
 }
 
-func (x *ChatMessage) GetStatus() string {
+func (x *ListWebhookDeliveriesResponse) GetTotal() int32 {
 	if x != nil {
-		return x.Status
+		return x.Total
 	}
-	return ""
+	return 0
 }
 
-type CreateChatRequest struct {
+type IncomingWebhook struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // The bot that posts the messages.
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	TokenPrefix   string                 `protobuf:"bytes,6,opt,name=token_prefix,json=tokenPrefix,proto3" json:"token_prefix,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatRequest) Reset() {
-	*x = CreateChatRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[11]
+func (x *IncomingWebhook) Reset() {
+	*x = IncomingWebhook{}
+	mi := &file_proto_messaging_proto_msgTypes[122]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatRequest) String() string {
+func (x *IncomingWebhook) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatRequest) ProtoMessage() {}
+func (*IncomingWebhook) ProtoMessage() {}
 
-func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[11]
+func (x *IncomingWebhook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[122]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -740,47 +6939,83 @@ func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatRequest.ProtoReflect.Descriptor instead.
-func (*CreateChatRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use IncomingWebhook.ProtoReflect.Descriptor instead.
+func (*IncomingWebhook) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{122}
 }
 
-func (x *CreateChatRequest) GetName() string {
+func (x *IncomingWebhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateChatRequest) GetEmail() string {
+func (x *IncomingWebhook) GetCreatedBy() string {
 	if x != nil {
-		return x.Email
+		return x.CreatedBy
 	}
 	return ""
 }
 
-type CreateChatResponse struct {
+func (x *IncomingWebhook) GetTokenPrefix() string {
+	if x != nil {
+		return x.TokenPrefix
+	}
+	return ""
+}
+
+func (x *IncomingWebhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateIncomingWebhookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Shown as the sender of the messages.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatResponse) Reset() {
-	*x = CreateChatResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[12]
+func (x *CreateIncomingWebhookRequest) Reset() {
+	*x = CreateIncomingWebhookRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[123]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatResponse) String() string {
+func (x *CreateIncomingWebhookRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatResponse) ProtoMessage() {}
+func (*CreateIncomingWebhookRequest) ProtoMessage() {}
 
-func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[12]
+func (x *CreateIncomingWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[123]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -791,40 +7026,49 @@ func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatResponse.ProtoReflect.Descriptor instead.
-func (*CreateChatResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use CreateIncomingWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateIncomingWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{123}
 }
 
-func (x *CreateChatResponse) GetChatId() string {
+func (x *CreateIncomingWebhookRequest) GetChatId() string {
 	if x != nil {
 		return x.ChatId
 	}
 	return ""
 }
 
-type GetChatRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreateIncomingWebhookRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
 }
 
-func (x *GetChatRequest) Reset() {
-	*x = GetChatRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[13]
+// The URL contains the secret token and is only returned here.
+type CreateIncomingWebhookResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IncomingWebhook *IncomingWebhook       `protobuf:"bytes,1,opt,name=incoming_webhook,json=incomingWebhook,proto3" json:"incoming_webhook,omitempty"`
+	Url             string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateIncomingWebhookResponse) Reset() {
+	*x = CreateIncomingWebhookResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[124]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetChatRequest) String() string {
+func (x *CreateIncomingWebhookResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetChatRequest) ProtoMessage() {}
+func (*CreateIncomingWebhookResponse) ProtoMessage() {}
 
-func (x *GetChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[13]
+func (x *CreateIncomingWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[124]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -835,40 +7079,47 @@ func (x *GetChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetChatRequest.ProtoReflect.Descriptor instead.
-func (*GetChatRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use CreateIncomingWebhookResponse.ProtoReflect.Descriptor instead.
+func (*CreateIncomingWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{124}
 }
 
-func (x *GetChatRequest) GetChatId() string {
+func (x *CreateIncomingWebhookResponse) GetIncomingWebhook() *IncomingWebhook {
 	if x != nil {
-		return x.ChatId
+		return x.IncomingWebhook
+	}
+	return nil
+}
+
+func (x *CreateIncomingWebhookResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
 	}
 	return ""
 }
 
-type GetChatResponse struct {
+type ListIncomingWebhooksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetChatResponse) Reset() {
-	*x = GetChatResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[14]
+func (x *ListIncomingWebhooksRequest) Reset() {
+	*x = ListIncomingWebhooksRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[125]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetChatResponse) String() string {
+func (x *ListIncomingWebhooksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetChatResponse) ProtoMessage() {}
+func (*ListIncomingWebhooksRequest) ProtoMessage() {}
 
-func (x *GetChatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[14]
+func (x *ListIncomingWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[125]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -879,41 +7130,40 @@ func (x *GetChatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetChatResponse.ProtoReflect.Descriptor instead.
-func (*GetChatResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListIncomingWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListIncomingWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{125}
 }
 
-func (x *GetChatResponse) GetChat() *Chat {
+func (x *ListIncomingWebhooksRequest) GetChatId() string {
 	if x != nil {
-		return x.Chat
+		return x.ChatId
 	}
-	return nil
+	return ""
 }
 
-type ListChatsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ListIncomingWebhooksResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	IncomingWebhooks []*IncomingWebhook     `protobuf:"bytes,1,rep,name=incoming_webhooks,json=incomingWebhooks,proto3" json:"incoming_webhooks,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ListChatsRequest) Reset() {
-	*x = ListChatsRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[15]
+func (x *ListIncomingWebhooksResponse) Reset() {
+	*x = ListIncomingWebhooksResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[126]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListChatsRequest) String() string {
+func (x *ListIncomingWebhooksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListChatsRequest) ProtoMessage() {}
+func (*ListIncomingWebhooksResponse) ProtoMessage() {}
 
-func (x *ListChatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[15]
+func (x *ListIncomingWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[126]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -924,48 +7174,84 @@ func (x *ListChatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListChatsRequest.ProtoReflect.Descriptor instead.
-func (*ListChatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ListIncomingWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListIncomingWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{126}
 }
 
-func (x *ListChatsRequest) GetPage() int32 {
+func (x *ListIncomingWebhooksResponse) GetIncomingWebhooks() []*IncomingWebhook {
 	if x != nil {
-		return x.Page
+		return x.IncomingWebhooks
 	}
-	return 0
+	return nil
 }
 
-func (x *ListChatsRequest) GetLimit() int32 {
+type RevokeIncomingWebhookRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IncomingWebhookId string                 `protobuf:"bytes,1,opt,name=incoming_webhook_id,json=incomingWebhookId,proto3" json:"incoming_webhook_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RevokeIncomingWebhookRequest) Reset() {
+	*x = RevokeIncomingWebhookRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[127]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeIncomingWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeIncomingWebhookRequest) ProtoMessage() {}
+
+func (x *RevokeIncomingWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[127]
 	if x != nil {
-		return x.Limit
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-type ListChatsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chats         []*Chat                `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Deprecated: Use RevokeIncomingWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RevokeIncomingWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{127}
 }
 
-func (x *ListChatsResponse) Reset() {
-	*x = ListChatsResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[16]
+func (x *RevokeIncomingWebhookRequest) GetIncomingWebhookId() string {
+	if x != nil {
+		return x.IncomingWebhookId
+	}
+	return ""
+}
+
+type RevokeIncomingWebhookResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IncomingWebhookId string                 `protobuf:"bytes,1,opt,name=incoming_webhook_id,json=incomingWebhookId,proto3" json:"incoming_webhook_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RevokeIncomingWebhookResponse) Reset() {
+	*x = RevokeIncomingWebhookResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListChatsResponse) String() string {
+func (x *RevokeIncomingWebhookResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListChatsResponse) ProtoMessage() {}
+func (*RevokeIncomingWebhookResponse) ProtoMessage() {}
 
-func (x *ListChatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[16]
+func (x *RevokeIncomingWebhookResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -976,49 +7262,46 @@ func (x *ListChatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListChatsResponse.ProtoReflect.Descriptor instead.
-func (*ListChatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{16}
-}
-
-func (x *ListChatsResponse) GetChats() []*Chat {
-	if x != nil {
-		return x.Chats
-	}
-	return nil
+// Deprecated: Use RevokeIncomingWebhookResponse.ProtoReflect.Descriptor instead.
+func (*RevokeIncomingWebhookResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{128}
 }
 
-func (x *ListChatsResponse) GetTotal() int32 {
+func (x *RevokeIncomingWebhookResponse) GetIncomingWebhookId() string {
 	if x != nil {
-		return x.Total
+		return x.IncomingWebhookId
 	}
-	return 0
+	return ""
 }
 
-type CreateUserRequest struct {
+type Command struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                    // Empty for built-in commands.
+	BotId         string                 `protobuf:"bytes,2,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"` // Empty for built-in commands.
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                // Without the slash.
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Usage         string                 `protobuf:"bytes,5,opt,name=usage,proto3" json:"usage,omitempty"` // The arguments, like "<what> at <time>".
+	Url           string                 `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`     // Only listed for the bot's owner.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserRequest) Reset() {
-	*x = CreateUserRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[17]
+func (x *Command) Reset() {
+	*x = Command{}
+	mi := &file_proto_messaging_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserRequest) String() string {
+func (x *Command) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserRequest) ProtoMessage() {}
+func (*Command) ProtoMessage() {}
 
-func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[17]
+func (x *Command) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1029,55 +7312,86 @@ func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
-func (*CreateUserRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use Command.ProtoReflect.Descriptor instead.
+func (*Command) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{129}
 }
 
-func (x *CreateUserRequest) GetUsername() string {
+func (x *Command) GetId() string {
 	if x != nil {
-		return x.Username
+		return x.Id
 	}
 	return ""
 }
 
-func (x *CreateUserRequest) GetEmail() string {
+func (x *Command) GetBotId() string {
 	if x != nil {
-		return x.Email
+		return x.BotId
 	}
 	return ""
 }
 
-func (x *CreateUserRequest) GetPassword() string {
+func (x *Command) GetName() string {
 	if x != nil {
-		return x.Password
+		return x.Name
 	}
 	return ""
 }
 
-type CreateUserResponse struct {
+func (x *Command) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Command) GetUsage() string {
+	if x != nil {
+		return x.Usage
+	}
+	return ""
+}
+
+func (x *Command) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Command) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RegisterCommandRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	BotId         string                 `protobuf:"bytes,1,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Usage         string                 `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	Url           string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateUserResponse) Reset() {
-	*x = CreateUserResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[18]
+func (x *RegisterCommandRequest) Reset() {
+	*x = RegisterCommandRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateUserResponse) String() string {
+func (x *RegisterCommandRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateUserResponse) ProtoMessage() {}
+func (*RegisterCommandRequest) ProtoMessage() {}
 
-func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[18]
+func (x *RegisterCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1088,48 +7402,71 @@ func (x *CreateUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateUserResponse.ProtoReflect.Descriptor instead.
-func (*CreateUserResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use RegisterCommandRequest.ProtoReflect.Descriptor instead.
+func (*RegisterCommandRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{130}
 }
 
-func (x *CreateUserResponse) GetUser() *User {
+func (x *RegisterCommandRequest) GetBotId() string {
 	if x != nil {
-		return x.User
+		return x.BotId
 	}
-	return nil
+	return ""
 }
 
-func (x *CreateUserResponse) GetToken() string {
+func (x *RegisterCommandRequest) GetName() string {
 	if x != nil {
-		return x.Token
+		return x.Name
 	}
 	return ""
 }
 
-type LoginRequest struct {
+func (x *RegisterCommandRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RegisterCommandRequest) GetUsage() string {
+	if x != nil {
+		return x.Usage
+	}
+	return ""
+}
+
+func (x *RegisterCommandRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// The secret verifies the X-Webhook-Signature header of the calls and is
+// only returned here.
+type RegisterCommandResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Command       *Command               `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoginRequest) Reset() {
-	*x = LoginRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[19]
+func (x *RegisterCommandResponse) Reset() {
+	*x = RegisterCommandResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[131]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoginRequest) String() string {
+func (x *RegisterCommandResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoginRequest) ProtoMessage() {}
+func (*RegisterCommandResponse) ProtoMessage() {}
 
-func (x *LoginRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[19]
+func (x *RegisterCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[131]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1140,48 +7477,49 @@ func (x *LoginRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoginRequest.ProtoReflect.Descriptor instead.
-func (*LoginRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use RegisterCommandResponse.ProtoReflect.Descriptor instead.
+func (*RegisterCommandResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{131}
 }
 
-func (x *LoginRequest) GetEmail() string {
+func (x *RegisterCommandResponse) GetCommand() *Command {
 	if x != nil {
-		return x.Email
+		return x.Command
 	}
-	return ""
+	return nil
 }
 
-func (x *LoginRequest) GetPassword() string {
+func (x *RegisterCommandResponse) GetSecret() string {
 	if x != nil {
-		return x.Password
+		return x.Secret
 	}
 	return ""
 }
 
-type LoginResponse struct {
+// Exactly one of chat_id and bot_id is set.
+type ListCommandsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
-	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	BotId         string                 `protobuf:"bytes,2,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoginResponse) Reset() {
-	*x = LoginResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[20]
+func (x *ListCommandsRequest) Reset() {
+	*x = ListCommandsRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[132]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoginResponse) String() string {
+func (x *ListCommandsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoginResponse) ProtoMessage() {}
+func (*ListCommandsRequest) ProtoMessage() {}
 
-func (x *LoginResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[20]
+func (x *ListCommandsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[132]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1192,47 +7530,47 @@ func (x *LoginResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoginResponse.ProtoReflect.Descriptor instead.
-func (*LoginResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ListCommandsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommandsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{132}
 }
 
-func (x *LoginResponse) GetUser() *User {
+func (x *ListCommandsRequest) GetChatId() string {
 	if x != nil {
-		return x.User
+		return x.ChatId
 	}
-	return nil
+	return ""
 }
 
-func (x *LoginResponse) GetToken() string {
+func (x *ListCommandsRequest) GetBotId() string {
 	if x != nil {
-		return x.Token
+		return x.BotId
 	}
 	return ""
 }
 
-type GetUserRequest struct {
+type ListCommandsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Commands      []*Command             `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserRequest) Reset() {
-	*x = GetUserRequest{}
-	mi := &file_proto_messaging_proto_msgTypes[21]
+func (x *ListCommandsResponse) Reset() {
+	*x = ListCommandsResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[133]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserRequest) String() string {
+func (x *ListCommandsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserRequest) ProtoMessage() {}
+func (*ListCommandsResponse) ProtoMessage() {}
 
-func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[21]
+func (x *ListCommandsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[133]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1243,40 +7581,41 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
-func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ListCommandsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommandsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{133}
 }
 
-func (x *GetUserRequest) GetUserId() string {
+func (x *ListCommandsResponse) GetCommands() []*Command {
 	if x != nil {
-		return x.UserId
+		return x.Commands
 	}
-	return ""
+	return nil
 }
 
-type GetUserResponse struct {
+type DeleteCommandRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	BotId         string                 `protobuf:"bytes,1,opt,name=bot_id,json=botId,proto3" json:"bot_id,omitempty"`
+	CommandId     string                 `protobuf:"bytes,2,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserResponse) Reset() {
-	*x = GetUserResponse{}
-	mi := &file_proto_messaging_proto_msgTypes[22]
+func (x *DeleteCommandRequest) Reset() {
+	*x = DeleteCommandRequest{}
+	mi := &file_proto_messaging_proto_msgTypes[134]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserResponse) String() string {
+func (x *DeleteCommandRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserResponse) ProtoMessage() {}
+func (*DeleteCommandRequest) ProtoMessage() {}
 
-func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[22]
+func (x *DeleteCommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[134]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1287,43 +7626,47 @@ func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
-func (*GetUserResponse) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use DeleteCommandRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCommandRequest) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{134}
 }
 
-func (x *GetUserResponse) GetUser() *User {
+func (x *DeleteCommandRequest) GetBotId() string {
 	if x != nil {
-		return x.User
+		return x.BotId
 	}
-	return nil
+	return ""
 }
 
-type UserUpdate struct {
+func (x *DeleteCommandRequest) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+type DeleteCommandResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	UpdateType    string                 `protobuf:"bytes,2,opt,name=update_type,json=updateType,proto3" json:"update_type,omitempty"` // "profile_update", "status_change", "new_chat"
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	CommandId     string                 `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UserUpdate) Reset() {
-	*x = UserUpdate{}
-	mi := &file_proto_messaging_proto_msgTypes[23]
+func (x *DeleteCommandResponse) Reset() {
+	*x = DeleteCommandResponse{}
+	mi := &file_proto_messaging_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserUpdate) String() string {
+func (x *DeleteCommandResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserUpdate) ProtoMessage() {}
+func (*DeleteCommandResponse) ProtoMessage() {}
 
-func (x *UserUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_messaging_proto_msgTypes[23]
+func (x *DeleteCommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_messaging_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1334,70 +7677,84 @@ func (x *UserUpdate) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserUpdate.ProtoReflect.Descriptor instead.
-func (*UserUpdate) Descriptor() ([]byte, []int) {
-	return file_proto_messaging_proto_rawDescGZIP(), []int{23}
-}
-
-func (x *UserUpdate) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
-}
-
-func (x *UserUpdate) GetUpdateType() string {
-	if x != nil {
-		return x.UpdateType
-	}
-	return ""
+// Deprecated: Use DeleteCommandResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCommandResponse) Descriptor() ([]byte, []int) {
+	return file_proto_messaging_proto_rawDescGZIP(), []int{135}
 }
 
-func (x *UserUpdate) GetContent() string {
+func (x *DeleteCommandResponse) GetCommandId() string {
 	if x != nil {
-		return x.Content
+		return x.CommandId
 	}
 	return ""
 }
 
-func (x *UserUpdate) GetTimestamp() *timestamppb.Timestamp {
-	if x != nil {
-		return x.Timestamp
-	}
-	return nil
-}
-
 var File_proto_messaging_proto protoreflect.FileDescriptor
 
 const file_proto_messaging_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/messaging.proto\x12\tmessaging\x1a\x1fgoogle/protobuf/timestamp.proto\"\x83\x01\n" +
+	"\x15proto/messaging.proto\x12\tmessaging\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe9\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x129\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb2\x01\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12!\n" +
+	"\fdisplay_name\x18\x05 \x01(\tR\vdisplayName\x12\x10\n" +
+	"\x03bio\x18\x06 \x01(\tR\x03bio\x120\n" +
+	"\x14avatar_attachment_id\x18\a \x01(\tR\x12avatarAttachmentId\x12\"\n" +
+	"\fdiscoverable\x18\b \x01(\bR\fdiscoverable\x12%\n" +
+	"\x0eemail_verified\x18\t \x01(\bR\remailVerified\x12\x10\n" +
+	"\x03bot\x18\n" +
+	" \x01(\bR\x03bot\x12 \n" +
+	"\fbot_owner_id\x18\v \x01(\tR\n" +
+	"botOwnerId\"\xc6\x01\n" +
 	"\aMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x17\n" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x18\n" +
 	"\acontent\x18\x04 \x01(\tR\acontent\x123\n" +
 	"\asent_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x16\n" +
-	"\x06status\x18\x06 \x01(\tR\x06status\"\xc7\x01\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x12\n" +
+	"\x04kind\x18\a \x01(\tR\x04kind\"\x9a\x04\n" +
 	"\x04Chat\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12)\n" +
 	"\amembers\x18\x05 \x03(\v2\x0f.messaging.UserR\amembers\x125\n" +
-	"\flast_message\x18\x06 \x01(\v2\x12.messaging.MessageR\vlastMessage\"p\n" +
+	"\flast_message\x18\x06 \x01(\v2\x12.messaging.MessageR\vlastMessage\x123\n" +
+	"\bsettings\x18\a \x01(\v2\x17.messaging.ChatSettingsR\bsettings\x12D\n" +
+	"\x10last_activity_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0elastActivityAt\x12!\n" +
+	"\funread_count\x18\t \x01(\x05R\vunreadCount\x12+\n" +
+	"\x11participant_count\x18\n" +
+	" \x01(\x05R\x10participantCount\x12 \n" +
+	"\vdescription\x18\v \x01(\tR\vdescription\x120\n" +
+	"\x14avatar_attachment_id\x18\f \x01(\tR\x12avatarAttachmentId\x12\x12\n" +
+	"\x04kind\x18\r \x01(\tR\x04kind\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x0e \x01(\tR\n" +
+	"visibility\"\xcb\x01\n" +
+	"\fChatSettings\x12;\n" +
+	"\vmuted_until\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"mutedUntil\x12\x1a\n" +
+	"\barchived\x18\x02 \x01(\bR\barchived\x12\x16\n" +
+	"\x06pinned\x18\x03 \x01(\bR\x06pinned\x12\x1b\n" +
+	"\tpin_order\x18\x04 \x01(\x05R\bpinOrder\x12-\n" +
+	"\x12notification_level\x18\x05 \x01(\tR\x11notificationLevel\"p\n" +
 	"\x12SendMessageRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12'\n" +
-	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"C\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\x81\x01\n" +
 	"\x13SendMessageResponse\x12,\n" +
-	"\amessage\x18\x01 \x01(\v2\x12.messaging.MessageR\amessage\"X\n" +
+	"\amessage\x18\x01 \x01(\v2\x12.messaging.MessageR\amessage\x12<\n" +
+	"\rcommand_reply\x18\x02 \x01(\v2\x17.messaging.CommandReplyR\fcommandReply\"_\n" +
+	"\fCommandReply\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x02 \x01(\tR\n" +
+	"visibility\x12\x1b\n" +
+	"\tsender_id\x18\x03 \x01(\tR\bsenderId\"X\n" +
 	"\x13ListMessagesRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
 	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
@@ -1412,9 +7769,42 @@ const file_proto_messaging_proto_rawDesc = "" +
 	"\x1bUpdateMessageStatusResponse\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\"1\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"\xec\x02\n" +
+	"\x10ScheduledMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x123\n" +
+	"\asend_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06sendAt\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\a \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x123\n" +
+	"\asent_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x80\x01\n" +
+	"\x16ScheduleMessageRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x123\n" +
+	"\asend_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06sendAt\"c\n" +
+	"\x17ScheduleMessageResponse\x12H\n" +
+	"\x11scheduled_message\x18\x01 \x01(\v2\x1b.messaging.ScheduledMessageR\x10scheduledMessage\"q\n" +
+	"\x14ListScheduledRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"y\n" +
+	"\x15ListScheduledResponse\x12J\n" +
+	"\x12scheduled_messages\x18\x01 \x03(\v2\x1b.messaging.ScheduledMessageR\x11scheduledMessages\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"J\n" +
+	"\x16CancelScheduledRequest\x120\n" +
+	"\x14scheduled_message_id\x18\x01 \x01(\tR\x12scheduledMessageId\"K\n" +
+	"\x17CancelScheduledResponse\x120\n" +
+	"\x14scheduled_message_id\x18\x01 \x01(\tR\x12scheduledMessageId\"1\n" +
 	"\x16SubscribeToChatRequest\x12\x17\n" +
-	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\xe1\x01\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\x8d\x02\n" +
 	"\vChatMessage\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
@@ -1423,7 +7813,8 @@ const file_proto_messaging_proto_rawDesc = "" +
 	"\busername\x18\x04 \x01(\tR\busername\x12\x18\n" +
 	"\acontent\x18\x05 \x01(\tR\acontent\x123\n" +
 	"\asent_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\"=\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12*\n" +
+	"\x04type\x18\b \x01(\x0e2\x16.messaging.MessageTypeR\x04type\"=\n" +
 	"\x11CreateChatRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\"-\n" +
@@ -1432,51 +7823,505 @@ const file_proto_messaging_proto_rawDesc = "" +
 	"\x0eGetChatRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\"6\n" +
 	"\x0fGetChatResponse\x12#\n" +
-	"\x04chat\x18\x01 \x01(\v2\x0f.messaging.ChatR\x04chat\"<\n" +
+	"\x04chat\x18\x01 \x01(\v2\x0f.messaging.ChatR\x04chat\"\x8c\x01\n" +
 	"\x10ListChatsRequest\x12\x12\n" +
 	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
-	"\x05limit\x18\x02 \x01(\x05R\x05limit\"P\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12#\n" +
+	"\rarchived_only\x18\x03 \x01(\bR\farchivedOnly\x12)\n" +
+	"\x10exclude_archived\x18\x04 \x01(\bR\x0fexcludeArchived\"P\n" +
 	"\x11ListChatsResponse\x12%\n" +
 	"\x05chats\x18\x01 \x03(\v2\x0f.messaging.ChatR\x05chats\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"a\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xda\x02\n" +
+	"\x19UpdateChatSettingsRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12;\n" +
+	"\vmuted_until\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"mutedUntil\x12\x16\n" +
+	"\x06unmute\x18\x03 \x01(\bR\x06unmute\x12\x1f\n" +
+	"\barchived\x18\x04 \x01(\bH\x00R\barchived\x88\x01\x01\x12\x1b\n" +
+	"\x06pinned\x18\x05 \x01(\bH\x01R\x06pinned\x88\x01\x01\x12 \n" +
+	"\tpin_order\x18\x06 \x01(\x05H\x02R\bpinOrder\x88\x01\x01\x122\n" +
+	"\x12notification_level\x18\a \x01(\tH\x03R\x11notificationLevel\x88\x01\x01B\v\n" +
+	"\t_archivedB\t\n" +
+	"\a_pinnedB\f\n" +
+	"\n" +
+	"_pin_orderB\x15\n" +
+	"\x13_notification_level\"Q\n" +
+	"\x1aUpdateChatSettingsResponse\x123\n" +
+	"\bsettings\x18\x01 \x01(\v2\x17.messaging.ChatSettingsR\bsettings\"\xd5\x01\n" +
+	"\x11UpdateChatRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x03 \x01(\tH\x01R\vdescription\x88\x01\x01\x125\n" +
+	"\x14avatar_attachment_id\x18\x04 \x01(\tH\x02R\x12avatarAttachmentId\x88\x01\x01B\a\n" +
+	"\x05_nameB\x0e\n" +
+	"\f_descriptionB\x17\n" +
+	"\x15_avatar_attachment_id\"9\n" +
+	"\x12UpdateChatResponse\x12#\n" +
+	"\x04chat\x18\x01 \x01(\v2\x0f.messaging.ChatR\x04chat\"l\n" +
+	"\x14CreateChannelRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"visibility\x18\x03 \x01(\tR\n" +
+	"visibility\"0\n" +
+	"\x15CreateChannelResponse\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"-\n" +
+	"\x12JoinChannelRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\".\n" +
+	"\x13JoinChannelResponse\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\".\n" +
+	"\x13LeaveChannelRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"/\n" +
+	"\x14LeaveChannelResponse\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"K\n" +
+	"\x17AddChannelMemberRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"L\n" +
+	"\x18AddChannelMemberResponse\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"Y\n" +
+	"\x17DiscoverChannelsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"]\n" +
+	"\x18DiscoverChannelsResponse\x12+\n" +
+	"\bchannels\x18\x01 \x03(\v2\x0f.messaging.ChatR\bchannels\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\x82\x01\n" +
 	"\x11CreateUserRequest\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpassword\"O\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x1f\n" +
+	"\vdevice_name\x18\x04 \x01(\tR\n" +
+	"deviceName\"\xba\x01\n" +
 	"\x12CreateUserResponse\x12#\n" +
 	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\x12\x14\n" +
-	"\x05token\x18\x02 \x01(\tR\x05token\"@\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12D\n" +
+	"\x10token_expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0etokenExpiresAt\"a\n" +
 	"\fLoginRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
-	"\bpassword\x18\x02 \x01(\tR\bpassword\"J\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\"\xac\x02\n" +
 	"\rLoginResponse\x12#\n" +
 	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\x12\x14\n" +
-	"\x05token\x18\x02 \x01(\tR\x05token\")\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12D\n" +
+	"\x10token_expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0etokenExpiresAt\x12'\n" +
+	"\x0fchallenge_token\x18\x05 \x01(\tR\x0echallengeToken\x12L\n" +
+	"\x14challenge_expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x12challengeExpiresAt\"<\n" +
+	"\x15RefreshSessionRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\"\x99\x01\n" +
+	"\x16RefreshSessionResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x02 \x01(\tR\frefreshToken\x12D\n" +
+	"\x10token_expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0etokenExpiresAt\"W\n" +
+	"\rLogoutRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\x12!\n" +
+	"\fall_sessions\x18\x02 \x01(\bR\vallSessions\"\x10\n" +
+	"\x0eLogoutResponse\"\x8b\x02\n" +
+	"\aSession\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vdevice_name\x18\x02 \x01(\tR\n" +
+	"deviceName\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x03 \x01(\tR\tuserAgent\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x04 \x01(\tR\tipAddress\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\flast_used_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x12\x18\n" +
+	"\acurrent\x18\a \x01(\bR\acurrent\"\x15\n" +
+	"\x13ListSessionsRequest\"F\n" +
+	"\x14ListSessionsResponse\x12.\n" +
+	"\bsessions\x18\x01 \x03(\v2\x12.messaging.SessionR\bsessions\"5\n" +
+	"\x14RevokeSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"6\n" +
+	"\x15RevokeSessionResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"e\n" +
+	"\x15ChangePasswordRequest\x12)\n" +
+	"\x10current_password\x18\x01 \x01(\tR\x0fcurrentPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"\x18\n" +
+	"\x16ChangePasswordResponse\"3\n" +
+	"\x1bRequestPasswordResetRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"\x1e\n" +
+	"\x1cRequestPasswordResetResponse\"V\n" +
+	"\x1bConfirmPasswordResetRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"\x1e\n" +
+	"\x1cConfirmPasswordResetResponse\"*\n" +
+	"\x12VerifyEmailRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\x15\n" +
+	"\x13VerifyEmailResponse\" \n" +
+	"\x1eResendVerificationEmailRequest\"!\n" +
+	"\x1fResendVerificationEmailResponse\"\x13\n" +
+	"\x11EnrollTOTPRequest\"t\n" +
+	"\x12EnrollTOTPResponse\x12\x16\n" +
+	"\x06secret\x18\x01 \x01(\tR\x06secret\x12\x1f\n" +
+	"\votpauth_uri\x18\x02 \x01(\tR\n" +
+	"otpauthUri\x12%\n" +
+	"\x0erecovery_codes\x18\x03 \x03(\tR\rrecoveryCodes\"(\n" +
+	"\x12ConfirmTOTPRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\x15\n" +
+	"\x13ConfirmTOTPResponse\"Z\n" +
+	"\x1bVerifyLoginChallengeRequest\x12'\n" +
+	"\x0fchallenge_token\x18\x01 \x01(\tR\x0echallengeToken\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\xc4\x01\n" +
+	"\x1cVerifyLoginChallengeResponse\x12#\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12#\n" +
+	"\rrefresh_token\x18\x03 \x01(\tR\frefreshToken\x12D\n" +
+	"\x10token_expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0etokenExpiresAt\"k\n" +
+	"\x17LoginWithIDTokenRequest\x12\x19\n" +
+	"\bid_token\x18\x01 \x01(\tR\aidToken\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\tR\x05nonce\x12\x1f\n" +
+	"\vdevice_name\x18\x03 \x01(\tR\n" +
+	"deviceName\"\x96\x01\n" +
+	"\x17ExchangeOIDCCodeRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12#\n" +
+	"\rcode_verifier\x18\x02 \x01(\tR\fcodeVerifier\x12!\n" +
+	"\fredirect_uri\x18\x03 \x01(\tR\vredirectUri\x12\x1f\n" +
+	"\vdevice_name\x18\x04 \x01(\tR\n" +
+	"deviceName\")\n" +
 	"\x0eGetUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"6\n" +
 	"\x0fGetUserResponse\x12#\n" +
-	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\"\x9a\x01\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\"\x0e\n" +
+	"\fGetMeRequest\"4\n" +
+	"\rGetMeResponse\x12#\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\"\xa6\x02\n" +
+	"\x14UpdateProfileRequest\x12\x1f\n" +
+	"\busername\x18\x01 \x01(\tH\x00R\busername\x88\x01\x01\x12&\n" +
+	"\fdisplay_name\x18\x02 \x01(\tH\x01R\vdisplayName\x88\x01\x01\x12\x15\n" +
+	"\x03bio\x18\x03 \x01(\tH\x02R\x03bio\x88\x01\x01\x125\n" +
+	"\x14avatar_attachment_id\x18\x04 \x01(\tH\x03R\x12avatarAttachmentId\x88\x01\x01\x12'\n" +
+	"\fdiscoverable\x18\x05 \x01(\bH\x04R\fdiscoverable\x88\x01\x01B\v\n" +
+	"\t_usernameB\x0f\n" +
+	"\r_display_nameB\x06\n" +
+	"\x04_bioB\x17\n" +
+	"\x15_avatar_attachment_idB\x0f\n" +
+	"\r_discoverable\"<\n" +
+	"\x15UpdateProfileResponse\x12#\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\"1\n" +
+	"\x14BatchGetUsersRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\">\n" +
+	"\x15BatchGetUsersResponse\x12%\n" +
+	"\x05users\x18\x01 \x03(\v2\x0f.messaging.UserR\x05users\"X\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"]\n" +
+	"\x13SearchUsersResponse\x12%\n" +
+	"\x05users\x18\x01 \x03(\v2\x0f.messaging.UserR\x05users\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x1f\n" +
+	"\x1dSubscribeToUserUpdatesRequest\"\xbf\x01\n" +
 	"\n" +
 	"UserUpdate\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
 	"\vupdate_type\x18\x02 \x01(\tR\n" +
 	"updateType\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x128\n" +
-	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp2\xe6\x02\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12#\n" +
+	"\x04user\x18\x05 \x01(\v2\x0f.messaging.UserR\x04user\"+\n" +
+	"\x10BlockUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\",\n" +
+	"\x11BlockUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"-\n" +
+	"\x12UnblockUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\".\n" +
+	"\x13UnblockUserResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"m\n" +
+	"\vBlockedUser\x12#\n" +
+	"\x04user\x18\x01 \x01(\v2\x0f.messaging.UserR\x04user\x129\n" +
+	"\n" +
+	"blocked_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tblockedAt\">\n" +
+	"\x12ListBlockedRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"Y\n" +
+	"\x13ListBlockedResponse\x12,\n" +
+	"\x05users\x18\x01 \x03(\v2\x16.messaging.BlockedUserR\x05users\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"^\n" +
+	"\x11ReportUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\"1\n" +
+	"\x12ReportUserResponse\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\"g\n" +
+	"\x14ReportMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\x03 \x01(\tR\adetails\"4\n" +
+	"\x15ReportMessageResponse\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\"\xdb\x02\n" +
+	"\x06Report\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vreporter_id\x18\x02 \x01(\tR\n" +
+	"reporterId\x12(\n" +
+	"\x10reported_user_id\x18\x03 \x01(\tR\x0ereportedUserId\x12\x17\n" +
+	"\achat_id\x18\x04 \x01(\tR\x06chatId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageId\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x18\n" +
+	"\adetails\x18\a \x01(\tR\adetails\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"V\n" +
+	"\x12ListReportsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"X\n" +
+	"\x13ListReportsResponse\x12+\n" +
+	"\areports\x18\x01 \x03(\v2\x11.messaging.ReportR\areports\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"K\n" +
+	"\x14ResolveReportRequest\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"B\n" +
+	"\x15ResolveReportResponse\x12)\n" +
+	"\x06report\x18\x01 \x01(\v2\x11.messaging.ReportR\x06report\"Q\n" +
+	"\x10CreateBotRequest\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\"6\n" +
+	"\x11CreateBotResponse\x12!\n" +
+	"\x03bot\x18\x01 \x01(\v2\x0f.messaging.UserR\x03bot\"\x11\n" +
+	"\x0fListBotsRequest\"7\n" +
+	"\x10ListBotsResponse\x12#\n" +
+	"\x04bots\x18\x01 \x03(\v2\x0f.messaging.UserR\x04bots\"E\n" +
+	"\x13AddBotToChatRequest\x12\x15\n" +
+	"\x06bot_id\x18\x01 \x01(\tR\x05botId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\"/\n" +
+	"\x14AddBotToChatResponse\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\x8e\x02\n" +
+	"\x06APIKey\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06bot_id\x18\x02 \x01(\tR\x05botId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"key_prefix\x18\x04 \x01(\tR\tkeyPrefix\x12\x16\n" +
+	"\x06scopes\x18\x05 \x03(\tR\x06scopes\x12\x19\n" +
+	"\bchat_ids\x18\x06 \x03(\tR\achatIds\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\flast_used_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\"s\n" +
+	"\x13CreateAPIKeyRequest\x12\x15\n" +
+	"\x06bot_id\x18\x01 \x01(\tR\x05botId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06scopes\x18\x03 \x03(\tR\x06scopes\x12\x19\n" +
+	"\bchat_ids\x18\x04 \x03(\tR\achatIds\"T\n" +
+	"\x14CreateAPIKeyResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\aapi_key\x18\x02 \x01(\v2\x11.messaging.APIKeyR\x06apiKey\"+\n" +
+	"\x12ListAPIKeysRequest\x12\x15\n" +
+	"\x06bot_id\x18\x01 \x01(\tR\x05botId\"C\n" +
+	"\x13ListAPIKeysResponse\x12,\n" +
+	"\bapi_keys\x18\x01 \x03(\v2\x11.messaging.APIKeyR\aapiKeys\"J\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x06bot_id\x18\x01 \x01(\tR\x05botId\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x02 \x01(\tR\bapiKeyId\"4\n" +
+	"\x14RevokeAPIKeyResponse\x12\x1c\n" +
+	"\n" +
+	"api_key_id\x18\x01 \x01(\tR\bapiKeyId\"\xae\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x15\n" +
+	"\x06bot_id\x18\x03 \x01(\tR\x05botId\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x05 \x03(\tR\x06events\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"p\n" +
+	"\x14CreateWebhookRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x15\n" +
+	"\x06bot_id\x18\x02 \x01(\tR\x05botId\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x04 \x03(\tR\x06events\"]\n" +
+	"\x15CreateWebhookResponse\x12,\n" +
+	"\awebhook\x18\x01 \x01(\v2\x12.messaging.WebhookR\awebhook\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"E\n" +
+	"\x13ListWebhooksRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x15\n" +
+	"\x06bot_id\x18\x02 \x01(\tR\x05botId\"F\n" +
+	"\x14ListWebhooksResponse\x12.\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\x12.messaging.WebhookR\bwebhooks\"5\n" +
+	"\x14DeleteWebhookRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\"6\n" +
+	"\x15DeleteWebhookResponse\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\"\xef\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12B\n" +
+	"\x0flast_attempt_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\rlastAttemptAt\x12(\n" +
+	"\x10last_status_code\x18\t \x01(\x05R\x0elastStatusCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x12=\n" +
+	"\fdelivered_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x7f\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"q\n" +
+	"\x1dListWebhookDeliveriesResponse\x12:\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1a.messaging.WebhookDeliveryR\n" +
+	"deliveries\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xe4\x01\n" +
+	"\x0fIncomingWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\tR\tcreatedBy\x12!\n" +
+	"\ftoken_prefix\x18\x06 \x01(\tR\vtokenPrefix\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"K\n" +
+	"\x1cCreateIncomingWebhookRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"x\n" +
+	"\x1dCreateIncomingWebhookResponse\x12E\n" +
+	"\x10incoming_webhook\x18\x01 \x01(\v2\x1a.messaging.IncomingWebhookR\x0fincomingWebhook\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"6\n" +
+	"\x1bListIncomingWebhooksRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"g\n" +
+	"\x1cListIncomingWebhooksResponse\x12G\n" +
+	"\x11incoming_webhooks\x18\x01 \x03(\v2\x1a.messaging.IncomingWebhookR\x10incomingWebhooks\"N\n" +
+	"\x1cRevokeIncomingWebhookRequest\x12.\n" +
+	"\x13incoming_webhook_id\x18\x01 \x01(\tR\x11incomingWebhookId\"O\n" +
+	"\x1dRevokeIncomingWebhookResponse\x12.\n" +
+	"\x13incoming_webhook_id\x18\x01 \x01(\tR\x11incomingWebhookId\"\xc9\x01\n" +
+	"\aCommand\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06bot_id\x18\x02 \x01(\tR\x05botId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05usage\x18\x05 \x01(\tR\x05usage\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8d\x01\n" +
+	"\x16RegisterCommandRequest\x12\x15\n" +
+	"\x06bot_id\x18\x01 \x01(\tR\x05botId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05usage\x18\x04 \x01(\tR\x05usage\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\"_\n" +
+	"\x17RegisterCommandResponse\x12,\n" +
+	"\acommand\x18\x01 \x01(\v2\x12.messaging.CommandR\acommand\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"E\n" +
+	"\x13ListCommandsRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x15\n" +
+	"\x06bot_id\x18\x02 \x01(\tR\x05botId\"F\n" +
+	"\x14ListCommandsResponse\x12.\n" +
+	"\bcommands\x18\x01 \x03(\v2\x12.messaging.CommandR\bcommands\"L\n" +
+	"\x14DeleteCommandRequest\x12\x15\n" +
+	"\x06bot_id\x18\x01 \x01(\tR\x05botId\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x02 \x01(\tR\tcommandId\"6\n" +
+	"\x15DeleteCommandResponse\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId*\xdf\x01\n" +
+	"\vMessageType\x12\x1c\n" +
+	"\x18MESSAGE_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10MESSAGE_TYPE_NEW\x10\x01\x12\x15\n" +
+	"\x11MESSAGE_TYPE_READ\x10\x02\x12\x17\n" +
+	"\x13MESSAGE_TYPE_TYPING\x10\x03\x12\x17\n" +
+	"\x13MESSAGE_TYPE_ONLINE\x10\x04\x12\x18\n" +
+	"\x14MESSAGE_TYPE_OFFLINE\x10\x05\x12\x1d\n" +
+	"\x19MESSAGE_TYPE_CHAT_UPDATED\x10\x06\x12\x1a\n" +
+	"\x16MESSAGE_TYPE_EPHEMERAL\x10\a2\xee\x04\n" +
 	"\x0fMessagesService\x12L\n" +
 	"\vSendMessage\x12\x1d.messaging.SendMessageRequest\x1a\x1e.messaging.SendMessageResponse\x12O\n" +
 	"\fListMessages\x12\x1e.messaging.ListMessagesRequest\x1a\x1f.messaging.ListMessagesResponse\x12d\n" +
-	"\x13UpdateMessageStatus\x12%.messaging.UpdateMessageStatusRequest\x1a&.messaging.UpdateMessageStatusResponse\x12N\n" +
-	"\x0fSubscribeToChat\x12!.messaging.SubscribeToChatRequest\x1a\x16.messaging.ChatMessage0\x012\xe3\x01\n" +
+	"\x13UpdateMessageStatus\x12%.messaging.UpdateMessageStatusRequest\x1a&.messaging.UpdateMessageStatusResponse\x12X\n" +
+	"\x0fScheduleMessage\x12!.messaging.ScheduleMessageRequest\x1a\".messaging.ScheduleMessageResponse\x12R\n" +
+	"\rListScheduled\x12\x1f.messaging.ListScheduledRequest\x1a .messaging.ListScheduledResponse\x12X\n" +
+	"\x0fCancelScheduled\x12!.messaging.CancelScheduledRequest\x1a\".messaging.CancelScheduledResponse\x12N\n" +
+	"\x0fSubscribeToChat\x12!.messaging.SubscribeToChatRequest\x1a\x16.messaging.ChatMessage0\x012\xbe\x06\n" +
 	"\fChatsService\x12I\n" +
 	"\n" +
 	"CreateChat\x12\x1c.messaging.CreateChatRequest\x1a\x1d.messaging.CreateChatResponse\x12@\n" +
 	"\aGetChat\x12\x19.messaging.GetChatRequest\x1a\x1a.messaging.GetChatResponse\x12F\n" +
-	"\tListChats\x12\x1b.messaging.ListChatsRequest\x1a\x1c.messaging.ListChatsResponse2\x95\x01\n" +
+	"\tListChats\x12\x1b.messaging.ListChatsRequest\x1a\x1c.messaging.ListChatsResponse\x12a\n" +
+	"\x12UpdateChatSettings\x12$.messaging.UpdateChatSettingsRequest\x1a%.messaging.UpdateChatSettingsResponse\x12I\n" +
+	"\n" +
+	"UpdateChat\x12\x1c.messaging.UpdateChatRequest\x1a\x1d.messaging.UpdateChatResponse\x12R\n" +
+	"\rCreateChannel\x12\x1f.messaging.CreateChannelRequest\x1a .messaging.CreateChannelResponse\x12L\n" +
+	"\vJoinChannel\x12\x1d.messaging.JoinChannelRequest\x1a\x1e.messaging.JoinChannelResponse\x12O\n" +
+	"\fLeaveChannel\x12\x1e.messaging.LeaveChannelRequest\x1a\x1f.messaging.LeaveChannelResponse\x12[\n" +
+	"\x10AddChannelMember\x12\".messaging.AddChannelMemberRequest\x1a#.messaging.AddChannelMemberResponse\x12[\n" +
+	"\x10DiscoverChannels\x12\".messaging.DiscoverChannelsRequest\x1a#.messaging.DiscoverChannelsResponse2\xb0\x0e\n" +
 	"\fUsersService\x12I\n" +
 	"\n" +
 	"CreateUser\x12\x1c.messaging.CreateUserRequest\x1a\x1d.messaging.CreateUserResponse\x12:\n" +
-	"\x05Login\x12\x17.messaging.LoginRequest\x1a\x18.messaging.LoginResponseB/Z-github.com/brenocoelho/messaging-app-go/protob\x06proto3"
+	"\x05Login\x12\x17.messaging.LoginRequest\x1a\x18.messaging.LoginResponse\x12U\n" +
+	"\x0eRefreshSession\x12 .messaging.RefreshSessionRequest\x1a!.messaging.RefreshSessionResponse\x12=\n" +
+	"\x06Logout\x12\x18.messaging.LogoutRequest\x1a\x19.messaging.LogoutResponse\x12O\n" +
+	"\fListSessions\x12\x1e.messaging.ListSessionsRequest\x1a\x1f.messaging.ListSessionsResponse\x12R\n" +
+	"\rRevokeSession\x12\x1f.messaging.RevokeSessionRequest\x1a .messaging.RevokeSessionResponse\x12U\n" +
+	"\x0eChangePassword\x12 .messaging.ChangePasswordRequest\x1a!.messaging.ChangePasswordResponse\x12g\n" +
+	"\x14RequestPasswordReset\x12&.messaging.RequestPasswordResetRequest\x1a'.messaging.RequestPasswordResetResponse\x12g\n" +
+	"\x14ConfirmPasswordReset\x12&.messaging.ConfirmPasswordResetRequest\x1a'.messaging.ConfirmPasswordResetResponse\x12L\n" +
+	"\vVerifyEmail\x12\x1d.messaging.VerifyEmailRequest\x1a\x1e.messaging.VerifyEmailResponse\x12p\n" +
+	"\x17ResendVerificationEmail\x12).messaging.ResendVerificationEmailRequest\x1a*.messaging.ResendVerificationEmailResponse\x12I\n" +
+	"\n" +
+	"EnrollTOTP\x12\x1c.messaging.EnrollTOTPRequest\x1a\x1d.messaging.EnrollTOTPResponse\x12L\n" +
+	"\vConfirmTOTP\x12\x1d.messaging.ConfirmTOTPRequest\x1a\x1e.messaging.ConfirmTOTPResponse\x12g\n" +
+	"\x14VerifyLoginChallenge\x12&.messaging.VerifyLoginChallengeRequest\x1a'.messaging.VerifyLoginChallengeResponse\x12P\n" +
+	"\x10LoginWithIDToken\x12\".messaging.LoginWithIDTokenRequest\x1a\x18.messaging.LoginResponse\x12P\n" +
+	"\x10ExchangeOIDCCode\x12\".messaging.ExchangeOIDCCodeRequest\x1a\x18.messaging.LoginResponse\x12@\n" +
+	"\aGetUser\x12\x19.messaging.GetUserRequest\x1a\x1a.messaging.GetUserResponse\x12:\n" +
+	"\x05GetMe\x12\x17.messaging.GetMeRequest\x1a\x18.messaging.GetMeResponse\x12R\n" +
+	"\rUpdateProfile\x12\x1f.messaging.UpdateProfileRequest\x1a .messaging.UpdateProfileResponse\x12R\n" +
+	"\rBatchGetUsers\x12\x1f.messaging.BatchGetUsersRequest\x1a .messaging.BatchGetUsersResponse\x12L\n" +
+	"\vSearchUsers\x12\x1d.messaging.SearchUsersRequest\x1a\x1e.messaging.SearchUsersResponse\x12[\n" +
+	"\x16SubscribeToUserUpdates\x12(.messaging.SubscribeToUserUpdatesRequest\x1a\x15.messaging.UserUpdate0\x012\xb8\x04\n" +
+	"\x11ModerationService\x12F\n" +
+	"\tBlockUser\x12\x1b.messaging.BlockUserRequest\x1a\x1c.messaging.BlockUserResponse\x12L\n" +
+	"\vUnblockUser\x12\x1d.messaging.UnblockUserRequest\x1a\x1e.messaging.UnblockUserResponse\x12L\n" +
+	"\vListBlocked\x12\x1d.messaging.ListBlockedRequest\x1a\x1e.messaging.ListBlockedResponse\x12I\n" +
+	"\n" +
+	"ReportUser\x12\x1c.messaging.ReportUserRequest\x1a\x1d.messaging.ReportUserResponse\x12R\n" +
+	"\rReportMessage\x12\x1f.messaging.ReportMessageRequest\x1a .messaging.ReportMessageResponse\x12L\n" +
+	"\vListReports\x12\x1d.messaging.ListReportsRequest\x1a\x1e.messaging.ListReportsResponse\x12R\n" +
+	"\rResolveReport\x12\x1f.messaging.ResolveReportRequest\x1a .messaging.ResolveReportResponse2\xdb\x03\n" +
+	"\vBotsService\x12F\n" +
+	"\tCreateBot\x12\x1b.messaging.CreateBotRequest\x1a\x1c.messaging.CreateBotResponse\x12C\n" +
+	"\bListBots\x12\x1a.messaging.ListBotsRequest\x1a\x1b.messaging.ListBotsResponse\x12O\n" +
+	"\fAddBotToChat\x12\x1e.messaging.AddBotToChatRequest\x1a\x1f.messaging.AddBotToChatResponse\x12O\n" +
+	"\fCreateAPIKey\x12\x1e.messaging.CreateAPIKeyRequest\x1a\x1f.messaging.CreateAPIKeyResponse\x12L\n" +
+	"\vListAPIKeys\x12\x1d.messaging.ListAPIKeysRequest\x1a\x1e.messaging.ListAPIKeysResponse\x12O\n" +
+	"\fRevokeAPIKey\x12\x1e.messaging.RevokeAPIKeyRequest\x1a\x1f.messaging.RevokeAPIKeyResponse2\xb7\x05\n" +
+	"\x0fWebhooksService\x12R\n" +
+	"\rCreateWebhook\x12\x1f.messaging.CreateWebhookRequest\x1a .messaging.CreateWebhookResponse\x12O\n" +
+	"\fListWebhooks\x12\x1e.messaging.ListWebhooksRequest\x1a\x1f.messaging.ListWebhooksResponse\x12R\n" +
+	"\rDeleteWebhook\x12\x1f.messaging.DeleteWebhookRequest\x1a .messaging.DeleteWebhookResponse\x12j\n" +
+	"\x15ListWebhookDeliveries\x12'.messaging.ListWebhookDeliveriesRequest\x1a(.messaging.ListWebhookDeliveriesResponse\x12j\n" +
+	"\x15CreateIncomingWebhook\x12'.messaging.CreateIncomingWebhookRequest\x1a(.messaging.CreateIncomingWebhookResponse\x12g\n" +
+	"\x14ListIncomingWebhooks\x12&.messaging.ListIncomingWebhooksRequest\x1a'.messaging.ListIncomingWebhooksResponse\x12j\n" +
+	"\x15RevokeIncomingWebhook\x12'.messaging.RevokeIncomingWebhookRequest\x1a(.messaging.RevokeIncomingWebhookResponse2\x90\x02\n" +
+	"\x0fCommandsService\x12X\n" +
+	"\x0fRegisterCommand\x12!.messaging.RegisterCommandRequest\x1a\".messaging.RegisterCommandResponse\x12O\n" +
+	"\fListCommands\x12\x1e.messaging.ListCommandsRequest\x1a\x1f.messaging.ListCommandsResponse\x12R\n" +
+	"\rDeleteCommand\x12\x1f.messaging.DeleteCommandRequest\x1a .messaging.DeleteCommandResponseB/Z-github.com/brenocoelho/messaging-app-go/protob\x06proto3"
 
 var (
 	file_proto_messaging_proto_rawDescOnce sync.Once
@@ -1490,72 +8335,348 @@ func file_proto_messaging_proto_rawDescGZIP() []byte {
 	return file_proto_messaging_proto_rawDescData
 }
 
-var file_proto_messaging_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_proto_messaging_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_messaging_proto_msgTypes = make([]protoimpl.MessageInfo, 136)
 var file_proto_messaging_proto_goTypes = []any{
-	(*User)(nil),                        // 0: messaging.User
-	(*Message)(nil),                     // 1: messaging.Message
-	(*Chat)(nil),                        // 2: messaging.Chat
-	(*SendMessageRequest)(nil),          // 3: messaging.SendMessageRequest
-	(*SendMessageResponse)(nil),         // 4: messaging.SendMessageResponse
-	(*ListMessagesRequest)(nil),         // 5: messaging.ListMessagesRequest
-	(*ListMessagesResponse)(nil),        // 6: messaging.ListMessagesResponse
-	(*UpdateMessageStatusRequest)(nil),  // 7: messaging.UpdateMessageStatusRequest
-	(*UpdateMessageStatusResponse)(nil), // 8: messaging.UpdateMessageStatusResponse
-	(*SubscribeToChatRequest)(nil),      // 9: messaging.SubscribeToChatRequest
-	(*ChatMessage)(nil),                 // 10: messaging.ChatMessage
-	(*CreateChatRequest)(nil),           // 11: messaging.CreateChatRequest
-	(*CreateChatResponse)(nil),          // 12: messaging.CreateChatResponse
-	(*GetChatRequest)(nil),              // 13: messaging.GetChatRequest
-	(*GetChatResponse)(nil),             // 14: messaging.GetChatResponse
-	(*ListChatsRequest)(nil),            // 15: messaging.ListChatsRequest
-	(*ListChatsResponse)(nil),           // 16: messaging.ListChatsResponse
-	(*CreateUserRequest)(nil),           // 17: messaging.CreateUserRequest
-	(*CreateUserResponse)(nil),          // 18: messaging.CreateUserResponse
-	(*LoginRequest)(nil),                // 19: messaging.LoginRequest
-	(*LoginResponse)(nil),               // 20: messaging.LoginResponse
-	(*GetUserRequest)(nil),              // 21: messaging.GetUserRequest
-	(*GetUserResponse)(nil),             // 22: messaging.GetUserResponse
-	(*UserUpdate)(nil),                  // 23: messaging.UserUpdate
-	(*timestamppb.Timestamp)(nil),       // 24: google.protobuf.Timestamp
+	(MessageType)(0),                        // 0: messaging.MessageType
+	(*User)(nil),                            // 1: messaging.User
+	(*Message)(nil),                         // 2: messaging.Message
+	(*Chat)(nil),                            // 3: messaging.Chat
+	(*ChatSettings)(nil),                    // 4: messaging.ChatSettings
+	(*SendMessageRequest)(nil),              // 5: messaging.SendMessageRequest
+	(*SendMessageResponse)(nil),             // 6: messaging.SendMessageResponse
+	(*CommandReply)(nil),                    // 7: messaging.CommandReply
+	(*ListMessagesRequest)(nil),             // 8: messaging.ListMessagesRequest
+	(*ListMessagesResponse)(nil),            // 9: messaging.ListMessagesResponse
+	(*UpdateMessageStatusRequest)(nil),      // 10: messaging.UpdateMessageStatusRequest
+	(*UpdateMessageStatusResponse)(nil),     // 11: messaging.UpdateMessageStatusResponse
+	(*ScheduledMessage)(nil),                // 12: messaging.ScheduledMessage
+	(*ScheduleMessageRequest)(nil),          // 13: messaging.ScheduleMessageRequest
+	(*ScheduleMessageResponse)(nil),         // 14: messaging.ScheduleMessageResponse
+	(*ListScheduledRequest)(nil),            // 15: messaging.ListScheduledRequest
+	(*ListScheduledResponse)(nil),           // 16: messaging.ListScheduledResponse
+	(*CancelScheduledRequest)(nil),          // 17: messaging.CancelScheduledRequest
+	(*CancelScheduledResponse)(nil),         // 18: messaging.CancelScheduledResponse
+	(*SubscribeToChatRequest)(nil),          // 19: messaging.SubscribeToChatRequest
+	(*ChatMessage)(nil),                     // 20: messaging.ChatMessage
+	(*CreateChatRequest)(nil),               // 21: messaging.CreateChatRequest
+	(*CreateChatResponse)(nil),              // 22: messaging.CreateChatResponse
+	(*GetChatRequest)(nil),                  // 23: messaging.GetChatRequest
+	(*GetChatResponse)(nil),                 // 24: messaging.GetChatResponse
+	(*ListChatsRequest)(nil),                // 25: messaging.ListChatsRequest
+	(*ListChatsResponse)(nil),               // 26: messaging.ListChatsResponse
+	(*UpdateChatSettingsRequest)(nil),       // 27: messaging.UpdateChatSettingsRequest
+	(*UpdateChatSettingsResponse)(nil),      // 28: messaging.UpdateChatSettingsResponse
+	(*UpdateChatRequest)(nil),               // 29: messaging.UpdateChatRequest
+	(*UpdateChatResponse)(nil),              // 30: messaging.UpdateChatResponse
+	(*CreateChannelRequest)(nil),            // 31: messaging.CreateChannelRequest
+	(*CreateChannelResponse)(nil),           // 32: messaging.CreateChannelResponse
+	(*JoinChannelRequest)(nil),              // 33: messaging.JoinChannelRequest
+	(*JoinChannelResponse)(nil),             // 34: messaging.JoinChannelResponse
+	(*LeaveChannelRequest)(nil),             // 35: messaging.LeaveChannelRequest
+	(*LeaveChannelResponse)(nil),            // 36: messaging.LeaveChannelResponse
+	(*AddChannelMemberRequest)(nil),         // 37: messaging.AddChannelMemberRequest
+	(*AddChannelMemberResponse)(nil),        // 38: messaging.AddChannelMemberResponse
+	(*DiscoverChannelsRequest)(nil),         // 39: messaging.DiscoverChannelsRequest
+	(*DiscoverChannelsResponse)(nil),        // 40: messaging.DiscoverChannelsResponse
+	(*CreateUserRequest)(nil),               // 41: messaging.CreateUserRequest
+	(*CreateUserResponse)(nil),              // 42: messaging.CreateUserResponse
+	(*LoginRequest)(nil),                    // 43: messaging.LoginRequest
+	(*LoginResponse)(nil),                   // 44: messaging.LoginResponse
+	(*RefreshSessionRequest)(nil),           // 45: messaging.RefreshSessionRequest
+	(*RefreshSessionResponse)(nil),          // 46: messaging.RefreshSessionResponse
+	(*LogoutRequest)(nil),                   // 47: messaging.LogoutRequest
+	(*LogoutResponse)(nil),                  // 48: messaging.LogoutResponse
+	(*Session)(nil),                         // 49: messaging.Session
+	(*ListSessionsRequest)(nil),             // 50: messaging.ListSessionsRequest
+	(*ListSessionsResponse)(nil),            // 51: messaging.ListSessionsResponse
+	(*RevokeSessionRequest)(nil),            // 52: messaging.RevokeSessionRequest
+	(*RevokeSessionResponse)(nil),           // 53: messaging.RevokeSessionResponse
+	(*ChangePasswordRequest)(nil),           // 54: messaging.ChangePasswordRequest
+	(*ChangePasswordResponse)(nil),          // 55: messaging.ChangePasswordResponse
+	(*RequestPasswordResetRequest)(nil),     // 56: messaging.RequestPasswordResetRequest
+	(*RequestPasswordResetResponse)(nil),    // 57: messaging.RequestPasswordResetResponse
+	(*ConfirmPasswordResetRequest)(nil),     // 58: messaging.ConfirmPasswordResetRequest
+	(*ConfirmPasswordResetResponse)(nil),    // 59: messaging.ConfirmPasswordResetResponse
+	(*VerifyEmailRequest)(nil),              // 60: messaging.VerifyEmailRequest
+	(*VerifyEmailResponse)(nil),             // 61: messaging.VerifyEmailResponse
+	(*ResendVerificationEmailRequest)(nil),  // 62: messaging.ResendVerificationEmailRequest
+	(*ResendVerificationEmailResponse)(nil), // 63: messaging.ResendVerificationEmailResponse
+	(*EnrollTOTPRequest)(nil),               // 64: messaging.EnrollTOTPRequest
+	(*EnrollTOTPResponse)(nil),              // 65: messaging.EnrollTOTPResponse
+	(*ConfirmTOTPRequest)(nil),              // 66: messaging.ConfirmTOTPRequest
+	(*ConfirmTOTPResponse)(nil),             // 67: messaging.ConfirmTOTPResponse
+	(*VerifyLoginChallengeRequest)(nil),     // 68: messaging.VerifyLoginChallengeRequest
+	(*VerifyLoginChallengeResponse)(nil),    // 69: messaging.VerifyLoginChallengeResponse
+	(*LoginWithIDTokenRequest)(nil),         // 70: messaging.LoginWithIDTokenRequest
+	(*ExchangeOIDCCodeRequest)(nil),         // 71: messaging.ExchangeOIDCCodeRequest
+	(*GetUserRequest)(nil),                  // 72: messaging.GetUserRequest
+	(*GetUserResponse)(nil),                 // 73: messaging.GetUserResponse
+	(*GetMeRequest)(nil),                    // 74: messaging.GetMeRequest
+	(*GetMeResponse)(nil),                   // 75: messaging.GetMeResponse
+	(*UpdateProfileRequest)(nil),            // 76: messaging.UpdateProfileRequest
+	(*UpdateProfileResponse)(nil),           // 77: messaging.UpdateProfileResponse
+	(*BatchGetUsersRequest)(nil),            // 78: messaging.BatchGetUsersRequest
+	(*BatchGetUsersResponse)(nil),           // 79: messaging.BatchGetUsersResponse
+	(*SearchUsersRequest)(nil),              // 80: messaging.SearchUsersRequest
+	(*SearchUsersResponse)(nil),             // 81: messaging.SearchUsersResponse
+	(*SubscribeToUserUpdatesRequest)(nil),   // 82: messaging.SubscribeToUserUpdatesRequest
+	(*UserUpdate)(nil),                      // 83: messaging.UserUpdate
+	(*BlockUserRequest)(nil),                // 84: messaging.BlockUserRequest
+	(*BlockUserResponse)(nil),               // 85: messaging.BlockUserResponse
+	(*UnblockUserRequest)(nil),              // 86: messaging.UnblockUserRequest
+	(*UnblockUserResponse)(nil),             // 87: messaging.UnblockUserResponse
+	(*BlockedUser)(nil),                     // 88: messaging.BlockedUser
+	(*ListBlockedRequest)(nil),              // 89: messaging.ListBlockedRequest
+	(*ListBlockedResponse)(nil),             // 90: messaging.ListBlockedResponse
+	(*ReportUserRequest)(nil),               // 91: messaging.ReportUserRequest
+	(*ReportUserResponse)(nil),              // 92: messaging.ReportUserResponse
+	(*ReportMessageRequest)(nil),            // 93: messaging.ReportMessageRequest
+	(*ReportMessageResponse)(nil),           // 94: messaging.ReportMessageResponse
+	(*Report)(nil),                          // 95: messaging.Report
+	(*ListReportsRequest)(nil),              // 96: messaging.ListReportsRequest
+	(*ListReportsResponse)(nil),             // 97: messaging.ListReportsResponse
+	(*ResolveReportRequest)(nil),            // 98: messaging.ResolveReportRequest
+	(*ResolveReportResponse)(nil),           // 99: messaging.ResolveReportResponse
+	(*CreateBotRequest)(nil),                // 100: messaging.CreateBotRequest
+	(*CreateBotResponse)(nil),               // 101: messaging.CreateBotResponse
+	(*ListBotsRequest)(nil),                 // 102: messaging.ListBotsRequest
+	(*ListBotsResponse)(nil),                // 103: messaging.ListBotsResponse
+	(*AddBotToChatRequest)(nil),             // 104: messaging.AddBotToChatRequest
+	(*AddBotToChatResponse)(nil),            // 105: messaging.AddBotToChatResponse
+	(*APIKey)(nil),                          // 106: messaging.APIKey
+	(*CreateAPIKeyRequest)(nil),             // 107: messaging.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),            // 108: messaging.CreateAPIKeyResponse
+	(*ListAPIKeysRequest)(nil),              // 109: messaging.ListAPIKeysRequest
+	(*ListAPIKeysResponse)(nil),             // 110: messaging.ListAPIKeysResponse
+	(*RevokeAPIKeyRequest)(nil),             // 111: messaging.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),            // 112: messaging.RevokeAPIKeyResponse
+	(*Webhook)(nil),                         // 113: messaging.Webhook
+	(*CreateWebhookRequest)(nil),            // 114: messaging.CreateWebhookRequest
+	(*CreateWebhookResponse)(nil),           // 115: messaging.CreateWebhookResponse
+	(*ListWebhooksRequest)(nil),             // 116: messaging.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),            // 117: messaging.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),            // 118: messaging.DeleteWebhookRequest
+	(*DeleteWebhookResponse)(nil),           // 119: messaging.DeleteWebhookResponse
+	(*WebhookDelivery)(nil),                 // 120: messaging.WebhookDelivery
+	(*ListWebhookDeliveriesRequest)(nil),    // 121: messaging.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil),   // 122: messaging.ListWebhookDeliveriesResponse
+	(*IncomingWebhook)(nil),                 // 123: messaging.IncomingWebhook
+	(*CreateIncomingWebhookRequest)(nil),    // 124: messaging.CreateIncomingWebhookRequest
+	(*CreateIncomingWebhookResponse)(nil),   // 125: messaging.CreateIncomingWebhookResponse
+	(*ListIncomingWebhooksRequest)(nil),     // 126: messaging.ListIncomingWebhooksRequest
+	(*ListIncomingWebhooksResponse)(nil),    // 127: messaging.ListIncomingWebhooksResponse
+	(*RevokeIncomingWebhookRequest)(nil),    // 128: messaging.RevokeIncomingWebhookRequest
+	(*RevokeIncomingWebhookResponse)(nil),   // 129: messaging.RevokeIncomingWebhookResponse
+	(*Command)(nil),                         // 130: messaging.Command
+	(*RegisterCommandRequest)(nil),          // 131: messaging.RegisterCommandRequest
+	(*RegisterCommandResponse)(nil),         // 132: messaging.RegisterCommandResponse
+	(*ListCommandsRequest)(nil),             // 133: messaging.ListCommandsRequest
+	(*ListCommandsResponse)(nil),            // 134: messaging.ListCommandsResponse
+	(*DeleteCommandRequest)(nil),            // 135: messaging.DeleteCommandRequest
+	(*DeleteCommandResponse)(nil),           // 136: messaging.DeleteCommandResponse
+	(*timestamppb.Timestamp)(nil),           // 137: google.protobuf.Timestamp
 }
 var file_proto_messaging_proto_depIdxs = []int32{
-	24, // 0: messaging.User.created_at:type_name -> google.protobuf.Timestamp
-	24, // 1: messaging.Message.sent_at:type_name -> google.protobuf.Timestamp
-	24, // 2: messaging.Chat.created_at:type_name -> google.protobuf.Timestamp
-	0,  // 3: messaging.Chat.members:type_name -> messaging.User
-	1,  // 4: messaging.Chat.last_message:type_name -> messaging.Message
-	1,  // 5: messaging.SendMessageResponse.message:type_name -> messaging.Message
-	1,  // 6: messaging.ListMessagesResponse.messages:type_name -> messaging.Message
-	24, // 7: messaging.ChatMessage.sent_at:type_name -> google.protobuf.Timestamp
-	2,  // 8: messaging.GetChatResponse.chat:type_name -> messaging.Chat
-	2,  // 9: messaging.ListChatsResponse.chats:type_name -> messaging.Chat
-	0,  // 10: messaging.CreateUserResponse.user:type_name -> messaging.User
-	0,  // 11: messaging.LoginResponse.user:type_name -> messaging.User
-	0,  // 12: messaging.GetUserResponse.user:type_name -> messaging.User
-	24, // 13: messaging.UserUpdate.timestamp:type_name -> google.protobuf.Timestamp
-	3,  // 14: messaging.MessagesService.SendMessage:input_type -> messaging.SendMessageRequest
-	5,  // 15: messaging.MessagesService.ListMessages:input_type -> messaging.ListMessagesRequest
-	7,  // 16: messaging.MessagesService.UpdateMessageStatus:input_type -> messaging.UpdateMessageStatusRequest
-	9,  // 17: messaging.MessagesService.SubscribeToChat:input_type -> messaging.SubscribeToChatRequest
-	11, // 18: messaging.ChatsService.CreateChat:input_type -> messaging.CreateChatRequest
-	13, // 19: messaging.ChatsService.GetChat:input_type -> messaging.GetChatRequest
-	15, // 20: messaging.ChatsService.ListChats:input_type -> messaging.ListChatsRequest
-	17, // 21: messaging.UsersService.CreateUser:input_type -> messaging.CreateUserRequest
-	19, // 22: messaging.UsersService.Login:input_type -> messaging.LoginRequest
-	4,  // 23: messaging.MessagesService.SendMessage:output_type -> messaging.SendMessageResponse
-	6,  // 24: messaging.MessagesService.ListMessages:output_type -> messaging.ListMessagesResponse
-	8,  // 25: messaging.MessagesService.UpdateMessageStatus:output_type -> messaging.UpdateMessageStatusResponse
-	10, // 26: messaging.MessagesService.SubscribeToChat:output_type -> messaging.ChatMessage
-	12, // 27: messaging.ChatsService.CreateChat:output_type -> messaging.CreateChatResponse
-	14, // 28: messaging.ChatsService.GetChat:output_type -> messaging.GetChatResponse
-	16, // 29: messaging.ChatsService.ListChats:output_type -> messaging.ListChatsResponse
-	18, // 30: messaging.UsersService.CreateUser:output_type -> messaging.CreateUserResponse
-	20, // 31: messaging.UsersService.Login:output_type -> messaging.LoginResponse
-	23, // [23:32] is the sub-list for method output_type
-	14, // [14:23] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	137, // 0: messaging.User.created_at:type_name -> google.protobuf.Timestamp
+	137, // 1: messaging.Message.sent_at:type_name -> google.protobuf.Timestamp
+	137, // 2: messaging.Chat.created_at:type_name -> google.protobuf.Timestamp
+	1,   // 3: messaging.Chat.members:type_name -> messaging.User
+	2,   // 4: messaging.Chat.last_message:type_name -> messaging.Message
+	4,   // 5: messaging.Chat.settings:type_name -> messaging.ChatSettings
+	137, // 6: messaging.Chat.last_activity_at:type_name -> google.protobuf.Timestamp
+	137, // 7: messaging.ChatSettings.muted_until:type_name -> google.protobuf.Timestamp
+	2,   // 8: messaging.SendMessageResponse.message:type_name -> messaging.Message
+	7,   // 9: messaging.SendMessageResponse.command_reply:type_name -> messaging.CommandReply
+	2,   // 10: messaging.ListMessagesResponse.messages:type_name -> messaging.Message
+	137, // 11: messaging.ScheduledMessage.send_at:type_name -> google.protobuf.Timestamp
+	137, // 12: messaging.ScheduledMessage.sent_at:type_name -> google.protobuf.Timestamp
+	137, // 13: messaging.ScheduledMessage.created_at:type_name -> google.protobuf.Timestamp
+	137, // 14: messaging.ScheduleMessageRequest.send_at:type_name -> google.protobuf.Timestamp
+	12,  // 15: messaging.ScheduleMessageResponse.scheduled_message:type_name -> messaging.ScheduledMessage
+	12,  // 16: messaging.ListScheduledResponse.scheduled_messages:type_name -> messaging.ScheduledMessage
+	137, // 17: messaging.ChatMessage.sent_at:type_name -> google.protobuf.Timestamp
+	0,   // 18: messaging.ChatMessage.type:type_name -> messaging.MessageType
+	3,   // 19: messaging.GetChatResponse.chat:type_name -> messaging.Chat
+	3,   // 20: messaging.ListChatsResponse.chats:type_name -> messaging.Chat
+	137, // 21: messaging.UpdateChatSettingsRequest.muted_until:type_name -> google.protobuf.Timestamp
+	4,   // 22: messaging.UpdateChatSettingsResponse.settings:type_name -> messaging.ChatSettings
+	3,   // 23: messaging.UpdateChatResponse.chat:type_name -> messaging.Chat
+	3,   // 24: messaging.DiscoverChannelsResponse.channels:type_name -> messaging.Chat
+	1,   // 25: messaging.CreateUserResponse.user:type_name -> messaging.User
+	137, // 26: messaging.CreateUserResponse.token_expires_at:type_name -> google.protobuf.Timestamp
+	1,   // 27: messaging.LoginResponse.user:type_name -> messaging.User
+	137, // 28: messaging.LoginResponse.token_expires_at:type_name -> google.protobuf.Timestamp
+	137, // 29: messaging.LoginResponse.challenge_expires_at:type_name -> google.protobuf.Timestamp
+	137, // 30: messaging.RefreshSessionResponse.token_expires_at:type_name -> google.protobuf.Timestamp
+	137, // 31: messaging.Session.created_at:type_name -> google.protobuf.Timestamp
+	137, // 32: messaging.Session.last_used_at:type_name -> google.protobuf.Timestamp
+	49,  // 33: messaging.ListSessionsResponse.sessions:type_name -> messaging.Session
+	1,   // 34: messaging.VerifyLoginChallengeResponse.user:type_name -> messaging.User
+	137, // 35: messaging.VerifyLoginChallengeResponse.token_expires_at:type_name -> google.protobuf.Timestamp
+	1,   // 36: messaging.GetUserResponse.user:type_name -> messaging.User
+	1,   // 37: messaging.GetMeResponse.user:type_name -> messaging.User
+	1,   // 38: messaging.UpdateProfileResponse.user:type_name -> messaging.User
+	1,   // 39: messaging.BatchGetUsersResponse.users:type_name -> messaging.User
+	1,   // 40: messaging.SearchUsersResponse.users:type_name -> messaging.User
+	137, // 41: messaging.UserUpdate.timestamp:type_name -> google.protobuf.Timestamp
+	1,   // 42: messaging.UserUpdate.user:type_name -> messaging.User
+	1,   // 43: messaging.BlockedUser.user:type_name -> messaging.User
+	137, // 44: messaging.BlockedUser.blocked_at:type_name -> google.protobuf.Timestamp
+	88,  // 45: messaging.ListBlockedResponse.users:type_name -> messaging.BlockedUser
+	137, // 46: messaging.Report.created_at:type_name -> google.protobuf.Timestamp
+	137, // 47: messaging.Report.updated_at:type_name -> google.protobuf.Timestamp
+	95,  // 48: messaging.ListReportsResponse.reports:type_name -> messaging.Report
+	95,  // 49: messaging.ResolveReportResponse.report:type_name -> messaging.Report
+	1,   // 50: messaging.CreateBotResponse.bot:type_name -> messaging.User
+	1,   // 51: messaging.ListBotsResponse.bots:type_name -> messaging.User
+	137, // 52: messaging.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	137, // 53: messaging.APIKey.last_used_at:type_name -> google.protobuf.Timestamp
+	106, // 54: messaging.CreateAPIKeyResponse.api_key:type_name -> messaging.APIKey
+	106, // 55: messaging.ListAPIKeysResponse.api_keys:type_name -> messaging.APIKey
+	137, // 56: messaging.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	113, // 57: messaging.CreateWebhookResponse.webhook:type_name -> messaging.Webhook
+	113, // 58: messaging.ListWebhooksResponse.webhooks:type_name -> messaging.Webhook
+	137, // 59: messaging.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	137, // 60: messaging.WebhookDelivery.last_attempt_at:type_name -> google.protobuf.Timestamp
+	137, // 61: messaging.WebhookDelivery.delivered_at:type_name -> google.protobuf.Timestamp
+	137, // 62: messaging.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	120, // 63: messaging.ListWebhookDeliveriesResponse.deliveries:type_name -> messaging.WebhookDelivery
+	137, // 64: messaging.IncomingWebhook.created_at:type_name -> google.protobuf.Timestamp
+	123, // 65: messaging.CreateIncomingWebhookResponse.incoming_webhook:type_name -> messaging.IncomingWebhook
+	123, // 66: messaging.ListIncomingWebhooksResponse.incoming_webhooks:type_name -> messaging.IncomingWebhook
+	137, // 67: messaging.Command.created_at:type_name -> google.protobuf.Timestamp
+	130, // 68: messaging.RegisterCommandResponse.command:type_name -> messaging.Command
+	130, // 69: messaging.ListCommandsResponse.commands:type_name -> messaging.Command
+	5,   // 70: messaging.MessagesService.SendMessage:input_type -> messaging.SendMessageRequest
+	8,   // 71: messaging.MessagesService.ListMessages:input_type -> messaging.ListMessagesRequest
+	10,  // 72: messaging.MessagesService.UpdateMessageStatus:input_type -> messaging.UpdateMessageStatusRequest
+	13,  // 73: messaging.MessagesService.ScheduleMessage:input_type -> messaging.ScheduleMessageRequest
+	15,  // 74: messaging.MessagesService.ListScheduled:input_type -> messaging.ListScheduledRequest
+	17,  // 75: messaging.MessagesService.CancelScheduled:input_type -> messaging.CancelScheduledRequest
+	19,  // 76: messaging.MessagesService.SubscribeToChat:input_type -> messaging.SubscribeToChatRequest
+	21,  // 77: messaging.ChatsService.CreateChat:input_type -> messaging.CreateChatRequest
+	23,  // 78: messaging.ChatsService.GetChat:input_type -> messaging.GetChatRequest
+	25,  // 79: messaging.ChatsService.ListChats:input_type -> messaging.ListChatsRequest
+	27,  // 80: messaging.ChatsService.UpdateChatSettings:input_type -> messaging.UpdateChatSettingsRequest
+	29,  // 81: messaging.ChatsService.UpdateChat:input_type -> messaging.UpdateChatRequest
+	31,  // 82: messaging.ChatsService.CreateChannel:input_type -> messaging.CreateChannelRequest
+	33,  // 83: messaging.ChatsService.JoinChannel:input_type -> messaging.JoinChannelRequest
+	35,  // 84: messaging.ChatsService.LeaveChannel:input_type -> messaging.LeaveChannelRequest
+	37,  // 85: messaging.ChatsService.AddChannelMember:input_type -> messaging.AddChannelMemberRequest
+	39,  // 86: messaging.ChatsService.DiscoverChannels:input_type -> messaging.DiscoverChannelsRequest
+	41,  // 87: messaging.UsersService.CreateUser:input_type -> messaging.CreateUserRequest
+	43,  // 88: messaging.UsersService.Login:input_type -> messaging.LoginRequest
+	45,  // 89: messaging.UsersService.RefreshSession:input_type -> messaging.RefreshSessionRequest
+	47,  // 90: messaging.UsersService.Logout:input_type -> messaging.LogoutRequest
+	50,  // 91: messaging.UsersService.ListSessions:input_type -> messaging.ListSessionsRequest
+	52,  // 92: messaging.UsersService.RevokeSession:input_type -> messaging.RevokeSessionRequest
+	54,  // 93: messaging.UsersService.ChangePassword:input_type -> messaging.ChangePasswordRequest
+	56,  // 94: messaging.UsersService.RequestPasswordReset:input_type -> messaging.RequestPasswordResetRequest
+	58,  // 95: messaging.UsersService.ConfirmPasswordReset:input_type -> messaging.ConfirmPasswordResetRequest
+	60,  // 96: messaging.UsersService.VerifyEmail:input_type -> messaging.VerifyEmailRequest
+	62,  // 97: messaging.UsersService.ResendVerificationEmail:input_type -> messaging.ResendVerificationEmailRequest
+	64,  // 98: messaging.UsersService.EnrollTOTP:input_type -> messaging.EnrollTOTPRequest
+	66,  // 99: messaging.UsersService.ConfirmTOTP:input_type -> messaging.ConfirmTOTPRequest
+	68,  // 100: messaging.UsersService.VerifyLoginChallenge:input_type -> messaging.VerifyLoginChallengeRequest
+	70,  // 101: messaging.UsersService.LoginWithIDToken:input_type -> messaging.LoginWithIDTokenRequest
+	71,  // 102: messaging.UsersService.ExchangeOIDCCode:input_type -> messaging.ExchangeOIDCCodeRequest
+	72,  // 103: messaging.UsersService.GetUser:input_type -> messaging.GetUserRequest
+	74,  // 104: messaging.UsersService.GetMe:input_type -> messaging.GetMeRequest
+	76,  // 105: messaging.UsersService.UpdateProfile:input_type -> messaging.UpdateProfileRequest
+	78,  // 106: messaging.UsersService.BatchGetUsers:input_type -> messaging.BatchGetUsersRequest
+	80,  // 107: messaging.UsersService.SearchUsers:input_type -> messaging.SearchUsersRequest
+	82,  // 108: messaging.UsersService.SubscribeToUserUpdates:input_type -> messaging.SubscribeToUserUpdatesRequest
+	84,  // 109: messaging.ModerationService.BlockUser:input_type -> messaging.BlockUserRequest
+	86,  // 110: messaging.ModerationService.UnblockUser:input_type -> messaging.UnblockUserRequest
+	89,  // 111: messaging.ModerationService.ListBlocked:input_type -> messaging.ListBlockedRequest
+	91,  // 112: messaging.ModerationService.ReportUser:input_type -> messaging.ReportUserRequest
+	93,  // 113: messaging.ModerationService.ReportMessage:input_type -> messaging.ReportMessageRequest
+	96,  // 114: messaging.ModerationService.ListReports:input_type -> messaging.ListReportsRequest
+	98,  // 115: messaging.ModerationService.ResolveReport:input_type -> messaging.ResolveReportRequest
+	100, // 116: messaging.BotsService.CreateBot:input_type -> messaging.CreateBotRequest
+	102, // 117: messaging.BotsService.ListBots:input_type -> messaging.ListBotsRequest
+	104, // 118: messaging.BotsService.AddBotToChat:input_type -> messaging.AddBotToChatRequest
+	107, // 119: messaging.BotsService.CreateAPIKey:input_type -> messaging.CreateAPIKeyRequest
+	109, // 120: messaging.BotsService.ListAPIKeys:input_type -> messaging.ListAPIKeysRequest
+	111, // 121: messaging.BotsService.RevokeAPIKey:input_type -> messaging.RevokeAPIKeyRequest
+	114, // 122: messaging.WebhooksService.CreateWebhook:input_type -> messaging.CreateWebhookRequest
+	116, // 123: messaging.WebhooksService.ListWebhooks:input_type -> messaging.ListWebhooksRequest
+	118, // 124: messaging.WebhooksService.DeleteWebhook:input_type -> messaging.DeleteWebhookRequest
+	121, // 125: messaging.WebhooksService.ListWebhookDeliveries:input_type -> messaging.ListWebhookDeliveriesRequest
+	124, // 126: messaging.WebhooksService.CreateIncomingWebhook:input_type -> messaging.CreateIncomingWebhookRequest
+	126, // 127: messaging.WebhooksService.ListIncomingWebhooks:input_type -> messaging.ListIncomingWebhooksRequest
+	128, // 128: messaging.WebhooksService.RevokeIncomingWebhook:input_type -> messaging.RevokeIncomingWebhookRequest
+	131, // 129: messaging.CommandsService.RegisterCommand:input_type -> messaging.RegisterCommandRequest
+	133, // 130: messaging.CommandsService.ListCommands:input_type -> messaging.ListCommandsRequest
+	135, // 131: messaging.CommandsService.DeleteCommand:input_type -> messaging.DeleteCommandRequest
+	6,   // 132: messaging.MessagesService.SendMessage:output_type -> messaging.SendMessageResponse
+	9,   // 133: messaging.MessagesService.ListMessages:output_type -> messaging.ListMessagesResponse
+	11,  // 134: messaging.MessagesService.UpdateMessageStatus:output_type -> messaging.UpdateMessageStatusResponse
+	14,  // 135: messaging.MessagesService.ScheduleMessage:output_type -> messaging.ScheduleMessageResponse
+	16,  // 136: messaging.MessagesService.ListScheduled:output_type -> messaging.ListScheduledResponse
+	18,  // 137: messaging.MessagesService.CancelScheduled:output_type -> messaging.CancelScheduledResponse
+	20,  // 138: messaging.MessagesService.SubscribeToChat:output_type -> messaging.ChatMessage
+	22,  // 139: messaging.ChatsService.CreateChat:output_type -> messaging.CreateChatResponse
+	24,  // 140: messaging.ChatsService.GetChat:output_type -> messaging.GetChatResponse
+	26,  // 141: messaging.ChatsService.ListChats:output_type -> messaging.ListChatsResponse
+	28,  // 142: messaging.ChatsService.UpdateChatSettings:output_type -> messaging.UpdateChatSettingsResponse
+	30,  // 143: messaging.ChatsService.UpdateChat:output_type -> messaging.UpdateChatResponse
+	32,  // 144: messaging.ChatsService.CreateChannel:output_type -> messaging.CreateChannelResponse
+	34,  // 145: messaging.ChatsService.JoinChannel:output_type -> messaging.JoinChannelResponse
+	36,  // 146: messaging.ChatsService.LeaveChannel:output_type -> messaging.LeaveChannelResponse
+	38,  // 147: messaging.ChatsService.AddChannelMember:output_type -> messaging.AddChannelMemberResponse
+	40,  // 148: messaging.ChatsService.DiscoverChannels:output_type -> messaging.DiscoverChannelsResponse
+	42,  // 149: messaging.UsersService.CreateUser:output_type -> messaging.CreateUserResponse
+	44,  // 150: messaging.UsersService.Login:output_type -> messaging.LoginResponse
+	46,  // 151: messaging.UsersService.RefreshSession:output_type -> messaging.RefreshSessionResponse
+	48,  // 152: messaging.UsersService.Logout:output_type -> messaging.LogoutResponse
+	51,  // 153: messaging.UsersService.ListSessions:output_type -> messaging.ListSessionsResponse
+	53,  // 154: messaging.UsersService.RevokeSession:output_type -> messaging.RevokeSessionResponse
+	55,  // 155: messaging.UsersService.ChangePassword:output_type -> messaging.ChangePasswordResponse
+	57,  // 156: messaging.UsersService.RequestPasswordReset:output_type -> messaging.RequestPasswordResetResponse
+	59,  // 157: messaging.UsersService.ConfirmPasswordReset:output_type -> messaging.ConfirmPasswordResetResponse
+	61,  // 158: messaging.UsersService.VerifyEmail:output_type -> messaging.VerifyEmailResponse
+	63,  // 159: messaging.UsersService.ResendVerificationEmail:output_type -> messaging.ResendVerificationEmailResponse
+	65,  // 160: messaging.UsersService.EnrollTOTP:output_type -> messaging.EnrollTOTPResponse
+	67,  // 161: messaging.UsersService.ConfirmTOTP:output_type -> messaging.ConfirmTOTPResponse
+	69,  // 162: messaging.UsersService.VerifyLoginChallenge:output_type -> messaging.VerifyLoginChallengeResponse
+	44,  // 163: messaging.UsersService.LoginWithIDToken:output_type -> messaging.LoginResponse
+	44,  // 164: messaging.UsersService.ExchangeOIDCCode:output_type -> messaging.LoginResponse
+	73,  // 165: messaging.UsersService.GetUser:output_type -> messaging.GetUserResponse
+	75,  // 166: messaging.UsersService.GetMe:output_type -> messaging.GetMeResponse
+	77,  // 167: messaging.UsersService.UpdateProfile:output_type -> messaging.UpdateProfileResponse
+	79,  // 168: messaging.UsersService.BatchGetUsers:output_type -> messaging.BatchGetUsersResponse
+	81,  // 169: messaging.UsersService.SearchUsers:output_type -> messaging.SearchUsersResponse
+	83,  // 170: messaging.UsersService.SubscribeToUserUpdates:output_type -> messaging.UserUpdate
+	85,  // 171: messaging.ModerationService.BlockUser:output_type -> messaging.BlockUserResponse
+	87,  // 172: messaging.ModerationService.UnblockUser:output_type -> messaging.UnblockUserResponse
+	90,  // 173: messaging.ModerationService.ListBlocked:output_type -> messaging.ListBlockedResponse
+	92,  // 174: messaging.ModerationService.ReportUser:output_type -> messaging.ReportUserResponse
+	94,  // 175: messaging.ModerationService.ReportMessage:output_type -> messaging.ReportMessageResponse
+	97,  // 176: messaging.ModerationService.ListReports:output_type -> messaging.ListReportsResponse
+	99,  // 177: messaging.ModerationService.ResolveReport:output_type -> messaging.ResolveReportResponse
+	101, // 178: messaging.BotsService.CreateBot:output_type -> messaging.CreateBotResponse
+	103, // 179: messaging.BotsService.ListBots:output_type -> messaging.ListBotsResponse
+	105, // 180: messaging.BotsService.AddBotToChat:output_type -> messaging.AddBotToChatResponse
+	108, // 181: messaging.BotsService.CreateAPIKey:output_type -> messaging.CreateAPIKeyResponse
+	110, // 182: messaging.BotsService.ListAPIKeys:output_type -> messaging.ListAPIKeysResponse
+	112, // 183: messaging.BotsService.RevokeAPIKey:output_type -> messaging.RevokeAPIKeyResponse
+	115, // 184: messaging.WebhooksService.CreateWebhook:output_type -> messaging.CreateWebhookResponse
+	117, // 185: messaging.WebhooksService.ListWebhooks:output_type -> messaging.ListWebhooksResponse
+	119, // 186: messaging.WebhooksService.DeleteWebhook:output_type -> messaging.DeleteWebhookResponse
+	122, // 187: messaging.WebhooksService.ListWebhookDeliveries:output_type -> messaging.ListWebhookDeliveriesResponse
+	125, // 188: messaging.WebhooksService.CreateIncomingWebhook:output_type -> messaging.CreateIncomingWebhookResponse
+	127, // 189: messaging.WebhooksService.ListIncomingWebhooks:output_type -> messaging.ListIncomingWebhooksResponse
+	129, // 190: messaging.WebhooksService.RevokeIncomingWebhook:output_type -> messaging.RevokeIncomingWebhookResponse
+	132, // 191: messaging.CommandsService.RegisterCommand:output_type -> messaging.RegisterCommandResponse
+	134, // 192: messaging.CommandsService.ListCommands:output_type -> messaging.ListCommandsResponse
+	136, // 193: messaging.CommandsService.DeleteCommand:output_type -> messaging.DeleteCommandResponse
+	132, // [132:194] is the sub-list for method output_type
+	70,  // [70:132] is the sub-list for method input_type
+	70,  // [70:70] is the sub-list for extension type_name
+	70,  // [70:70] is the sub-list for extension extendee
+	0,   // [0:70] is the sub-list for field type_name
 }
 
 func init() { file_proto_messaging_proto_init() }
@@ -1563,18 +8684,22 @@ func file_proto_messaging_proto_init() {
 	if File_proto_messaging_proto != nil {
 		return
 	}
+	file_proto_messaging_proto_msgTypes[26].OneofWrappers = []any{}
+	file_proto_messaging_proto_msgTypes[28].OneofWrappers = []any{}
+	file_proto_messaging_proto_msgTypes[75].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_messaging_proto_rawDesc), len(file_proto_messaging_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   24,
+			NumEnums:      1,
+			NumMessages:   136,
 			NumExtensions: 0,
-			NumServices:   3,
+			NumServices:   7,
 		},
 		GoTypes:           file_proto_messaging_proto_goTypes,
 		DependencyIndexes: file_proto_messaging_proto_depIdxs,
+		EnumInfos:         file_proto_messaging_proto_enumTypes,
 		MessageInfos:      file_proto_messaging_proto_msgTypes,
 	}.Build()
 	File_proto_messaging_proto = out.File